@@ -25,6 +25,13 @@ type ImageSetSpec struct {
 	// Images is the list of images to use digests. All images that the operator will deploy
 	// must be specified.
 	Images []Image `json:"images,omitempty"`
+
+	// Signature is the base64 encoded detached signature of the ImageSet's digest list, as written by
+	// `cosign sign-blob`. The signed payload has one line per image in the form `<image>@<digest>`,
+	// sorted by image and each terminated by a newline. It is required when the Installation enables
+	// ImageSetVerification.
+	// +optional
+	Signature string `json:"signature,omitempty"`
 }
 
 type Image struct {
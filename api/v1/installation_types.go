@@ -19,6 +19,7 @@ package v1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -72,6 +73,25 @@ type InstallationSpec struct {
 	// +optional
 	ImagePrefix string `json:"imagePrefix,omitempty"`
 
+	// ImageOverrides remaps specific images, or all images whose reference starts with a given prefix, to a
+	// different registry, e.g. so that images from different vendors can be pulled from separate mirrors.
+	// Overrides are applied after Registry, ImagePath and ImagePrefix, and the first override that matches an
+	// image is used. Image digests from an ImageSet are still used for overridden images.
+	// +optional
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+
+	// ImageSetVerification configures the operator to only use ImageSets whose digest list is signed by a trusted
+	// key. When set, an ImageSet without a valid signature is not used and the components that require it are
+	// reported as degraded.
+	// +optional
+	ImageSetVerification *ImageSetVerification `json:"imageSetVerification,omitempty"`
+
+	// ImageSetCanary runs calico-node with the images from a second ImageSet on a subset of the nodes, so that new
+	// image digests can be tried out before they are rolled out to the whole cluster. It cannot be used with
+	// NodeUpgrade.
+	// +optional
+	ImageSetCanary *ImageSetCanary `json:"imageSetCanary,omitempty"`
+
 	// ImagePullSecrets is an array of references to container registry pull secrets to use. These are
 	// applied to all images to be pulled.
 	// +optional
@@ -140,6 +160,12 @@ type InstallationSpec struct {
 	// +optional
 	NodeUpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"nodeUpdateStrategy,omitempty"`
 
+	// NodeUpgrade configures a staged upgrade of calico-node. When set, changes to calico-node are rolled out by the
+	// operator in health-gated batches, after Typha has been upgraded, instead of by NodeUpdateStrategy. It cannot be
+	// used with ImageSetCanary.
+	// +optional
+	NodeUpgrade *NodeUpgrade `json:"nodeUpgrade,omitempty"`
+
 	// Deprecated. Please use CalicoNodeDaemonSet, TyphaDeployment, and KubeControllersDeployment.
 	// ComponentResources can be used to customize the resource requirements for each component.
 	// Node, Typha, and KubeControllers are supported for installations.
@@ -152,6 +178,10 @@ type InstallationSpec struct {
 	// +optional
 	CertificateManagement *CertificateManagement `json:"certificateManagement,omitempty"`
 
+	// CertificateRotation configures when the certificates issued by the operator, and the operator CA itself, are renewed.
+	// +optional
+	CertificateRotation *CertificateRotation `json:"certificateRotation,omitempty"`
+
 	// NonPrivileged configures Calico to be run in non-privileged containers as non-root users where possible.
 	// +optional
 	NonPrivileged *NonPrivilegedType `json:"nonPrivileged,omitempty"`
@@ -286,6 +316,112 @@ type ComponentResource struct {
 	ResourceRequirements *v1.ResourceRequirements `json:"resourceRequirements"`
 }
 
+// ImageOverride remaps the registry that one or more images are pulled from.
+type ImageOverride struct {
+	// Image selects a single image by its name, without registry, image path or tag,
+	// e.g. calico/node or tigera/eck-operator. Exactly one of Image and Prefix must be set.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Prefix selects every image whose reference, after Registry, ImagePath and ImagePrefix have been
+	// applied, starts with the given value, e.g. docker.io/calico/ or quay.io/. Exactly one of Image
+	// and Prefix must be set.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Mirror replaces the matched prefix or, for an Image override, the registry of the image. For
+	// example, an Image override for calico/node with the mirror registry.example.com/patched/ results in
+	// registry.example.com/patched/calico/node.
+	Mirror string `json:"mirror"`
+}
+
+// ImageSetVerification configures the keys that ImageSets must be signed with.
+type ImageSetVerification struct {
+	// PublicKeySecretName is the name of a Secret in the tigera-operator namespace holding the PEM encoded public
+	// keys that are trusted to sign ImageSets, such as the cosign.pub written by `cosign generate-key-pair`.
+	// ECDSA, RSA and Ed25519 keys are supported, and every key in the Secret is trusted.
+	PublicKeySecretName string `json:"publicKeySecretName"`
+}
+
+// ImageSetCanary configures a canary rollout of the calico-node images.
+type ImageSetCanary struct {
+	// ImageSet is the name of the ImageSet with the images for the canary nodes. Only the images of the calico-node
+	// DaemonSet are taken from it, and it is validated and verified in the same way as the ImageSet for the release.
+	ImageSet string `json:"imageSet"`
+
+	// NodeSelector selects the canary nodes. A second DaemonSet, calico-node-canary, runs on the nodes that have all
+	// of the labels and calico-node runs on every other node.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// PromoteAfter is how long every calico-node-canary pod must have been available before the operator promotes
+	// the canary: calico-node then runs the canary's images on every node, calico-node-canary is removed and the
+	// promotion is recorded in the Installation's status.promotedImageSetCanary. The promotion lasts until
+	// ImageSetCanary is removed or names another ImageSet, so the ImageSet for the release should be updated with the
+	// canary's digests before ImageSetCanary is removed. If not set, the canary is not promoted by the operator.
+	// +optional
+	PromoteAfter *metav1.Duration `json:"promoteAfter,omitempty"`
+}
+
+// NodeUpgrade configures how calico-node is upgraded in batches.
+type NodeUpgrade struct {
+	// BatchPercent is the percentage of calico-node pods that are replaced in each batch. At least one pod is
+	// replaced per batch.
+	// Default: 10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BatchPercent *int32 `json:"batchPercent,omitempty"`
+
+	// ZoneLabel is a node label, such as topology.kubernetes.io/zone, used to upgrade one zone at a time. When set,
+	// a batch only contains nodes from a single zone, and zones are upgraded in the order of their label values.
+	// +optional
+	ZoneLabel string `json:"zoneLabel,omitempty"`
+
+	// SoakPeriod is how long the cluster must be healthy after a batch before the next batch is started.
+	// Default: 5m
+	// +optional
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+
+	// FailurePolicy is what the operator does if the calico component becomes degraded during an upgrade. With Pause,
+	// no more batches are started until it is no longer degraded. With Rollback, calico-node is reverted to its
+	// previous revision until the Installation is changed again.
+	// Default: Pause
+	// +kubebuilder:validation:Enum=Pause;Rollback
+	// +optional
+	FailurePolicy NodeUpgradeFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+type NodeUpgradeFailurePolicy string
+
+const (
+	NodeUpgradeFailurePolicyPause    NodeUpgradeFailurePolicy = "Pause"
+	NodeUpgradeFailurePolicyRollback NodeUpgradeFailurePolicy = "Rollback"
+)
+
+// GetBatchPercent returns the percentage of calico-node pods to replace in each batch.
+func (u *NodeUpgrade) GetBatchPercent() int32 {
+	if u.BatchPercent == nil {
+		return 10
+	}
+	return *u.BatchPercent
+}
+
+// GetSoakPeriod returns how long to wait between batches.
+func (u *NodeUpgrade) GetSoakPeriod() time.Duration {
+	if u.SoakPeriod == nil {
+		return 5 * time.Minute
+	}
+	return u.SoakPeriod.Duration
+}
+
+// GetFailurePolicy returns the FailurePolicy, defaulting to Pause.
+func (u *NodeUpgrade) GetFailurePolicy() NodeUpgradeFailurePolicy {
+	if u.FailurePolicy == "" {
+		return NodeUpgradeFailurePolicyPause
+	}
+	return u.FailurePolicy
+}
+
 // Provider represents a particular provider or flavor of Kubernetes. Valid options
 // are: EKS, GKE, AKS, RKE2, OpenShift, DockerEnterprise.
 type Provider string
@@ -734,12 +870,116 @@ type InstallationStatus struct {
 	// version deployed.
 	CalicoVersion string `json:"calicoVersion,omitempty"`
 
+	// PromotedImageSetCanary is the name of the canary ImageSet whose images calico-node runs on every node, once
+	// the canary set up by ImageSetCanary has been promoted.
+	// +optional
+	PromotedImageSetCanary string `json:"promotedImageSetCanary,omitempty"`
+
+	// NodeUpgrade is the progress of the current staged upgrade of calico-node, if NodeUpgrade is configured.
+	// +optional
+	NodeUpgrade *NodeUpgradeStatus `json:"nodeUpgrade,omitempty"`
+
+	// Report explains where the values in Computed came from and lists the objects managed for the Installation that
+	// no longer match what the operator renders.
+	// +optional
+	Report *InstallationReport `json:"report,omitempty"`
+
 	// Conditions represents the latest observed set of conditions for the component. A component may be one or more of
 	// Ready, Progressing, Degraded or other customer types.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// NodeUpgradeStatus is the progress of a staged upgrade of calico-node.
+type NodeUpgradeStatus struct {
+	// State is one of Upgrading, Paused, RollingBack or Complete.
+	State NodeUpgradeState `json:"state"`
+
+	// Message explains why the upgrade is waiting, paused or rolling back.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// UpdatedPods is the number of calico-node pods running the current revision.
+	UpdatedPods int32 `json:"updatedPods"`
+
+	// TotalPods is the number of calico-node pods.
+	TotalPods int32 `json:"totalPods"`
+}
+
+// InstallationReport explains the computed Installation and its drift from the objects in the cluster.
+type InstallationReport struct {
+	// FieldSources lists each field set in the computed Installation spec and where its value came from.
+	// +optional
+	FieldSources []InstallationFieldSource `json:"fieldSources,omitempty"`
+
+	// DriftedObjects lists the objects rendered for the Installation whose live state differed from what the operator
+	// renders when it last checked them for drift, before it wrote them, for example because they were edited or are
+	// annotated to be ignored by the operator. The objects are checked at most every five minutes.
+	// +optional
+	DriftedObjects []DriftedObject `json:"driftedObjects,omitempty"`
+}
+
+// InstallationFieldSource is where the value of a field in the computed Installation spec came from.
+type InstallationFieldSource struct {
+	// Path is the path of the field in the spec, e.g. calicoNetwork.ipPools[0].cidr.
+	Path string `json:"path"`
+
+	// Value is the JSON encoded value of the field.
+	Value string `json:"value"`
+
+	// Source is what set the field.
+	Source InstallationFieldSourceType `json:"source"`
+}
+
+// InstallationFieldSourceType is what set a field in the computed Installation spec.
+// One of: User, Overlay, Migration, Detected, OpenShift, Kubeadm, EKS, Default
+type InstallationFieldSourceType string
+
+const (
+	// FieldSourceUser is a value set in the default Installation. Values written to the Installation by an operator
+	// that did not report sources are also attributed to the user.
+	FieldSourceUser InstallationFieldSourceType = "User"
+	// FieldSourceOverlay is a value set in the overlay Installation.
+	FieldSourceOverlay InstallationFieldSourceType = "Overlay"
+	// FieldSourceMigration is a value migrated from a Calico installation that was not managed by the operator.
+	FieldSourceMigration InstallationFieldSourceType = "Migration"
+	// FieldSourceDetected is a value detected from the cluster, such as the Kubernetes provider.
+	FieldSourceDetected InstallationFieldSourceType = "Detected"
+	// FieldSourceOpenShift is a value taken from the OpenShift network configuration.
+	FieldSourceOpenShift InstallationFieldSourceType = "OpenShift"
+	// FieldSourceKubeadm is a value taken from the kubeadm configuration.
+	FieldSourceKubeadm InstallationFieldSourceType = "Kubeadm"
+	// FieldSourceEKS is a value set because the cluster runs the AWS VPC CNI plugin.
+	FieldSourceEKS InstallationFieldSourceType = "EKS"
+	// FieldSourceDefault is a default value filled in by the operator.
+	FieldSourceDefault InstallationFieldSourceType = "Default"
+)
+
+// DriftedObject is an object whose live state differs from what the operator renders.
+type DriftedObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Fields are the paths of the first fields found to differ.
+	Fields []string `json:"fields"`
+
+	// Ignored is true if the object is annotated to be ignored by the operator.
+	// +optional
+	Ignored bool `json:"ignored,omitempty"`
+}
+
+type NodeUpgradeState string
+
+const (
+	NodeUpgradeStateUpgrading   NodeUpgradeState = "Upgrading"
+	NodeUpgradeStatePaused      NodeUpgradeState = "Paused"
+	NodeUpgradeStateRollingBack NodeUpgradeState = "RollingBack"
+	NodeUpgradeStateComplete    NodeUpgradeState = "Complete"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -780,7 +1020,9 @@ type CertificateManagement struct {
 	// When a CSR is issued to the certificates.k8s.io API, the signerName is added to the request in order to accommodate for clusters
 	// with multiple signers.
 	// Must be formatted as: `<my-domain>/<my-signername>`.
-	SignerName string `json:"signerName"`
+	// Required unless an issuer is configured.
+	// +optional
+	SignerName string `json:"signerName,omitempty"`
 
 	// Specify the algorithm used by pods to generate a key pair that is associated with the X.509 certificate request.
 	// Default: RSAWithSize2048
@@ -793,6 +1035,81 @@ type CertificateManagement struct {
 	// +kubebuilder:validation:Enum="";SHA256WithRSA;SHA384WithRSA;SHA512WithRSA;ECDSAWithSHA256;ECDSAWithSHA384;ECDSAWithSHA512;
 	// +optional
 	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+
+	// Issuer configures the operator to delegate the issuance of every key pair to an external issuer, such as a
+	// cert-manager Issuer or ClusterIssuer. For each key pair, the operator creates a cert-manager Certificate in the
+	// operator namespace and waits for the issuer to write the resulting secret. Pods then mount the issued secret,
+	// instead of submitting CertificateSigningRequests. The CACert must be the certificate of the issuer's CA.
+	// +optional
+	Issuer *CertificateIssuer `json:"issuer,omitempty"`
+
+	// CSRApprovals lists the assets for which the operator approves CertificateSigningRequests. Requests that use the
+	// signerName and match an entry are approved, and are then signed by the signer. Requests for other assets are left
+	// for the cluster's own approval process.
+	// +optional
+	CSRApprovals []CSRApproval `json:"csrApprovals,omitempty"`
+}
+
+// CertificateIssuer references an issuer that signs the certificates requested by the operator.
+type CertificateIssuer struct {
+	// Name of the issuer.
+	Name string `json:"name"`
+
+	// Kind of the issuer.
+	// Default: ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer.
+	// Default: cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CSRApproval describes an asset for which the operator approves CertificateSigningRequests.
+type CSRApproval struct {
+	// SecretName is the name of the secret that the certificate is requested for. CertificateSigningRequests that are
+	// created by pods are named after the secret.
+	SecretName string `json:"secretName"`
+
+	// ServiceAccountName is the name of the service account of the pods that may request the certificate.
+	ServiceAccountName string `json:"serviceAccountName"`
+
+	// ServiceAccountNamespace is the namespace of the service account of the pods that may request the certificate.
+	ServiceAccountNamespace string `json:"serviceAccountNamespace"`
+
+	// DNSNames lists the DNS names, including the common name, that the certificate may contain.
+	DNSNames []string `json:"dnsNames"`
+}
+
+// UseCSRs returns true if pods submit CertificateSigningRequests to obtain their certificates. This is the case when
+// certificate management is configured without an issuer.
+func (c *CertificateManagement) UseCSRs() bool {
+	return c != nil && c.Issuer == nil
+}
+
+// CertificateRotation configures the renewal of the certificates that are issued by the operator. Certificates that
+// are provided by the user are never renewed by the operator, but are reported on the "certificates" TigeraStatus
+// when they are about to expire.
+type CertificateRotation struct {
+	// RenewalPercentage is the percentage of a certificate's lifetime after which the operator issues a new certificate
+	// to replace it. It applies to both the certificates signed by the operator CA and to the operator CA.
+	// Default: 67
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	RenewalPercentage *int32 `json:"renewalPercentage,omitempty"`
+
+	// CAOverlapDuration is how long the previous operator CA remains in the trusted bundles after the operator CA has
+	// been rotated. This gives components the time to pick up certificates that are signed by the new CA.
+	// Default: 24h
+	// +optional
+	CAOverlapDuration *metav1.Duration `json:"caOverlapDuration,omitempty"`
+
+	// ExpiryWarningDuration is how long before a certificate expires that the certificates TigeraStatus is degraded.
+	// Default: 720h
+	// +optional
+	ExpiryWarningDuration *metav1.Duration `json:"expiryWarningDuration,omitempty"`
 }
 
 // IsFIPSModeEnabled is a convenience function for turning a FIPSMode reference into a bool.
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -70,6 +71,15 @@ type AdditionalLogStoreSpec struct {
 	// If specified, enables exporting of flow, audit, and DNS logs to splunk.
 	// +optional
 	Splunk *SplunkStoreSpec `json:"splunk,omitempty"`
+	// If specified, enables exporting of logs to a Kafka topic.
+	// +optional
+	Kafka *KafkaStoreSpec `json:"kafka,omitempty"`
+	// If specified, enables exporting of logs to an HTTP endpoint.
+	// +optional
+	HTTP *HTTPStoreSpec `json:"http,omitempty"`
+	// If specified, enables exporting of logs to an OpenTelemetry collector with OTLP.
+	// +optional
+	OTLP *OTLPStoreSpec `json:"otlp,omitempty"`
 }
 
 type AdditionalLogSourceSpec struct {
@@ -157,6 +167,96 @@ type SplunkStoreSpec struct {
 	Endpoint string `json:"endpoint"`
 }
 
+// KafkaSASLMechanism is the SASL mechanism used to authenticate to Kafka.
+//
+// One of: PLAIN, SCRAM-SHA-256, SCRAM-SHA-512
+// +kubebuilder:validation:Enum=PLAIN;SCRAM-SHA-256;SCRAM-SHA-512
+type KafkaSASLMechanism string
+
+const (
+	KafkaSASLPlain       KafkaSASLMechanism = "PLAIN"
+	KafkaSASLScramSHA256 KafkaSASLMechanism = "SCRAM-SHA-256"
+	KafkaSASLScramSHA512 KafkaSASLMechanism = "SCRAM-SHA-512"
+)
+
+// KafkaStoreSpec defines configuration for exporting logs to Kafka.
+type KafkaStoreSpec struct {
+	// Brokers are the Kafka brokers to connect to. example: kafka-0.example.com:9093
+	// +kubebuilder:validation:MinItems=1
+	Brokers []string `json:"brokers"`
+
+	// Topic is the Kafka topic the logs are written to.
+	Topic string `json:"topic"`
+
+	// If no values are provided, the list will be updated to include log types Audit, DNS and Flows.
+	// Default: Audit, DNS, Flows
+	// +optional
+	LogTypes []SyslogLogType `json:"logTypes,omitempty"`
+
+	// Encryption configures traffic encryption to the Kafka brokers.
+	// Default: None
+	// +optional
+	// +kubebuilder:validation:Enum=None;TLS
+	Encryption EncryptionOption `json:"encryption,omitempty"`
+
+	// CredentialsSecretName is the name of a Secret in the tigera-operator namespace with the username and password
+	// used to authenticate to Kafka with SASL, in its username and password fields. If not specified, SASL is not used.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// SASLMechanism is the SASL mechanism used with the credentials.
+	// Default: PLAIN
+	// +optional
+	SASLMechanism KafkaSASLMechanism `json:"saslMechanism,omitempty"`
+}
+
+// HTTPStoreSpec defines configuration for exporting logs to an HTTP endpoint. The logs are sent as JSON in the body of
+// POST requests.
+type HTTPStoreSpec struct {
+	// Endpoint is the URL the logs are sent to. example: https://logs.example.com/ingest
+	Endpoint string `json:"endpoint"`
+
+	// Headers are added to each request, for example to authenticate to the endpoint.
+	// +optional
+	Headers []HTTPHeader `json:"headers,omitempty"`
+
+	// If no values are provided, the list will be updated to include log types Audit, DNS and Flows.
+	// Default: Audit, DNS, Flows
+	// +optional
+	LogTypes []SyslogLogType `json:"logTypes,omitempty"`
+}
+
+// OTLPStoreSpec defines configuration for exporting logs to an OpenTelemetry collector, with OTLP over HTTP.
+type OTLPStoreSpec struct {
+	// Endpoint is the URL of the OTLP logs receiver. example: https://otel-collector.example.com:4318/v1/logs
+	Endpoint string `json:"endpoint"`
+
+	// Headers are added to each request, for example to authenticate to the collector.
+	// +optional
+	Headers []HTTPHeader `json:"headers,omitempty"`
+
+	// If no values are provided, the list will be updated to include log types Audit, DNS and Flows.
+	// Default: Audit, DNS, Flows
+	// +optional
+	LogTypes []SyslogLogType `json:"logTypes,omitempty"`
+}
+
+// HTTPHeader is a header added to the requests sent to an HTTP endpoint. Exactly one of Value and ValueFrom must be
+// set.
+type HTTPHeader struct {
+	// Name of the header. example: Authorization
+	Name string `json:"name"`
+
+	// Value of the header.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom reads the value of the header from a key of a Secret in the tigera-operator namespace, for headers
+	// with credentials.
+	// +optional
+	ValueFrom *corev1.SecretKeySelector `json:"valueFrom,omitempty"`
+}
+
 // EksConfigSpec defines configuration for fetching EKS audit logs.
 type EksCloudwatchLogsSpec struct {
 	// AWS Region EKS cluster is hosted in.
@@ -18,12 +18,14 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // LogStorageSpec defines the desired state of Tigera flow and DNS log storage.
 type LogStorageSpec struct {
-	// Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.
+	// Nodes defines the configuration for the Elasticsearch cluster nodes. Unless their NodeSets set their roles, the nodes
+	// are each of type master, data, and ingest.
 	Nodes *Nodes `json:"nodes,omitempty"`
 
 	// Index defines the configuration for the indices in the Elasticsearch cluster.
@@ -34,6 +36,17 @@ type LogStorageSpec struct {
 	// +optional
 	Retention *Retention `json:"retention,omitempty"`
 
+	// IndexLifecyclePolicies configures the index lifecycle management (ILM) policies of the time series indices for
+	// each type of data. Settings that are not configured here are computed from the size of the Elasticsearch disk
+	// and the Retention.
+	// +optional
+	IndexLifecyclePolicies []IndexLifecyclePolicy `json:"indexLifecyclePolicies,omitempty"`
+
+	// Snapshots configures snapshots of the Elasticsearch indices, so that the logs, compliance reports and audit
+	// history can be restored if the Elasticsearch volumes are lost.
+	// +optional
+	Snapshots *ElasticsearchSnapshots `json:"snapshots,omitempty"`
+
 	// StorageClassName will populate the PersistentVolumeClaim.StorageClassName that is used to provision disks to the
 	// Tigera Elasticsearch cluster. The StorageClassName should only be modified when no LogStorage is currently
 	// active. We recommend choosing a storage class dedicated to Tigera LogStorage only. Otherwise, data retention
@@ -71,9 +84,166 @@ type LogStorageStatus struct {
 	// Ready, Progressing, Degraded or other customer types.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Snapshots reports the most recent Elasticsearch snapshots and restore.
+	// +optional
+	Snapshots *ElasticsearchSnapshotStatus `json:"snapshots,omitempty"`
+}
+
+// ElasticsearchUpgradeCondition is the type of the LogStorage condition that tracks the upgrade of Elasticsearch to the
+// version of the operator. Its reason is the phase of the upgrade, and it is True once the upgrade has succeeded.
+const ElasticsearchUpgradeCondition = "ElasticsearchUpgrade"
+
+// ElasticsearchUpgradePhase is a phase of an upgrade of Elasticsearch.
+type ElasticsearchUpgradePhase string
+
+const (
+	// ElasticsearchUpgradeBlocked means the pre-flight checks have failed, for example because the cluster is not
+	// green or a node is over the high disk watermark. Elasticsearch keeps running its current version.
+	ElasticsearchUpgradeBlocked ElasticsearchUpgradePhase = "Blocked"
+	// ElasticsearchUpgradeSnapshot means a snapshot of the cluster is being taken before it is upgraded.
+	ElasticsearchUpgradeSnapshot ElasticsearchUpgradePhase = "Snapshot"
+	// ElasticsearchUpgradeUpgrading means the new version of Elasticsearch is being rolled out.
+	ElasticsearchUpgradeUpgrading ElasticsearchUpgradePhase = "Upgrading"
+	// ElasticsearchUpgradeSucceeded means all the nodes run the new version of Elasticsearch.
+	ElasticsearchUpgradeSucceeded ElasticsearchUpgradePhase = "Succeeded"
+)
+
+// SnapshotRestorePhase is a phase of the restore of a snapshot.
+// +kubebuilder:validation:Enum=InProgress;Succeeded;Failed
+type SnapshotRestorePhase string
+
+const (
+	// SnapshotRestoreInProgress means the indices of the snapshot are being restored.
+	SnapshotRestoreInProgress SnapshotRestorePhase = "InProgress"
+	// SnapshotRestoreSucceeded means all the indices of the snapshot have been restored.
+	SnapshotRestoreSucceeded SnapshotRestorePhase = "Succeeded"
+	// SnapshotRestoreFailed means the restore could not be started. The indices it closed have been reopened.
+	SnapshotRestoreFailed SnapshotRestorePhase = "Failed"
+)
+
+// ElasticsearchSnapshots configures a snapshot repository, the schedule of the snapshots, and restores.
+type ElasticsearchSnapshots struct {
+	// Repository is where the snapshots are stored.
+	Repository SnapshotRepository `json:"repository"`
+
+	// Schedule is when snapshots are taken, in the Elasticsearch cron syntax.
+	// Default: 0 30 1 * * ? (every day at 01:30 UTC)
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Indices are the patterns of the indices included in the snapshots.
+	// Default: tigera_secure_ee_*
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// ExpireAfter is how long snapshots are kept, as an Elasticsearch time unit such as 30d. The most recent
+	// snapshots are always kept.
+	// Default: 30d
+	// +kubebuilder:validation:Pattern=`^[0-9]+(d|h|m|s)$`
+	// +optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+
+	// Restore restores indices from a snapshot. The operator restores each snapshot once, so set a different snapshot
+	// to restore again. A restore that failed is tried again once it has been removed and set again.
+	// +optional
+	Restore *SnapshotRestore `json:"restore,omitempty"`
+}
+
+// SnapshotRepository is a location for Elasticsearch snapshots. Exactly one of its fields must be set.
+type SnapshotRepository struct {
+	// Filesystem stores the snapshots on a shared volume mounted by all of the Elasticsearch nodes.
+	// +optional
+	Filesystem *FilesystemSnapshotRepository `json:"filesystem,omitempty"`
+
+	// S3 stores the snapshots in an S3 compatible object store, such as AWS S3 or MinIO. It requires an Elasticsearch
+	// image with the repository-s3 plugin.
+	// +optional
+	S3 *S3SnapshotRepository `json:"s3,omitempty"`
+}
+
+// FilesystemSnapshotRepository stores snapshots on a shared volume.
+type FilesystemSnapshotRepository struct {
+	// PersistentVolumeClaimName is the name of a ReadWriteMany PersistentVolumeClaim in the tigera-elasticsearch
+	// namespace. It is mounted by all of the Elasticsearch nodes.
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+}
+
+// S3SnapshotRepository stores snapshots in an S3 bucket.
+type S3SnapshotRepository struct {
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// BasePath is the path within the bucket for the snapshots.
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+
+	// Endpoint is the URL of the S3 service, such as http://minio.minio.svc:9000. The scheme sets the protocol.
+	// Default: the AWS S3 endpoint.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// PathStyleAccess configures path style access, which most S3 compatible services other than AWS S3 require.
+	// +optional
+	PathStyleAccess bool `json:"pathStyleAccess,omitempty"`
+
+	// CredentialsSecretName is the name of a Secret in the tigera-operator namespace with the access-key and
+	// secret-key to use for the bucket.
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// SnapshotRestore restores indices from a snapshot.
+type SnapshotRestore struct {
+	// Snapshot is the name of the snapshot to restore.
+	Snapshot string `json:"snapshot"`
+
+	// Indices are the patterns of the indices to restore. Existing indices that match are closed and replaced.
+	// Default: all of the indices in the snapshot.
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+}
+
+// ElasticsearchSnapshotStatus reports the most recent Elasticsearch snapshots and restore.
+type ElasticsearchSnapshotStatus struct {
+	// LastSuccessfulSnapshot is the name of the most recent successful snapshot.
+	// +optional
+	LastSuccessfulSnapshot string `json:"lastSuccessfulSnapshot,omitempty"`
+
+	// LastSuccessfulSnapshotTime is when the most recent successful snapshot was taken.
+	// +optional
+	LastSuccessfulSnapshotTime *metav1.Time `json:"lastSuccessfulSnapshotTime,omitempty"`
+
+	// LastFailedSnapshot is the name of the most recent failed snapshot.
+	// +optional
+	LastFailedSnapshot string `json:"lastFailedSnapshot,omitempty"`
+
+	// LastFailedSnapshotTime is when the most recent failed snapshot was attempted.
+	// +optional
+	LastFailedSnapshotTime *metav1.Time `json:"lastFailedSnapshotTime,omitempty"`
+
+	// LastFailure describes why the most recent failed snapshot failed.
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// RestoredSnapshot is the name of the most recent snapshot the operator restored, or started to restore.
+	// +optional
+	RestoredSnapshot string `json:"restoredSnapshot,omitempty"`
+
+	// RestoreTime is when the operator started to restore the RestoredSnapshot.
+	// +optional
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
+
+	// RestorePhase is the phase of the restore of the RestoredSnapshot.
+	// +optional
+	RestorePhase SnapshotRestorePhase `json:"restorePhase,omitempty"`
+
+	// RestoreMessage describes the progress of the restore of the RestoredSnapshot, or why it failed.
+	// +optional
+	RestoreMessage string `json:"restoreMessage,omitempty"`
 }
 
-// Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.
+// Nodes defines the configuration for the Elasticsearch cluster nodes. Unless their NodeSets set their roles, the nodes
+// are each of type master, data, and ingest.
 type Nodes struct {
 	// Count defines the number of nodes in the Elasticsearch cluster.
 	Count int64 `json:"count,omitempty"`
@@ -93,8 +263,52 @@ type NodeSet struct {
 	// Elasticsearch cluster awareness attributes for the Elasticsearch nodes. The list of SelectionAttributes are used
 	// to define Node Affinities and set the node awareness configuration in the running Elasticsearch instance.
 	SelectionAttributes []NodeSetSelectionAttribute `json:"selectionAttributes,omitempty"`
+
+	// Roles are the Elasticsearch roles of the nodes in the NodeSet. New indices are written to the Hot nodes, and the
+	// ILM policies move them to the Warm nodes when they are rolled over, to the Cold nodes as they age, and to the
+	// Frozen nodes in their frozen phase. The cluster needs Master and Hot nodes, either in NodeSets with those roles or
+	// in NodeSets without roles. The Frozen role can't be combined with other roles.
+	// Default: the nodes have every role.
+	// +optional
+	Roles []ElasticsearchNodeRole `json:"roles,omitempty"`
+
+	// Count is the number of nodes in the NodeSet. It is part of Nodes.Count, and the NodeSets without a count share
+	// the rest of Nodes.Count evenly.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Count int64 `json:"count,omitempty"`
+
+	// ResourceRequirements overrides Nodes.ResourceRequirements for the nodes in the NodeSet, for example to give the
+	// Warm and Cold nodes more storage and less CPU than the Hot nodes. In FIPS mode, the JVM heap size of the nodes is
+	// still based on Nodes.ResourceRequirements.
+	// +optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// StorageClassName overrides LogStorageSpec.StorageClassName for the nodes in the NodeSet.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
 }
 
+// ElasticsearchNodeRole is a role of the Elasticsearch nodes in a NodeSet.
+// +kubebuilder:validation:Enum=Master;Ingest;Hot;Warm;Cold;Frozen
+type ElasticsearchNodeRole string
+
+const (
+	// ElasticsearchNodeRoleMaster nodes are eligible to be elected as the master of the cluster.
+	ElasticsearchNodeRoleMaster ElasticsearchNodeRole = "Master"
+	// ElasticsearchNodeRoleIngest nodes run ingest pipelines.
+	ElasticsearchNodeRoleIngest ElasticsearchNodeRole = "Ingest"
+	// ElasticsearchNodeRoleHot nodes hold the indices that are being written to.
+	ElasticsearchNodeRoleHot ElasticsearchNodeRole = "Hot"
+	// ElasticsearchNodeRoleWarm nodes hold the indices that have been rolled over.
+	ElasticsearchNodeRoleWarm ElasticsearchNodeRole = "Warm"
+	// ElasticsearchNodeRoleCold nodes hold old indices that are rarely searched.
+	ElasticsearchNodeRoleCold ElasticsearchNodeRole = "Cold"
+	// ElasticsearchNodeRoleFrozen nodes hold the searchable snapshots of the indices in the frozen phase, and use most
+	// of their disk to cache them.
+	ElasticsearchNodeRoleFrozen ElasticsearchNodeRole = "Frozen"
+)
+
 // NodeSetSelectionAttribute defines a K8s node "attribute" the Elasticsearch nodes should be aware of. The "Name" and "Value"
 // are used together to set the "awareness" attributes in Elasticsearch, while the "NodeLabel" and "Value" are used together
 // to define Node Affinity for the Pods created for the Elasticsearch nodes.
@@ -159,6 +373,82 @@ type Retention struct {
 	BGPLogs *int32 `json:"bgpLogs"`
 }
 
+// IndexLifecyclePolicy configures the ILM policy of the indices for one type of data. Ages are Elasticsearch time
+// units, such as 12h or 30d. The warm, cold and frozen phase ages are measured from rollover, must be in increasing
+// order and must be less than the retention.
+type IndexLifecyclePolicy struct {
+	// DataType is the type of data the policy applies to. The AuditLogs policy applies to both the Calico Enterprise
+	// and the Kubernetes audit logs. The operator only creates policies for WAFLogs and RuntimeReports when they are
+	// configured here.
+	// +kubebuilder:validation:Enum=Alerts;AuditLogs;BGPLogs;ComplianceBenchmarks;ComplianceReports;ComplianceSnapshots;DNSLogs;FlowLogs;L7Logs;RuntimeReports;WAFLogs
+	DataType DataType `json:"dataType"`
+
+	// DiskShare is the percentage of the Elasticsearch disk used for the data type, which sets the rollover size when
+	// RolloverSize is not set. The data types without a DiskShare divide the rest of the disk in the same proportions
+	// as they do by default, so the disk shares must add up to less than 100 unless every data type has one.
+	// Default: 59.5 for flow logs, 3.5 each for DNS, BGP and L7 logs, and 1.67 for each of the other indices, of
+	// which the audit logs have two.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	DiskShare *int32 `json:"diskShare,omitempty"`
+
+	// RolloverSize is the size at which an index is rolled over.
+	// Default: a quarter of the data type's share of the disk, up to 30Gi.
+	// +optional
+	RolloverSize *resource.Quantity `json:"rolloverSize,omitempty"`
+
+	// RolloverAge is the age at which an index is rolled over.
+	// Default: a quarter of the retention, and at least 1d.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(d|h|m|s)$`
+	// +optional
+	RolloverAge string `json:"rolloverAge,omitempty"`
+
+	// Retention is the number of days the data is kept. It overrides the data type's setting in spec.retention.
+	// Default: the setting in spec.retention, 1 for L7 logs, 91 for alerts and compliance benchmarks, and 8 for
+	// WAF logs and runtime reports.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retention *int32 `json:"retention,omitempty"`
+
+	// WarmAge is the age at which an index moves to the warm phase, where it becomes read-only and moves to the Warm
+	// nodes, if there are any.
+	// Default: immediately after rollover.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(d|h|m|s)$`
+	// +optional
+	WarmAge string `json:"warmAge,omitempty"`
+
+	// WarmShards is the number of primary shards an index is shrunk to when it moves to the warm phase. By default,
+	// indices are not shrunk. It is the only shard count in the policy: ILM can only change the number of primary
+	// shards by shrinking an index, which the operator does in the warm phase, and the number of replicas of every
+	// index is set by spec.indices.replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WarmShards *int32 `json:"warmShards,omitempty"`
+
+	// ColdAge is the age at which an index moves to the cold phase, where it has the lowest priority for recovery and
+	// moves to the Cold nodes, if there are any. By default, indices only have a cold phase when there are Cold nodes
+	// and the policy sets neither warmAge nor coldAge, in which case it starts after half of the retention period unless
+	// the frozen phase starts first.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(d|h|m|s)$`
+	// +optional
+	ColdAge string `json:"coldAge,omitempty"`
+
+	// FrozenAge is the age at which an index moves to the frozen phase, where it is converted to a searchable snapshot
+	// in the SnapshotRepository that is mounted on the Frozen nodes. It requires spec.snapshots, a NodeSet with the
+	// Frozen role and an Enterprise Elasticsearch license. By default, indices don't have a frozen phase.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(d|h|m|s)$`
+	// +optional
+	FrozenAge string `json:"frozenAge,omitempty"`
+
+	// SnapshotRepository is the Elasticsearch snapshot repository for the searchable snapshots of the frozen phase.
+	// The operator only manages the tigera-snapshots repository, which is configured by spec.snapshots, so it must be
+	// tigera-snapshots.
+	// Default: tigera-snapshots
+	// +optional
+	SnapshotRepository string `json:"snapshotRepository,omitempty"`
+}
+
 // LogStorageComponentName CRD enum
 type LogStorageComponentName string
 
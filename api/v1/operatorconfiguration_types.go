@@ -0,0 +1,164 @@
+/*
+Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorControllerName is the name of one of the operator's controllers.
+// +kubebuilder:validation:Enum=Installation;APIServer;LogStorage;IntrusionDetection;LogCollector;Compliance;ApplicationLayer;Monitor;Manager;ManagementClusterConnection;AmazonCloudIntegration;Authentication;Tiers;PolicyRecommendation;EgressGateway;Secrets;Windows;CertificateSigningRequest
+type OperatorControllerName string
+
+const (
+	OperatorControllerInstallation                OperatorControllerName = "Installation"
+	OperatorControllerAPIServer                   OperatorControllerName = "APIServer"
+	OperatorControllerLogStorage                  OperatorControllerName = "LogStorage"
+	OperatorControllerIntrusionDetection          OperatorControllerName = "IntrusionDetection"
+	OperatorControllerLogCollector                OperatorControllerName = "LogCollector"
+	OperatorControllerCompliance                  OperatorControllerName = "Compliance"
+	OperatorControllerApplicationLayer            OperatorControllerName = "ApplicationLayer"
+	OperatorControllerMonitor                     OperatorControllerName = "Monitor"
+	OperatorControllerManager                     OperatorControllerName = "Manager"
+	OperatorControllerManagementClusterConnection OperatorControllerName = "ManagementClusterConnection"
+	OperatorControllerAmazonCloudIntegration      OperatorControllerName = "AmazonCloudIntegration"
+	OperatorControllerAuthentication              OperatorControllerName = "Authentication"
+	OperatorControllerTiers                       OperatorControllerName = "Tiers"
+	OperatorControllerPolicyRecommendation        OperatorControllerName = "PolicyRecommendation"
+	OperatorControllerEgressGateway               OperatorControllerName = "EgressGateway"
+	OperatorControllerSecrets                     OperatorControllerName = "Secrets"
+	OperatorControllerWindows                     OperatorControllerName = "Windows"
+	OperatorControllerCertificateSigningRequest   OperatorControllerName = "CertificateSigningRequest"
+)
+
+// ServerSideApplyComponentName is the name of a component whose objects the operator can write with server-side apply.
+// +kubebuilder:validation:Enum=CSI
+type ServerSideApplyComponentName string
+
+const (
+	ServerSideApplyComponentCSI ServerSideApplyComponentName = "CSI"
+)
+
+// OperatorConfigurationSpec defines the desired state of OperatorConfiguration
+type OperatorConfigurationSpec struct {
+	// LogLevel is the verbosity of the operator's logs. Debug adds the operator's verbose messages, and Trace adds
+	// all of them. Changes take effect without restarting the operator.
+	// Default: Info
+	// +kubebuilder:validation:Enum=Error;Info;Debug;Trace
+	// +optional
+	LogLevel *LogLevel `json:"logLevel,omitempty"`
+
+	// LoggerLogLevels overrides LogLevel for individual loggers, such as controller_installation. Changes take effect
+	// without restarting the operator.
+	// +optional
+	LoggerLogLevels []LoggerLogLevel `json:"loggerLogLevels,omitempty"`
+
+	// MetricsHost is the address the operator serves Prometheus metrics on. If neither this nor MetricsPort is set,
+	// the METRICS_HOST and METRICS_PORT environment variables are used, and metrics are disabled if those are unset.
+	// +optional
+	MetricsHost string `json:"metricsHost,omitempty"`
+
+	// MetricsPort is the port the operator serves Prometheus metrics on.
+	// Default: 8484 when MetricsHost is set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	MetricsPort *int32 `json:"metricsPort,omitempty"`
+
+	// ManageCRDs configures the operator to install and update the Calico and Calico Enterprise CRDs. When not set,
+	// the --manage-crds flag is used.
+	// +optional
+	ManageCRDs *bool `json:"manageCRDs,omitempty"`
+
+	// LeaderElection configures the operator to elect a leader among its replicas. When not set, the
+	// --enable-leader-election flag is used.
+	// +optional
+	LeaderElection *bool `json:"leaderElection,omitempty"`
+
+	// ExternalElasticsearch configures a management cluster to use an external Elasticsearch instead of installing
+	// one. When not set, the ELASTIC_EXTERNAL key of the operator-bootstrap-config ConfigMap is used.
+	// +optional
+	ExternalElasticsearch *bool `json:"externalElasticsearch,omitempty"`
+
+	// EnabledControllers is the list of controllers the operator runs. When not set, all of the controllers run.
+	// +optional
+	EnabledControllers []OperatorControllerName `json:"enabledControllers,omitempty"`
+
+	// MaxConcurrentReconciles is the number of tenants the controllers that reconcile each tenant's namespace on
+	// their own, without sharing a TigeraStatus between tenants, may reconcile at once in multi-tenant mode. The other
+	// controllers always run one reconcile at a time.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	// +optional
+	MaxConcurrentReconciles *int32 `json:"maxConcurrentReconciles,omitempty"`
+
+	// ServerSideApplyComponents is the list of components whose objects the operator writes with server-side apply,
+	// instead of merging them with their current state and updating them. Fields that other controllers set on those
+	// objects are then left alone. Only the CSI component supports server-side apply. When not set, no component uses it.
+	// +optional
+	ServerSideApplyComponents []ServerSideApplyComponentName `json:"serverSideApplyComponents,omitempty"`
+}
+
+// LoggerLogLevel sets the verbosity of one of the operator's loggers.
+type LoggerLogLevel struct {
+	// Logger is the name of the logger, for example controller_installation. The level also applies to the loggers
+	// created from it, whose names start with this name followed by a ".". A name ending in "*" matches every logger
+	// that starts with the rest of the name, for example controller_logstorage*.
+	Logger string `json:"logger"`
+
+	// LogLevel is the verbosity of the logger. Debug adds the logger's V(1) and V(2) messages.
+	// +kubebuilder:validation:Enum=Error;Info;Debug;Trace
+	LogLevel LogLevel `json:"logLevel"`
+}
+
+// OperatorConfigurationStatus defines the observed state of OperatorConfiguration
+type OperatorConfigurationStatus struct {
+	// Conditions represents the latest observed set of conditions for the OperatorConfiguration. The Ready condition
+	// reports whether the configuration is valid and has been applied. The Degraded condition reports that the
+	// configuration is invalid, in which case the operator runs with the defaults.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfiguration configures the operator itself. Only the OperatorConfiguration named default is used.
+// Changes to the log level are applied immediately, while the operator restarts to apply any other change.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type OperatorConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigurationSpec   `json:"spec,omitempty"`
+	Status OperatorConfigurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigurationList contains a list of OperatorConfiguration
+type OperatorConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfiguration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfiguration{}, &OperatorConfigurationList{})
+}
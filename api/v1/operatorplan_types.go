@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlannedAction is the action the operator would take on an object.
+// One of: Create, Update, Delete
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type PlannedAction string
+
+const (
+	PlannedActionCreate PlannedAction = "Create"
+	PlannedActionUpdate PlannedAction = "Update"
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// OperatorPlanSpec defines the desired state of OperatorPlan
+type OperatorPlanSpec struct{}
+
+// OperatorPlanStatus defines the observed state of OperatorPlan
+type OperatorPlanStatus struct {
+	// Component is the name of the rendered component that this plan was computed for.
+	// +optional
+	Component string `json:"component,omitempty"`
+
+	// Changes is the list of changes the operator would make to the cluster for the component.
+	// An empty list means the component is up to date.
+	// +optional
+	Changes []PlannedChange `json:"changes,omitempty"`
+}
+
+// PlannedChange describes a single change the operator would make to an object.
+type PlannedChange struct {
+	// Action is the action the operator would take on the object.
+	Action PlannedAction `json:"action"`
+
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace of the object. Empty for cluster scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Patch is the JSON merge patch that would be applied to the object for an Update.
+	// Fields that are only defaulted by the API server are not included.
+	// +optional
+	Patch string `json:"patch,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorPlan records the changes the operator would make for a component while plan mode is enabled on
+// the Installation.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Component",type="string",JSONPath=".status.component",description="The component the plan was computed for."
+type OperatorPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorPlanSpec   `json:"spec,omitempty"`
+	Status OperatorPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorPlanList contains a list of OperatorPlan
+type OperatorPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorPlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorPlan{}, &OperatorPlanList{})
+}
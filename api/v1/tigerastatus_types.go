@@ -31,7 +31,7 @@ type TigeraStatusSpec struct{}
 // TigeraStatusStatus defines the observed state of TigeraStatus
 type TigeraStatusStatus struct {
 	// Conditions represents the latest observed set of conditions for this component. A component may be one or more of
-	// Available, Progressing, or Degraded.
+	// Available, Progressing, Degraded, or Paused.
 	Conditions []TigeraStatusCondition `json:"conditions"`
 }
 
@@ -77,12 +77,15 @@ const (
 
 	// Ready indicates that the component is healthy and ready.it is identical to Available and used in Status conditions for CRs.
 	ComponentReady StatusConditionType = "Ready"
+
+	// Paused means that reconciliation of the component has been paused by the user.
+	ComponentPaused StatusConditionType = "Paused"
 )
 
 // TigeraStatusCondition represents a condition attached to a particular component.
 // +k8s:deepcopy-gen=true
 type TigeraStatusCondition struct {
-	// The type of condition. May be Available, Progressing, or Degraded.
+	// The type of condition. May be Available, Progressing, Degraded, or Paused.
 	Type StatusConditionType `json:"type"`
 
 	// The status of the condition. May be True, False, or Unknown.
@@ -121,6 +124,7 @@ const (
 	ResourceNotReady          TigeraStatusReason = "ResourceNotReady"
 	PodFailure                TigeraStatusReason = "PodFailure"
 	CertificateError          TigeraStatusReason = "CertificateError"
+	CertificateExpiring       TigeraStatusReason = "CertificateExpiring"
 	InvalidConfigurationError TigeraStatusReason = "InvalidConfigurationError"
 	ResourceCreateError       TigeraStatusReason = "ResourceCreateError"
 	ResourceMigrationError    TigeraStatusReason = "ResourceMigrationError"
@@ -137,6 +141,7 @@ const (
 	UpgradeError              TigeraStatusReason = "UpgradeError"
 	Unknown                   TigeraStatusReason = "Unknown"
 	ImageSetError             TigeraStatusReason = "ImageSetError"
+	ReconciliationPaused      TigeraStatusReason = "ReconciliationPaused"
 )
 
 func init() {
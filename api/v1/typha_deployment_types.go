@@ -19,6 +19,7 @@ package v1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TyphaDeploymentContainer is a typha Deployment container.
@@ -145,6 +146,61 @@ type TyphaDeploymentSpec struct {
 	// +optional
 	// +patchStrategy=retainKeys
 	Strategy *TyphaDeploymentStrategy `json:"strategy,omitempty" patchStrategy:"retainKeys" protobuf:"bytes,4,opt,name=strategy"`
+
+	// Autoscaling configures how the operator scales the typha Deployment.
+	// If omitted, the number of replicas is derived from the number of nodes in the cluster.
+	// +optional
+	Autoscaling *TyphaAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// TyphaAutoscalingPolicy is the policy that is used to determine the number of typha replicas.
+// +kubebuilder:validation:Enum=NodeCount;ConnectionLoad
+type TyphaAutoscalingPolicy string
+
+const (
+	// TyphaAutoscalingPolicyNodeCount scales typha based on the number of schedulable nodes in the cluster.
+	TyphaAutoscalingPolicyNodeCount TyphaAutoscalingPolicy = "NodeCount"
+
+	// TyphaAutoscalingPolicyConnectionLoad scales typha based on the number of connections that the typha replicas
+	// report in their metrics. It requires typhaMetricsPort to be set on the Installation.
+	TyphaAutoscalingPolicyConnectionLoad TyphaAutoscalingPolicy = "ConnectionLoad"
+)
+
+// TyphaAutoscaling configures how the operator scales the typha Deployment.
+type TyphaAutoscaling struct {
+	// Policy is the policy that is used to determine the number of typha replicas.
+	// Default: NodeCount
+	// +optional
+	Policy TyphaAutoscalingPolicy `json:"policy,omitempty"`
+
+	// MinReplicas is the minimum number of typha replicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of typha replicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// NodesPerTypha is the number of nodes that a single typha replica serves when the NodeCount policy is used.
+	// Default: 200
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	NodesPerTypha *int32 `json:"nodesPerTypha,omitempty"`
+
+	// ConnectionsPerTypha is the number of connections that a single typha replica serves when the ConnectionLoad
+	// policy is used.
+	// Default: 200
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ConnectionsPerTypha *int32 `json:"connectionsPerTypha,omitempty"`
+
+	// ScaleDownDelay is how long fewer typha replicas must be needed before the typha Deployment is scaled down.
+	// Scaling up is never delayed.
+	// Default: 5m
+	// +optional
+	ScaleDownDelay *metav1.Duration `json:"scaleDownDelay,omitempty"`
 }
 
 // TyphaDeploymentStrategy describes how to replace existing pods with new ones.  Only RollingUpdate is supported
@@ -285,3 +341,11 @@ func (c *TyphaDeployment) GetDeploymentStrategy() *appsv1.DeploymentStrategy {
 	}
 	return nil
 }
+
+// GetAutoscaling returns the autoscaling configuration of the typha Deployment, or nil if it is not configured.
+func (c *TyphaDeployment) GetAutoscaling() *TyphaAutoscaling {
+	if c != nil && c.Spec != nil {
+		return c.Spec.Autoscaling
+	}
+	return nil
+}
@@ -363,6 +363,21 @@ func (in *AdditionalLogStoreSpec) DeepCopyInto(out *AdditionalLogStoreSpec) {
 		*out = new(SplunkStoreSpec)
 		**out = **in
 	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalLogStoreSpec.
@@ -1004,6 +1019,26 @@ func (in *CSINodeDriverDaemonSetSpec) DeepCopy() *CSINodeDriverDaemonSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRApproval) DeepCopyInto(out *CSRApproval) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRApproval.
+func (in *CSRApproval) DeepCopy() *CSRApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoKubeControllersDeployment) DeepCopyInto(out *CalicoKubeControllersDeployment) {
 	*out = *in
@@ -1674,6 +1709,21 @@ func (in *CalicoWindowsUpgradeDaemonSetSpec) DeepCopy() *CalicoWindowsUpgradeDae
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuer) DeepCopyInto(out *CertificateIssuer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuer.
+func (in *CertificateIssuer) DeepCopy() *CertificateIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateManagement) DeepCopyInto(out *CertificateManagement) {
 	*out = *in
@@ -1682,6 +1732,18 @@ func (in *CertificateManagement) DeepCopyInto(out *CertificateManagement) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(CertificateIssuer)
+		**out = **in
+	}
+	if in.CSRApprovals != nil {
+		in, out := &in.CSRApprovals, &out.CSRApprovals
+		*out = make([]CSRApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateManagement.
@@ -1694,6 +1756,36 @@ func (in *CertificateManagement) DeepCopy() *CertificateManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotation) DeepCopyInto(out *CertificateRotation) {
+	*out = *in
+	if in.RenewalPercentage != nil {
+		in, out := &in.RenewalPercentage, &out.RenewalPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CAOverlapDuration != nil {
+		in, out := &in.CAOverlapDuration, &out.CAOverlapDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ExpiryWarningDuration != nil {
+		in, out := &in.ExpiryWarningDuration, &out.ExpiryWarningDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotation.
+func (in *CertificateRotation) DeepCopy() *CertificateRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Compliance) DeepCopyInto(out *Compliance) {
 	*out = *in
@@ -1810,6 +1902,26 @@ func (in *ComponentResource) DeepCopy() *ComponentResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedObject) DeepCopyInto(out *DriftedObject) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedObject.
+func (in *DriftedObject) DeepCopy() *DriftedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EGWDeploymentContainer) DeepCopyInto(out *EGWDeploymentContainer) {
 	*out = *in
@@ -2155,6 +2267,59 @@ func (in *EksCloudwatchLogsSpec) DeepCopy() *EksCloudwatchLogsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshotStatus) DeepCopyInto(out *ElasticsearchSnapshotStatus) {
+	*out = *in
+	if in.LastSuccessfulSnapshotTime != nil {
+		in, out := &in.LastSuccessfulSnapshotTime, &out.LastSuccessfulSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedSnapshotTime != nil {
+		in, out := &in.LastFailedSnapshotTime, &out.LastFailedSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RestoreTime != nil {
+		in, out := &in.RestoreTime, &out.RestoreTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotStatus.
+func (in *ElasticsearchSnapshotStatus) DeepCopy() *ElasticsearchSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshots) DeepCopyInto(out *ElasticsearchSnapshots) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(SnapshotRestore)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshots.
+func (in *ElasticsearchSnapshots) DeepCopy() *ElasticsearchSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -2248,6 +2413,21 @@ func (in *ExternalPrometheus) DeepCopy() *ExternalPrometheus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSnapshotRepository) DeepCopyInto(out *FilesystemSnapshotRepository) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSnapshotRepository.
+func (in *FilesystemSnapshotRepository) DeepCopy() *FilesystemSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupSearch) DeepCopyInto(out *GroupSearch) {
 	*out = *in
@@ -2268,6 +2448,26 @@ func (in *GroupSearch) DeepCopy() *GroupSearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
 	*out = *in
@@ -2298,6 +2498,33 @@ func (in *HTTPProbe) DeepCopy() *HTTPProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPStoreSpec) DeepCopyInto(out *HTTPStoreSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogTypes != nil {
+		in, out := &in.LogTypes, &out.LogTypes
+		*out = make([]SyslogLogType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPStoreSpec.
+func (in *HTTPStoreSpec) DeepCopy() *HTTPStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ICMPProbe) DeepCopyInto(out *ICMPProbe) {
 	*out = *in
@@ -2383,6 +2610,21 @@ func (in *Image) DeepCopy() *Image {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverride) DeepCopyInto(out *ImageOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverride.
+func (in *ImageOverride) DeepCopy() *ImageOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSet) DeepCopyInto(out *ImageSet) {
 	*out = *in
@@ -2409,6 +2651,33 @@ func (in *ImageSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSetCanary) DeepCopyInto(out *ImageSetCanary) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PromoteAfter != nil {
+		in, out := &in.PromoteAfter, &out.PromoteAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetCanary.
+func (in *ImageSetCanary) DeepCopy() *ImageSetCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSetCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSetList) DeepCopyInto(out *ImageSetList) {
 	*out = *in
@@ -2461,6 +2730,21 @@ func (in *ImageSetSpec) DeepCopy() *ImageSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSetVerification) DeepCopyInto(out *ImageSetVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetVerification.
+func (in *ImageSetVerification) DeepCopy() *ImageSetVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSetVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Index) DeepCopyInto(out *Index) {
 	*out = *in
@@ -2476,6 +2760,41 @@ func (in *Index) DeepCopy() *Index {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecyclePolicy) DeepCopyInto(out *IndexLifecyclePolicy) {
+	*out = *in
+	if in.DiskShare != nil {
+		in, out := &in.DiskShare, &out.DiskShare
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RolloverSize != nil {
+		in, out := &in.RolloverSize, &out.RolloverSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WarmShards != nil {
+		in, out := &in.WarmShards, &out.WarmShards
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecyclePolicy.
+func (in *IndexLifecyclePolicy) DeepCopy() *IndexLifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Indices) DeepCopyInto(out *Indices) {
 	*out = *in
@@ -2523,6 +2842,21 @@ func (in *Installation) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationFieldSource) DeepCopyInto(out *InstallationFieldSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationFieldSource.
+func (in *InstallationFieldSource) DeepCopy() *InstallationFieldSource {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationFieldSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallationList) DeepCopyInto(out *InstallationList) {
 	*out = *in
@@ -2555,9 +2889,51 @@ func (in *InstallationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationReport) DeepCopyInto(out *InstallationReport) {
+	*out = *in
+	if in.FieldSources != nil {
+		in, out := &in.FieldSources, &out.FieldSources
+		*out = make([]InstallationFieldSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedObjects != nil {
+		in, out := &in.DriftedObjects, &out.DriftedObjects
+		*out = make([]DriftedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationReport.
+func (in *InstallationReport) DeepCopy() *InstallationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 	*out = *in
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make([]ImageOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageSetVerification != nil {
+		in, out := &in.ImageSetVerification, &out.ImageSetVerification
+		*out = new(ImageSetVerification)
+		**out = **in
+	}
+	if in.ImageSetCanary != nil {
+		in, out := &in.ImageSetCanary, &out.ImageSetCanary
+		*out = new(ImageSetCanary)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -2608,6 +2984,11 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		**out = **in
 	}
 	in.NodeUpdateStrategy.DeepCopyInto(&out.NodeUpdateStrategy)
+	if in.NodeUpgrade != nil {
+		in, out := &in.NodeUpgrade, &out.NodeUpgrade
+		*out = new(NodeUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ComponentResources != nil {
 		in, out := &in.ComponentResources, &out.ComponentResources
 		*out = make([]ComponentResource, len(*in))
@@ -2620,6 +3001,11 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(CertificateManagement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NonPrivileged != nil {
 		in, out := &in.NonPrivileged, &out.NonPrivileged
 		*out = new(NonPrivilegedType)
@@ -2695,6 +3081,16 @@ func (in *InstallationStatus) DeepCopyInto(out *InstallationStatus) {
 		*out = new(InstallationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeUpgrade != nil {
+		in, out := &in.NodeUpgrade, &out.NodeUpgrade
+		*out = new(NodeUpgradeStatus)
+		**out = **in
+	}
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(InstallationReport)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -2839,9 +3235,34 @@ func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LinseedDeployment) DeepCopyInto(out *LinseedDeployment) {
+func (in *KafkaStoreSpec) DeepCopyInto(out *KafkaStoreSpec) {
 	*out = *in
-	if in.Spec != nil {
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogTypes != nil {
+		in, out := &in.LogTypes, &out.LogTypes
+		*out = make([]SyslogLogType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaStoreSpec.
+func (in *KafkaStoreSpec) DeepCopy() *KafkaStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinseedDeployment) DeepCopyInto(out *LinseedDeployment) {
+	*out = *in
+	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
 		*out = new(LinseedDeploymentSpec)
 		(*in).DeepCopyInto(*out)
@@ -3205,6 +3626,18 @@ func (in *LogStorageSpec) DeepCopyInto(out *LogStorageSpec) {
 		*out = new(Retention)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IndexLifecyclePolicies != nil {
+		in, out := &in.IndexLifecyclePolicies, &out.IndexLifecyclePolicies
+		*out = make([]IndexLifecyclePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(ElasticsearchSnapshots)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DataNodeSelector != nil {
 		in, out := &in.DataNodeSelector, &out.DataNodeSelector
 		*out = make(map[string]string, len(*in))
@@ -3241,6 +3674,11 @@ func (in *LogStorageStatus) DeepCopyInto(out *LogStorageStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(ElasticsearchSnapshotStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageStatus.
@@ -3902,6 +4340,16 @@ func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 		*out = make([]NodeSetSelectionAttribute, len(*in))
 		copy(*out, *in)
 	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchNodeRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
@@ -3929,6 +4377,46 @@ func (in *NodeSetSelectionAttribute) DeepCopy() *NodeSetSelectionAttribute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgrade) DeepCopyInto(out *NodeUpgrade) {
+	*out = *in
+	if in.BatchPercent != nil {
+		in, out := &in.BatchPercent, &out.BatchPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgrade.
+func (in *NodeUpgrade) DeepCopy() *NodeUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeStatus) DeepCopyInto(out *NodeUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeStatus.
+func (in *NodeUpgradeStatus) DeepCopy() *NodeUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nodes) DeepCopyInto(out *Nodes) {
 	*out = *in
@@ -3956,6 +4444,283 @@ func (in *Nodes) DeepCopy() *Nodes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPStoreSpec) DeepCopyInto(out *OTLPStoreSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogTypes != nil {
+		in, out := &in.LogTypes, &out.LogTypes
+		*out = make([]SyslogLogType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPStoreSpec.
+func (in *OTLPStoreSpec) DeepCopy() *OTLPStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfiguration) DeepCopyInto(out *OperatorConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfiguration.
+func (in *OperatorConfiguration) DeepCopy() *OperatorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigurationList) DeepCopyInto(out *OperatorConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigurationList.
+func (in *OperatorConfigurationList) DeepCopy() *OperatorConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigurationSpec) DeepCopyInto(out *OperatorConfigurationSpec) {
+	*out = *in
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(LogLevel)
+		**out = **in
+	}
+	if in.LoggerLogLevels != nil {
+		in, out := &in.LoggerLogLevels, &out.LoggerLogLevels
+		*out = make([]LoggerLogLevel, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetricsPort != nil {
+		in, out := &in.MetricsPort, &out.MetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ManageCRDs != nil {
+		in, out := &in.ManageCRDs, &out.ManageCRDs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LeaderElection != nil {
+		in, out := &in.LeaderElection, &out.LeaderElection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExternalElasticsearch != nil {
+		in, out := &in.ExternalElasticsearch, &out.ExternalElasticsearch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnabledControllers != nil {
+		in, out := &in.EnabledControllers, &out.EnabledControllers
+		*out = make([]OperatorControllerName, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConcurrentReconciles != nil {
+		in, out := &in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServerSideApplyComponents != nil {
+		in, out := &in.ServerSideApplyComponents, &out.ServerSideApplyComponents
+		*out = make([]ServerSideApplyComponentName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigurationSpec.
+func (in *OperatorConfigurationSpec) DeepCopy() *OperatorConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigurationStatus) DeepCopyInto(out *OperatorConfigurationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigurationStatus.
+func (in *OperatorConfigurationStatus) DeepCopy() *OperatorConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorPlan) DeepCopyInto(out *OperatorPlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorPlan.
+func (in *OperatorPlan) DeepCopy() *OperatorPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorPlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorPlanList) DeepCopyInto(out *OperatorPlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorPlanList.
+func (in *OperatorPlanList) DeepCopy() *OperatorPlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorPlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorPlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorPlanSpec) DeepCopyInto(out *OperatorPlanSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorPlanSpec.
+func (in *OperatorPlanSpec) DeepCopy() *OperatorPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorPlanStatus) DeepCopyInto(out *OperatorPlanStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorPlanStatus.
+func (in *OperatorPlanStatus) DeepCopy() *OperatorPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRecommendation) DeepCopyInto(out *PolicyRecommendation) {
 	*out = *in
@@ -4224,6 +4989,21 @@ func (in *Retention) DeepCopy() *Retention {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3SnapshotRepository) DeepCopyInto(out *S3SnapshotRepository) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3SnapshotRepository.
+func (in *S3SnapshotRepository) DeepCopy() *S3SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(S3SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3StoreSpec) DeepCopyInto(out *S3StoreSpec) {
 	*out = *in
@@ -4268,6 +5048,51 @@ func (in *ServiceMonitor) DeepCopy() *ServiceMonitor {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = new(FilesystemSnapshotRepository)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3SnapshotRepository)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRestore) DeepCopyInto(out *SnapshotRestore) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRestore.
+func (in *SnapshotRestore) DeepCopy() *SnapshotRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SplunkStoreSpec) DeepCopyInto(out *SplunkStoreSpec) {
 	*out = *in
@@ -4594,6 +5419,46 @@ func (in *TyphaAffinity) DeepCopy() *TyphaAffinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TyphaAutoscaling) DeepCopyInto(out *TyphaAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodesPerTypha != nil {
+		in, out := &in.NodesPerTypha, &out.NodesPerTypha
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnectionsPerTypha != nil {
+		in, out := &in.ConnectionsPerTypha, &out.ConnectionsPerTypha
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownDelay != nil {
+		in, out := &in.ScaleDownDelay, &out.ScaleDownDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TyphaAutoscaling.
+func (in *TyphaAutoscaling) DeepCopy() *TyphaAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(TyphaAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TyphaDeployment) DeepCopyInto(out *TyphaDeployment) {
 	*out = *in
@@ -4762,6 +5627,11 @@ func (in *TyphaDeploymentSpec) DeepCopyInto(out *TyphaDeploymentSpec) {
 		*out = new(TyphaDeploymentStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(TyphaAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TyphaDeploymentSpec.
@@ -19,136 +19,130 @@ package controllers
 import (
 	"fmt"
 
+	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/controller/options"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// reconciler is implemented by each of the operator's controllers.
+type reconciler interface {
+	SetupWithManager(mgr ctrl.Manager, opts options.AddOptions) error
+}
+
 func AddToManager(mgr ctrl.Manager, options options.AddOptions) error {
-	if err := (&InstallationReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Installation"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Installation", err)
-	}
-	if err := (&APIServerReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("APIServer"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "APIServer", err)
-	}
-	if err := (&LogStorageReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("LogStorage"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "LogStorage", err)
-	}
-	if err := (&IntrusionDetectionReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("IntrusionDetection"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "IntrusionDetection", err)
-	}
-	if err := (&LogCollectorReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("LogCollector"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "LogCollector", err)
-	}
-	if err := (&ComplianceReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Compliance"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Compliance", err)
-	}
-	if err := (&ApplicationLayerReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ApplicationLayer"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "ApplicationLayer", err)
-	}
-	if err := (&MonitorReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Monitor"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Monitor", err)
-	}
-	if err := (&ManagerReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Manager"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Manager", err)
-	}
-	if err := (&ManagementClusterConnectionReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ManagementClusterConnection"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "ManagementClusterConnection", err)
-	}
-	if err := (&AmazonCloudIntegrationReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AmazonCloudIntegration"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "AmazonCloudIntegration", err)
-	}
-	if err := (&AuthenticationReconciler{
+	// The OperatorConfiguration controller always runs, so that the operator restarts when the enabled controllers
+	// change.
+	if err := (&OperatorConfigurationReconciler{
 		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Authentication"),
+		Log:    ctrl.Log.WithName("controllers").WithName("OperatorConfiguration"),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Authentication", err)
+		return fmt.Errorf("failed to create controller %s: %v", "OperatorConfiguration", err)
 	}
-	if err := (&TiersReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Tiers"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Tiers", err)
-	}
-	if err := (&PolicyRecommendationReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("PolicyRecommendation"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "PolicyRecommendation", err)
-	}
-	if err := (&EgressGatewayReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("EgressGateway"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "EgressGateway", err)
-	}
-	if err := (&SecretsReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Secrets"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "Secrets", err)
-	}
-	if err := (&WindowsReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Windows"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller Windows: %v", err)
-	}
-	if err := (&CSRReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("CertificateSigningRequest"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, options); err != nil {
-		return fmt.Errorf("failed to create controller %s: %v", "CSR", err)
+
+	controllers := []struct {
+		name       operatorv1.OperatorControllerName
+		reconciler reconciler
+	}{
+		{operatorv1.OperatorControllerInstallation, &InstallationReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Installation"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerAPIServer, &APIServerReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("APIServer"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerLogStorage, &LogStorageReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("LogStorage"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerIntrusionDetection, &IntrusionDetectionReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("IntrusionDetection"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerLogCollector, &LogCollectorReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("LogCollector"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerCompliance, &ComplianceReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Compliance"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerApplicationLayer, &ApplicationLayerReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("ApplicationLayer"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerMonitor, &MonitorReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Monitor"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerManager, &ManagerReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Manager"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerManagementClusterConnection, &ManagementClusterConnectionReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("ManagementClusterConnection"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerAmazonCloudIntegration, &AmazonCloudIntegrationReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("AmazonCloudIntegration"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerAuthentication, &AuthenticationReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Authentication"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerTiers, &TiersReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Tiers"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerPolicyRecommendation, &PolicyRecommendationReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("PolicyRecommendation"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerEgressGateway, &EgressGatewayReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("EgressGateway"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerSecrets, &SecretsReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Secrets"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerWindows, &WindowsReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Windows"),
+			Scheme: mgr.GetScheme(),
+		}},
+		{operatorv1.OperatorControllerCertificateSigningRequest, &CSRReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("CertificateSigningRequest"),
+			Scheme: mgr.GetScheme(),
+		}},
+	}
+	for _, c := range controllers {
+		if !options.ControllerEnabled(c.name) {
+			ctrl.Log.WithName("controllers").Info("Controller is disabled by the OperatorConfiguration", "controller", c.name)
+			continue
+		}
+		if err := c.reconciler.SetupWithManager(mgr, options); err != nil {
+			return fmt.Errorf("failed to create controller %s: %v", c.name, err)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 	return nil
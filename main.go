@@ -18,6 +18,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	goruntime "runtime"
@@ -36,15 +37,20 @@ import (
 	"github.com/tigera/operator/pkg/awssgsetup"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/operatorconfig"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/crds"
+	"github.com/tigera/operator/pkg/diagnostics"
 	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/offline"
 	"github.com/tigera/operator/pkg/render"
 	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 	"github.com/tigera/operator/pkg/render/logstorage"
+	"github.com/tigera/operator/pkg/webhooks"
 	"github.com/tigera/operator/version"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,6 +68,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/yaml"
+
+	uzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -71,10 +80,6 @@ var (
 	setupLog                 = ctrl.Log.WithName("setup")
 )
 
-// bootstrapConfigMapName is the name of the ConfigMap that contains cluster-wide
-// configuration for the operator loaded at startup.
-const bootstrapConfigMapName = "operator-bootstrap-config"
-
 func init() {
 	// +kubebuilder:scaffold:scheme
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
@@ -104,6 +109,11 @@ func main() {
 	var sgSetup bool
 	var manageCRDs bool
 	var preDelete bool
+	var renderFile string
+	var generateImageSet string
+	var imageSetVariant string
+	var collectDiagnostics string
+	var enableWebhooks bool
 
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", true,
 		"Enable leader election for controller manager. "+
@@ -124,12 +134,38 @@ func main() {
 		"Operator should manage the projectcalico.org and operator.tigera.io CRDs.")
 	flag.BoolVar(&preDelete, "pre-delete", false,
 		"Run helm pre-deletion hook logic, then exit.")
+	flag.StringVar(&renderFile, "render", "",
+		"Print the objects the operator would apply for the custom resources in the specified YAML file, then exit. "+
+			"The file must contain the default Installation and may contain an APIServer, ImageSets, Secrets and ConfigMaps; "+
+			"any other resource is rejected. Only the namespaces, certificates, Typha, calico-node, CSI, kube-controllers and "+
+			"API server components are rendered, with the default Felix health port (9099, or 9199 on OpenShift) and node "+
+			"reporter port (9081). No API server is required.")
+	flag.StringVar(&generateImageSet, "generate-imageset", "",
+		"Print an ImageSet with the digests of the images for this release, resolved from the specified directory in "+
+			"OCI image layout format or registry mirror (e.g. registry.example.com/mirror/), then exit.")
+	flag.StringVar(&imageSetVariant, "imageset-variant", string(operatorv1.Calico),
+		"The variant to generate an ImageSet for with --generate-imageset. Possible values: Calico, TigeraSecureEnterprise")
+	flag.StringVar(&collectDiagnostics, "collect-diagnostics", "",
+		"Write a tarball of the operator's custom resources, status, ConfigMaps, rendered objects and their differences "+
+			"from the cluster, and failing pods to the specified directory, then exit.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve validating and defaulting admission webhooks for the operator.tigera.io custom resources. "+
+			"The operator creates the serving certificate, Service and webhook configurations itself.")
 
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.WriteTo(os.Stdout), zap.UseFlagOptions(&opts)))
+	// The OperatorConfiguration controller sets the level of each logger at runtime, starting from the level in the
+	// flags, so the underlying logger lets every message through.
+	if lvl, ok := opts.Level.(uzap.AtomicLevel); ok {
+		operatorconfig.InitLogLevel(lvl.Level())
+	} else if opts.Development {
+		operatorconfig.InitLogLevel(zapcore.DebugLevel)
+	}
+	opts.Level = zapcore.Level(math.MinInt8)
+
+	ctrl.SetLogger(operatorconfig.NewLogger(zap.New(zap.WriteTo(os.Stdout), zap.UseFlagOptions(&opts))))
 
 	if showVersion {
 		// If the following line is updated then it might be necessary to update the release-verify target in the Makefile
@@ -145,7 +181,7 @@ func main() {
 			cmpnts = append(cmpnts, components.CommonImages...)
 
 			for _, x := range cmpnts {
-				ref, _ := components.GetReference(x, "", "", "", nil)
+				ref, _ := components.GetReference(x, "", "", "", nil, nil)
 				fmt.Println(ref)
 			}
 			os.Exit(0)
@@ -169,6 +205,22 @@ func main() {
 		os.Exit(0)
 	}
 
+	if renderFile != "" {
+		if err := renderOffline(renderFile); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if generateImageSet != "" {
+		if err := printImageSet(generateImageSet, operatorv1.ProductVariant(imageSetVariant)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if urlOnlyKubeconfig != "" {
 		if err := setKubernetesServiceEnv(urlOnlyKubeconfig); err != nil {
 			setupLog.Error(err, "Terminating")
@@ -198,6 +250,43 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Load the OperatorConfiguration, if there is one. Its settings take precedence over the operator's flags and
+	// environment.
+	operatorConfig, err := operatorconfig.Get(ctx, c)
+	if err != nil {
+		log.Error(err, "Failed to load OperatorConfiguration")
+		os.Exit(1)
+	}
+	if err = operatorconfig.Validate(operatorConfig); err != nil {
+		// Run with the defaults. The OperatorConfiguration controller reports that the configuration is invalid, as
+		// it does when the OperatorConfiguration becomes invalid while the operator is running.
+		log.Error(err, "Invalid OperatorConfiguration, using the default configuration")
+		operatorConfig = nil
+	}
+	operatorconfig.ApplyLogLevel(operatorConfig)
+	maxConcurrentReconciles := 1
+	if operatorConfig != nil {
+		if operatorConfig.Spec.LeaderElection != nil {
+			enableLeaderElection = *operatorConfig.Spec.LeaderElection
+		}
+		if operatorConfig.Spec.ManageCRDs != nil {
+			manageCRDs = *operatorConfig.Spec.ManageCRDs
+		}
+		if operatorConfig.Spec.MaxConcurrentReconciles != nil {
+			maxConcurrentReconciles = int(*operatorConfig.Spec.MaxConcurrentReconciles)
+		}
+	}
+
+	if collectDiagnostics != "" {
+		path, err := diagnostics.Collect(ctx, c, scheme, collectDiagnostics)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+		os.Exit(0)
+	}
+
 	policySelector, err := labels.Parse(fmt.Sprintf("projectcalico.org/tier == %s", networkpolicy.TigeraComponentTierName))
 	if err != nil {
 		log.Error(err, "")
@@ -243,8 +332,9 @@ func main() {
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr(),
-		Port:               9443,
+		MetricsBindAddress: metricsAddr(operatorConfig),
+		Port:               webhooks.Port,
+		CertDir:            webhooks.CertDir,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "operator-lock",
 		// We should test this again in the future to see if the problem with LicenseKey updates
@@ -407,7 +497,7 @@ func main() {
 	}
 
 	// Laod the operator's bootstrap configmap, if it exists.
-	bootConfig, err := clientset.CoreV1().ConfigMaps(common.OperatorNamespace()).Get(ctx, bootstrapConfigMapName, metav1.GetOptions{})
+	bootConfig, err := clientset.CoreV1().ConfigMaps(common.OperatorNamespace()).Get(ctx, common.BootstrapConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			log.Error(err, "Failed to load bootstrap configmap")
@@ -416,22 +506,32 @@ func main() {
 	}
 
 	// Start a watch on our bootstrap configmap so we can restart if it changes.
-	if err = utils.MonitorConfigMap(clientset, bootstrapConfigMapName, bootConfig.Data); err != nil {
+	if err = utils.MonitorConfigMap(clientset, common.BootstrapConfigMapName, bootConfig.Data); err != nil {
 		log.Error(err, "Failed to monitor bootstrap configmap")
 		os.Exit(1)
 	}
 
 	options := options.AddOptions{
-		DetectedProvider:    provider,
-		EnterpriseCRDExists: enterpriseCRDExists,
-		UsePSP:              usePSP,
-		AmazonCRDExists:     amazonCRDExists,
-		ClusterDomain:       clusterDomain,
-		KubernetesVersion:   kubernetesVersion,
-		ManageCRDs:          manageCRDs,
-		ShutdownContext:     ctx,
-		MultiTenant:         multiTenant,
-		ElasticExternal:     utils.UseExternalElastic(bootConfig),
+		DetectedProvider:        provider,
+		EnterpriseCRDExists:     enterpriseCRDExists,
+		UsePSP:                  usePSP,
+		AmazonCRDExists:         amazonCRDExists,
+		ClusterDomain:           clusterDomain,
+		KubernetesVersion:       kubernetesVersion,
+		ManageCRDs:              manageCRDs,
+		ShutdownContext:         ctx,
+		MultiTenant:             multiTenant,
+		ElasticExternal:         utils.UseExternalElastic(bootConfig),
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		OperatorConfiguration:   operatorConfig,
+		Restart:                 cancel,
+	}
+	if operatorConfig != nil {
+		options.EnabledControllers = operatorConfig.Spec.EnabledControllers
+		options.ServerSideApplyComponents = operatorConfig.Spec.ServerSideApplyComponents
+		if operatorConfig.Spec.ExternalElasticsearch != nil {
+			options.ElasticExternal = *operatorConfig.Spec.ExternalElasticsearch
+		}
 	}
 
 	// Before we start any controllers, make sure our options are valid.
@@ -446,11 +546,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		if err := webhooks.AddToManager(ctx, mgr, c, options); err != nil {
+			setupLog.Error(err, "unable to set up webhooks")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	setupLog.Info("manager stopped")
 }
 
 // setKubernetesServiceEnv configured the environment with the location of the Kubernetes API
@@ -479,10 +587,17 @@ func setKubernetesServiceEnv(kubeconfigFile string) error {
 }
 
 // metricsAddr processes user-specified metrics host and port and sets
-// default values accordingly.
-func metricsAddr() string {
+// default values accordingly. The OperatorConfiguration takes precedence over the environment.
+func metricsAddr(operatorConfig *operatorv1.OperatorConfiguration) string {
 	metricsHost := os.Getenv("METRICS_HOST")
 	metricsPort := os.Getenv("METRICS_PORT")
+	if operatorConfig != nil && (operatorConfig.Spec.MetricsHost != "" || operatorConfig.Spec.MetricsPort != nil) {
+		metricsHost = operatorConfig.Spec.MetricsHost
+		metricsPort = ""
+		if operatorConfig.Spec.MetricsPort != nil {
+			metricsPort = fmt.Sprintf("%d", *operatorConfig.Spec.MetricsPort)
+		}
+	}
 
 	// if neither are specified, disable metrics.
 	if metricsHost == "" && metricsPort == "" {
@@ -529,9 +644,58 @@ func showCRDs(variant operatorv1.ProductVariant, outputType string) error {
 	return nil
 }
 
+// renderOffline reads the custom resources from the given file and prints the objects the operator would apply for them.
+func renderOffline(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s := offline.NewScheme()
+	objs, err := offline.Decode(s, data)
+	if err != nil {
+		return fmt.Errorf("Failed to decode %s: %v", path, err)
+	}
+	result, err := offline.Render(context.Background(), s, objs, offline.Options{ClusterDomain: dns.DefaultClusterDomain})
+	if err != nil {
+		return fmt.Errorf("Failed to render %s: %v", path, err)
+	}
+	return offline.Write(os.Stdout, result)
+}
+
+func printImageSet(source string, variant operatorv1.ProductVariant) error {
+	if variant != operatorv1.Calico && variant != operatorv1.TigeraSecureEnterprise {
+		return fmt.Errorf("Invalid option for --imageset-variant flag %s", variant)
+	}
+	r, err := offline.NewDigestResolver(source)
+	if err != nil {
+		return fmt.Errorf("Failed to read %s: %v", source, err)
+	}
+	is, err := offline.GenerateImageSet(context.Background(), variant, r)
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(is)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
 func executePreDeleteHook(ctx context.Context, c client.Client) error {
 	defer log.Info("preDelete hook exiting")
 
+	// Remove the webhook configurations, if the webhooks were enabled, so that they don't outlive the operator.
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	validating.Name = webhooks.ConfigurationName
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	mutating.Name = webhooks.ConfigurationName
+	for _, o := range []client.Object{validating, mutating} {
+		if err := c.Delete(ctx, o); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	// Clean up any custom-resources first - this will trigger teardown of pods deloyed
 	// by the operator, and give the operator a chance to clean up gracefully.
 	installation := &operatorv1.Installation{}
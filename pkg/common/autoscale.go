@@ -30,10 +30,20 @@ package common
 //	...
 //	>3600             20
 func GetExpectedTyphaScale(nodes int) int {
-	var maxNodesPerTypha int = 200
+	return GetExpectedTyphaScaleForRatio(nodes, DefaultNodesPerTypha)
+}
+
+// DefaultNodesPerTypha is the number of nodes served by a single Typha, unless configured otherwise.
+const DefaultNodesPerTypha = 200
+
+// GetExpectedTyphaScaleForRatio is like GetExpectedTyphaScale, but for a custom number of nodes per Typha.
+func GetExpectedTyphaScaleForRatio(nodes, maxNodesPerTypha int) int {
+	if maxNodesPerTypha < 1 {
+		maxNodesPerTypha = DefaultNodesPerTypha
+	}
 
-	// This gives a count of how many 200s so we need 1+ this number to get at least
-	// 1 typha for every 200 nodes.
+	// This gives a count of how many multiples of maxNodesPerTypha there are, so we need 1+ this number to get
+	// at least 1 typha for every maxNodesPerTypha nodes.
 	typhas := (nodes / maxNodesPerTypha) + 1
 
 	// We add one more to ensure there is always 1 extra for high availability purposes.
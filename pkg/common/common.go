@@ -18,6 +18,7 @@ const (
 	CalicoNamespace               = "calico-system"
 	TyphaDeploymentName           = "calico-typha"
 	NodeDaemonSetName             = "calico-node"
+	NodeCanaryDaemonSetName       = "calico-node-canary"
 	KubeControllersDeploymentName = "calico-kube-controllers"
 	WindowsDaemonSetName          = "calico-node-windows"
 
@@ -41,4 +42,11 @@ const (
 	// references with any that already exist on the object rather than replace the owner references. Further
 	// the controller in the owner reference will not be set.
 	MultipleOwnersLabel = "operator.tigera.io/multipleOwners"
+	// BootstrapConfigMapName is the name of the ConfigMap in the operator namespace that contains cluster-wide
+	// configuration for the operator loaded at startup.
+	BootstrapConfigMapName = "operator-bootstrap-config"
+	// PausedAnnotation pauses reconciliation of a component when it is set to "true" on the component's CR. While a
+	// component is paused its controllers leave the objects they manage as they are, so that they can be edited by
+	// hand, and its TigeraStatus reports the Paused condition.
+	PausedAnnotation = "operator.tigera.io/paused"
 )
@@ -0,0 +1,24 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// IsPaused returns true if reconciliation of the component that owns the given CR has been paused by the user through
+// the PausedAnnotation, and returns false otherwise. A CR that is being deleted is never paused, so that its cleanup
+// runs and its finalizers are removed.
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetDeletionTimestamp() == nil && obj.GetAnnotations()[PausedAnnotation] == "true"
+}
@@ -28,7 +28,7 @@ var _ = Describe("test GetReference", func() {
 	Context("No registry override", func() {
 		DescribeTable("should render",
 			func(c component, registry, image string) {
-				Expect(GetReference(c, "", "", "", nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
+				Expect(GetReference(c, "", "", "", nil, nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, CalicoRegistry, "calico/node"),
 			Entry("a tigera image correctly", ComponentTigeraNode, TigeraRegistry, "tigera/cnx-node"),
@@ -42,7 +42,7 @@ var _ = Describe("test GetReference", func() {
 		DescribeTable("should render",
 			func(c component, registry, image string) {
 				ud := "UseDefault"
-				Expect(GetReference(c, ud, ud, "", nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
+				Expect(GetReference(c, ud, ud, "", nil, nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, CalicoRegistry, "calico/node"),
 			Entry("a tigera image correctly", ComponentTigeraNode, TigeraRegistry, "tigera/cnx-node"),
@@ -55,7 +55,7 @@ var _ = Describe("test GetReference", func() {
 	Context("registry override", func() {
 		DescribeTable("should render",
 			func(c component, image string) {
-				Expect(GetReference(c, "quay.io/", "", "", nil)).To(Equal(fmt.Sprintf("%s%s:%s", "quay.io/", image, c.Version)))
+				Expect(GetReference(c, "quay.io/", "", "", nil, nil)).To(Equal(fmt.Sprintf("%s%s:%s", "quay.io/", image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, "calico/node"),
 			Entry("a tigera image correctly", ComponentTigeraNode, "tigera/cnx-node"),
@@ -68,7 +68,7 @@ var _ = Describe("test GetReference", func() {
 	Context("image prefix override", func() {
 		DescribeTable("should render",
 			func(c component, image string) {
-				Expect(GetReference(c, "quay.io/", "", "pref", nil)).To(Equal(fmt.Sprintf("quay.io/%s:%s", image, c.Version)))
+				Expect(GetReference(c, "quay.io/", "", "pref", nil, nil)).To(Equal(fmt.Sprintf("quay.io/%s:%s", image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, "calico/prefnode"),
 			Entry("a tigera image correctly", ComponentTigeraNode, "tigera/prefcnx-node"),
@@ -81,7 +81,7 @@ var _ = Describe("test GetReference", func() {
 	Context("imagepath override", func() {
 		DescribeTable("should render",
 			func(c component, registry, image string) {
-				Expect(GetReference(c, "", "userpath", "", nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
+				Expect(GetReference(c, "", "userpath", "", nil, nil)).To(Equal(fmt.Sprintf("%s%s:%s", registry, image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, CalicoRegistry, "userpath/node"),
 			Entry("a tigera image correctly", ComponentTigeraNode, TigeraRegistry, "userpath/cnx-node"),
@@ -93,7 +93,7 @@ var _ = Describe("test GetReference", func() {
 	Context("registry and imagepath override", func() {
 		DescribeTable("should render",
 			func(c component, image string) {
-				Expect(GetReference(c, "quay.io/extra/", "userpath", "", nil)).To(Equal(fmt.Sprintf("quay.io/extra/%s:%s", image, c.Version)))
+				Expect(GetReference(c, "quay.io/extra/", "userpath", "", nil, nil)).To(Equal(fmt.Sprintf("quay.io/extra/%s:%s", image, c.Version)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, "userpath/node"),
 			Entry("a tigera image correctly", ComponentTigeraNode, "userpath/cnx-node"),
@@ -116,7 +116,7 @@ var _ = Describe("test GetReference", func() {
 						},
 					},
 				}
-				Expect(GetReference(c, "quay.io/extra/", "userpath", "", is)).To(Equal(fmt.Sprintf("quay.io/extra/%s%s", image, hash)))
+				Expect(GetReference(c, "quay.io/extra/", "userpath", "", nil, is)).To(Equal(fmt.Sprintf("quay.io/extra/%s%s", image, hash)))
 			},
 			Entry("a calico image correctly", ComponentCalicoNode, "userpath/node", "@sha256:caliconodehash"),
 			Entry("a tigera image correctly", ComponentTigeraNode, "userpath/cnx-node", "@sha256:tigeracnxnodehash"),
@@ -125,4 +125,36 @@ var _ = Describe("test GetReference", func() {
 			Entry("a CSR init image correctly", ComponentCSRInitContainer, "userpath/key-cert-provisioner", "@sha256:tigerakeycertprovisionerhash"),
 		)
 	})
+	Context("with image overrides", func() {
+		overrides := []op.ImageOverride{
+			{Image: "calico/node", Mirror: "mirror.example.com/patched/"},
+			{Prefix: TigeraRegistry, Mirror: "tigera-mirror.example.com/"},
+			{Prefix: TigeraRegistry + "tigera/", Mirror: "never-used.example.com/"},
+		}
+
+		It("should override a single image", func() {
+			Expect(GetReference(ComponentCalicoNode, "", "", "", overrides, nil)).To(Equal(
+				fmt.Sprintf("mirror.example.com/patched/calico/node:%s", ComponentCalicoNode.Version)))
+			Expect(GetReference(ComponentCalicoCNI, "", "", "", overrides, nil)).To(Equal(
+				fmt.Sprintf("%s%s:%s", CalicoRegistry, ComponentCalicoCNI.Image, ComponentCalicoCNI.Version)))
+		})
+
+		It("should override images by prefix using the first matching override", func() {
+			Expect(GetReference(ComponentElasticsearchOperator, "", "", "", overrides, nil)).To(Equal(
+				fmt.Sprintf("tigera-mirror.example.com/%s:%s", ComponentElasticsearchOperator.Image, ComponentElasticsearchOperator.Version)))
+		})
+
+		It("should match prefixes after the registry and image path have been applied", func() {
+			Expect(GetReference(ComponentTigeraNode, "", "", "", overrides, nil)).To(Equal(
+				fmt.Sprintf("tigera-mirror.example.com/%s:%s", ComponentTigeraNode.Image, ComponentTigeraNode.Version)))
+			pathOverrides := []op.ImageOverride{{Prefix: "quay.io/extra/userpath/", Mirror: "mirror.example.com/"}}
+			Expect(GetReference(ComponentTigeraNode, "quay.io/extra/", "userpath", "", pathOverrides, nil)).To(Equal(
+				fmt.Sprintf("mirror.example.com/cnx-node:%s", ComponentTigeraNode.Version)))
+		})
+
+		It("should use ImageSet digests for overridden images", func() {
+			is := &op.ImageSet{Spec: op.ImageSetSpec{Images: []op.Image{{Image: "calico/node", Digest: "sha256:caliconodehash"}}}}
+			Expect(GetReference(ComponentCalicoNode, "", "", "", overrides, is)).To(Equal("mirror.example.com/patched/calico/node@sha256:caliconodehash"))
+		})
+	})
 })
@@ -34,7 +34,7 @@ type component struct {
 const UseDefault = "UseDefault"
 
 // GetReference returns the fully qualified image to use, including registry and version.
-func GetReference(c component, registry, imagePath, imagePrefix string, is *operator.ImageSet) (string, error) {
+func GetReference(c component, registry, imagePath, imagePrefix string, overrides []operator.ImageOverride, is *operator.ImageSet) (string, error) {
 	// If a user did not supply a registry, use the default registry
 	// based on component
 	if registry == "" || registry == UseDefault {
@@ -81,19 +81,36 @@ func GetReference(c component, registry, imagePath, imagePrefix string, is *oper
 		image = ReplaceImagePath(image, imagePath)
 	}
 
+	ref := applyOverrides(c, registry+image, image, overrides)
+
 	if is == nil {
-		return fmt.Sprintf("%s%s:%s", registry, image, c.Version), nil
+		return fmt.Sprintf("%s:%s", ref, c.Version), nil
 	}
 
 	for _, img := range is.Spec.Images {
 		if img.Image == c.Image {
-			return fmt.Sprintf("%s%s@%s", registry, image, img.Digest), nil
+			return fmt.Sprintf("%s@%s", ref, img.Digest), nil
 		}
 	}
 
 	return "", fmt.Errorf("ImageSet did not contain image %s", c.Image)
 }
 
+// applyOverrides returns the image reference, without tag or digest, after applying the first override that matches
+// the component. An Image override matches the component's image name and replaces the registry, and a Prefix
+// override replaces the matching prefix of the reference.
+func applyOverrides(c component, ref, image string, overrides []operator.ImageOverride) string {
+	for _, o := range overrides {
+		if o.Image != "" && o.Image == c.Image {
+			return o.Mirror + image
+		}
+		if o.Prefix != "" && strings.HasPrefix(ref, o.Prefix) {
+			return o.Mirror + strings.TrimPrefix(ref, o.Prefix)
+		}
+	}
+	return ref
+}
+
 func ReplaceImagePath(image, imagePath string) string {
 	subs := strings.SplitAfterN(image, "/", 2)
 	if len(subs) == 2 {
@@ -40,6 +40,8 @@ import (
 	"github.com/tigera/operator/pkg/render"
 )
 
+const controllerName = "amazoncloudintegration-controller"
+
 const ResourceName = "amazon-cloud-integration"
 
 var log = logf.Log.WithName("controller_amazoncloudintegration")
@@ -60,7 +62,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Recon
 		client:        mgr.GetClient(),
 		scheme:        mgr.GetScheme(),
 		provider:      opts.DetectedProvider,
-		status:        status.New(mgr.GetClient(), "amazon-cloud-integration", opts.KubernetesVersion),
+		status:        status.NewForManager(mgr, "amazon-cloud-integration", opts.KubernetesVersion),
 		clusterDomain: opts.ClusterDomain,
 	}
 	r.status.Run(opts.ShutdownContext)
@@ -70,7 +72,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Recon
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("amazoncloudintegration-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return fmt.Errorf("failed to create amazoncloudintegration-controller: %v", err)
 	}
@@ -92,7 +94,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("amazoncloudintegration-controller failed to watch the Secret resource(%s): %v", render.AmazonCloudIntegrationCredentialName, err)
 	}
 
-	err = imageset.AddImageSetWatch(c)
+	err = imageset.AddImageSetWatch(c, mgr.GetClient())
 	if err != nil {
 		return fmt.Errorf("amazoncloudintegration-controller failed to watch ImageSet: %w", err)
 	}
@@ -146,7 +148,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating AmazonCloudIntegration status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -162,6 +164,11 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
@@ -205,7 +212,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		return reconcile.Result{}, err
 	}
 
-	certificateManager, err := certificatemanager.Create(r.client, network, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.client, network, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to load CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -220,6 +227,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetController(controllerName)
 
 	// Render the desired objects from the CRD and create or update them.
 	reqLogger.V(3).Info("rendering components")
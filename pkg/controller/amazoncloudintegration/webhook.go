@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amazoncloudintegration
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// Webhook defaults and validates AmazonCloudIntegrations at admission time, in the same way as the controller does
+// during reconcile.
+type Webhook struct{}
+
+func (w *Webhook) Default(_ context.Context, obj runtime.Object) error {
+	aci, ok := obj.(*operatorv1.AmazonCloudIntegration)
+	if !ok {
+		return fmt.Errorf("expected an AmazonCloudIntegration but got %T", obj)
+	}
+	fillDefaults(aci)
+	return nil
+}
+
+func (w *Webhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *Webhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *Webhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *Webhook) validate(ctx context.Context, obj runtime.Object) error {
+	aci, ok := obj.(*operatorv1.AmazonCloudIntegration)
+	if !ok {
+		return fmt.Errorf("expected an AmazonCloudIntegration but got %T", obj)
+	}
+	aci = aci.DeepCopy()
+	if err := w.Default(ctx, aci); err != nil {
+		return err
+	}
+	return validateCustomResource(aci)
+}
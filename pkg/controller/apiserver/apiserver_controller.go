@@ -52,6 +52,8 @@ import (
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 )
 
+const controllerName = "apiserver-controller"
+
 const ResourceName string = "apiserver"
 
 var log = logf.Log.WithName("controller_apiserver")
@@ -61,7 +63,7 @@ var log = logf.Log.WithName("controller_apiserver")
 func Add(mgr manager.Manager, opts options.AddOptions) error {
 	r := newReconciler(mgr, opts)
 
-	c, err := controller.New("apiserver-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return fmt.Errorf("failed to create apiserver-controller: %w", err)
 	}
@@ -94,7 +96,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAPISe
 		provider:            opts.DetectedProvider,
 		amazonCRDExists:     opts.AmazonCRDExists,
 		enterpriseCRDsExist: opts.EnterpriseCRDExists,
-		status:              status.New(mgr.GetClient(), "apiserver", opts.KubernetesVersion),
+		status:              status.NewForManager(mgr, "apiserver", opts.KubernetesVersion),
 		clusterDomain:       opts.ClusterDomain,
 		usePSP:              opts.UsePSP,
 		tierWatchReady:      &utils.ReadyFlag{},
@@ -167,7 +169,7 @@ func add(c controller.Controller, r *ReconcileAPIServer) error {
 		}
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, r.client); err != nil {
 		return fmt.Errorf("apiserver-controller failed to watch ImageSet: %w", err)
 	}
 	// Watch for changes to TigeraStatus.
@@ -226,7 +228,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating ApiServer status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -242,6 +244,11 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Query for the installation object.
 	variant, network, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
@@ -258,7 +265,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 	ns := rmeta.APIServerNamespace(variant)
 
-	certificateManager, err := certificatemanager.Create(r.client, network, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.client, network, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -369,6 +376,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetController(controllerName)
 
 	// Render the desired objects from the CRD and create or update them.
 	reqLogger.V(3).Info("rendering components")
@@ -481,7 +489,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, components...); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -46,6 +46,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const controllerName = "applicationlayer-controller"
+
 const ResourceName = "applicationlayer"
 
 var log = logf.Log.WithName("controller_applicationlayer")
@@ -65,7 +67,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
-	c, err := controller.New("applicationlayer-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
 	if err != nil {
 		return err
 	}
@@ -87,7 +89,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		client:          mgr.GetClient(),
 		scheme:          mgr.GetScheme(),
 		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "applicationlayer", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "applicationlayer", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
 		licenseAPIReady: licenseAPIReady,
 		usePSP:          opts.UsePSP,
@@ -106,7 +108,7 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return err
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("applicationlayer-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -189,7 +191,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating application layer status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -205,6 +207,11 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
 	updateApplicationLayerWithDefaults(instance)
@@ -284,9 +291,10 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	component := applicationlayer.ApplicationLayer(config)
 
 	ch := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	ch.SetController(controllerName)
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -103,7 +103,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, tierWatchReady
 		client:         mgr.GetClient(),
 		scheme:         mgr.GetScheme(),
 		provider:       opts.DetectedProvider,
-		status:         status.New(mgr.GetClient(), "authentication", opts.KubernetesVersion),
+		status:         status.NewForManager(mgr, "authentication", opts.KubernetesVersion),
 		clusterDomain:  opts.ClusterDomain,
 		tierWatchReady: tierWatchReady,
 		usePSP:         opts.UsePSP,
@@ -140,7 +140,7 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		}
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("%s failed to watch ImageSet: %w", controllerName, err)
 	}
 
@@ -187,7 +187,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&authentication.ObjectMeta)
+	defer r.status.SetMetaData(authentication)
 
 	// Changes for updating application layer status conditions
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -203,6 +203,11 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 		}
 	}
 
+	if utils.IsPaused(authentication) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	reqLogger.V(2).Info("Loaded config", "config", authentication)
 	preDefaultPatchFrom := client.MergeFrom(authentication.DeepCopy())
 
@@ -276,7 +281,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 	}
 
 	// Secret used for TLS between dex and other components.
-	certificateManager, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(oprv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -326,6 +331,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 
 	// Create a component handler to manage the rendered component.
 	hlr := utils.NewComponentHandler(log, r.client, r.scheme, authentication)
+	hlr.SetController(controllerName)
 
 	dexComponentCfg := &render.DexComponentConfiguration{
 		PullSecrets:   pullSecrets,
@@ -344,7 +350,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 	component := render.Dex(dexComponentCfg)
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
-		r.status.SetDegraded(oprv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(oprv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -28,6 +28,7 @@ import (
 	"github.com/openshift/library-go/pkg/crypto"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/metrics"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils/imageset"
 	"github.com/tigera/operator/pkg/render/common/meta"
@@ -69,10 +70,23 @@ type certificateManager struct {
 	log     logr.Logger
 	tenant  *operatorv1.Tenant
 
+	// ctx is used for the requests that the certificate manager makes to an external issuer.
+	ctx context.Context
+
 	// Controls whether this instance of the certificate manager is allowed to
 	// create new CAs. Most instances should simply read the existing CA and use it to sign
-	// certificates.
+	// certificates. Only instances that may create a CA will rotate it.
 	allowCACreation bool
+
+	// renewalPercentage is the percentage of the lifetime of a certificate after which it is renewed.
+	renewalPercentage int32
+
+	// previousCA is the certificate of the CA that was rotated out, if it is still within the overlap period.
+	previousCA certificatemanagement.CertificateInterface
+
+	// nextRotation is the earliest time at which a certificate that the manager has loaded or created is due for
+	// renewal, or the previous CA stops being trusted.
+	nextRotation time.Time
 }
 
 // CertificateManager can sign new certificates and has methods to retrieve existing KeyPairs and Certificates. If a user
@@ -83,6 +97,8 @@ type CertificateManager interface {
 	// is an implementation of KeyPairInterface using the provided dnsNames.
 	GetKeyPair(cli client.Client, secretName, secretNamespace string, dnsNames []string) (certificatemanagement.KeyPairInterface, error)
 	// GetOrCreateKeyPair returns a KeyPair. If one exists, some checks are performed. Otherwise, a new KeyPair is created.
+	// When an external issuer is configured, the KeyPair is requested from the issuer instead and an error is returned
+	// until the issuer has written the secret.
 	GetOrCreateKeyPair(cli client.Client, secretName, secretNamespace string, dnsNames []string) (certificatemanagement.KeyPairInterface, error)
 	// CreateCSRKeyPair returns a KeyPair that relies on issuing Certificate Signing Requests to the kubernetes api to be
 	// signed by OperatorCSRSignerName. This means that pkg/controller/csr/csr_controller.go will end up signing the CSR
@@ -113,6 +129,9 @@ type CertificateManager interface {
 	// SignCertificate signs a certificate using the certificate manager's private key. The function is assuming that the
 	// public key of the requestor is already set in the certificate template.
 	SignCertificate(certificate *x509.Certificate) ([]byte, error)
+	// NextRotation returns the earliest time at which the CA (for a manager that may create it) or a key pair returned
+	// by GetOrCreateKeyPair is due for renewal, or the previous CA stops being trusted. It is zero if there is none.
+	NextRotation() time.Time
 }
 
 type Option func(cm *certificateManager) error
@@ -131,6 +150,15 @@ func WithLogger(log logr.Logger) Option {
 	}
 }
 
+// WithContext sets the context of the requests that the certificate manager makes to an external issuer, usually the
+// context of the reconcile that it is created for.
+func WithContext(ctx context.Context) Option {
+	return func(cm *certificateManager) error {
+		cm.ctx = ctx
+		return nil
+	}
+}
+
 func WithTenant(t *operatorv1.Tenant) Option {
 	return func(cm *certificateManager) error {
 		cm.tenant = t
@@ -147,13 +175,14 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 		// The private key is of type any, as this is the interface used in the x509 package for all private key types.
 		privateKey                    any
 		privateKeyPEM, certificatePEM []byte
+		previousCAPEM                 []byte
 		certificateManagement         *operatorv1.CertificateManagement
 		err                           error
 	)
 
 	// Create a certificatemanager instance and apply any user-provided options to
 	// initialize it.
-	cm := &certificateManager{log: log}
+	cm := &certificateManager{log: log, ctx: context.Background(), renewalPercentage: RenewalPercentage(installation)}
 	for _, opt := range opts {
 		if err := opt(cm); err != nil {
 			return nil, err
@@ -170,7 +199,7 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 
 	var certificateManagementEnabled bool
 	if installation != nil {
-		imageSet, err := imageset.GetImageSet(context.Background(), cli, installation.Variant)
+		imageSet, err := imageset.GetVerifiedImageSet(context.Background(), cli, installation.Variant)
 		if err != nil {
 			return nil, err
 		}
@@ -180,6 +209,7 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 			installation.Registry,
 			installation.ImagePath,
 			installation.ImagePrefix,
+			installation.ImageOverrides,
 			imageSet,
 		)
 		if err != nil {
@@ -216,15 +246,10 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 			}
 			// No existing CA data - we need to generate a new one.
 			cm.log.Info("Generating a new CA", "namespace", ns)
-			cryptoCA, err = tls.MakeCA(rmeta.TigeraOperatorCAIssuerPrefix)
+			cryptoCA, privateKey, privateKeyPEM, certificatePEM, err = makeCA()
 			if err != nil {
 				return nil, err
 			}
-			keyContent, crtContent := &bytes.Buffer{}, &bytes.Buffer{}
-			if err := cryptoCA.Config.WriteCertConfig(crtContent, keyContent); err != nil {
-				return nil, err
-			}
-			privateKey, privateKeyPEM, certificatePEM = cryptoCA.Config.Key, keyContent.Bytes(), crtContent.Bytes()
 		} else {
 			// Found an existing CA - use that.
 			cm.log.V(2).Info("Found an existing CA secret")
@@ -246,6 +271,20 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 			if err != nil {
 				return nil, err
 			}
+			previousCAPEM = caSecret.Data[certificatemanagement.PreviousCACertKey]
+
+			if cm.allowCACreation && len(cryptoCA.Config.Certs) > 0 {
+				caCert := cryptoCA.Config.Certs[0]
+				// Only CAs that were generated by the operator are rotated. A user provided CA is left alone.
+				if strings.HasPrefix(caCert.Subject.CommonName, rmeta.TigeraOperatorCAIssuerPrefix) && needsRenewal(caCert, cm.renewalPercentage, time.Now()) {
+					cm.log.Info("Rotating the CA", "namespace", ns, "notAfter", caCert.NotAfter)
+					previousCAPEM = certificatePEM
+					cryptoCA, privateKey, privateKeyPEM, certificatePEM, err = makeCA()
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
 		}
 	}
 
@@ -256,6 +295,10 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 		return nil, err
 	}
 
+	if !certificateManagementEnabled {
+		metrics.SetCertificateExpiry(ns, caSecretName, x509Cert.NotAfter)
+	}
+
 	// Fill in remaining fields.
 	cm.CA = cryptoCA
 	cm.Certificate = x509Cert
@@ -270,17 +313,41 @@ func Create(cli client.Client, installation *operatorv1.InstallationSpec, cluste
 		CertificateManagement: certificateManagement,
 	}
 
+	// After the CA has been rotated, the previous CA is kept in the CA secret and added to the trusted bundles
+	// until the overlap period has passed. This gives the components the time to renew their certificates.
+	if overlapEnd := x509Cert.NotBefore.Add(CAOverlapDuration(installation)); len(previousCAPEM) > 0 && time.Now().Before(overlapEnd) {
+		cm.previousCA = certificatemanagement.NewCertificate(fmt.Sprintf("%s-previous", caSecretName), ns, previousCAPEM, nil)
+		cm.keyPair.PreviousCA = previousCAPEM
+		cm.scheduleRotation(overlapEnd)
+	}
+	if cm.allowCACreation && !certificateManagementEnabled && strings.HasPrefix(x509Cert.Subject.CommonName, rmeta.TigeraOperatorCAIssuerPrefix) {
+		cm.scheduleRotation(renewalTime(x509Cert, cm.renewalPercentage))
+	}
+
 	cm.log.V(2).Info("Created CertificateManager", "ns", ns, "authority", cm.AuthorityKeyId)
 	return cm, nil
 }
 
+// makeCA generates a new operator CA.
+func makeCA() (*crypto.CA, any, []byte, []byte, error) {
+	cryptoCA, err := tls.MakeCA(rmeta.TigeraOperatorCAIssuerPrefix)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	keyContent, crtContent := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := cryptoCA.Config.WriteCertConfig(crtContent, keyContent); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return cryptoCA, cryptoCA.Config.Key, keyContent.Bytes(), crtContent.Bytes(), nil
+}
+
 func (cm *certificateManager) KeyPair() certificatemanagement.KeyPairInterface {
 	return cm.keyPair
 }
 
 // AddToStatusManager lets the status manager monitor pending CSRs if the certificate management is enabled.
 func (cm *certificateManager) AddToStatusManager(statusManager status.StatusManager, namespace string) {
-	if cm.CertificateManagement() != nil {
+	if cm.CertificateManagement().UseCSRs() {
 		statusManager.AddCertificateSigningRequests(namespace, map[string]string{"k8s-app": namespace})
 	} else {
 		statusManager.RemoveCertificateSigningRequests(namespace)
@@ -303,6 +370,9 @@ func (cm *certificateManager) CreateCSRKeyPair(secretName, namespace string, dns
 
 // GetOrCreateKeyPair returns a KeyPair. If one exists, some checks are performed. Otherwise, a new KeyPair is created.
 func (cm *certificateManager) GetOrCreateKeyPair(cli client.Client, secretName, secretNamespace string, dnsNames []string) (certificatemanagement.KeyPairInterface, error) {
+	if issuer := cm.issuer(); issuer != nil {
+		return cm.getOrRequestIssuedKeyPair(cli, issuer, secretName, secretNamespace, dnsNames)
+	}
 	keyPair, x509Cert, err := cm.getKeyPair(cli, secretName, secretNamespace, false, dnsNames)
 	if keyPair != nil && keyPair.UseCertificateManagement() {
 		return certificateManagementKeyPair(cm, secretName, secretNamespace, dnsNames), nil
@@ -312,7 +382,14 @@ func (cm *certificateManager) GetOrCreateKeyPair(cli client.Client, secretName,
 	} else if keyPair != nil {
 		err = HasExpectedDNSNames(secretName, secretNamespace, x509Cert, dnsNames)
 		if err == nil {
-			return keyPair, nil
+			if keyPair.BYO() {
+				return keyPair, nil
+			}
+			if !needsRenewal(x509Cert, cm.renewalPercentage, time.Now()) {
+				cm.scheduleRotation(renewalTime(x509Cert, cm.renewalPercentage))
+				return keyPair, nil
+			}
+			cm.log.Info("KeyPair is due for renewal, create a new one", "namespace", secretNamespace, "name", secretName, "notAfter", x509Cert.NotAfter)
 		} else if keyPair.BYO() {
 			cm.log.V(3).Info("secret %s has invalid DNS names, the expected names are: %v", secretName, dnsNames)
 			return keyPair, nil
@@ -330,6 +407,10 @@ func (cm *certificateManager) GetOrCreateKeyPair(cli client.Client, secretName,
 	if err := tlsCfg.WriteCertConfig(crtContent, keyContent); err != nil {
 		return nil, err
 	}
+	if len(tlsCfg.Certs) > 0 {
+		metrics.SetCertificateExpiry(secretNamespace, secretName, tlsCfg.Certs[0].NotAfter)
+		cm.scheduleRotation(renewalTime(tlsCfg.Certs[0], cm.renewalPercentage))
+	}
 
 	return &certificatemanagement.KeyPair{
 		Issuer:         cm.keyPair,
@@ -341,6 +422,17 @@ func (cm *certificateManager) GetOrCreateKeyPair(cli client.Client, secretName,
 	}, nil
 }
 
+func (cm *certificateManager) NextRotation() time.Time {
+	return cm.nextRotation
+}
+
+// scheduleRotation records a rotation deadline, keeping the earliest one.
+func (cm *certificateManager) scheduleRotation(t time.Time) {
+	if cm.nextRotation.IsZero() || t.Before(cm.nextRotation) {
+		cm.nextRotation = t
+	}
+}
+
 // SignCertificate signs a certificate using the certificate manager's private key. The function is assuming that the
 // public key of the requestor is already set in the certificate template.
 func (cm *certificateManager) SignCertificate(certificateTemplate *x509.Certificate) ([]byte, error) {
@@ -413,7 +505,7 @@ func (cm *certificateManager) getKeyPair(cli client.Client, secretName, secretNa
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			cm.log.V(2).Info("KeyPair not found", "namespace", secretNamespace, "name", secretName)
-			if cm.keyPair.CertificateManagement != nil {
+			if cm.CertificateManagement().UseCSRs() {
 				// When certificate management is enabled, we expect that in most cases no secret will be present.
 				return certificateManagementKeyPair(cm, secretName, secretNamespace, dnsNames), nil, nil
 			}
@@ -441,7 +533,7 @@ func (cm *certificateManager) getKeyPair(cli client.Client, secretName, secretNa
 	timeInvalid := x509Cert.NotAfter.Before(time.Now()) || x509Cert.NotBefore.After(time.Now())
 	if timeInvalid || invalidKeyUsage {
 		if !readCertOnly && strings.HasPrefix(x509Cert.Issuer.CommonName, rmeta.TigeraOperatorCAIssuerPrefix) {
-			if cm.keyPair.CertificateManagement != nil {
+			if cm.CertificateManagement().UseCSRs() {
 				// When certificate management is enabled, we can simply return a certificate management key pair;
 				// the old secret will be deleted automatically.
 				return certificateManagementKeyPair(cm, secretName, secretNamespace, dnsNames), nil, nil
@@ -463,7 +555,7 @@ func (cm *certificateManager) getKeyPair(cli client.Client, secretName, secretNa
 
 	var issuer certificatemanagement.KeyPairInterface
 	if x509Cert.Issuer.CommonName == rmeta.TigeraOperatorCAIssuerPrefix {
-		if cm.keyPair.CertificateManagement != nil {
+		if cm.CertificateManagement().UseCSRs() {
 			return certificateManagementKeyPair(cm, secretName, secretNamespace, dnsNames), nil, nil
 		}
 		if string(x509Cert.AuthorityKeyId) == string(cm.AuthorityKeyId) {
@@ -479,6 +571,7 @@ func (cm *certificateManager) getKeyPair(cli client.Client, secretName, secretNa
 			issuer = nil
 		}
 	}
+	metrics.SetCertificateExpiry(secretNamespace, secretName, x509Cert.NotAfter)
 	return &certificatemanagement.KeyPair{
 		Issuer:         issuer,
 		Name:           secretName,
@@ -549,7 +642,7 @@ func HasExpectedDNSNames(secretName, secretNamespace string, cert *x509.Certific
 // It will include:
 // - A bundle with Calico's root certificates + any user supplied certificates in /etc/pki/tls/certs/tigera-ca-bundle.crt.
 func (cm *certificateManager) CreateTrustedBundle(certificates ...certificatemanagement.CertificateInterface) certificatemanagement.TrustedBundle {
-	return certificatemanagement.CreateTrustedBundle(append([]certificatemanagement.CertificateInterface{cm.keyPair}, certificates...)...)
+	return certificatemanagement.CreateTrustedBundle(cm.trustedCertificates(certificates...)...)
 }
 
 // CreateTrustedBundleWithSystemRootCertificates creates a TrustedBundle, which provides standardized methods for mounting a bundle of certificates to trust.
@@ -557,11 +650,21 @@ func (cm *certificateManager) CreateTrustedBundle(certificates ...certificateman
 // - A bundle with Calico's root certificates + any user supplied certificates in /etc/pki/tls/certs/tigera-ca-bundle.crt.
 // - A system root certificate bundle in /etc/pki/tls/certs/ca-bundle.crt.
 func (cm *certificateManager) CreateTrustedBundleWithSystemRootCertificates(certificates ...certificatemanagement.CertificateInterface) (certificatemanagement.TrustedBundle, error) {
-	return certificatemanagement.CreateTrustedBundleWithSystemRootCertificates(append([]certificatemanagement.CertificateInterface{cm.keyPair}, certificates...)...)
+	return certificatemanagement.CreateTrustedBundleWithSystemRootCertificates(cm.trustedCertificates(certificates...)...)
 }
 
 func (cm *certificateManager) CreateMultiTenantTrustedBundleWithSystemRootCertificates(certificates ...certificatemanagement.CertificateInterface) (certificatemanagement.TrustedBundle, error) {
-	return certificatemanagement.CreateMultiTenantTrustedBundleWithSystemRootCertificates(append([]certificatemanagement.CertificateInterface{cm.keyPair}, certificates...)...)
+	return certificatemanagement.CreateMultiTenantTrustedBundleWithSystemRootCertificates(cm.trustedCertificates(certificates...)...)
+}
+
+// trustedCertificates returns the certificates to include in a trusted bundle: the CA, the previous CA while it is
+// still within the overlap period, and the given certificates.
+func (cm *certificateManager) trustedCertificates(certificates ...certificatemanagement.CertificateInterface) []certificatemanagement.CertificateInterface {
+	trusted := []certificatemanagement.CertificateInterface{cm.keyPair}
+	if cm.previousCA != nil {
+		trusted = append(trusted, cm.previousCA)
+	}
+	return append(trusted, certificates...)
 }
 
 func (cm *certificateManager) LoadTrustedBundle(ctx context.Context, client client.Client, ns string) (certificatemanagement.TrustedBundleRO, error) {
@@ -177,6 +177,24 @@ var _ = Describe("Test CertificateManagement suite", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should not use an ImageSet for the CSR init image that isn't signed by a trusted key", func() {
+			Expect(cli.Create(ctx, &operatorv1.Installation{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: operatorv1.InstallationSpec{
+					ImageSetVerification: &operatorv1.ImageSetVerification{PublicKeySecretName: "imageset-keys"},
+				},
+			})).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, &operatorv1.ImageSet{
+				ObjectMeta: metav1.ObjectMeta{Name: imageset.SetName(installation.Variant)},
+				Spec: operatorv1.ImageSetSpec{Images: []operatorv1.Image{
+					{Image: components.ComponentCSRInitContainer.Image, Digest: "sha256:0123456789"},
+				}},
+			})).NotTo(HaveOccurred())
+
+			_, err := certificatemanager.Create(cli, installation, clusterDomain, common.OperatorNamespace(), certificatemanager.AllowCACreation())
+			Expect(err).To(MatchError(ContainSubstring("imageset-keys")))
+		})
+
 		It("should create a KeyPair if it does not exist yet or reconstruct it from secret", func() {
 			By("creating a key pair and storing the secret")
 			keyPair, err := certificateManager.GetOrCreateKeyPair(cli, appSecretName, appNs, appDNSNames)
@@ -407,6 +425,7 @@ var _ = Describe("Test CertificateManagement suite", func() {
 				installation.Registry,
 				installation.ImagePath,
 				installation.ImagePrefix,
+				installation.ImageOverrides,
 				imageSet,
 			)
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificatemanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+const (
+	// DefaultIssuerKind is the kind of the issuer that is used when none is configured.
+	DefaultIssuerKind = "ClusterIssuer"
+
+	// DefaultIssuerGroup is the group of the issuer that is used when none is configured.
+	DefaultIssuerGroup = "cert-manager.io"
+)
+
+// IssuedCertificateLabel is set on the Certificates that the operator creates, so that they can be found and deleted
+// once the issuer is removed from the Installation.
+const IssuedCertificateLabel = "operator.tigera.io/issued-certificate"
+
+// CertificateGVK is the kind of the objects that the operator creates to request a certificate from an external issuer.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// ErrWaitingForIssuer is returned while the external issuer has not yet written the secret of a requested key pair.
+// The status manager reports it as Progressing rather than Degraded, and the reconcile that returns it is retried.
+type ErrWaitingForIssuer struct {
+	Name      string
+	Namespace string
+}
+
+func (e *ErrWaitingForIssuer) Error() string {
+	return fmt.Sprintf("waiting for the issuer to write secret %s/%s", e.Namespace, e.Name)
+}
+
+// Waiting marks the error as one that the component is waiting on, rather than failing because of.
+func (e *ErrWaitingForIssuer) Waiting() bool {
+	return true
+}
+
+// issuer returns the external issuer that key pairs are delegated to, or nil if there is none.
+func (cm *certificateManager) issuer() *operatorv1.CertificateIssuer {
+	if cm.CertificateManagement() == nil {
+		return nil
+	}
+	return cm.CertificateManagement().Issuer
+}
+
+// getOrRequestIssuedKeyPair makes sure that a certificate is requested from the issuer for the given secret and returns
+// the key pair once the issuer has written the secret. The operator does not own the secret, so the key pair is treated
+// like one that was provided by the user: it is copied to the namespaces that need it, but it is never overwritten.
+func (cm *certificateManager) getOrRequestIssuedKeyPair(cli client.Client, issuer *operatorv1.CertificateIssuer, secretName, secretNamespace string, dnsNames []string) (certificatemanagement.KeyPairInterface, error) {
+	if err := requestCertificate(cm.ctx, cli, issuer, secretName, secretNamespace, dnsNames); err != nil {
+		return nil, err
+	}
+	keyPair, _, err := cm.getKeyPair(cli, secretName, secretNamespace, false, dnsNames)
+	if err != nil {
+		return nil, err
+	}
+	if keyPair == nil {
+		cm.log.V(1).Info("Waiting for the issuer to write the keypair", "namespace", secretNamespace, "name", secretName)
+		return nil, &ErrWaitingForIssuer{Name: secretName, Namespace: secretNamespace}
+	}
+	return keyPair, nil
+}
+
+// requestCertificate creates or updates the Certificate that asks the issuer to write a key pair to the given secret.
+// The Certificate is owned by the Installation, so that it is deleted along with it.
+func requestCertificate(ctx context.Context, cli client.Client, issuer *operatorv1.CertificateIssuer, secretName, secretNamespace string, dnsNames []string) error {
+	kind, group := issuer.Kind, issuer.Group
+	if kind == "" {
+		kind = DefaultIssuerKind
+	}
+	if group == "" {
+		group = DefaultIssuerGroup
+	}
+	names := make([]interface{}, len(dnsNames))
+	for i, name := range dnsNames {
+		names[i] = name
+	}
+	spec := map[string]interface{}{
+		"secretName": secretName,
+		"dnsNames":   names,
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  kind,
+			"group": group,
+		},
+		"usages": []interface{}{"server auth", "client auth"},
+	}
+	if len(dnsNames) > 0 {
+		spec["commonName"] = dnsNames[0]
+	}
+
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	err := cli.Get(ctx, client.ObjectKey{Name: secretName, Namespace: secretNamespace}, cert)
+	if kerrors.IsNotFound(err) {
+		cert = &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		cert.SetGroupVersionKind(CertificateGVK)
+		cert.SetName(secretName)
+		cert.SetNamespace(secretNamespace)
+		cert.SetLabels(map[string]string{IssuedCertificateLabel: "true"})
+		installation := &operatorv1.Installation{}
+		if err := cli.Get(ctx, utils.DefaultInstanceKey, installation); err != nil {
+			return err
+		}
+		cert.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: operatorv1.GroupVersion.String(),
+			Kind:       "Installation",
+			Name:       installation.Name,
+			UID:        installation.UID,
+		}})
+		return cli.Create(ctx, cert)
+	} else if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(cert.Object["spec"], spec) {
+		return nil
+	}
+	cert.Object["spec"] = spec
+	return cli.Update(ctx, cert)
+}
+
+// DeleteIssuedCertificates deletes the Certificates that the operator created to request key pairs from an issuer,
+// along with the secrets that the issuer wrote for them. It is used once the issuer has been removed from the
+// Installation, so that the key pairs are issued by the operator again. Nothing is done if the Certificate CRD is not
+// installed.
+func DeleteIssuedCertificates(ctx context.Context, cli client.Client) error {
+	certs := &unstructured.UnstructuredList{}
+	certs.SetGroupVersionKind(CertificateGVK.GroupVersion().WithKind(CertificateGVK.Kind + "List"))
+	if err := cli.List(ctx, certs, client.HasLabels{IssuedCertificateLabel}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for i := range certs.Items {
+		cert := &certs.Items[i]
+		secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+		if secretName != "" {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: cert.GetNamespace()}}
+			if err := cli.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+		if err := cli.Delete(ctx, cert); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Deleted the Certificate for a key pair from the removed issuer", "namespace", cert.GetNamespace(), "name", cert.GetName())
+	}
+	return nil
+}
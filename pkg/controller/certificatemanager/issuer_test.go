@@ -0,0 +1,176 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificatemanager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/render/common/secret"
+	"github.com/tigera/operator/pkg/tls"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+var _ = Describe("External issuer", func() {
+	const (
+		appSecretName = "my-app-tls"
+		ns            = "tigera-operator"
+	)
+
+	var (
+		cli          client.Client
+		ctx          = context.TODO()
+		issuerCA     *crypto.CA
+		installation *operatorv1.InstallationSpec
+	)
+
+	BeforeEach(func() {
+		scheme := k8sruntime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		var err error
+		issuerCA, err = tls.MakeCA("cert-manager-ca")
+		Expect(err).NotTo(HaveOccurred())
+		caSecret, err := secret.CreateTLSSecret(issuerCA, "ca", ns, corev1.TLSPrivateKeyKey, corev1.TLSCertKey, time.Hour, nil, "ca")
+		Expect(err).NotTo(HaveOccurred())
+		installation = &operatorv1.InstallationSpec{
+			CertificateManagement: &operatorv1.CertificateManagement{
+				CACert: caSecret.Data[corev1.TLSCertKey],
+				Issuer: &operatorv1.CertificateIssuer{Name: "my-issuer"},
+			},
+		}
+		Expect(cli.Create(ctx, &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "installation-uid"},
+			Spec:       *installation,
+		})).NotTo(HaveOccurred())
+	})
+
+	It("requests a certificate and waits until the issuer writes the secret", func() {
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", common.OperatorNamespace())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cm.GetOrCreateKeyPair(cli, appSecretName, ns, []string{"my-app", "my-app.my-ns.svc"})
+		Expect(err).To(BeAssignableToTypeOf(&certificatemanager.ErrWaitingForIssuer{}))
+
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificatemanager.CertificateGVK)
+		Expect(cli.Get(ctx, client.ObjectKey{Name: appSecretName, Namespace: ns}, cert)).NotTo(HaveOccurred())
+		Expect(cert.Object["spec"]).To(Equal(map[string]interface{}{
+			"secretName": appSecretName,
+			"commonName": "my-app",
+			"dnsNames":   []interface{}{"my-app", "my-app.my-ns.svc"},
+			"issuerRef": map[string]interface{}{
+				"name":  "my-issuer",
+				"kind":  "ClusterIssuer",
+				"group": "cert-manager.io",
+			},
+			"usages": []interface{}{"server auth", "client auth"},
+		}))
+		Expect(cert.GetLabels()).To(HaveKeyWithValue(certificatemanager.IssuedCertificateLabel, "true"))
+		Expect(cert.GetOwnerReferences()).To(ConsistOf(metav1.OwnerReference{
+			APIVersion: "operator.tigera.io/v1",
+			Kind:       "Installation",
+			Name:       "default",
+			UID:        "installation-uid",
+		}))
+
+		// There is no key pair to read yet.
+		keyPair, err := cm.GetKeyPair(cli, appSecretName, ns, []string{"my-app"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair).To(BeNil())
+
+		// Once the issuer has written the secret, it is returned, but never overwritten by the operator.
+		issued, err := secret.CreateTLSSecret(issuerCA, appSecretName, ns, corev1.TLSPrivateKeyKey, corev1.TLSCertKey, time.Hour, []crypto.CertificateExtensionFunc{tls.SetServerAuth, tls.SetClientAuth}, "my-app", "my-app.my-ns.svc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, issued)).NotTo(HaveOccurred())
+
+		keyPair, err = cm.GetOrCreateKeyPair(cli, appSecretName, ns, []string{"my-app", "my-app.my-ns.svc"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.GetCertificatePEM()).To(Equal(issued.Data[corev1.TLSCertKey]))
+		Expect(keyPair.BYO()).To(BeTrue())
+		Expect(keyPair.UseCertificateManagement()).To(BeFalse())
+	})
+
+	It("updates the certificate when the DNS names change", func() {
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", common.OperatorNamespace())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cm.GetOrCreateKeyPair(cli, appSecretName, ns, []string{"my-app"})
+		Expect(err).To(BeAssignableToTypeOf(&certificatemanager.ErrWaitingForIssuer{}))
+		_, err = cm.GetOrCreateKeyPair(cli, appSecretName, ns, []string{"my-app", "my-app.my-ns"})
+		Expect(err).To(BeAssignableToTypeOf(&certificatemanager.ErrWaitingForIssuer{}))
+
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificatemanager.CertificateGVK)
+		Expect(cli.Get(ctx, client.ObjectKey{Name: appSecretName, Namespace: ns}, cert)).NotTo(HaveOccurred())
+		dnsNames, _, err := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dnsNames).To(Equal([]string{"my-app", "my-app.my-ns"}))
+	})
+
+	It("deletes the certificates and the secrets issued for them once the issuer is removed", func() {
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", common.OperatorNamespace(), certificatemanager.WithContext(ctx))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cm.GetOrCreateKeyPair(cli, appSecretName, ns, []string{"my-app"})
+		Expect(err).To(BeAssignableToTypeOf(&certificatemanager.ErrWaitingForIssuer{}))
+		issued, err := secret.CreateTLSSecret(issuerCA, appSecretName, ns, corev1.TLSPrivateKeyKey, corev1.TLSCertKey, time.Hour, nil, "my-app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, issued)).NotTo(HaveOccurred())
+		// Secrets that were not issued for the operator's Certificates are left alone.
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: ns}}
+		Expect(cli.Create(ctx, other)).NotTo(HaveOccurred())
+
+		Expect(certificatemanager.DeleteIssuedCertificates(ctx, cli)).NotTo(HaveOccurred())
+
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificatemanager.CertificateGVK)
+		Expect(kerrors.IsNotFound(cli.Get(ctx, client.ObjectKey{Name: appSecretName, Namespace: ns}, cert))).To(BeTrue())
+		Expect(kerrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(issued), &corev1.Secret{}))).To(BeTrue())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(other), &corev1.Secret{})).NotTo(HaveOccurred())
+	})
+
+	It("does not render the CSR init container for issued key pairs", func() {
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", common.OperatorNamespace())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installation.CertificateManagement.UseCSRs()).To(BeFalse())
+		Expect(cm.KeyPair().GetCertificatePEM()).To(Equal(installation.CertificateManagement.CACert))
+
+		issued, err := secret.CreateTLSSecret(issuerCA, appSecretName, ns, corev1.TLSPrivateKeyKey, corev1.TLSCertKey, time.Hour, []crypto.CertificateExtensionFunc{tls.SetServerAuth, tls.SetClientAuth}, "my-app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, issued)).NotTo(HaveOccurred())
+		keyPair, err := cm.GetKeyPair(cli, appSecretName, ns, []string{"my-app"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.Volume().Secret).NotTo(BeNil())
+		Expect(keyPair.(*certificatemanagement.KeyPair).CertificateManagement).To(BeNil())
+	})
+})
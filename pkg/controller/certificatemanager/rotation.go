@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificatemanager
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+const (
+	// DefaultRenewalPercentage is the percentage of a certificate's lifetime after which it is renewed, unless
+	// configured otherwise on the Installation.
+	DefaultRenewalPercentage = 67
+
+	// DefaultCAOverlapDuration is how long the previous CA is trusted after the operator CA is rotated, unless
+	// configured otherwise on the Installation.
+	DefaultCAOverlapDuration = 24 * time.Hour
+
+	// DefaultExpiryWarningDuration is how long before a certificate expires that it is reported, unless configured
+	// otherwise on the Installation.
+	DefaultExpiryWarningDuration = 30 * 24 * time.Hour
+)
+
+// RenewalPercentage returns the percentage of a certificate's lifetime after which it is renewed.
+func RenewalPercentage(installation *operatorv1.InstallationSpec) int32 {
+	if installation != nil && installation.CertificateRotation != nil && installation.CertificateRotation.RenewalPercentage != nil {
+		return *installation.CertificateRotation.RenewalPercentage
+	}
+	return DefaultRenewalPercentage
+}
+
+// CAOverlapDuration returns how long the previous CA remains trusted after the operator CA is rotated.
+func CAOverlapDuration(installation *operatorv1.InstallationSpec) time.Duration {
+	if installation != nil && installation.CertificateRotation != nil && installation.CertificateRotation.CAOverlapDuration != nil {
+		return installation.CertificateRotation.CAOverlapDuration.Duration
+	}
+	return DefaultCAOverlapDuration
+}
+
+// ExpiryWarningDuration returns how long before a certificate expires that it is reported as expiring.
+func ExpiryWarningDuration(installation *operatorv1.InstallationSpec) time.Duration {
+	if installation != nil && installation.CertificateRotation != nil && installation.CertificateRotation.ExpiryWarningDuration != nil {
+		return installation.CertificateRotation.ExpiryWarningDuration.Duration
+	}
+	return DefaultExpiryWarningDuration
+}
+
+// renewalTime returns the time at which the given percentage of the certificate's lifetime has passed.
+func renewalTime(cert *x509.Certificate, percentage int32) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(lifetime / 100 * time.Duration(percentage))
+}
+
+// needsRenewal returns true once the given percentage of the certificate's lifetime has passed.
+func needsRenewal(cert *x509.Certificate, percentage int32, now time.Time) bool {
+	return !now.Before(renewalTime(cert, percentage))
+}
+
+// RequeueAfter returns how long until the next rotation deadline of the certificate manager, so that controllers can
+// reconcile in time to renew their certificates. It returns 0 if there is no deadline.
+func RequeueAfter(cm CertificateManager) time.Duration {
+	next := cm.NextRotation()
+	if next.IsZero() {
+		return 0
+	}
+	if d := time.Until(next); d > 0 {
+		return d
+	}
+	// The deadline has passed, e.g. because the certificate is renewed by another controller. Try again shortly.
+	return time.Second
+}
+
+// ExpiringCertificates returns a message for each certificate in the namespace that expires before the deadline. Only
+// secrets that contain a certificate are considered, and secrets that cannot be parsed are skipped.
+func ExpiringCertificates(ctx context.Context, cli client.Client, namespace string, deadline time.Time) ([]string, error) {
+	secrets := &corev1.SecretList{}
+	if err := cli.List(ctx, secrets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var msgs []string
+	for _, secret := range secrets.Items {
+		_, certPEM := certificatemanagement.GetKeyCertPEM(&secret)
+		if len(certPEM) == 0 {
+			continue
+		}
+		cert, err := certificatemanagement.ParseCertificate(certPEM)
+		if err != nil {
+			log.V(2).Info("Skipping secret with an invalid certificate", "namespace", secret.Namespace, "name", secret.Name)
+			continue
+		}
+		if cert.NotAfter.Before(deadline) {
+			msgs = append(msgs, fmt.Sprintf("Certificate %s/%s expires at %s", secret.Namespace, secret.Name, cert.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	sort.Strings(msgs)
+	return msgs, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificatemanager_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/ptr"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+var _ = Describe("Certificate rotation", func() {
+	const (
+		appSecretName = "my-app-tls"
+		appNs         = "my-app"
+	)
+
+	var (
+		cli          client.Client
+		ctx          = context.TODO()
+		installation *operatorv1.InstallationSpec
+		ns           = common.OperatorNamespace()
+	)
+
+	// createCertificate returns a certificate and key PEM valid between notBefore and notAfter. If parent is nil the
+	// certificate is a self-signed CA.
+	createCertificate := func(cn string, notBefore, notAfter time.Time, parent *x509.Certificate, parentKey *rsa.PrivateKey) ([]byte, []byte, *x509.Certificate, *rsa.PrivateKey) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			DNSNames:     []string{cn},
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		if parent == nil {
+			template.IsCA = true
+			template.BasicConstraintsValid = true
+			template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+			template.SubjectKeyId = []byte(cn)
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+		Expect(err).NotTo(HaveOccurred())
+		cert, err := x509.ParseCertificate(der)
+		Expect(err).NotTo(HaveOccurred())
+		certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		return certPEM, keyPEM, cert, key
+	}
+
+	tlsSecret := func(name, namespace string, certPEM, keyPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := k8sruntime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		installation = &operatorv1.InstallationSpec{}
+	})
+
+	It("should renew a key pair once the renewal percentage of its lifetime has passed", func() {
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", ns, certificatemanager.AllowCACreation())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, cm.KeyPair().Secret(ns))).NotTo(HaveOccurred())
+		caKeyPair := cm.KeyPair().(*certificatemanagement.KeyPair)
+		caCert, err := certificatemanagement.ParseCertificate(caKeyPair.CertificatePEM)
+		Expect(err).NotTo(HaveOccurred())
+
+		// A certificate that is 80% through its lifetime.
+		certPEM, keyPEM, cert, _ := createCertificate(appSecretName, time.Now().Add(-80*time.Hour), time.Now().Add(20*time.Hour), caCert, caKeyPair.PrivateKey.(*rsa.PrivateKey))
+		Expect(cli.Create(ctx, tlsSecret(appSecretName, appNs, certPEM, keyPEM))).NotTo(HaveOccurred())
+
+		By("keeping the key pair while the renewal percentage has not been reached")
+		installation.CertificateRotation = &operatorv1.CertificateRotation{RenewalPercentage: ptr.Int32ToPtr(90)}
+		cm, err = certificatemanager.Create(cli, installation, "cluster.local", ns)
+		Expect(err).NotTo(HaveOccurred())
+		keyPair, err := cm.GetOrCreateKeyPair(cli, appSecretName, appNs, []string{appSecretName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.GetCertificatePEM()).To(Equal(certPEM))
+
+		By("requeuing in time to renew the key pair")
+		renewAt := cert.NotBefore.Add(90 * time.Hour)
+		Expect(cm.NextRotation()).To(BeTemporally("~", renewAt, time.Second))
+		Expect(certificatemanager.RequeueAfter(cm)).To(BeNumerically("~", time.Until(renewAt), time.Second))
+
+		By("renewing the key pair with the default renewal percentage")
+		installation.CertificateRotation = nil
+		cm, err = certificatemanager.Create(cli, installation, "cluster.local", ns)
+		Expect(err).NotTo(HaveOccurred())
+		keyPair, err = cm.GetOrCreateKeyPair(cli, appSecretName, appNs, []string{appSecretName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.GetCertificatePEM()).NotTo(Equal(certPEM))
+		Expect(keyPair.GetIssuer()).To(Equal(cm.KeyPair()))
+		renewed, err := certificatemanagement.ParseCertificate(keyPair.GetCertificatePEM())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renewed.NotAfter).To(BeTemporally(">", time.Now().Add(365*24*time.Hour)))
+	})
+
+	It("should not renew user provided key pairs", func() {
+		_, _, caCert, caKey := createCertificate("byo-ca", time.Now().Add(-time.Hour), time.Now().Add(time.Hour), nil, nil)
+		certPEM, keyPEM, _, _ := createCertificate(appSecretName, time.Now().Add(-80*time.Hour), time.Now().Add(20*time.Hour), caCert, caKey)
+		Expect(cli.Create(ctx, tlsSecret(appSecretName, appNs, certPEM, keyPEM))).NotTo(HaveOccurred())
+
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", ns, certificatemanager.AllowCACreation())
+		Expect(err).NotTo(HaveOccurred())
+		keyPair, err := cm.GetOrCreateKeyPair(cli, appSecretName, appNs, []string{appSecretName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyPair.BYO()).To(BeTrue())
+		Expect(keyPair.GetCertificatePEM()).To(Equal(certPEM))
+	})
+
+	It("should rotate the operator CA and trust the previous CA during the overlap period", func() {
+		oldCAPEM, oldCAKeyPEM, _, _ := createCertificate(rmeta.TigeraOperatorCAIssuerPrefix, time.Now().Add(-80*time.Hour), time.Now().Add(20*time.Hour), nil, nil)
+		Expect(cli.Create(ctx, tlsSecret(certificatemanagement.CASecretName, ns, oldCAPEM, oldCAKeyPEM))).NotTo(HaveOccurred())
+
+		By("not rotating the CA from certificate managers that may not create a CA")
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.KeyPair().GetCertificatePEM()).To(Equal(oldCAPEM))
+
+		By("rotating the CA from the certificate manager that owns it")
+		cm, err = certificatemanager.Create(cli, installation, "cluster.local", ns, certificatemanager.AllowCACreation())
+		Expect(err).NotTo(HaveOccurred())
+		newCAPEM := cm.KeyPair().GetCertificatePEM()
+		Expect(newCAPEM).NotTo(Equal(oldCAPEM))
+		newCA, err := certificatemanagement.ParseCertificate(newCAPEM)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.NextRotation()).To(Equal(newCA.NotBefore.Add(certificatemanager.DefaultCAOverlapDuration)))
+
+		caSecret := cm.KeyPair().Secret(ns)
+		Expect(caSecret.Data[certificatemanagement.PreviousCACertKey]).To(Equal(oldCAPEM))
+
+		bundle := cm.CreateTrustedBundle().ConfigMap(appNs).Data[certificatemanagement.TrustedCertConfigMapKeyName]
+		Expect(bundle).To(ContainSubstring(string(oldCAPEM)))
+		Expect(bundle).To(ContainSubstring(string(newCAPEM)))
+
+		By("keeping the previous CA in the trusted bundle of other certificate managers")
+		Expect(cli.Update(ctx, caSecret)).NotTo(HaveOccurred())
+		cm, err = certificatemanager.Create(cli, installation, "cluster.local", ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.KeyPair().GetCertificatePEM()).To(Equal(newCAPEM))
+		bundle = cm.CreateTrustedBundle().ConfigMap(appNs).Data[certificatemanagement.TrustedCertConfigMapKeyName]
+		Expect(bundle).To(ContainSubstring(string(oldCAPEM)))
+
+		By("dropping the previous CA once the overlap period has passed")
+		installation.CertificateRotation = &operatorv1.CertificateRotation{CAOverlapDuration: &metav1.Duration{Duration: -time.Minute}}
+		cm, err = certificatemanager.Create(cli, installation, "cluster.local", ns, certificatemanager.AllowCACreation())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.KeyPair().GetCertificatePEM()).To(Equal(newCAPEM))
+		Expect(cm.KeyPair().Secret(ns).Data).NotTo(HaveKey(certificatemanagement.PreviousCACertKey))
+		bundle = cm.CreateTrustedBundle().ConfigMap(appNs).Data[certificatemanagement.TrustedCertConfigMapKeyName]
+		Expect(bundle).NotTo(ContainSubstring(string(oldCAPEM)))
+	})
+
+	It("should not rotate a user provided CA", func() {
+		caPEM, caKeyPEM, _, _ := createCertificate("byo-ca", time.Now().Add(-80*time.Hour), time.Now().Add(20*time.Hour), nil, nil)
+		Expect(cli.Create(ctx, tlsSecret(certificatemanagement.CASecretName, ns, caPEM, caKeyPEM))).NotTo(HaveOccurred())
+
+		cm, err := certificatemanager.Create(cli, installation, "cluster.local", ns, certificatemanager.AllowCACreation())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.KeyPair().GetCertificatePEM()).To(Equal(caPEM))
+	})
+
+	It("should report certificates that are about to expire", func() {
+		soonPEM, soonKeyPEM, _, _ := createCertificate("soon", time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), nil, nil)
+		laterPEM, laterKeyPEM, _, _ := createCertificate("later", time.Now().Add(-time.Hour), time.Now().Add(90*24*time.Hour), nil, nil)
+		Expect(cli.Create(ctx, tlsSecret("soon", ns, soonPEM, soonKeyPEM))).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, tlsSecret("later", ns, laterPEM, laterKeyPEM))).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-a-certificate", Namespace: ns},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		})).NotTo(HaveOccurred())
+
+		expiring, err := certificatemanager.ExpiringCertificates(ctx, cli, ns, time.Now().Add(certificatemanager.DefaultExpiryWarningDuration))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiring).To(HaveLen(1))
+		Expect(expiring[0]).To(HavePrefix("Certificate " + ns + "/soon expires at "))
+	})
+})
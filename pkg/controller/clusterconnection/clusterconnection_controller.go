@@ -63,7 +63,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		// No need to start this controller.
 		return nil
 	}
-	statusManager := status.New(mgr.GetClient(), "management-cluster-connection", opts.KubernetesVersion)
+	statusManager := status.NewForManager(mgr, "management-cluster-connection", opts.KubernetesVersion)
 
 	// Create the reconciler
 	tierWatchReady := &utils.ReadyFlag{}
@@ -162,7 +162,7 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("%s failed to watch Installation resource: %w", controllerName, err)
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("%s failed to watch ImageSet: %w", controllerName, err)
 	}
 
@@ -219,7 +219,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&managementClusterConnection.ObjectMeta)
+	defer r.status.SetMetaData(managementClusterConnection)
 
 	// Changes for updating ManagementClusterConnection status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -235,6 +235,11 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	if utils.IsPaused(managementClusterConnection) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	if managementClusterConnection != nil && managementCluster != nil {
 		err = fmt.Errorf("having both a ManagementCluster and a ManagementClusterConnection is not supported")
 		r.status.SetDegraded(operatorv1.ResourceValidationError, "", err, reqLogger)
@@ -259,7 +264,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 		return result, err
 	}
 
-	certificateManager, err := certificatemanager.Create(r.Client, instl, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.Client, instl, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -354,6 +359,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	ch := utils.NewComponentHandler(log, r.Client, r.Scheme, managementClusterConnection)
+	ch.SetController(controllerName)
 	guardianCfg := &render.GuardianConfiguration{
 		URL:               managementClusterConnection.Spec.ManagementClusterAddr,
 		TunnelCAType:      managementClusterConnection.Spec.TLS.CA,
@@ -381,7 +387,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	if err = imageset.ApplyImageSet(ctx, r.Client, variant, components...); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
@@ -65,9 +66,18 @@ var (
 
 // relevantCSR returns true if a csr is relevant to this controller.
 func relevantCSR(csr *certificatesv1.CertificateSigningRequest) bool {
-	if csr.Spec.SignerName != certificatemanager.OperatorCSRSignerName {
-		return false
-	}
+	return csr.Spec.SignerName == certificatemanager.OperatorCSRSignerName && pendingCSR(csr)
+}
+
+// watchedCSR returns true if a csr may need to be approved or signed by this controller. Besides the CSRs for the operator
+// signer, this includes CSRs for other signers that have not been approved yet, since they may be on the allowlist of
+// assets that the operator approves.
+func watchedCSR(csr *certificatesv1.CertificateSigningRequest) bool {
+	return pendingCSR(csr) && (csr.Spec.SignerName == certificatemanager.OperatorCSRSignerName || !approvedCSR(csr))
+}
+
+// pendingCSR returns true if a csr carries our label and has neither been signed, denied nor failed.
+func pendingCSR(csr *certificatesv1.CertificateSigningRequest) bool {
 	if _, found := csr.Labels[LabelName]; !found {
 		return false
 	}
@@ -82,6 +92,16 @@ func relevantCSR(csr *certificatesv1.CertificateSigningRequest) bool {
 	return csr.Status.Certificate == nil
 }
 
+// approvedCSR returns true if a csr has been approved.
+func approvedCSR(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateApproved && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 // Add creates a new CSR Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, opts options.AddOptions) error {
@@ -100,7 +120,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("monitor-controller failed to watch primary resource: %w", err)
 	}
 
-	return utils.AddCSRWatchWithRelevancyFn(ctrl, relevantCSR)
+	return utils.AddCSRWatchWithRelevancyFn(ctrl, watchedCSR)
 }
 
 type tlsAsset struct {
@@ -139,12 +159,30 @@ func allowedAssets(clusterDomain string) map[string]tlsAsset {
 	}
 }
 
+// configuredAssets returns the assets for which the user configured the operator to approve CSRs that use their signer.
+func configuredAssets(cm *operatorv1.CertificateManagement) map[string]tlsAsset {
+	assets := map[string]tlsAsset{}
+	if !cm.UseCSRs() {
+		return assets
+	}
+	for _, approval := range cm.CSRApprovals {
+		assets[approval.SecretName] = tlsAsset{
+			serviceaccountName:      approval.ServiceAccountName,
+			serviceaccountNamespace: approval.ServiceAccountNamespace,
+			validDNSNames:           approval.DNSNames,
+		}
+	}
+	return assets
+}
+
 // blank assignment to verify that ReconcileCompliance implements reconcile.Reconciler
 var _ reconcile.Reconciler = &reconcileCSR{}
 
 // reconcileCSR Components created by the operator may submit certificate signing requests against k8s under certain
 // conditions for signer name "tigera.io/operator-signer". This is the controller that monitors, approves and signs
 // these CSRs. It will only sign requests that are pre-defined and reject others in order to avoid malicious requests.
+// CSRs for the signer that is configured in the Installation's certificate management are approved, but not signed, if
+// they match an asset on the configured allowlist.
 type reconcileCSR struct {
 	client              client.Client
 	scheme              *runtime.Scheme
@@ -167,7 +205,13 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
-	needsCSRRole := instance.Spec.CertificateManagement != nil
+	// Pausing the Installation pauses the approval and signing of CSRs as well.
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
+	needsCSRRole := instance.Spec.CertificateManagement.UseCSRs()
 	if !needsCSRRole && r.enterpriseCRDExists {
 		monitorCR := &operatorv1.Monitor{}
 		if err := r.client.Get(ctx, utils.DefaultTSEEInstanceKey, monitorCR); err != nil {
@@ -180,6 +224,7 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 	}
 
 	componentHandler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	componentHandler.SetController(controllerName)
 	var passthrough render.Component
 	if needsCSRRole {
 		// This controller creates the cluster role for any pod in the cluster that requires certificate management.
@@ -203,66 +248,97 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
-	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger))
+	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger), certificatemanager.WithContext(ctx))
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	// CSRs for the user's signer are approved if they match an asset from the allowlist. They are signed by the signer.
+	userAssets := configuredAssets(instance.Spec.CertificateManagement)
 	for _, csr := range csrList.Items {
-		if !relevantCSR(&csr) {
-			// Not for us, or already signed.
-			continue
-		}
-		reqLogger.V(5).Info("Inspecting CSR with name : %v.", csr.Name)
-		pod, err := r.getPod(ctx, &csr)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		certificateTemplate, err := r.validate(&csr, pod)
-		if err != nil {
-			csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
-				{
-					Type:    certificatesv1.CertificateDenied,
-					Message: err.Error(),
-					Reason:  err.Error(),
-					Status:  corev1.ConditionTrue,
-				},
+		if relevantCSR(&csr) {
+			reqLogger.V(5).Info("Inspecting CSR with name : %v.", csr.Name)
+			pod, err := r.getPod(ctx, &csr)
+			if err != nil {
+				return reconcile.Result{}, err
 			}
-			reqLogger.Error(err, "Rejecting the CSR.")
-			if err = r.client.SubResource("approval").Update(ctx, &csr); err != nil {
+			certificateTemplate, err := r.validate(&csr, pod)
+			if err != nil {
+				if err = r.deny(ctx, &csr, err, reqLogger); err != nil {
+					return reconcile.Result{}, err
+				}
+				continue
+			}
+			if err = r.approve(ctx, &csr, reqLogger); err != nil {
 				return reconcile.Result{}, err
 			}
-			continue
-		}
-		csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
-			{
-				Type:    certificatesv1.CertificateApproved,
-				Message: "Approved",
-				Reason:  "Approved",
-				Status:  corev1.ConditionTrue,
-			},
-		}
-		err = r.client.SubResource("approval").Update(ctx, &csr)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		reqLogger.V(5).Info("Approved CSR with name : %v.", csr.Name)
 
-		certificatePEM, err := certificateManager.SignCertificate(certificateTemplate)
-		if err != nil {
-			reqLogger.Error(err, "error signing certificate request")
-			return reconcile.Result{}, err
-		}
-		csr.Status.Certificate = certificatePEM
-		err = r.client.SubResource("status").Update(ctx, &csr)
-		if err != nil {
-			return reconcile.Result{}, err
+			certificatePEM, err := certificateManager.SignCertificate(certificateTemplate)
+			if err != nil {
+				reqLogger.Error(err, "error signing certificate request")
+				return reconcile.Result{}, err
+			}
+			csr.Status.Certificate = certificatePEM
+			err = r.client.SubResource("status").Update(ctx, &csr)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			reqLogger.V(5).Info("Signed CSR with name : %v.", csr.Name)
+		} else if len(userAssets) > 0 && csr.Spec.SignerName == instance.Spec.CertificateManagement.SignerName && pendingCSR(&csr) && !approvedCSR(&csr) {
+			if _, ok := userAssets[strings.Split(csr.Name, ":")[0]]; !ok {
+				// Not on the allowlist, this is left for the cluster's own approval process.
+				continue
+			}
+			reqLogger.V(5).Info("Inspecting CSR with name : %v.", csr.Name)
+			pod, err := r.getPod(ctx, &csr)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if _, err = validateWithAssets(&csr, pod, userAssets); err != nil {
+				if err = r.deny(ctx, &csr, err, reqLogger); err != nil {
+					return reconcile.Result{}, err
+				}
+				continue
+			}
+			if err = r.approve(ctx, &csr, reqLogger); err != nil {
+				return reconcile.Result{}, err
+			}
 		}
-		reqLogger.V(5).Info("Signed CSR with name : %v.", csr.Name)
 	}
 	return reconcile.Result{}, nil
 }
 
+// approve marks the CSR as approved.
+func (r *reconcileCSR) approve(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, reqLogger logr.Logger) error {
+	csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+		{
+			Type:    certificatesv1.CertificateApproved,
+			Message: "Approved",
+			Reason:  "Approved",
+			Status:  corev1.ConditionTrue,
+		},
+	}
+	if err := r.client.SubResource("approval").Update(ctx, csr); err != nil {
+		return err
+	}
+	reqLogger.V(5).Info("Approved CSR with name : %v.", csr.Name)
+	return nil
+}
+
+// deny marks the CSR as denied, using the validation error as the reason.
+func (r *reconcileCSR) deny(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, validationErr error, reqLogger logr.Logger) error {
+	csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+		{
+			Type:    certificatesv1.CertificateDenied,
+			Message: validationErr.Error(),
+			Reason:  validationErr.Error(),
+			Status:  corev1.ConditionTrue,
+		},
+	}
+	reqLogger.Error(validationErr, "Rejecting the CSR.")
+	return r.client.SubResource("approval").Update(ctx, csr)
+}
+
 // validate Criteria include:
 // - Verify that the x509 request can be parsed and contains one request block.
 // - Verify that the request name matches the deterministic name format that we expect. (More for practical reasons, than for security reasons.)
@@ -272,6 +348,11 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 // - Verify that the public key matches the signature on the CSR for the provider algorithm.
 // - Key usages are fixed, so the CSR won't be able to affect these settings.
 func (r *reconcileCSR) validate(csr *certificatesv1.CertificateSigningRequest, pod *corev1.Pod) (*x509.Certificate, error) {
+	return validateWithAssets(csr, pod, r.allowedTLSAssets)
+}
+
+// validateWithAssets validates the CSR against the given assets. See validate for the criteria.
+func validateWithAssets(csr *certificatesv1.CertificateSigningRequest, pod *corev1.Pod, assets map[string]tlsAsset) (*x509.Certificate, error) {
 	if pod == nil {
 		return nil, fmt.Errorf("invalid: no pod can be associated with CSR %s", csr.Name)
 	}
@@ -299,7 +380,7 @@ func (r *reconcileCSR) validate(csr *certificatesv1.CertificateSigningRequest, p
 	}
 	secretName := nameChunks[0]
 	// Validate whether this is a CSR we monitor at all.
-	asset, ok := r.allowedTLSAssets[secretName]
+	asset, ok := assets[secretName]
 	if !ok {
 		return nil, fmt.Errorf("invalid: this controller is not configured to sign secretName: %s", secretName)
 	}
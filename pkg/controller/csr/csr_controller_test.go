@@ -102,6 +102,19 @@ var _ = Describe("CSR controller tests", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("should not approve or sign CSRs while the Installation is paused", func() {
+			installation.Annotations = map[string]string{common.PausedAnnotation: "true"}
+			Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, validPod())).NotTo(HaveOccurred())
+			csr := validCSR(validX509CR(), validPod())
+			Expect(cli.Create(ctx, csr)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(r.client.Get(ctx, client.ObjectKey{Name: csr.Name}, csr)).NotTo(HaveOccurred())
+			Expect(csr.Status.Conditions).To(BeEmpty())
+			Expect(csr.Status.Certificate).To(BeEmpty())
+		})
+
 		It("should reconcile a submitted CSR", func() {
 			Expect(cli.Create(ctx, validPod())).NotTo(HaveOccurred())
 			csr := validCSR(validX509CR(), validPod())
@@ -156,6 +169,39 @@ var _ = Describe("CSR controller tests", func() {
 			Expect(csr.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
 			Expect(csr.Status.Certificate).To(BeEmpty())
 		})
+
+		It("should approve, but not sign, an allowlisted CSR for the user's signer", func() {
+			installation.Spec.CertificateManagement = &operatorv1.CertificateManagement{
+				CACert:     certificateManager.KeyPair().GetCertificatePEM(),
+				SignerName: "example.com/my-signer",
+				CSRApprovals: []operatorv1.CSRApproval{{
+					SecretName:              "calico-node-prometheus-tls",
+					ServiceAccountName:      "prometheus",
+					ServiceAccountNamespace: "tigera-prometheus",
+					DNSNames:                monitor.PrometheusTLSServerDNSNames(dns.DefaultClusterDomain),
+				}},
+			}
+			Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, validPod())).NotTo(HaveOccurred())
+			csr := validCSR(validX509CR(), validPod())
+			csr.Spec.SignerName = "example.com/my-signer"
+			Expect(cli.Create(ctx, csr)).NotTo(HaveOccurred())
+
+			// A CSR for an asset that is not on the allowlist is left alone.
+			other := validCSR(validX509CR(), validPod())
+			other.Name = "other-tls:prometheus-calico-node-prometheus-0"
+			other.Spec.SignerName = "example.com/my-signer"
+			Expect(cli.Create(ctx, other)).NotTo(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(r.client.Get(ctx, client.ObjectKey{Name: csr.Name}, csr)).NotTo(HaveOccurred())
+			Expect(csr.Status.Conditions).To(HaveLen(1))
+			Expect(csr.Status.Conditions[0].Type).To(Equal(certificatesv1.CertificateApproved))
+			Expect(csr.Status.Certificate).To(BeEmpty())
+			Expect(r.client.Get(ctx, client.ObjectKey{Name: other.Name}, other)).NotTo(HaveOccurred())
+			Expect(other.Status.Conditions).To(BeEmpty())
+		})
 	})
 
 	table.DescribeTable("csr validation", func(csr *certificatesv1.CertificateSigningRequest, pod *corev1.Pod, expectError, expectRelevant bool) {
@@ -50,6 +50,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+const controllerName = "egressgateway-controller"
+
 const (
 	reconcileErr = "Error_reconciling_Egress_Gateway"
 )
@@ -67,7 +69,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
-	c, err := controller.New("egressgateway-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
 	if err != nil {
 		return err
 	}
@@ -80,7 +82,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 
 	go utils.WaitToAddLicenseKeyWatch(c, k8sClient, log, licenseAPIReady)
 
-	return add(mgr, c)
+	return add(c, mgr.GetClient())
 }
 
 // newReconciler returns a new *reconcile.Reconciler.
@@ -89,7 +91,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		client:          mgr.GetClient(),
 		scheme:          mgr.GetScheme(),
 		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "egressgateway", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "egressgateway", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
 		licenseAPIReady: licenseAPIReady,
 		usePSP:          opts.UsePSP,
@@ -102,7 +104,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 // Watching namespaced resources must be avoided as the controller
 // can't differentiate if the request namespaced resource is an
 // Egress Gateway resource or not.
-func add(_ manager.Manager, c controller.Controller) error {
+func add(c controller.Controller, cli client.Client) error {
 	var err error
 
 	// Watch for changes to primary resource Egress Gateway.
@@ -111,7 +113,7 @@ func add(_ manager.Manager, c controller.Controller) error {
 		return err
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, cli); err != nil {
 		return fmt.Errorf("egressgateway-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -161,6 +163,7 @@ func (r *ReconcileEgressGateway) Reconcile(ctx context.Context, request reconcil
 
 	// If there are no Egress Gateway resources, return.
 	ch := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+	ch.SetController(controllerName)
 	if len(egws) == 0 {
 		var objects []client.Object
 		if r.provider == operatorv1.ProviderOpenShift {
@@ -357,6 +360,12 @@ func (r *ReconcileEgressGateway) reconcileEgressGateway(ctx context.Context, egw
 	variant operatorv1.ProductVariant, fc *crdv1.FelixConfiguration, pullSecrets []*v1.Secret,
 	installation *operatorv1.InstallationSpec, namespaceAndNames []string,
 ) error {
+	// A paused egress gateway is left as it is, without affecting the others.
+	if utils.IsPaused(egw) {
+		reqLogger.Info("Reconciliation is paused", "Name", egw.Name, "Namespace", egw.Namespace)
+		return nil
+	}
+
 	preDefaultPatchFrom := client.MergeFrom(egw.DeepCopy())
 	// update the EGW resource with default values.
 	fillDefaults(egw, installation)
@@ -414,10 +423,11 @@ func (r *ReconcileEgressGateway) reconcileEgressGateway(ctx context.Context, egw
 
 	component := egressgateway.EgressGateway(config)
 	ch := utils.NewComponentHandler(log, r.client, r.scheme, egw)
+	ch.SetController(controllerName)
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		setDegraded(r.client, ctx, egw, reconcileErr, fmt.Sprintf("Error with images from ImageSet err = %s", err.Error()))
 		return err
 	}
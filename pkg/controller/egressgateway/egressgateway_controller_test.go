@@ -30,6 +30,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
@@ -47,7 +48,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -827,6 +827,54 @@ var _ = Describe("Egress Gateway controller tests", func() {
 
 		})
 
+		It("should leave a paused egress gateway as it is", func() {
+			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("AddDeployments", mock.Anything).Return()
+			mockStatus.On("IsAvailable").Return(true)
+			mockStatus.On("AddStatefulSets", mock.Anything).Return()
+			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("OnCRNotFound").Return()
+			mockStatus.On("ClearDegraded")
+			mockStatus.On("SetDegraded", "Waiting for LicenseKeyAPI to be ready", "").Return().Maybe()
+			mockStatus.On("ReadyToMonitor")
+			Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+			var replicas int32 = 2
+			logSeverity := operatorv1.LogLevelInfo
+			egw := &operatorv1.EgressGateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "calico-red",
+					Namespace:   "calico-egress",
+					Annotations: map[string]string{common.PausedAnnotation: "true"},
+				},
+				Spec: operatorv1.EgressGatewaySpec{
+					Replicas:         &replicas,
+					LogSeverity:      &logSeverity,
+					IPPools:          []operatorv1.EgressGatewayIPPool{{Name: "ippool-1"}},
+					ExternalNetworks: []string{"one"},
+				},
+				Status: operatorv1.EgressGatewayStatus{
+					State: operatorv1.TigeraStatusReady,
+				},
+			}
+			Expect(c.Create(ctx, egw)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			dep := appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "calico-red", Namespace: "calico-egress"},
+			}
+			Expect(test.GetResource(c, &dep)).NotTo(BeNil())
+
+			By("resuming reconciliation")
+			Expect(c.Get(ctx, types.NamespacedName{Name: "calico-red", Namespace: "calico-egress"}, egw)).NotTo(HaveOccurred())
+			egw.Annotations = nil
+			Expect(c.Update(ctx, egw)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(test.GetResource(c, &dep)).To(BeNil())
+		})
+
 		It("should wait for correct calico version before reconciling EGW", func() {
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
@@ -896,8 +944,7 @@ var _ = Describe("Egress Gateway controller tests", func() {
 
 		It("should not watch namespaced resources", func() {
 			m := &mockController{}
-			var mgr manager.Manager
-			err := add(mgr, m)
+			err := add(m, c)
 			Expect(err).ShouldNot(HaveOccurred())
 			for _, watch := range m.watches {
 				kind := watch.(*source.Kind)
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+// Webhook defaults and validates EgressGateways at admission time, in the same way as the controller does during
+// reconcile.
+type Webhook struct {
+	Client client.Client
+}
+
+func (w *Webhook) Default(ctx context.Context, obj runtime.Object) error {
+	egw, ok := obj.(*operatorv1.EgressGateway)
+	if !ok {
+		return fmt.Errorf("expected an EgressGateway but got %T", obj)
+	}
+	installation, err := w.installation(ctx)
+	if err != nil {
+		return err
+	}
+	fillDefaults(egw, installation)
+	return nil
+}
+
+func (w *Webhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *Webhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *Webhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *Webhook) validate(ctx context.Context, obj runtime.Object) error {
+	egw, ok := obj.(*operatorv1.EgressGateway)
+	if !ok {
+		return fmt.Errorf("expected an EgressGateway but got %T", obj)
+	}
+	egw = egw.DeepCopy()
+	if err := w.Default(ctx, egw); err != nil {
+		return err
+	}
+	return validateEgressGateway(ctx, w.Client, egw)
+}
+
+// installation returns the spec of the default Installation, or an empty spec if there isn't one yet.
+func (w *Webhook) installation(ctx context.Context) (*operatorv1.InstallationSpec, error) {
+	instance := &operatorv1.Installation{}
+	if err := w.Client.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return &operatorv1.InstallationSpec{}, nil
+		}
+		return nil, err
+	}
+	return &instance.Spec, nil
+}
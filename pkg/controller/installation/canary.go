@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+)
+
+// canaryPromoter decides when the canary calico-node pods of an ImageSetCanary have been available for long enough
+// to be promoted. How long they have been available is only kept in memory, so a restart of the operator restarts
+// the wait.
+type canaryPromoter struct {
+	// imageSet is the canary ImageSet that healthySince applies to.
+	imageSet string
+
+	// healthySince is when every canary pod was first seen available, or zero if they are not all available.
+	healthySince time.Time
+
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+}
+
+func newCanaryPromoter() *canaryPromoter {
+	return &canaryPromoter{now: time.Now}
+}
+
+// canaryPromoted returns true if the Installation's canary has already been promoted.
+func canaryPromoted(instance *operator.Installation) bool {
+	canary := instance.Spec.ImageSetCanary
+	return canary != nil && instance.Status.PromotedImageSetCanary == canary.ImageSet
+}
+
+// check returns true if the canary should be promoted. Otherwise it returns how long to wait before checking again,
+// or zero if the canary pods are not all available, as the change to the canary DaemonSet reconciles the Installation.
+// It must be called after the canary DaemonSet has been written, so that a change to its pods restarts the wait.
+func (p *canaryPromoter) check(ctx context.Context, cli client.Client, canary *operator.ImageSetCanary) (bool, time.Duration, error) {
+	if canary == nil || canary.PromoteAfter == nil {
+		p.imageSet, p.healthySince = "", time.Time{}
+		return false, 0, nil
+	}
+	if p.imageSet != canary.ImageSet {
+		p.imageSet, p.healthySince = canary.ImageSet, time.Time{}
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: common.NodeCanaryDaemonSetName, Namespace: common.CalicoNamespace}, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			p.healthySince = time.Time{}
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	if !daemonSetAvailable(ds) {
+		p.healthySince = time.Time{}
+		return false, 0, nil
+	}
+
+	now := p.now()
+	if p.healthySince.IsZero() {
+		p.healthySince = now
+	}
+	if remaining := canary.PromoteAfter.Duration - now.Sub(p.healthySince); remaining > 0 {
+		return false, remaining, nil
+	}
+	return true, 0, nil
+}
+
+// daemonSetAvailable returns true if the DaemonSet has rolled out its current template and every one of its pods
+// is available.
+func daemonSetAvailable(ds *appsv1.DaemonSet) bool {
+	s := ds.Status
+	return s.ObservedGeneration >= ds.Generation &&
+		s.DesiredNumberScheduled > 0 &&
+		s.UpdatedNumberScheduled == s.DesiredNumberScheduled &&
+		s.NumberAvailable == s.DesiredNumberScheduled &&
+		s.NumberUnavailable == 0
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+)
+
+var _ = Describe("ImageSet canary promotion", func() {
+	var cli client.Client
+	var ctx context.Context
+	var p *canaryPromoter
+	var now time.Time
+	var canary *operator.ImageSetCanary
+	var ds *appsv1.DaemonSet
+
+	setAvailable := func(available int32) {
+		ds.Status = appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 2,
+			UpdatedNumberScheduled: 2,
+			NumberAvailable:        available,
+			NumberUnavailable:      2 - available,
+		}
+		Expect(cli.Update(ctx, ds)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+
+		now = time.Now()
+		p = newCanaryPromoter()
+		p.now = func() time.Time { return now }
+
+		canary = &operator.ImageSetCanary{
+			ImageSet:     "calico-canary",
+			NodeSelector: map[string]string{"canary": "true"},
+			PromoteAfter: &metav1.Duration{Duration: 10 * time.Minute},
+		}
+		ds = &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: common.NodeCanaryDaemonSetName, Namespace: common.CalicoNamespace}}
+		Expect(cli.Create(ctx, ds)).To(Succeed())
+	})
+
+	It("should promote once the canary pods have been available for PromoteAfter", func() {
+		setAvailable(2)
+		promote, wait, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(Equal(10 * time.Minute))
+
+		now = now.Add(4 * time.Minute)
+		promote, wait, err = p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(Equal(6 * time.Minute))
+
+		now = now.Add(6 * time.Minute)
+		promote, _, err = p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeTrue())
+	})
+
+	It("should restart the wait when a canary pod becomes unavailable", func() {
+		setAvailable(2)
+		_, _, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(8 * time.Minute)
+		setAvailable(1)
+		promote, wait, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(BeZero())
+
+		now = now.Add(8 * time.Minute)
+		setAvailable(2)
+		promote, wait, err = p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(Equal(10 * time.Minute))
+	})
+
+	It("should restart the wait when the canary ImageSet changes", func() {
+		setAvailable(2)
+		_, _, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(10 * time.Minute)
+		canary.ImageSet = "calico-canary-2"
+		promote, wait, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(Equal(10 * time.Minute))
+	})
+
+	It("should not promote without PromoteAfter", func() {
+		setAvailable(2)
+		canary.PromoteAfter = nil
+		now = now.Add(time.Hour)
+		promote, wait, err := p.check(ctx, cli, canary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(promote).To(BeFalse())
+		Expect(wait).To(BeZero())
+	})
+
+	It("should report whether the Installation's canary has been promoted", func() {
+		instance := &operator.Installation{Spec: operator.InstallationSpec{ImageSetCanary: canary}}
+		Expect(canaryPromoted(instance)).To(BeFalse())
+		instance.Status.PromotedImageSetCanary = "calico-canary"
+		Expect(canaryPromoted(instance)).To(BeTrue())
+		canary.ImageSet = "calico-canary-2"
+		Expect(canaryPromoted(instance)).To(BeFalse())
+		instance.Spec.ImageSetCanary = nil
+		Expect(canaryPromoted(instance)).To(BeFalse())
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 
@@ -82,6 +83,8 @@ import (
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 )
 
+const controllerName = "tigera-installation-controller"
+
 const (
 	techPreviewFeatureSeccompApparmor = "tech-preview.operator.tigera.io/node-apparmor-profile"
 
@@ -130,7 +133,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("failed to create Core Reconciler: %w", err)
 	}
 
-	c, err := controller.New("tigera-installation-controller", mgr, controller.Options{Reconciler: ri})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: ri})
 	if err != nil {
 		return fmt.Errorf("Failed to create tigera-installation-controller: %w", err)
 	}
@@ -162,7 +165,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileInst
 		return nil, fmt.Errorf("Failed to initialize Namespace migration: %w", err)
 	}
 
-	statusManager := status.New(mgr.GetClient(), "calico", opts.KubernetesVersion)
+	statusManager := status.NewForManager(mgr, "calico", opts.KubernetesVersion)
 
 	// The typhaAutoscaler needs a clientset.
 	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
@@ -183,20 +186,26 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileInst
 		config:               mgr.GetConfig(),
 		client:               mgr.GetClient(),
 		scheme:               mgr.GetScheme(),
+		apiReader:            mgr.GetAPIReader(),
 		watches:              make(map[runtime.Object]struct{}),
 		autoDetectedProvider: opts.DetectedProvider,
 		status:               statusManager,
 		typhaAutoscaler:      typhaScaler,
+		nodeUpgrader:         newNodeUpgrader(cs, mgr.GetClient(), statusManager),
+		canaryPromoter:       newCanaryPromoter(),
+		driftChecker:         newDriftChecker(),
 		namespaceMigration:   nm,
 		amazonCRDExists:      opts.AmazonCRDExists,
 		enterpriseCRDsExist:  opts.EnterpriseCRDExists,
 		clusterDomain:        opts.ClusterDomain,
 		manageCRDs:           opts.ManageCRDs,
 		usePSP:               opts.UsePSP,
+		csiServerSideApply:   opts.ServerSideApply(operator.ServerSideApplyComponentCSI),
 		tierWatchReady:       &utils.ReadyFlag{},
 	}
 	r.status.Run(opts.ShutdownContext)
 	r.typhaAutoscaler.start(opts.ShutdownContext)
+	r.nodeUpgrader.start(opts.ShutdownContext)
 	return r, nil
 }
 
@@ -251,7 +260,7 @@ func add(c controller.Controller, r *ReconcileInstallation) error {
 		}
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, r.client); err != nil {
 		return fmt.Errorf("tigera-installation-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -361,6 +370,9 @@ type ReconcileInstallation struct {
 	autoDetectedProvider operator.Provider
 	status               status.StatusManager
 	typhaAutoscaler      *typhaAutoscaler
+	nodeUpgrader         *nodeUpgrader
+	canaryPromoter       *canaryPromoter
+	driftChecker         *driftChecker
 	namespaceMigration   migration.NamespaceMigration
 	enterpriseCRDsExist  bool
 	amazonCRDExists      bool
@@ -368,17 +380,22 @@ type ReconcileInstallation struct {
 	clusterDomain        string
 	manageCRDs           bool
 	usePSP               bool
+	csiServerSideApply   bool
 	tierWatchReady       *utils.ReadyFlag
+
+	// apiReader reads from the API server rather than the cache, for objects that the controller doesn't watch.
+	apiReader client.Reader
 }
 
 // updateInstallationWithDefaults returns the default installation instance with defaults populated.
-func updateInstallationWithDefaults(ctx context.Context, client client.Client, instance *operator.Installation, provider operator.Provider) error {
+func updateInstallationWithDefaults(ctx context.Context, client client.Client, instance *operator.Installation, provider operator.Provider, sources *fieldSources) error {
 	// Determine the provider in use by combining any auto-detected value with any value
 	// specified in the Installation CR. mergeProvider updates the CR with the correct value.
 	err := mergeProvider(instance, provider)
 	if err != nil {
 		return err
 	}
+	sources.record(operator.FieldSourceDetected, &instance.Spec)
 
 	var openshiftConfig *configv1.Network
 	var kubeadmConfig *corev1.ConfigMap
@@ -411,7 +428,7 @@ func updateInstallationWithDefaults(ctx context.Context, client client.Client, i
 		awsNode = nil
 	}
 
-	err = mergeAndFillDefaults(instance, openshiftConfig, kubeadmConfig, awsNode)
+	err = mergeAndFillDefaults(instance, openshiftConfig, kubeadmConfig, awsNode, sources)
 	if err != nil {
 		return err
 	}
@@ -419,26 +436,52 @@ func updateInstallationWithDefaults(ctx context.Context, client client.Client, i
 }
 
 // mergeAndFillDefaults merges in configuration from the Kubernetes provider, if applicable, and then
-// populates defaults in the Installation instance.
-func mergeAndFillDefaults(i *operator.Installation, o *configv1.Network, kubeadmConfig *corev1.ConfigMap, awsNode *appsv1.DaemonSet) error {
+// populates defaults in the Installation instance. The source of each value is recorded in sources, if set.
+func mergeAndFillDefaults(i *operator.Installation, o *configv1.Network, kubeadmConfig *corev1.ConfigMap, awsNode *appsv1.DaemonSet, sources *fieldSources) error {
 	if o != nil {
 		// Merge in OpenShift configuration.
 		if err := updateInstallationForOpenshiftNetwork(i, o); err != nil {
 			return fmt.Errorf("Could not resolve CalicoNetwork IPPool and OpenShift network: %s", err.Error())
 		}
+		sources.record(operator.FieldSourceOpenShift, &i.Spec)
 	} else if kubeadmConfig != nil {
 		// Merge in kubeadm configuration.
 		if err := updateInstallationForKubeadm(i, kubeadmConfig); err != nil {
 			return fmt.Errorf("Could not resolve CalicoNetwork IPPool and kubeadm configuration: %s", err.Error())
 		}
+		sources.record(operator.FieldSourceKubeadm, &i.Spec)
 	}
 	if awsNode != nil {
 		if err := updateInstallationForAWSNode(i, awsNode); err != nil {
 			return fmt.Errorf("Could not resolve AWS node configuration: %s", err.Error())
 		}
+		sources.record(operator.FieldSourceEKS, &i.Spec)
 	}
 
-	return fillDefaults(i)
+	if err := fillDefaults(i); err != nil {
+		return err
+	}
+	sources.record(operator.FieldSourceDefault, &i.Spec)
+	return nil
+}
+
+// FillDefaults populates the default values onto an Installation object without merging in any configuration
+// detected from the cluster. It is intended for rendering an Installation outside of a running cluster.
+func FillDefaults(instance *operator.Installation) error {
+	if err := fillDefaults(instance); err != nil {
+		return err
+	}
+	return validateCustomResource(instance)
+}
+
+// MergeOverlay merges the spec of the 'overlay' Installation over the defaulted instance, as the core controller does
+// before rendering, and validates the computed config.
+func MergeOverlay(instance, overlay *operator.Installation) error {
+	instance.Spec = utils.OverrideInstallationSpec(instance.Spec, overlay.Spec)
+	if err := validateCustomResource(instance); err != nil {
+		return fmt.Errorf("invalid computed config: %w", err)
+	}
+	return nil
 }
 
 // fillDefaults populates the default values onto an Installation object.
@@ -815,11 +858,13 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		reqLogger.Info("Installation object is terminating")
 	}
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
+	// The spec as set by the user, which is what a snapshot of the configuration restores.
+	userSpec := instance.Spec.DeepCopy()
 
 	// Mark CR found so we can report converter problems via tigerastatus
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating Installation status conditions.
 	if request.Name == InstallationName && request.Namespace == "" {
@@ -835,7 +880,44 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		// Stop replacing calico-node pods and scaling Typha until reconciliation is resumed.
+		r.nodeUpgrader.setConfig(nil)
+		r.typhaAutoscaler.setPaused(true)
+		return reconcile.Result{}, nil
+	}
+
+	// While plan mode is enabled, the changes to the rendered objects are only recorded in OperatorPlans, so nothing
+	// else that changes the cluster is written either. The plans are deleted once plan mode is disabled, as they no
+	// longer describe the changes the operator will make.
+	planMode := utils.IsPlanModeEnabled(instance)
+	if !planMode {
+		if err := utils.DeleteOperatorPlans(ctx, r.client); err != nil {
+			r.status.SetDegraded(operator.ResourceUpdateError, "Failed to delete OperatorPlans", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Reapply a snapshot of a previously applied configuration if a rollback has been requested. The Installation is
+	// reconciled again once it has been updated. A rollback requested in plan mode waits for it to be disabled.
+	if revision, ok := instance.Annotations[RollbackAnnotation]; ok && !terminating && !planMode {
+		if err := rollbackToSnapshot(ctx, r.client, instance, revision); err != nil {
+			if errors.As(err, &errSnapshotNotFound{}) || errors.As(err, &errInvalidSnapshot{}) {
+				r.status.SetDegraded(operator.InvalidConfigurationError, "Unable to roll back Installation", err, reqLogger)
+				return reconcile.Result{}, nil
+			}
+			r.status.SetDegraded(operator.ResourceUpdateError, "Failed to roll back Installation", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("Rolled back Installation to snapshot", "revision", revision)
+		return reconcile.Result{}, nil
+	}
+
 	instanceStatus := instance.Status
+
+	// Track where each value in the computed spec comes from, for the Installation's report.
+	sources := newFieldSources(&instance.Spec, instance.Status.Report)
 	if !r.migrationChecked {
 		// update Installation resource with existing install if it exists.
 		nc, err := convert.NeedsConversion(ctx, r.client)
@@ -856,11 +938,12 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 				return reconcile.Result{}, err
 			}
 			instance.Spec = utils.OverrideInstallationSpec(install.Spec, instance.Spec)
+			sources.record(operator.FieldSourceMigration, &instance.Spec)
 		}
 	}
 
 	// update Installation with defaults
-	if err := updateInstallationWithDefaults(ctx, r.client, instance, r.autoDetectedProvider); err != nil {
+	if err := updateInstallationWithDefaults(ctx, r.client, instance, r.autoDetectedProvider, sources); err != nil {
 		r.status.SetDegraded(operator.ResourceReadError, "Error querying installation", err, reqLogger)
 		return reconcile.Result{}, err
 	}
@@ -931,6 +1014,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		reqLogger.V(5).Info("no 'overlay' installation found")
 	} else {
 		instance.Spec = utils.OverrideInstallationSpec(instance.Spec, overlay.Spec)
+		sources.record(operator.FieldSourceOverlay, &instance.Spec)
 		reqLogger.V(2).Info("loaded final computed config", "config", instance)
 
 		// Validate the configuration.
@@ -959,6 +1043,22 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// Pass the latest autoscaling configuration to the typha autoscaler, it is used from its next run.
+	r.typhaAutoscaler.setConfig(instance.Spec.TyphaDeployment.GetAutoscaling(), instance.Spec.TyphaMetricsPort)
+	r.typhaAutoscaler.setPaused(planMode)
+
+	// Pass the latest staged upgrade configuration to the node upgrader. As when reconciliation is paused, no calico-node
+	// pods are replaced and the rollback recorded on the Installation is left alone while plan mode is enabled.
+	if planMode {
+		r.nodeUpgrader.setConfig(nil)
+	} else {
+		r.nodeUpgrader.setConfig(instance.Spec.NodeUpgrade)
+		if err := r.nodeUpgrader.syncRollback(ctx, instance); err != nil {
+			r.status.SetDegraded(operator.ResourceUpdateError, "Failed to sync the calico-node rollback", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
 	// If the autoscalar is degraded then trigger a run and recheck the degraded status. If it is still degraded after the
 	// the run the reset the degraded status and requeue the request.
 	if r.typhaAutoscaler.isDegraded() {
@@ -1060,7 +1160,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
-	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger))
+	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operator.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -1336,6 +1436,16 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// Fetch the ImageSet for the canary nodes, if calico-node is being rolled out to them first.
+	var canaryImageSet *operator.ImageSet
+	if canary := instance.Spec.ImageSetCanary; canary != nil && !terminating {
+		canaryImageSet, err = imageset.GetCanaryImageSet(ctx, r.client, canary.ImageSet)
+		if err != nil {
+			r.status.SetDegraded(operator.ImageSetError, "Error with canary ImageSet", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Build a configuration for rendering calico/node.
 	nodeCfg := render.NodeConfiguration{
 		K8sServiceEp:            k8sapi.Endpoint,
@@ -1354,14 +1464,23 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		FelixHealthPort:         *felixConfiguration.Spec.HealthPort,
 		BindMode:                bgpConfiguration.Spec.BindMode,
 		UsePSP:                  r.usePSP,
+		CanaryImageSet:          canaryImageSet,
+		CanaryPromoted:          canaryPromoted(instance),
+	}
+	var nodeComponent render.Component = render.Node(&nodeCfg)
+	var nodeUpgrade *nodeUpgradeComponent
+	if instance.Spec.NodeUpgrade != nil {
+		nodeUpgrade = &nodeUpgradeComponent{Component: nodeComponent}
+		nodeComponent = nodeUpgrade
 	}
-	components = append(components, render.Node(&nodeCfg))
+	components = append(components, nodeComponent)
 
 	csiCfg := render.CSIConfiguration{
-		Installation: &instance.Spec,
-		Terminating:  terminating,
-		UsePSP:       r.usePSP,
-		OpenShift:    instance.Spec.KubernetesProvider == operator.ProviderOpenShift,
+		Installation:    &instance.Spec,
+		Terminating:     terminating,
+		UsePSP:          r.usePSP,
+		OpenShift:       instance.Spec.KubernetesProvider == operator.ProviderOpenShift,
+		ServerSideApply: r.csiServerSideApply,
 	}
 	components = append(components, render.CSI(&csiCfg))
 
@@ -1403,13 +1522,38 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	if err = imageset.VerifyImageSet(ctx, r.client, imageSet); err != nil {
+		r.status.SetDegraded(operator.ImageSetError, "Error verifying ImageSet signature", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	if err = imageset.ResolveImages(imageSet, components...); err != nil {
 		r.status.SetDegraded(operator.ResourceValidationError, "Error resolving ImageSet for components", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
+	// Tell the upgrader which calico-node template is rendered, once, before the node component's objects are used.
+	if nodeUpgrade != nil {
+		nodeUpgrade.recordRendered(r.nodeUpgrader)
+	}
+
+	// Compare the rendered objects with the cluster before they are written, so that the report shows the objects that
+	// were changed since the last time they were written. Between checks, the report keeps the last check's result.
+	var rendered []client.Object
+	for _, component := range components {
+		objsToCreate, _ := component.Objects()
+		rendered = append(rendered, objsToCreate...)
+	}
+	var drifted []operator.DriftedObject
+	if r.driftChecker.due() {
+		drifted = findDrift(ctx, r.apiReader, r.scheme, rendered)
+	} else if instance.Status.Report != nil {
+		drifted = instance.Status.Report.DriftedObjects
+	}
+
 	// Create a component handler to create or update the rendered components.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetController(controllerName)
 	for _, component := range components {
 		if err := handler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
 			r.status.SetDegraded(operator.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
@@ -1420,9 +1564,34 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	// TODO: We handle too many components in this controller at the moment. Once we are done consolidating,
 	// we can have the CreateOrUpdate logic handle this for us.
 	r.status.AddDaemonsets([]types.NamespacedName{{Name: "calico-node", Namespace: "calico-system"}})
+	canaryDaemonSet := types.NamespacedName{Name: common.NodeCanaryDaemonSetName, Namespace: common.CalicoNamespace}
+	if canaryImageSet != nil && !canaryPromoted(instance) {
+		r.status.AddDaemonsets([]types.NamespacedName{canaryDaemonSet})
+	} else {
+		r.status.RemoveDaemonsets(canaryDaemonSet)
+	}
 	r.status.AddDeployments([]types.NamespacedName{{Name: "calico-kube-controllers", Namespace: "calico-system"}})
 	certificateManager.AddToStatusManager(r.status, common.CalicoNamespace)
 
+	// Promote the canary once its pods have been available for long enough. The promotion is recorded in the status
+	// below, and the next reconcile renders calico-node with the canary's images.
+	promotedCanary := ""
+	var canaryRequeue time.Duration
+	if canaryPromoted(instance) {
+		promotedCanary = instance.Spec.ImageSetCanary.ImageSet
+	} else if canaryImageSet != nil && !planMode {
+		promote, wait, err := r.canaryPromoter.check(ctx, r.client, instance.Spec.ImageSetCanary)
+		if err != nil {
+			r.status.SetDegraded(operator.ResourceReadError, "Error checking the canary calico-node DaemonSet", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		if promote {
+			reqLogger.Info("Promoting the canary ImageSet to every node", "imageSet", canaryImageSet.Name)
+			promotedCanary = canaryImageSet.Name
+		}
+		canaryRequeue = wait
+	}
+
 	// Run this after we have rendered our components so the new (operator created)
 	// Deployments and Daemonset exist with our special migration nodeSelectors.
 	if needNsMigration {
@@ -1499,6 +1668,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	}
 
 	// Write updated status.
+	statusBefore := instance.Status.DeepCopy()
 	if statusMTU > math.MaxInt32 || statusMTU < 0 {
 		return reconcile.Result{}, errors.New("The MTU size should be between Max int32 (2147483647) and 0")
 	}
@@ -1511,13 +1681,47 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	} else {
 		instance.Status.ImageSet = imageSet.Name
 	}
+	instance.Status.NodeUpgrade = r.nodeUpgrader.getStatus()
+	instance.Status.PromotedImageSetCanary = promotedCanary
+	instance.Status.Report = &operator.InstallationReport{
+		FieldSources:   sources.list(),
+		DriftedObjects: drifted,
+	}
 	instance.Status.Computed = &instance.Spec
-	if err = r.client.Status().Update(ctx, instance); err != nil {
+	if planMode {
+		// The status describes the configuration that has been applied, which plan mode doesn't do.
+		reqLogger.V(1).Info("Plan mode is enabled, not updating the Installation status or recording a snapshot")
+		return reconcile.Result{RequeueAfter: certificatemanager.RequeueAfter(certificateManager)}, nil
+	}
+	if statusChanged(statusBefore, &instance.Status) {
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Once the configuration has rolled out and is healthy, keep a snapshot of it that can be rolled back to. The
+	// rollout changes the TigeraStatus as it progresses, which reconciles the Installation again, so there is no need
+	// to requeue for it.
+	done, err := rolledOut(ctx, r.client, rendered)
+	if err != nil {
+		reqLogger.Error(err, "Failed to check whether the Installation has rolled out")
+		return reconcile.Result{}, err
+	}
+	if !done {
+		reqLogger.V(1).Info("Waiting for the Installation to roll out before recording a snapshot")
+	} else if r.status.IsDegraded() {
+		reqLogger.V(1).Info("Not recording a snapshot while the Installation is degraded")
+	} else if err = recordSnapshot(ctx, r.client, userSpec, &instance.Spec, imageSet); err != nil {
+		reqLogger.Error(err, "Failed to record Installation snapshot")
 		return reconcile.Result{}, err
 	}
 
 	reqLogger.V(1).Info("Finished reconciling Installation")
-	return reconcile.Result{}, nil
+	requeueAfter := certificatemanager.RequeueAfter(certificateManager)
+	if canaryRequeue > 0 && (requeueAfter == 0 || canaryRequeue < requeueAfter) {
+		requeueAfter = canaryRequeue
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func readMTUFile() (int, error) {
@@ -1742,6 +1946,7 @@ func (r *ReconcileInstallation) updateCRDs(ctx context.Context, variant operator
 	// Specify nil for the CR so no ownership is put on the CRDs. We do this so removing the
 	// Installation CR will not remove the CRDs.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+	handler.SetController(controllerName)
 	if err := handler.CreateOrUpdateOrDelete(ctx, crdComponent, nil); err != nil {
 		r.status.SetDegraded(operator.ResourceUpdateError, "Error creating / updating CRD resource", err, log)
 		return err
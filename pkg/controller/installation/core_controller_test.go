@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -32,10 +33,12 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -126,9 +129,9 @@ var _ = Describe("Testing core-controller installation", func() {
 	table.DescribeTable("Installation and Openshift should be merged and defaulted by mergeAndFillDefaults",
 		func(i *operator.Installation, on *osconfigv1.Network, expectSuccess bool, calicoNet *operator.CalicoNetworkSpec) {
 			if expectSuccess {
-				Expect(mergeAndFillDefaults(i, on, nil, nil)).To(BeNil())
+				Expect(mergeAndFillDefaults(i, on, nil, nil, nil)).To(BeNil())
 			} else {
-				Expect(mergeAndFillDefaults(i, on, nil, nil)).ToNot(BeNil())
+				Expect(mergeAndFillDefaults(i, on, nil, nil, nil)).ToNot(BeNil())
 				return
 			}
 
@@ -334,8 +337,9 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(operator.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 			Expect(storagev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 
-			// Create a client that will have a crud interface of k8s objects.
-			c = fake.NewClientBuilder().WithScheme(scheme).Build()
+			// Create a client that will have a crud interface of k8s objects, and that emulates server-side apply for the
+			// components that use it.
+			c = &utils.ApplyClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
 			ctx, cancel = context.WithCancel(context.Background())
 
 			// Create a fake clientset for the autoscaler.
@@ -360,6 +364,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the compliance reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
@@ -388,11 +393,13 @@ var _ = Describe("Testing core-controller installation", func() {
 				autoDetectedProvider: operator.ProviderNone,
 				status:               mockStatus,
 				typhaAutoscaler:      newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
+				nodeUpgrader:         newNodeUpgrader(cs, c, mockStatus),
 				namespaceMigration:   &fakeNamespaceMigration{},
 				amazonCRDExists:      true,
 				enterpriseCRDsExist:  true,
 				migrationChecked:     true,
 				tierWatchReady:       ready,
+				apiReader:            c,
 			}
 
 			r.typhaAutoscaler.start(ctx)
@@ -411,7 +418,7 @@ var _ = Describe("Testing core-controller installation", func() {
 					Spec: operator.InstallationSpec{
 						Variant:               operator.TigeraSecureEnterprise,
 						Registry:              "some.registry.org/",
-						CertificateManagement: &operator.CertificateManagement{CACert: prometheusTLS.GetCertificatePEM()},
+						CertificateManagement: &operator.CertificateManagement{SignerName: "a.b/c", CACert: prometheusTLS.GetCertificatePEM()},
 					},
 					Status: operator.InstallationStatus{
 						Variant: operator.TigeraSecureEnterprise,
@@ -648,7 +655,7 @@ var _ = Describe("Testing core-controller installation", func() {
 					KubernetesProvider: operator.ProviderDockerEE,
 				},
 			}
-			Expect(mergeAndFillDefaults(installation, nil, nil, nil)).To(BeNil())
+			Expect(mergeAndFillDefaults(installation, nil, nil, nil, nil)).To(BeNil())
 			Expect(installation.Spec.CalicoNetwork.NodeAddressAutodetectionV4.SkipInterface).Should(Equal("^br-.*"))
 		})
 	})
@@ -660,7 +667,7 @@ var _ = Describe("Testing core-controller installation", func() {
 					KubernetesProvider: provider,
 				},
 			}
-			Expect(mergeAndFillDefaults(installation, nil, nil, nil)).To(BeNil())
+			Expect(mergeAndFillDefaults(installation, nil, nil, nil, nil)).To(BeNil())
 			if expected {
 				Expect(installation.Spec.TyphaAffinity).ToNot(BeNil())
 				Expect(installation.Spec.TyphaAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).Should(Equal(result))
@@ -716,8 +723,9 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(operator.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 			Expect(storagev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 
-			// Create a client that will have a crud interface of k8s objects.
-			c = fake.NewClientBuilder().WithScheme(scheme).Build()
+			// Create a client that will have a crud interface of k8s objects, and that emulates server-side apply for the
+			// components that use it.
+			c = &utils.ApplyClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
 			ctx, cancel = context.WithCancel(context.Background())
 
 			// Create a fake clientset for the autoscaler.
@@ -744,6 +752,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the compliance reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
@@ -772,12 +781,14 @@ var _ = Describe("Testing core-controller installation", func() {
 				autoDetectedProvider: operator.ProviderNone,
 				status:               mockStatus,
 				typhaAutoscaler:      newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
+				nodeUpgrader:         newNodeUpgrader(cs, c, mockStatus),
 				namespaceMigration:   &fakeNamespaceMigration{},
 				amazonCRDExists:      true,
 				enterpriseCRDsExist:  true,
 				migrationChecked:     true,
 				clusterDomain:        dns.DefaultClusterDomain,
 				tierWatchReady:       ready,
+				apiReader:            c,
 			}
 			r.typhaAutoscaler.start(ctx)
 
@@ -904,8 +915,9 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(operator.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 			Expect(storagev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 
-			// Create a client that will have a crud interface of k8s objects.
-			c = fake.NewClientBuilder().WithScheme(scheme).Build()
+			// Create a client that will have a crud interface of k8s objects, and that emulates server-side apply for the
+			// components that use it.
+			c = &utils.ApplyClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
 			ctx, cancel = context.WithCancel(context.Background())
 
 			// Create a fake clientset for the autoscaler.
@@ -946,6 +958,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			// Create an object we can use throughout the test to do the core reconcile loops.
 			mockStatus = &status.MockStatus{}
 			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
@@ -972,11 +985,13 @@ var _ = Describe("Testing core-controller installation", func() {
 				autoDetectedProvider: operator.ProviderNone,
 				status:               mockStatus,
 				typhaAutoscaler:      newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
+				nodeUpgrader:         newNodeUpgrader(cs, c, mockStatus),
 				namespaceMigration:   &fakeNamespaceMigration{},
 				amazonCRDExists:      true,
 				enterpriseCRDsExist:  true,
 				migrationChecked:     true,
 				tierWatchReady:       ready,
+				apiReader:            c,
 			}
 
 			r.typhaAutoscaler.start(ctx)
@@ -989,7 +1004,7 @@ var _ = Describe("Testing core-controller installation", func() {
 				Spec: operator.InstallationSpec{
 					Variant:               operator.TigeraSecureEnterprise,
 					Registry:              "some.registry.org/",
-					CertificateManagement: &operator.CertificateManagement{CACert: cert},
+					CertificateManagement: &operator.CertificateManagement{SignerName: "a.b/c", CACert: cert},
 				},
 			}
 			certificateManager, err := certificatemanager.Create(c, nil, "", common.OperatorNamespace(), certificatemanager.AllowCACreation())
@@ -1019,6 +1034,123 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(fc.Spec.RouteTableRange).To(BeNil())
 		})
 
+		It("should only write the CSI objects with server-side apply when it is enabled", func() {
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.(*utils.ApplyClient).Applied).To(BeEmpty())
+
+			r.csiServerSideApply = true
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var applied []string
+			for _, obj := range c.(*utils.ApplyClient).Applied {
+				applied = append(applied, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+			}
+			Expect(applied).To(ContainElement("DaemonSet/" + render.CSIDaemonSetName))
+			Expect(applied).To(ContainElement("CSIDriver/" + render.CSIDriverName))
+			Expect(applied).NotTo(ContainElement("DaemonSet/" + common.NodeDaemonSetName))
+
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: render.CSIDaemonSetName, Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+		})
+
+		It("should move the fields of CSI objects written with updates to the apply field manager", func() {
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			// The DaemonSet was written with an update by an operator that didn't use server-side apply.
+			userAgent := strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+			Expect(c.Create(ctx, &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      render.CSIDaemonSetName,
+					Namespace: common.CalicoNamespace,
+					ManagedFields: []metav1.ManagedFieldsEntry{{
+						Manager:    userAgent,
+						Operation:  metav1.ManagedFieldsOperationUpdate,
+						APIVersion: "apps/v1",
+						FieldsType: "FieldsV1",
+						FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:hostNetwork":{}}}}}`)},
+					}},
+				},
+			})).NotTo(HaveOccurred())
+
+			r.csiServerSideApply = true
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ds := &appsv1.DaemonSet{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: render.CSIDaemonSetName, Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+			Expect(ds.ManagedFields).To(HaveLen(1))
+			Expect(ds.ManagedFields[0].Manager).To(Equal(utils.FieldManager))
+			Expect(ds.ManagedFields[0].Operation).To(Equal(metav1.ManagedFieldsOperationApply))
+		})
+
+		It("should only record plans while plan mode is enabled", func() {
+			cr.Annotations = map[string]string{
+				utils.PlanModeAnnotation: utils.PlanModeEnabled,
+				RollbackAnnotation:       "1",
+				nodeRollbackAnnotation:   `{"failed":"abc","revision":"calico-node-1"}`,
+			}
+			cr.Spec.NodeUpgrade = &operator.NodeUpgrade{}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Nothing but the plans was written: no calico-node, status, snapshot or rollback.
+			plans := &operator.OperatorPlanList{}
+			Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+			Expect(plans.Items).NotTo(BeEmpty())
+			err = c.Get(ctx, types.NamespacedName{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			snapshots, err := listSnapshots(ctx, c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshots).To(BeEmpty())
+			instance := &operator.Installation{}
+			Expect(c.Get(ctx, utils.DefaultInstanceKey, instance)).NotTo(HaveOccurred())
+			Expect(instance.Status.Variant).To(BeEmpty())
+			Expect(instance.Annotations).To(HaveKey(RollbackAnnotation))
+			Expect(instance.Annotations).To(HaveKey(nodeRollbackAnnotation))
+			Expect(r.typhaAutoscaler.isPaused()).To(BeTrue())
+			Expect(r.nodeUpgrader.config).To(BeNil())
+
+			// Once plan mode is disabled, the plans are deleted.
+			instance.Annotations = nil
+			Expect(c.Update(ctx, instance)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+			Expect(plans.Items).To(BeEmpty())
+			Expect(r.typhaAutoscaler.isPaused()).To(BeFalse())
+		})
+
+		It("should not poll for the Installation to roll out before recording a snapshot", func() {
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// None of the rendered workloads have been updated yet.
+			Expect(result.RequeueAfter).NotTo(Equal(utils.StandardRetry))
+			snapshots, err := listSnapshots(ctx, c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshots).To(BeEmpty())
+		})
+
+		It("should stop scaling Typha while reconciliation is paused", func() {
+			cr.Annotations = map[string]string{common.PausedAnnotation: "true"}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(r.typhaAutoscaler.isPaused()).To(BeTrue())
+
+			instance := &operator.Installation{}
+			Expect(c.Get(ctx, utils.DefaultInstanceKey, instance)).NotTo(HaveOccurred())
+			instance.Annotations = nil
+			Expect(c.Update(ctx, instance)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(r.typhaAutoscaler.isPaused()).To(BeFalse())
+		})
+
 		It("generates FelixConfiguration with correct DNS service for Rancher", func() {
 			cr.Spec.KubernetesProvider = operator.ProviderRKE2
 			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
@@ -0,0 +1,531 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var nodeUpgradeLog = logf.Log.WithName("node_upgrader")
+
+const defaultNodeUpgraderSyncPeriod = 10 * time.Second
+
+// nodeRollbackAnnotation is set on the Installation while calico-node is being rolled back from a failed staged
+// upgrade, so that the rollback carries on if the operator restarts. Its value is a persistedNodeRollback.
+const nodeRollbackAnnotation = "operator.tigera.io/calico-node-rollback"
+
+// persistedNodeRollback is the form of a nodeRollback that is stored in the nodeRollbackAnnotation.
+type persistedNodeRollback struct {
+	// Failed is the hash of the template that failed.
+	Failed string `json:"failed"`
+	// Revision is the name of the ControllerRevision with the template being rolled back to.
+	Revision string `json:"revision"`
+}
+
+// nodeRollback is a rollback of calico-node to the revision before a failed upgrade. It applies for as long as the
+// operator renders the same template that failed.
+type nodeRollback struct {
+	persistedNodeRollback
+	previous *corev1.PodTemplateSpec
+}
+
+// templateHash returns the hash of a calico-node template that identifies it across operator restarts.
+func templateHash(t *corev1.PodTemplateSpec) string {
+	data, _ := json.Marshal(t)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// nodeUpgrader replaces calico-node pods in batches when the Installation's NodeUpgrade is set. The calico-node
+// DaemonSet is rendered with the OnDelete update strategy in that case, so that its pods are only replaced when the
+// upgrader deletes them. Typha is upgraded by its deployment first: no calico-node pods are replaced until Typha has
+// finished rolling out. Between batches the upgrader waits for calico-node to be ready and then for the soak period.
+// If the calico component becomes degraded the upgrade is paused or, with the Rollback policy, the previous template is
+// restored and rolled out instead. The rollback is recorded on the Installation, so that the operator doesn't roll the
+// failed template out again after a restart.
+type nodeUpgrader struct {
+	client        kubernetes.Interface
+	crClient      client.Client
+	statusManager status.StatusManager
+	syncPeriod    time.Duration
+	now           func() time.Time
+
+	// The configuration, last rendered template, rollback and status are shared by the core controller and the
+	// upgrader's goroutine.
+	lock     sync.Mutex
+	config   *operator.NodeUpgrade
+	rendered *corev1.PodTemplateSpec
+	rollback *nodeRollback
+	status   *operator.NodeUpgradeStatus
+
+	// rollbackEnded is set when the rollback no longer applies, until it has been removed from the Installation.
+	rollbackEnded bool
+
+	// healthySince is when calico-node was last seen to become ready after a batch was replaced.
+	healthySince time.Time
+}
+
+type nodeUpgraderOption func(*nodeUpgrader)
+
+// nodeUpgraderPeriod is an option that sets a custom sync period for the node upgrader.
+func nodeUpgraderPeriod(syncPeriod time.Duration) nodeUpgraderOption {
+	return func(u *nodeUpgrader) {
+		u.syncPeriod = syncPeriod
+	}
+}
+
+// newNodeUpgrader creates a new node upgrader, optionally applying any options. The default sync period is 10 seconds.
+func newNodeUpgrader(cs kubernetes.Interface, cli client.Client, statusManager status.StatusManager, options ...nodeUpgraderOption) *nodeUpgrader {
+	u := &nodeUpgrader{
+		client:        cs,
+		crClient:      cli,
+		statusManager: statusManager,
+		syncPeriod:    defaultNodeUpgraderSyncPeriod,
+		now:           time.Now,
+	}
+	for _, option := range options {
+		option(u)
+	}
+	return u
+}
+
+// start runs the upgrader every sync period until the context is done.
+func (u *nodeUpgrader) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(u.syncPeriod)
+		defer ticker.Stop()
+		nodeUpgradeLog.Info("Starting node upgrader", "syncPeriod", u.syncPeriod)
+		for {
+			select {
+			case <-ticker.C:
+				if err := u.sync(ctx); err != nil {
+					nodeUpgradeLog.Error(err, "Failed to upgrade calico-node")
+				}
+			case <-ctx.Done():
+				nodeUpgradeLog.Info("node upgrader shutting down")
+				return
+			}
+		}
+	}()
+}
+
+// setConfig updates the upgrade configuration. A nil configuration disables the upgrader. A rollback recorded on the
+// Installation is loaded again by syncRollback once the upgrader is enabled.
+func (u *nodeUpgrader) setConfig(cfg *operator.NodeUpgrade) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.config = cfg.DeepCopy()
+	if cfg == nil {
+		u.rendered = nil
+		u.rollback = nil
+		u.status = nil
+	}
+}
+
+// syncRollback reconciles the rollback recorded on the Installation with the upgrader's: a recorded rollback is loaded
+// when the upgrader has none, for example after a restart, and one that has ended, or that no longer applies because
+// the Installation no longer has a NodeUpgrade, is removed.
+func (u *nodeUpgrader) syncRollback(ctx context.Context, instance *operator.Installation) error {
+	value, recorded := instance.Annotations[nodeRollbackAnnotation]
+
+	u.lock.Lock()
+	ended := u.rollbackEnded || instance.Spec.NodeUpgrade == nil
+	load := !ended && recorded && u.rollback == nil
+	u.lock.Unlock()
+
+	if ended {
+		if recorded || u.rollbackEnded {
+			patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, nodeRollbackAnnotation))
+			// Patch a copy, as the Installation passed in holds the computed spec.
+			obj := &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: instance.Name}}
+			if err := u.crClient.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+				return fmt.Errorf("failed to remove the calico-node rollback from the Installation: %w", err)
+			}
+		}
+		u.lock.Lock()
+		u.rollbackEnded = false
+		u.lock.Unlock()
+		return nil
+	}
+	if !load {
+		return nil
+	}
+
+	var persisted persistedNodeRollback
+	if err := json.Unmarshal([]byte(value), &persisted); err != nil {
+		return fmt.Errorf("failed to decode the calico-node rollback on the Installation: %w", err)
+	}
+	rev, err := u.client.AppsV1().ControllerRevisions(common.CalicoNamespace).Get(ctx, persisted.Revision, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get calico-node revision %s to roll back to: %w", persisted.Revision, err)
+	}
+	previous, err := revisionTemplate(rev)
+	if err != nil {
+		return err
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.rollback == nil {
+		nodeUpgradeLog.Info("Resuming calico-node rollback", "revision", persisted.Revision)
+		u.rollback = &nodeRollback{persistedNodeRollback: persisted, previous: previous}
+	}
+	return nil
+}
+
+// getStatus returns the progress of the upgrade, or nil if no upgrade is configured.
+func (u *nodeUpgrader) getStatus() *operator.NodeUpgradeStatus {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	return u.status.DeepCopy()
+}
+
+// setRendered records the calico-node template rendered by the core controller. If calico-node is being rolled back
+// from that template, the template to roll back to is returned. A different template means the Installation has
+// changed since the failed upgrade, so the rollback no longer applies.
+func (u *nodeUpgrader) setRendered(t *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.rendered = t.DeepCopy()
+	if u.rollback == nil {
+		return nil
+	}
+	if u.rollback.Failed == templateHash(t) {
+		return u.rollback.previous.DeepCopy()
+	}
+	nodeUpgradeLog.Info("calico-node configuration changed, ending rollback")
+	u.rollback = nil
+	u.rollbackEnded = true
+	return nil
+}
+
+func (u *nodeUpgrader) setStatus(s *operator.NodeUpgradeStatus) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.status = s
+}
+
+// sync checks the progress of the upgrade and, when it is safe to, replaces the next batch of calico-node pods.
+func (u *nodeUpgrader) sync(ctx context.Context) error {
+	u.lock.Lock()
+	cfg := u.config.DeepCopy()
+	rollingBack := u.rollback != nil
+	u.lock.Unlock()
+	if cfg == nil {
+		return nil
+	}
+
+	ds, err := u.client.AppsV1().DaemonSets(common.CalicoNamespace).Get(ctx, common.NodeDaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			u.setStatus(nil)
+			return nil
+		}
+		return err
+	}
+	revisions, err := u.revisions(ctx, ds)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		// The DaemonSet controller has not yet recorded a revision for the DaemonSet.
+		return nil
+	}
+	currentHash := revisions[len(revisions)-1].Labels[appsv1.DefaultDaemonSetUniqueLabelKey]
+
+	pods, err := u.client.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(ds.Spec.Selector),
+	})
+	if err != nil {
+		return err
+	}
+	var outdated []corev1.Pod
+	var total int32
+	for _, p := range pods.Items {
+		if !metav1.IsControlledBy(&p, ds) {
+			continue
+		}
+		total++
+		if p.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] != currentHash {
+			outdated = append(outdated, p)
+		}
+	}
+
+	s := &operator.NodeUpgradeStatus{State: operator.NodeUpgradeStateUpgrading, UpdatedPods: total - int32(len(outdated)), TotalPods: total}
+	if rollingBack {
+		s.State = operator.NodeUpgradeStateRollingBack
+	}
+	defer func() { u.setStatus(s) }()
+
+	if len(outdated) == 0 {
+		u.healthySince = time.Time{}
+		s.State = operator.NodeUpgradeStateComplete
+		if rollingBack {
+			s.Message = "Rolled back to the previous calico-node revision"
+		}
+		return nil
+	}
+
+	if !rollingBack && u.statusManager.IsDegraded() {
+		if cfg.GetFailurePolicy() == operator.NodeUpgradeFailurePolicyRollback {
+			if err := u.startRollback(ctx, revisions); err != nil {
+				s.State = operator.NodeUpgradeStatePaused
+				s.Message = fmt.Sprintf("Paused because calico is degraded, unable to roll back: %s", err)
+				return nil
+			}
+			s.State = operator.NodeUpgradeStateRollingBack
+			s.Message = "Rolling back because calico is degraded"
+			return nil
+		}
+		s.State = operator.NodeUpgradeStatePaused
+		s.Message = "Paused because calico is degraded"
+		return nil
+	}
+
+	if ready, err := u.typhaRolledOut(ctx); err != nil {
+		return err
+	} else if !ready {
+		s.Message = "Waiting for Typha to be upgraded"
+		return nil
+	}
+
+	if ds.Status.ObservedGeneration < ds.Generation || ds.Status.NumberUnavailable > 0 || ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+		u.healthySince = time.Time{}
+		s.Message = "Waiting for calico-node to be ready"
+		return nil
+	}
+	if u.healthySince.IsZero() {
+		u.healthySince = u.now()
+	}
+	// A rollback is not soaked, the revision being restored was already running before the upgrade.
+	if soak := cfg.GetSoakPeriod(); !rollingBack && u.now().Sub(u.healthySince) < soak {
+		s.Message = fmt.Sprintf("Waiting for the %s soak period", soak)
+		return nil
+	}
+
+	batch, err := u.nextBatch(ctx, cfg, outdated, total)
+	if err != nil {
+		return err
+	}
+	for _, p := range batch {
+		nodeUpgradeLog.Info("Replacing calico-node pod", "pod", p.Name, "node", p.Spec.NodeName)
+		if err := u.client.CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	u.healthySince = time.Time{}
+	s.Message = fmt.Sprintf("Replacing %d calico-node pods", len(batch))
+	return nil
+}
+
+// revisions returns the ControllerRevisions of the DaemonSet, oldest first.
+func (u *nodeUpgrader) revisions(ctx context.Context, ds *appsv1.DaemonSet) ([]appsv1.ControllerRevision, error) {
+	list, err := u.client.AppsV1().ControllerRevisions(common.CalicoNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(ds.Spec.Selector),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var revs []appsv1.ControllerRevision
+	for _, r := range list.Items {
+		if metav1.IsControlledBy(&r, ds) {
+			revs = append(revs, r)
+		}
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Revision < revs[j].Revision })
+	return revs, nil
+}
+
+// startRollback records the template of the revision before the current one on the Installation and in the upgrader,
+// so that the core controller renders it in place of the failed template.
+func (u *nodeUpgrader) startRollback(ctx context.Context, revisions []appsv1.ControllerRevision) error {
+	if len(revisions) < 2 {
+		return fmt.Errorf("there is no previous calico-node revision")
+	}
+	rev := revisions[len(revisions)-2]
+	previous, err := revisionTemplate(&rev)
+	if err != nil {
+		return err
+	}
+
+	u.lock.Lock()
+	if u.rendered == nil {
+		u.lock.Unlock()
+		return fmt.Errorf("calico-node has not been rendered yet")
+	}
+	rollback := &nodeRollback{
+		persistedNodeRollback: persistedNodeRollback{Failed: templateHash(u.rendered), Revision: rev.Name},
+		previous:              previous,
+	}
+	u.lock.Unlock()
+
+	value, err := json.Marshal(rollback.persistedNodeRollback)
+	if err != nil {
+		return err
+	}
+	instance := &operator.Installation{}
+	if err := u.crClient.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		return fmt.Errorf("failed to get the Installation to record the calico-node rollback: %w", err)
+	}
+	patchFrom := client.MergeFrom(instance.DeepCopy())
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[nodeRollbackAnnotation] = string(value)
+	if err := u.crClient.Patch(ctx, instance, patchFrom); err != nil {
+		return fmt.Errorf("failed to record the calico-node rollback on the Installation: %w", err)
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	nodeUpgradeLog.Info("Rolling back calico-node", "revision", rev.Revision)
+	u.rollback = rollback
+	u.rollbackEnded = false
+	return nil
+}
+
+// revisionTemplate returns the calico-node template of a ControllerRevision. The DaemonSet controller stores each
+// revision as a patch that replaces the pod template.
+func revisionTemplate(rev *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	patch := struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(rev.Data.Raw, &patch); err != nil {
+		return nil, fmt.Errorf("failed to decode calico-node revision %s: %w", rev.Name, err)
+	}
+	return &patch.Spec.Template, nil
+}
+
+// typhaRolledOut returns whether every Typha replica is running the Typha deployment's current template.
+func (u *nodeUpgrader) typhaRolledOut(ctx context.Context) (bool, error) {
+	d, err := u.client.AppsV1().Deployments(common.CalicoNamespace).Get(ctx, common.TyphaDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas, nil
+}
+
+// nextBatch returns the outdated pods to replace next: BatchPercent of all calico-node pods, rounded up, and only from
+// the first zone, by label value, that has outdated pods if a ZoneLabel is configured.
+func (u *nodeUpgrader) nextBatch(ctx context.Context, cfg *operator.NodeUpgrade, outdated []corev1.Pod, total int32) ([]corev1.Pod, error) {
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].Spec.NodeName < outdated[j].Spec.NodeName })
+
+	if cfg.ZoneLabel != "" {
+		nodes, err := u.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		zones := map[string]string{}
+		for _, n := range nodes.Items {
+			zones[n.Name] = n.Labels[cfg.ZoneLabel]
+		}
+		zone := zones[outdated[0].Spec.NodeName]
+		for _, p := range outdated {
+			if z := zones[p.Spec.NodeName]; z < zone {
+				zone = z
+			}
+		}
+		var inZone []corev1.Pod
+		for _, p := range outdated {
+			if zones[p.Spec.NodeName] == zone {
+				inZone = append(inZone, p)
+			}
+		}
+		outdated = inZone
+	}
+
+	size := int(math.Ceil(float64(total) * float64(cfg.GetBatchPercent()) / 100))
+	if size < 1 {
+		size = 1
+	}
+	if size > len(outdated) {
+		size = len(outdated)
+	}
+	return outdated[:size], nil
+}
+
+// nodeUpgradeComponent renders calico-node for a staged upgrade: its DaemonSet only replaces pods when they are deleted
+// by the nodeUpgrader and, while calico-node is being rolled back, it renders the template being rolled back to.
+type nodeUpgradeComponent struct {
+	render.Component
+
+	// rollbackTo is the template being rolled back to, if calico-node is being rolled back. It is set by
+	// recordRendered rather than when the objects are rendered, so that rendering them has no side effects.
+	rollbackTo *corev1.PodTemplateSpec
+}
+
+// recordRendered records the rendered calico-node template with the upgrader, which decides whether calico-node is
+// still being rolled back. It must be called once per reconcile, after the component's images have been resolved.
+func (c *nodeUpgradeComponent) recordRendered(u *nodeUpgrader) {
+	c.rollbackTo = nil
+	objsToCreate, _ := c.Component.Objects()
+	if ds := nodeDaemonSet(objsToCreate); ds != nil {
+		c.rollbackTo = u.setRendered(&ds.Spec.Template)
+	}
+}
+
+func (c *nodeUpgradeComponent) Objects() ([]client.Object, []client.Object) {
+	objsToCreate, objsToDelete := c.Component.Objects()
+	if ds := nodeDaemonSet(objsToCreate); ds != nil {
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+		if c.rollbackTo != nil {
+			ds.Spec.Template = *c.rollbackTo.DeepCopy()
+		}
+	}
+	return objsToCreate, objsToDelete
+}
+
+// nodeDaemonSet returns the calico-node DaemonSet from the objects, if it is one of them.
+func nodeDaemonSet(objs []client.Object) *appsv1.DaemonSet {
+	for _, obj := range objs {
+		if ds, ok := obj.(*appsv1.DaemonSet); ok && ds.Name == common.NodeDaemonSetName {
+			return ds
+		}
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/ptr"
+	"github.com/tigera/operator/pkg/render"
+)
+
+func nodeTemplate(image string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"k8s-app": "calico-node"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "calico-node", Image: image}}},
+	}
+}
+
+// staticComponent is a component that only renders the given objects.
+type staticComponent struct {
+	render.Component
+	objs []client.Object
+}
+
+func (c staticComponent) Objects() ([]client.Object, []client.Object) {
+	return c.objs, nil
+}
+
+var _ = Describe("Test node upgrader", func() {
+	var statusManager *status.MockStatus
+	var c *kfake.Clientset
+	var cli client.Client
+	var ctx context.Context
+	var u *nodeUpgrader
+	var now time.Time
+
+	selector := map[string]string{"k8s-app": "calico-node"}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace, UID: types.UID("node-uid")},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: nodeTemplate("calico/node:new"),
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 4, NumberReady: 4},
+	}
+	owner := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: ds.Name, UID: ds.UID, Controller: ptr.BoolToPtr(true)}}
+
+	revision := func(rev int64, hash, image string) *appsv1.ControllerRevision {
+		patch := map[string]interface{}{"spec": map[string]interface{}{"template": nodeTemplate(image)}}
+		data, err := json.Marshal(patch)
+		Expect(err).NotTo(HaveOccurred())
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("calico-node-%s", hash),
+				Namespace:       common.CalicoNamespace,
+				Labels:          map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: hash},
+				OwnerReferences: owner,
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: rev,
+		}
+	}
+
+	pod := func(node, hash string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "calico-node-" + node,
+				Namespace:       common.CalicoNamespace,
+				Labels:          map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: hash},
+				OwnerReferences: owner,
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+	}
+
+	node := func(name, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}}}
+	}
+
+	typha := func(updated int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: common.TyphaDeploymentName, Namespace: common.CalicoNamespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.Int32ToPtr(2)},
+			Status:     appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: updated, AvailableReplicas: 2},
+		}
+	}
+
+	remainingPods := func() []string {
+		pods, err := c.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, p := range pods.Items {
+			names = append(names, p.Spec.NodeName)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		statusManager = new(status.MockStatus)
+		c = kfake.NewSimpleClientset(
+			ds.DeepCopy(),
+			revision(1, "old", "calico/node:old"),
+			revision(2, "new", "calico/node:new"),
+			pod("n1", "old"), pod("n2", "old"), pod("n3", "old"), pod("n4", "old"),
+			node("n1", "b"), node("n2", "b"), node("n3", "a"), node("n4", "a"),
+			typha(2),
+		)
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operator.InstallationSpec{NodeUpgrade: &operator.NodeUpgrade{FailurePolicy: operator.NodeUpgradeFailurePolicyRollback}},
+		}).Build()
+		now = time.Now()
+		u = newNodeUpgrader(c, cli, statusManager)
+		u.now = func() time.Time { return now }
+		u.setConfig(&operator.NodeUpgrade{BatchPercent: ptr.Int32ToPtr(25), SoakPeriod: &metav1.Duration{}})
+	})
+
+	It("should do nothing when no upgrade is configured", func() {
+		u.setConfig(nil)
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(HaveLen(4))
+		Expect(u.getStatus()).To(BeNil())
+	})
+
+	It("should replace a batch of outdated pods", func() {
+		statusManager.On("IsDegraded").Return(false)
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(ConsistOf("n2", "n3", "n4"))
+		Expect(u.getStatus()).To(Equal(&operator.NodeUpgradeStatus{
+			State: operator.NodeUpgradeStateUpgrading, Message: "Replacing 1 calico-node pods", UpdatedPods: 0, TotalPods: 4,
+		}))
+	})
+
+	It("should wait for Typha to be upgraded first", func() {
+		statusManager.On("IsDegraded").Return(false)
+		_, err := c.AppsV1().Deployments(common.CalicoNamespace).UpdateStatus(ctx, typha(1), metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(HaveLen(4))
+		Expect(u.getStatus().Message).To(Equal("Waiting for Typha to be upgraded"))
+	})
+
+	It("should wait for calico-node to be ready and then soak between batches", func() {
+		statusManager.On("IsDegraded").Return(false)
+		u.setConfig(&operator.NodeUpgrade{BatchPercent: ptr.Int32ToPtr(25), SoakPeriod: &metav1.Duration{Duration: 5 * time.Minute}})
+
+		notReady := ds.DeepCopy()
+		notReady.Status.NumberReady = 3
+		_, err := c.AppsV1().DaemonSets(common.CalicoNamespace).UpdateStatus(ctx, notReady, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(u.getStatus().Message).To(Equal("Waiting for calico-node to be ready"))
+
+		_, err = c.AppsV1().DaemonSets(common.CalicoNamespace).UpdateStatus(ctx, ds.DeepCopy(), metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(u.getStatus().Message).To(Equal("Waiting for the 5m0s soak period"))
+		Expect(remainingPods()).To(HaveLen(4))
+
+		now = now.Add(5 * time.Minute)
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(HaveLen(3))
+	})
+
+	It("should upgrade one zone at a time", func() {
+		statusManager.On("IsDegraded").Return(false)
+		u.setConfig(&operator.NodeUpgrade{BatchPercent: ptr.Int32ToPtr(100), ZoneLabel: "zone", SoakPeriod: &metav1.Duration{}})
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(ConsistOf("n1", "n2"))
+	})
+
+	It("should pause when calico is degraded", func() {
+		statusManager.On("IsDegraded").Return(true)
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(remainingPods()).To(HaveLen(4))
+		Expect(u.getStatus().State).To(Equal(operator.NodeUpgradeStatePaused))
+	})
+
+	It("should report when the upgrade is complete", func() {
+		for _, n := range []string{"n1", "n2", "n3", "n4"} {
+			_, err := c.CoreV1().Pods(common.CalicoNamespace).Update(ctx, pod(n, "new"), metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(u.getStatus()).To(Equal(&operator.NodeUpgradeStatus{State: operator.NodeUpgradeStateComplete, UpdatedPods: 4, TotalPods: 4}))
+	})
+
+	It("should roll back to the previous revision when calico is degraded", func() {
+		statusManager.On("IsDegraded").Return(true)
+		u.setConfig(&operator.NodeUpgrade{FailurePolicy: operator.NodeUpgradeFailurePolicyRollback})
+		failed := nodeTemplate("calico/node:new")
+		Expect(u.setRendered(&failed)).To(BeNil())
+
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(u.getStatus().State).To(Equal(operator.NodeUpgradeStateRollingBack))
+		Expect(remainingPods()).To(HaveLen(4))
+
+		// While the same template is rendered, the previous one is rolled out instead.
+		previous := u.setRendered(&failed)
+		Expect(previous).NotTo(BeNil())
+		Expect(previous.Spec.Containers[0].Image).To(Equal("calico/node:old"))
+
+		// Once the Installation changes, the rollback ends.
+		changed := nodeTemplate("calico/node:newer")
+		Expect(u.setRendered(&changed)).To(BeNil())
+		Expect(u.setRendered(&failed)).To(BeNil())
+	})
+
+	It("should carry on with a rollback after a restart", func() {
+		statusManager.On("IsDegraded").Return(true)
+		u.setConfig(&operator.NodeUpgrade{FailurePolicy: operator.NodeUpgradeFailurePolicyRollback})
+		failed := nodeTemplate("calico/node:new")
+		Expect(u.setRendered(&failed)).To(BeNil())
+		Expect(u.sync(ctx)).To(Succeed())
+
+		instance := &operator.Installation{}
+		Expect(cli.Get(ctx, utils.DefaultInstanceKey, instance)).To(Succeed())
+		Expect(instance.Annotations).To(HaveKey(nodeRollbackAnnotation))
+
+		// A new upgrader loads the rollback from the Installation and keeps rendering the previous template.
+		restarted := newNodeUpgrader(c, cli, statusManager)
+		restarted.setConfig(instance.Spec.NodeUpgrade)
+		Expect(restarted.syncRollback(ctx, instance)).To(Succeed())
+		previous := restarted.setRendered(&failed)
+		Expect(previous).NotTo(BeNil())
+		Expect(previous.Spec.Containers[0].Image).To(Equal("calico/node:old"))
+
+		// Once the Installation changes, the rollback ends and is removed from the Installation.
+		changed := nodeTemplate("calico/node:newer")
+		Expect(restarted.setRendered(&changed)).To(BeNil())
+		Expect(restarted.syncRollback(ctx, instance)).To(Succeed())
+		Expect(cli.Get(ctx, utils.DefaultInstanceKey, instance)).To(Succeed())
+		Expect(instance.Annotations).NotTo(HaveKey(nodeRollbackAnnotation))
+	})
+
+	It("should render calico-node to only replace pods the upgrader deletes", func() {
+		component := &nodeUpgradeComponent{Component: staticComponent{objs: []client.Object{ds.DeepCopy()}}}
+		component.recordRendered(u)
+		objs, _ := component.Objects()
+		rendered := objs[0].(*appsv1.DaemonSet)
+		Expect(rendered.Spec.UpdateStrategy.Type).To(Equal(appsv1.OnDeleteDaemonSetStrategyType))
+		Expect(rendered.Spec.Template).To(Equal(ds.Spec.Template))
+
+		// During a rollback the previous template is rendered, once the rendered template has been recorded again.
+		statusManager.On("IsDegraded").Return(true)
+		u.setConfig(&operator.NodeUpgrade{FailurePolicy: operator.NodeUpgradeFailurePolicyRollback})
+		Expect(u.sync(ctx)).To(Succeed())
+		objs, _ = component.Objects()
+		Expect(objs[0].(*appsv1.DaemonSet).Spec.Template.Spec.Containers[0].Image).To(Equal("calico/node:new"))
+		component.recordRendered(u)
+		objs, _ = component.Objects()
+		Expect(objs[0].(*appsv1.DaemonSet).Spec.Template.Spec.Containers[0].Image).To(Equal("calico/node:old"))
+	})
+
+	It("should not change the upgrader when the objects are rendered", func() {
+		statusManager.On("IsDegraded").Return(true)
+		u.setConfig(&operator.NodeUpgrade{FailurePolicy: operator.NodeUpgradeFailurePolicyRollback})
+		failed := nodeTemplate("calico/node:new")
+		Expect(u.setRendered(&failed)).To(BeNil())
+		Expect(u.sync(ctx)).To(Succeed())
+		Expect(u.getStatus().State).To(Equal(operator.NodeUpgradeStateRollingBack))
+
+		// Rendering a changed template doesn't end the rollback until it is recorded.
+		changed := ds.DeepCopy()
+		changed.Spec.Template = nodeTemplate("calico/node:newer")
+		component := &nodeUpgradeComponent{Component: staticComponent{objs: []client.Object{changed}}}
+		component.Objects()
+		component.Objects()
+		Expect(u.setRendered(&failed)).NotTo(BeNil())
+	})
+})
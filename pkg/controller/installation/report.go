@@ -0,0 +1,289 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+// maxDriftedFields is the number of differing fields reported for each drifted object.
+const maxDriftedFields = 10
+
+// driftCheckInterval is how often the rendered objects are checked for drift. Each check reads every rendered object
+// from the API server, so it isn't done on every reconcile.
+const driftCheckInterval = 5 * time.Minute
+
+// fieldSources attributes each field of the computed Installation spec to the step that last set or changed it.
+// A nil *fieldSources records nothing.
+type fieldSources struct {
+	values  map[string]string
+	sources map[string]operator.InstallationFieldSourceType
+}
+
+// newFieldSources attributes the fields of the user's Installation spec. The operator writes the values it fills in
+// back to the Installation, so a field is only attributed to the user if the previous report did not attribute the
+// same value to another source.
+func newFieldSources(user *operator.InstallationSpec, previous *operator.InstallationReport) *fieldSources {
+	f := &fieldSources{values: flattenSpec(user), sources: map[string]operator.InstallationFieldSourceType{}}
+	prev := map[string]operator.InstallationFieldSource{}
+	if previous != nil {
+		for _, s := range previous.FieldSources {
+			prev[s.Path] = s
+		}
+	}
+	for path, value := range f.values {
+		f.sources[path] = operator.FieldSourceUser
+		if p, ok := prev[path]; ok && p.Value == value && p.Source != operator.FieldSourceOverlay {
+			f.sources[path] = p.Source
+		}
+	}
+	return f
+}
+
+// record attributes the fields of the spec that were added or changed since the last step to the source.
+func (f *fieldSources) record(source operator.InstallationFieldSourceType, spec *operator.InstallationSpec) {
+	if f == nil {
+		return
+	}
+	values := flattenSpec(spec)
+	for path, value := range values {
+		if old, ok := f.values[path]; !ok || old != value {
+			f.sources[path] = source
+		}
+	}
+	for path := range f.values {
+		if _, ok := values[path]; !ok {
+			delete(f.sources, path)
+		}
+	}
+	f.values = values
+}
+
+// list returns the source of every field, sorted by path.
+func (f *fieldSources) list() []operator.InstallationFieldSource {
+	if f == nil {
+		return nil
+	}
+	var list []operator.InstallationFieldSource
+	for path, value := range f.values {
+		list = append(list, operator.InstallationFieldSource{Path: path, Value: value, Source: f.sources[path]})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list
+}
+
+// flattenSpec returns the JSON encoded value of each leaf field of the spec by its path. Lists of objects are
+// flattened by index, while lists of other values are leaves.
+func flattenSpec(spec *operator.InstallationSpec) map[string]string {
+	fields := map[string]string{}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fields
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fields
+	}
+
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				walk(p, child)
+			}
+			return
+		case []interface{}:
+			if len(t) > 0 {
+				if _, ok := t[0].(map[string]interface{}); ok {
+					for i, child := range t {
+						walk(fmt.Sprintf("%s[%d]", path, i), child)
+					}
+					return
+				}
+			}
+		}
+		b, _ := json.Marshal(v)
+		fields[path] = string(b)
+	}
+	walk("", obj)
+	return fields
+}
+
+// driftChecker limits how often the rendered objects are checked for drift. A nil *driftChecker checks every time.
+type driftChecker struct {
+	// lastChecked is when the objects were last checked, or zero if they haven't been.
+	lastChecked time.Time
+
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+}
+
+func newDriftChecker() *driftChecker {
+	return &driftChecker{now: time.Now}
+}
+
+// due returns true if the objects should be checked for drift now, and records the check if so.
+func (d *driftChecker) due() bool {
+	if d == nil {
+		return true
+	}
+	now := d.now()
+	if !d.lastChecked.IsZero() && now.Sub(d.lastChecked) < driftCheckInterval {
+		return false
+	}
+	d.lastChecked = now
+	return true
+}
+
+// findDrift compares the rendered objects with those in the cluster and returns the objects that differ. Only the
+// fields set by the renderer are compared, since the API server and other controllers fill in others. The objects are
+// read with a reader that doesn't cache them, as the controller doesn't watch all of the kinds it renders and a cached
+// read would start an informer, for Secrets across the cluster, for each of them.
+func findDrift(ctx context.Context, reader client.Reader, scheme *runtime.Scheme, objs []client.Object) []operator.DriftedObject {
+	var drifted []operator.DriftedObject
+	for _, obj := range objs {
+		cur, ok := obj.DeepCopyObject().(client.Object)
+		if !ok {
+			continue
+		}
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(obj), cur); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.V(2).Info("Unable to check object for drift", "name", obj.GetName(), "error", err)
+			}
+			continue
+		}
+		fields := objectDrift(obj, cur)
+		if len(fields) == 0 {
+			continue
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Empty() {
+			if kinds, _, err := scheme.ObjectKinds(obj); err == nil && len(kinds) > 0 {
+				gvk = kinds[0]
+			}
+		}
+		drifted = append(drifted, operator.DriftedObject{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+			Fields:     fields,
+			Ignored:    utils.IgnoreObject(cur),
+		})
+	}
+	return drifted
+}
+
+// statusChanged returns true if the Installation's status has changed and needs to be written. The statuses are
+// compared by their JSON encoding, so that fields that are nil in one and empty in the other are equal.
+func statusChanged(before, after *operator.InstallationStatus) bool {
+	b, err := json.Marshal(before)
+	if err != nil {
+		return true
+	}
+	a, err := json.Marshal(after)
+	if err != nil {
+		return true
+	}
+	return string(a) != string(b)
+}
+
+// objectDrift returns the paths of the fields of the desired object that differ in the current object. The labels and
+// annotations of the desired object are compared, but not the rest of its metadata, nor its status.
+func objectDrift(desired, current client.Object) []string {
+	want, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil
+	}
+	have, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil
+	}
+
+	var fields []string
+	var compare func(path string, want, have interface{})
+	compare = func(path string, want, have interface{}) {
+		if len(fields) >= maxDriftedFields || want == nil {
+			return
+		}
+		switch w := want.(type) {
+		case map[string]interface{}:
+			h, ok := have.(map[string]interface{})
+			if !ok {
+				if len(w) > 0 {
+					fields = append(fields, path)
+				}
+				return
+			}
+			keys := make([]string, 0, len(w))
+			for k := range w {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				compare(path+"."+k, w[k], h[k])
+			}
+		case []interface{}:
+			h, ok := have.([]interface{})
+			if !ok || len(h) != len(w) {
+				fields = append(fields, path)
+				return
+			}
+			for i := range w {
+				compare(fmt.Sprintf("%s[%d]", path, i), w[i], h[i])
+			}
+		default:
+			if !reflect.DeepEqual(want, have) {
+				fields = append(fields, path)
+			}
+		}
+	}
+
+	if wm, ok := want["metadata"].(map[string]interface{}); ok {
+		hm, _ := have["metadata"].(map[string]interface{})
+		compare("metadata.labels", wm["labels"], hm["labels"])
+		compare("metadata.annotations", wm["annotations"], hm["annotations"])
+	}
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		compare(k, want[k], have[k])
+	}
+	return fields
+}
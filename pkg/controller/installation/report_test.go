@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+)
+
+var _ = Describe("Installation report", func() {
+	sourceOf := func(list []operator.InstallationFieldSource, path string) operator.InstallationFieldSourceType {
+		for _, s := range list {
+			if s.Path == path {
+				return s.Source
+			}
+		}
+		return ""
+	}
+
+	Context("field sources", func() {
+		It("should attribute each field to the step that set it", func() {
+			instance := &operator.Installation{Spec: operator.InstallationSpec{Variant: operator.Calico}}
+			sources := newFieldSources(&instance.Spec, nil)
+
+			Expect(mergeProvider(instance, operator.ProviderOpenShift)).To(Succeed())
+			sources.record(operator.FieldSourceDetected, &instance.Spec)
+			openshift := &configv1.Network{Spec: configv1.NetworkSpec{ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14"}}}}
+			Expect(mergeAndFillDefaults(instance, openshift, nil, nil, sources)).To(Succeed())
+
+			list := sources.list()
+			Expect(sourceOf(list, "variant")).To(Equal(operator.FieldSourceUser))
+			Expect(sourceOf(list, "kubernetesProvider")).To(Equal(operator.FieldSourceDetected))
+			Expect(sourceOf(list, "calicoNetwork.ipPools[0].cidr")).To(Equal(operator.FieldSourceOpenShift))
+			Expect(sourceOf(list, "calicoNetwork.ipPools[0].encapsulation")).To(Equal(operator.FieldSourceDefault))
+			Expect(sourceOf(list, "flexVolumePath")).To(Equal(operator.FieldSourceDefault))
+		})
+
+		It("should keep the sources of values written back to the Installation", func() {
+			instance := &operator.Installation{Spec: operator.InstallationSpec{Variant: operator.Calico}}
+			sources := newFieldSources(&instance.Spec, nil)
+			Expect(mergeAndFillDefaults(instance, nil, nil, nil, sources)).To(Succeed())
+			report := &operator.InstallationReport{FieldSources: sources.list()}
+
+			// On the next reconcile the defaults are part of the user's spec, except for one the user has changed.
+			instance.Spec.FlexVolumePath = "/custom"
+			sources = newFieldSources(&instance.Spec, report)
+			Expect(mergeAndFillDefaults(instance, nil, nil, nil, sources)).To(Succeed())
+			list := sources.list()
+			Expect(sourceOf(list, "cni.type")).To(Equal(operator.FieldSourceDefault))
+			Expect(sourceOf(list, "flexVolumePath")).To(Equal(operator.FieldSourceUser))
+		})
+
+		It("should attribute the CNI plugin to EKS when aws-node is running", func() {
+			instance := &operator.Installation{}
+			sources := newFieldSources(&instance.Spec, nil)
+			Expect(mergeAndFillDefaults(instance, nil, nil, &appsv1.DaemonSet{}, sources)).To(Succeed())
+			Expect(sourceOf(sources.list(), "cni.type")).To(Equal(operator.FieldSourceEKS))
+		})
+	})
+
+	Context("drift", func() {
+		var cli client.Client
+		var ctx context.Context
+		var desired *appsv1.Deployment
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(apis.AddToScheme(scheme)).To(Succeed())
+			Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+			ctx = context.Background()
+
+			desired = &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "calico-kube-controllers", Namespace: "calico-system", Labels: map[string]string{"k8s-app": "calico-kube-controllers"}},
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "calico-kube-controllers", Image: "calico/kube-controllers:v1"}}},
+					},
+				},
+			}
+		})
+
+		It("should not report objects that match, even with fields added by the cluster", func() {
+			live := desired.DeepCopy()
+			live.Labels["added"] = "by-someone-else"
+			live.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+			live.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+			Expect(cli.Create(ctx, live)).To(Succeed())
+
+			Expect(findDrift(ctx, cli, cli.Scheme(), []client.Object{desired})).To(BeEmpty())
+		})
+
+		It("should report objects that were changed", func() {
+			live := desired.DeepCopy()
+			live.Spec.Template.Spec.Containers[0].Image = "calico/kube-controllers:hand-edited"
+			live.Annotations = map[string]string{"unsupported.operator.tigera.io/ignore": "true"}
+			Expect(cli.Create(ctx, live)).To(Succeed())
+
+			Expect(findDrift(ctx, cli, cli.Scheme(), []client.Object{desired})).To(Equal([]operator.DriftedObject{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "calico-kube-controllers",
+				Namespace:  "calico-system",
+				Fields:     []string{"spec.template.spec.containers[0].image"},
+				Ignored:    true,
+			}}))
+		})
+
+		It("should not report objects that don't exist yet", func() {
+			Expect(findDrift(ctx, cli, cli.Scheme(), []client.Object{desired})).To(BeEmpty())
+		})
+
+		It("should only check for drift once per interval", func() {
+			now := time.Now()
+			d := newDriftChecker()
+			d.now = func() time.Time { return now }
+			Expect(d.due()).To(BeTrue())
+			Expect(d.due()).To(BeFalse())
+			now = now.Add(driftCheckInterval - time.Second)
+			Expect(d.due()).To(BeFalse())
+			now = now.Add(time.Second)
+			Expect(d.due()).To(BeTrue())
+
+			var unlimited *driftChecker
+			Expect(unlimited.due()).To(BeTrue())
+			Expect(unlimited.due()).To(BeTrue())
+		})
+	})
+
+	Context("status", func() {
+		It("should only report a change when the status differs", func() {
+			before := &operator.InstallationStatus{
+				Variant: operator.Calico,
+				Report:  &operator.InstallationReport{FieldSources: []operator.InstallationFieldSource{{Path: "variant", Value: `"Calico"`, Source: operator.FieldSourceUser}}},
+			}
+			after := before.DeepCopy()
+			after.Report.DriftedObjects = []operator.DriftedObject{}
+			Expect(statusChanged(before, after)).To(BeFalse())
+
+			after.Report.DriftedObjects = []operator.DriftedObject{{APIVersion: "apps/v1", Kind: "Deployment", Name: "calico-kube-controllers"}}
+			Expect(statusChanged(before, after)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,266 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+)
+
+const (
+	// RollbackAnnotation can be set on the default Installation to the revision of a snapshot to roll back to. The
+	// operator replaces the Installation's spec with the one the user had set when the snapshot was taken, restores
+	// the snapshot's ImageSet, or deletes the ImageSet for the release if the snapshot had none, and then removes the
+	// annotation.
+	RollbackAnnotation = "operator.tigera.io/rollback-to-revision"
+
+	// InstallationSnapshotLabel labels the ConfigMaps in the operator namespace that hold the snapshots of the
+	// configuration applied by the operator. The value of the label is the revision of the snapshot.
+	InstallationSnapshotLabel = "operator.tigera.io/installation-snapshot"
+
+	// installationSnapshotLimit is how many snapshots are kept.
+	installationSnapshotLimit = 10
+
+	// snapshotInstallationKey holds the spec of the Installation resource, which is what a rollback restores, and
+	// snapshotComputedKey the spec computed from it, the overlay and the defaults, which is what was applied.
+	snapshotInstallationKey = "installation.json"
+	snapshotComputedKey     = "computed.json"
+	snapshotImageSetKey     = "imageset.json"
+)
+
+// errSnapshotNotFound is returned when a rollback is requested to a revision that has no snapshot.
+type errSnapshotNotFound struct {
+	revision string
+}
+
+func (e errSnapshotNotFound) Error() string {
+	return fmt.Sprintf("there is no Installation snapshot with revision %q", e.revision)
+}
+
+// errInvalidSnapshot is returned when a rollback is requested to a snapshot that this operator can't apply.
+type errInvalidSnapshot struct {
+	revision string
+	reason   string
+}
+
+func (e errInvalidSnapshot) Error() string {
+	return fmt.Sprintf("Installation snapshot with revision %q can't be rolled back to: %s", e.revision, e.reason)
+}
+
+func snapshotName(revision int64) string {
+	return fmt.Sprintf("installation-snapshot-%d", revision)
+}
+
+// listSnapshots returns the snapshot ConfigMaps, oldest first.
+func listSnapshots(ctx context.Context, cli client.Client) ([]corev1.ConfigMap, error) {
+	list := &corev1.ConfigMapList{}
+	if err := cli.List(ctx, list, client.InNamespace(common.OperatorNamespace()), client.HasLabels{InstallationSnapshotLabel}); err != nil {
+		return nil, err
+	}
+	snapshots := list.Items
+	sort.Slice(snapshots, func(i, j int) bool { return snapshotRevision(snapshots[i]) < snapshotRevision(snapshots[j]) })
+	return snapshots, nil
+}
+
+func snapshotRevision(cm corev1.ConfigMap) int64 {
+	rev, _ := strconv.ParseInt(cm.Labels[InstallationSnapshotLabel], 10, 64)
+	return rev
+}
+
+// recordSnapshot stores the Installation's spec, the spec computed from it and the ImageSet it was applied with as a
+// new snapshot, unless the computed spec and ImageSet are the same as in the latest snapshot. Only the most recent
+// snapshots are kept.
+func recordSnapshot(ctx context.Context, cli client.Client, spec, computed *operator.InstallationSpec, is *operator.ImageSet) error {
+	data := map[string]string{}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	data[snapshotInstallationKey] = string(specJSON)
+	computedJSON, err := json.Marshal(computed)
+	if err != nil {
+		return err
+	}
+	data[snapshotComputedKey] = string(computedJSON)
+	if is != nil {
+		// Only the name and content of the ImageSet are kept, so that it can be recreated.
+		isJSON, err := json.Marshal(&operator.ImageSet{
+			TypeMeta:   is.TypeMeta,
+			ObjectMeta: metav1.ObjectMeta{Name: is.Name},
+			Spec:       is.Spec,
+		})
+		if err != nil {
+			return err
+		}
+		data[snapshotImageSetKey] = string(isJSON)
+	}
+
+	snapshots, err := listSnapshots(ctx, cli)
+	if err != nil {
+		return fmt.Errorf("failed to list Installation snapshots: %w", err)
+	}
+	var revision int64 = 1
+	if len(snapshots) > 0 {
+		latest := snapshots[len(snapshots)-1]
+		if latest.Data[snapshotComputedKey] == data[snapshotComputedKey] && latest.Data[snapshotImageSetKey] == data[snapshotImageSetKey] {
+			return nil
+		}
+		revision = snapshotRevision(latest) + 1
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName(revision),
+			Namespace: common.OperatorNamespace(),
+			Labels:    map[string]string{InstallationSnapshotLabel: strconv.FormatInt(revision, 10)},
+		},
+		Data: data,
+	}
+	if err := cli.Create(ctx, cm); err != nil {
+		return fmt.Errorf("failed to create Installation snapshot %s: %w", cm.Name, err)
+	}
+	log.Info("Recorded Installation snapshot", "revision", revision)
+
+	snapshots = append(snapshots, *cm)
+	for len(snapshots) > installationSnapshotLimit {
+		if err := cli.Delete(ctx, &snapshots[0]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Installation snapshot %s: %w", snapshots[0].Name, err)
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// rollbackToSnapshot reapplies the snapshot with the given revision: its ImageSet, if it had one, is recreated, or else
+// the ImageSet for the release is deleted, and the Installation's spec is replaced with the snapshot's, removing the
+// RollbackAnnotation. Only the fields that were set
+// in the Installation are restored, so that the overlay and the defaults are applied to them as usual.
+func rollbackToSnapshot(ctx context.Context, cli client.Client, instance *operator.Installation, revision string) error {
+	cm := &corev1.ConfigMap{}
+	err := cli.Get(ctx, client.ObjectKey{Name: "installation-snapshot-" + revision, Namespace: common.OperatorNamespace()}, cm)
+	if apierrors.IsNotFound(err) || (err == nil && cm.Labels[InstallationSnapshotLabel] != revision) {
+		return errSnapshotNotFound{revision: revision}
+	} else if err != nil {
+		return err
+	}
+
+	spec := operator.InstallationSpec{}
+	if err := json.Unmarshal([]byte(cm.Data[snapshotInstallationKey]), &spec); err != nil {
+		return fmt.Errorf("failed to decode Installation snapshot %s: %w", cm.Name, err)
+	}
+
+	computed := operator.InstallationSpec{}
+	if err := json.Unmarshal([]byte(cm.Data[snapshotComputedKey]), &computed); err != nil {
+		return fmt.Errorf("failed to decode Installation snapshot %s: %w", cm.Name, err)
+	}
+	name := imageset.SetName(computed.Variant)
+	if data, ok := cm.Data[snapshotImageSetKey]; ok {
+		is := &operator.ImageSet{}
+		if err := json.Unmarshal([]byte(data), is); err != nil {
+			return fmt.Errorf("failed to decode ImageSet in snapshot %s: %w", cm.Name, err)
+		}
+		// The operator only uses the ImageSet for its own release, so an ImageSet recorded by another release can't
+		// be restored.
+		if is.Name != name {
+			return errInvalidSnapshot{revision: revision, reason: fmt.Sprintf("its ImageSet %s is not for this release, which uses ImageSet %s", is.Name, name)}
+		}
+		if err := restoreImageSet(ctx, cli, is); err != nil {
+			return err
+		}
+	} else {
+		// The snapshot was applied with the default images, so an ImageSet created since would still override them.
+		err := cli.Delete(ctx, &operator.ImageSet{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ImageSet %s: %w", name, err)
+		}
+	}
+
+	instance.Spec = spec
+	delete(instance.Annotations, RollbackAnnotation)
+	if err := cli.Update(ctx, instance); err != nil {
+		return fmt.Errorf("failed to update Installation: %w", err)
+	}
+	return nil
+}
+
+// restoreImageSet creates the ImageSet or, if it exists and has changed, updates it to the snapshot's content.
+func restoreImageSet(ctx context.Context, cli client.Client, is *operator.ImageSet) error {
+	current := &operator.ImageSet{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: is.Name}, current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ImageSet %s: %w", is.Name, err)
+		}
+		if err := cli.Create(ctx, is); err != nil {
+			return fmt.Errorf("failed to restore ImageSet %s: %w", is.Name, err)
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(current.Spec, is.Spec) {
+		return nil
+	}
+	current.Spec = is.Spec
+	if err := cli.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed to restore ImageSet %s: %w", is.Name, err)
+	}
+	return nil
+}
+
+// rolledOut returns true if the DaemonSets and Deployments among the objects rendered for the Installation run the
+// latest revision of their spec on every pod, and every pod is available. The status manager only notices the changes
+// that have just been applied when it next syncs, so it can't tell on its own whether they have rolled out yet. A
+// configuration whose pods aren't all available isn't known to work, so it isn't worth rolling back to.
+func rolledOut(ctx context.Context, cli client.Client, objs []client.Object) (bool, error) {
+	for _, obj := range objs {
+		switch obj.(type) {
+		case *appsv1.DaemonSet:
+			ds := &appsv1.DaemonSet{}
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), ds); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			if ds.Status.ObservedGeneration < ds.Generation || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled ||
+				ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled {
+				return false, nil
+			}
+		case *appsv1.Deployment:
+			dep := &appsv1.Deployment{}
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), dep); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			replicas := int32(1)
+			if dep.Spec.Replicas != nil {
+				replicas = *dep.Spec.Replicas
+			}
+			if dep.Status.ObservedGeneration < dep.Generation || dep.Status.UpdatedReplicas < replicas || dep.Status.AvailableReplicas < replicas {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
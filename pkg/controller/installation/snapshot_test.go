@@ -0,0 +1,231 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+)
+
+var _ = Describe("Installation snapshots", func() {
+	var cli client.Client
+	var ctx context.Context
+	var is *operator.ImageSet
+
+	snapshotRevisions := func() []int64 {
+		snapshots, err := listSnapshots(ctx, cli)
+		Expect(err).NotTo(HaveOccurred())
+		var revs []int64
+		for _, s := range snapshots {
+			revs = append(revs, snapshotRevision(s))
+		}
+		return revs
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+		is = &operator.ImageSet{
+			ObjectMeta: metav1.ObjectMeta{Name: imageset.SetName(operator.Calico)},
+			Spec:       operator.ImageSetSpec{Images: []operator.Image{{Image: "calico/node", Digest: "sha256:aaaa"}}},
+		}
+	})
+
+	It("should record a snapshot only when the configuration changes", func() {
+		user := &operator.InstallationSpec{}
+		spec := &operator.InstallationSpec{Variant: operator.Calico}
+		Expect(recordSnapshot(ctx, cli, user, spec, nil)).To(Succeed())
+		Expect(recordSnapshot(ctx, cli, user, spec, nil)).To(Succeed())
+		Expect(snapshotRevisions()).To(Equal([]int64{1}))
+
+		Expect(recordSnapshot(ctx, cli, user, spec, is)).To(Succeed())
+		spec.FlexVolumePath = "/flex"
+		Expect(recordSnapshot(ctx, cli, user, spec, is)).To(Succeed())
+		Expect(snapshotRevisions()).To(Equal([]int64{1, 2, 3}))
+	})
+
+	It("should keep only the latest snapshots", func() {
+		for i := 0; i < installationSnapshotLimit+2; i++ {
+			spec := &operator.InstallationSpec{FlexVolumePath: fmt.Sprintf("/flex/%d", i)}
+			Expect(recordSnapshot(ctx, cli, spec, spec, nil)).To(Succeed())
+		}
+		revs := snapshotRevisions()
+		Expect(revs).To(HaveLen(installationSnapshotLimit))
+		Expect(revs[0]).To(Equal(int64(3)))
+		Expect(revs[len(revs)-1]).To(Equal(int64(installationSnapshotLimit + 2)))
+	})
+
+	It("should roll back the Installation and ImageSet to a snapshot", func() {
+		// Only the fields set in the Installation are restored, not the ones that were computed from the defaults.
+		Expect(recordSnapshot(ctx, cli,
+			&operator.InstallationSpec{FlexVolumePath: "/old"},
+			&operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/old", KubeletVolumePluginPath: "/var/lib/kubelet"},
+			is)).To(Succeed())
+
+		Expect(cli.Create(ctx, &operator.ImageSet{
+			ObjectMeta: metav1.ObjectMeta{Name: is.Name},
+			Spec:       operator.ImageSetSpec{Images: []operator.Image{{Image: "calico/node", Digest: "sha256:bbbb"}}},
+		})).To(Succeed())
+		instance := &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: map[string]string{RollbackAnnotation: "1"}},
+			Spec:       operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/new"},
+		}
+		Expect(cli.Create(ctx, instance)).To(Succeed())
+
+		Expect(rollbackToSnapshot(ctx, cli, instance, "1")).To(Succeed())
+
+		Expect(cli.Get(ctx, utils.DefaultInstanceKey, instance)).To(Succeed())
+		Expect(instance.Spec).To(Equal(operator.InstallationSpec{FlexVolumePath: "/old"}))
+		Expect(instance.Annotations).NotTo(HaveKey(RollbackAnnotation))
+		restored := &operator.ImageSet{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: is.Name}, restored)).To(Succeed())
+		Expect(restored.Spec).To(Equal(is.Spec))
+	})
+
+	It("should report a rollback to an unknown revision", func() {
+		instance := &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(cli.Create(ctx, instance)).To(Succeed())
+		Expect(cli.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "installation-snapshot-foo", Namespace: common.OperatorNamespace()}})).To(Succeed())
+
+		err := rollbackToSnapshot(ctx, cli, instance, "4")
+		Expect(err).To(BeAssignableToTypeOf(errSnapshotNotFound{}))
+		// Only ConfigMaps labelled as snapshots can be rolled back to.
+		err = rollbackToSnapshot(ctx, cli, instance, "foo")
+		Expect(err).To(BeAssignableToTypeOf(errSnapshotNotFound{}))
+	})
+
+	It("should refuse to restore an ImageSet for another release", func() {
+		is.Name = "calico-v3.0.0"
+		spec := &operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/old"}
+		Expect(recordSnapshot(ctx, cli, spec, spec, is)).To(Succeed())
+		instance := &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: map[string]string{RollbackAnnotation: "1"}},
+			Spec:       operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/new"},
+		}
+		Expect(cli.Create(ctx, instance)).To(Succeed())
+
+		err := rollbackToSnapshot(ctx, cli, instance, "1")
+		Expect(err).To(BeAssignableToTypeOf(errInvalidSnapshot{}))
+		Expect(cli.Get(ctx, client.ObjectKey{Name: is.Name}, &operator.ImageSet{})).NotTo(Succeed())
+		Expect(cli.Get(ctx, utils.DefaultInstanceKey, instance)).To(Succeed())
+		Expect(instance.Spec.FlexVolumePath).To(Equal("/new"))
+	})
+
+	It("should delete the ImageSet when rolling back to a snapshot that had none", func() {
+		spec := &operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/old"}
+		Expect(recordSnapshot(ctx, cli, spec, spec, nil)).To(Succeed())
+		Expect(cli.Create(ctx, is)).To(Succeed())
+		instance := &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: map[string]string{RollbackAnnotation: "1"}},
+			Spec:       operator.InstallationSpec{Variant: operator.Calico, FlexVolumePath: "/new"},
+		}
+		Expect(cli.Create(ctx, instance)).To(Succeed())
+
+		Expect(rollbackToSnapshot(ctx, cli, instance, "1")).To(Succeed())
+		err := cli.Get(ctx, client.ObjectKey{Name: is.Name}, &operator.ImageSet{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(cli.Get(ctx, utils.DefaultInstanceKey, instance)).To(Succeed())
+		Expect(instance.Spec.FlexVolumePath).To(Equal("/old"))
+
+		// There is nothing to delete when the ImageSet doesn't exist.
+		instance.Annotations = map[string]string{RollbackAnnotation: "1"}
+		Expect(cli.Update(ctx, instance)).To(Succeed())
+		Expect(rollbackToSnapshot(ctx, cli, instance, "1")).To(Succeed())
+	})
+
+	It("should only report the configuration as rolled out once the rendered workloads are updated and available", func() {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: common.CalicoNamespace, Generation: 2},
+			Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3},
+		}
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-kube-controllers", Namespace: common.CalicoNamespace, Generation: 1},
+		}
+		rendered := []client.Object{
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: common.CalicoNamespace}},
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: common.CalicoNamespace}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "calico-kube-controllers", Namespace: common.CalicoNamespace}},
+		}
+
+		// The workloads haven't been created yet.
+		done, err := rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		Expect(cli.Create(ctx, ds)).To(Succeed())
+		Expect(cli.Create(ctx, dep)).To(Succeed())
+		dep.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+		Expect(cli.Update(ctx, dep)).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		ds.Status = appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2}
+		Expect(cli.Update(ctx, ds)).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		// Updated pods that aren't available yet hold up the rollout.
+		ds.Status = appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2, NumberUnavailable: 1}
+		Expect(cli.Update(ctx, ds)).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		ds.Status = appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3}
+		Expect(cli.Update(ctx, ds)).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		dep.Status.AvailableReplicas = 0
+		Expect(cli.Update(ctx, dep)).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+		dep.Status.AvailableReplicas = 1
+		Expect(cli.Update(ctx, dep)).To(Succeed())
+
+		// Workloads that weren't rendered for the Installation are not considered.
+		Expect(cli.Create(ctx, &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: common.CalicoNamespace, Generation: 2},
+		})).To(Succeed())
+		done, err = rolledOut(ctx, cli, rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+	})
+})
@@ -17,6 +17,7 @@ package installation
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -28,6 +29,7 @@ import (
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/metrics"
 	"github.com/tigera/operator/pkg/controller/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -36,11 +38,19 @@ var typhaLog = logf.Log.WithName("typha_autoscaler")
 
 const (
 	defaultTyphaAutoscalerSyncPeriod = 10 * time.Second
+
+	// defaultTyphaScaleDownDelay is how long fewer replicas must be needed before typha is scaled down, when
+	// autoscaling is configured on the Installation.
+	defaultTyphaScaleDownDelay = 5 * time.Minute
+
+	// defaultConnectionsPerTypha is the number of connections served by a single typha for the ConnectionLoad policy.
+	defaultConnectionsPerTypha = 200
 )
 
 // typhaAutoscaler periodically lists the nodes and, if needed, scales the Typha deployment up/down.
 // Number of replicas should be at least (1 typha for every 200 nodes) + 1 but the number of typhas
-// cannot exceed the number of nodes+masters.
+// cannot exceed the number of nodes+masters. The policy, the ratio and the replica bounds can be configured on the
+// Installation's TyphaDeployment, in which case scaling down is delayed to avoid flapping.
 type typhaAutoscaler struct {
 	client            kubernetes.Interface
 	syncPeriod        time.Duration
@@ -53,6 +63,23 @@ type typhaAutoscaler struct {
 
 	// Number of currently running replicas.
 	activeReplicas int32
+
+	// The autoscaling configuration and Typha metrics port from the Installation. These are set by the core
+	// controller and read by the autoscaler's goroutine.
+	configLock  sync.Mutex
+	autoscaling *operator.TyphaAutoscaling
+	metricsPort *int32
+
+	// paused stops the autoscaler from scaling Typha while plan mode is enabled or reconciliation of the Installation
+	// is paused. It is guarded by configLock.
+	paused bool
+
+	countConnections typhaConnectionCounter
+
+	// scaleDownSince is when fewer replicas were first needed than are active. It is reset whenever that is no
+	// longer the case.
+	scaleDownSince time.Time
+	now            func() time.Time
 }
 
 type typhaAutoscalerOption func(*typhaAutoscaler)
@@ -64,6 +91,14 @@ func typhaAutoscalerPeriod(syncPeriod time.Duration) typhaAutoscalerOption {
 	}
 }
 
+// typhaAutoscalerConnectionCounter is an option that sets how the Typha connections are counted for the ConnectionLoad
+// policy.
+func typhaAutoscalerConnectionCounter(counter typhaConnectionCounter) typhaAutoscalerOption {
+	return func(t *typhaAutoscaler) {
+		t.countConnections = counter
+	}
+}
+
 // newTyphaAutoscaler creates a new Typha autoscaler, optionally applying any options to the default autoscaler instance.
 // The default sync period is 10 seconds.
 func newTyphaAutoscaler(cs kubernetes.Interface, nodeIndexInformer cache.SharedIndexInformer, typhaListWatch cache.ListerWatcher, statusManager status.StatusManager, options ...typhaAutoscalerOption) *typhaAutoscaler {
@@ -74,6 +109,8 @@ func newTyphaAutoscaler(cs kubernetes.Interface, nodeIndexInformer cache.SharedI
 		triggerRunChan:    make(chan chan error),
 		isDegradedChan:    make(chan chan bool),
 		nodeIndexInformer: nodeIndexInformer,
+		countConnections:  scrapeTyphaConnections(cs),
+		now:               time.Now,
 	}
 
 	// Configure an informer to monitor the active replicas.
@@ -82,6 +119,7 @@ func newTyphaAutoscaler(cs kubernetes.Interface, nodeIndexInformer cache.SharedI
 			if d, ok := obj.(*appsv1.Deployment); ok {
 				if d.Spec.Replicas != nil {
 					ta.activeReplicas = *d.Spec.Replicas
+					metrics.SetTyphaActualReplicas(ta.activeReplicas)
 				}
 			}
 		},
@@ -89,6 +127,7 @@ func newTyphaAutoscaler(cs kubernetes.Interface, nodeIndexInformer cache.SharedI
 			if d, ok := obj.(*appsv1.Deployment); ok {
 				if d.Spec.Replicas != nil {
 					ta.activeReplicas = *d.Spec.Replicas
+					metrics.SetTyphaActualReplicas(ta.activeReplicas)
 				}
 			}
 		},
@@ -162,6 +201,34 @@ func (t *typhaAutoscaler) start(ctx context.Context) {
 	}()
 }
 
+// setConfig updates the autoscaling configuration and the port on which Typha serves its metrics. The new
+// configuration is used from the next autoscale run.
+func (t *typhaAutoscaler) setConfig(autoscaling *operator.TyphaAutoscaling, metricsPort *int32) {
+	t.configLock.Lock()
+	defer t.configLock.Unlock()
+	t.autoscaling = autoscaling.DeepCopy()
+	t.metricsPort = metricsPort
+}
+
+// setPaused pauses or resumes scaling Typha. While paused, autoscale runs don't change the Typha deployment.
+func (t *typhaAutoscaler) setPaused(paused bool) {
+	t.configLock.Lock()
+	defer t.configLock.Unlock()
+	t.paused = paused
+}
+
+func (t *typhaAutoscaler) isPaused() bool {
+	t.configLock.Lock()
+	defer t.configLock.Unlock()
+	return t.paused
+}
+
+func (t *typhaAutoscaler) getConfig() (*operator.TyphaAutoscaling, *int32) {
+	t.configLock.Lock()
+	defer t.configLock.Unlock()
+	return t.autoscaling, t.metricsPort
+}
+
 func (t *typhaAutoscaler) triggerRun() error {
 	errChan := make(chan error)
 	t.triggerRunChan <- errChan
@@ -179,17 +246,32 @@ func (t *typhaAutoscaler) isDegraded() bool {
 
 // autoscaleReplicas calculates the number of typha pods that should be running and scales the typha deployment accordingly
 func (t *typhaAutoscaler) autoscaleReplicas() error {
+	if t.isPaused() {
+		typhaLog.V(5).Info("Typha autoscaling is paused")
+		return nil
+	}
 	allSchedulableNodes, linuxNodes, err := t.getNodeCounts()
 	if err != nil {
 		return fmt.Errorf("could not get number of nodes: %w", err)
 	}
 	typhaLog.V(5).Info("Number of nodes to consider for typha autoscaling", "all", allSchedulableNodes, "linux", linuxNodes)
-	expectedReplicas := common.GetExpectedTyphaScale(allSchedulableNodes)
+
+	autoscaling, metricsPort := t.getConfig()
+	expectedReplicas, err := t.expectedReplicas(autoscaling, metricsPort, allSchedulableNodes)
+	if err != nil {
+		return err
+	}
+	metrics.SetTyphaDesiredReplicas(expectedReplicas)
 	if linuxNodes < expectedReplicas {
 		return fmt.Errorf("not enough linux nodes to schedule typha pods on, require %d and have %d", expectedReplicas, linuxNodes)
 	}
 
 	typhaLog.V(5).Info("Checking if we need to scale typha", "expectedReplicas", expectedReplicas, "currentReplicas", t.activeReplicas)
+	if int32(expectedReplicas) < t.activeReplicas && !t.scaleDownAllowed(autoscaling) {
+		typhaLog.V(5).Info("Delaying scaling typha down", "since", t.scaleDownSince)
+		return nil
+	}
+	t.scaleDownSince = time.Time{}
 	if int32(expectedReplicas) != t.activeReplicas {
 		err = t.updateReplicas(int32(expectedReplicas))
 		if err != nil && !apierrors.IsNotFound(err) {
@@ -200,6 +282,63 @@ func (t *typhaAutoscaler) autoscaleReplicas() error {
 	return nil
 }
 
+// expectedReplicas returns the number of typha replicas for the configured policy, within the configured bounds.
+func (t *typhaAutoscaler) expectedReplicas(autoscaling *operator.TyphaAutoscaling, metricsPort *int32, nodes int) (int, error) {
+	if autoscaling == nil {
+		return common.GetExpectedTyphaScale(nodes), nil
+	}
+
+	var replicas int
+	switch autoscaling.Policy {
+	case operator.TyphaAutoscalingPolicyConnectionLoad:
+		if metricsPort == nil {
+			return 0, fmt.Errorf("the %s autoscaling policy requires typhaMetricsPort to be set", operator.TyphaAutoscalingPolicyConnectionLoad)
+		}
+		connections, err := t.countConnections(context.Background(), *metricsPort)
+		if err != nil {
+			return 0, fmt.Errorf("could not count typha connections: %w", err)
+		}
+		connectionsPerTypha := defaultConnectionsPerTypha
+		if autoscaling.ConnectionsPerTypha != nil {
+			connectionsPerTypha = int(*autoscaling.ConnectionsPerTypha)
+		}
+		// Round up and keep one extra typha for high availability, as the node count policy does.
+		replicas = (connections+connectionsPerTypha-1)/connectionsPerTypha + 1
+		typhaLog.V(5).Info("Typha connection load", "connections", connections, "connectionsPerTypha", connectionsPerTypha)
+	default:
+		nodesPerTypha := common.DefaultNodesPerTypha
+		if autoscaling.NodesPerTypha != nil {
+			nodesPerTypha = int(*autoscaling.NodesPerTypha)
+		}
+		replicas = common.GetExpectedTyphaScaleForRatio(nodes, nodesPerTypha)
+	}
+
+	if autoscaling.MinReplicas != nil && replicas < int(*autoscaling.MinReplicas) {
+		replicas = int(*autoscaling.MinReplicas)
+	}
+	if autoscaling.MaxReplicas != nil && replicas > int(*autoscaling.MaxReplicas) {
+		replicas = int(*autoscaling.MaxReplicas)
+	}
+	return replicas, nil
+}
+
+// scaleDownAllowed returns true once fewer replicas have been needed for the scale down delay. Without autoscaling
+// configuration, typha is scaled down right away.
+func (t *typhaAutoscaler) scaleDownAllowed(autoscaling *operator.TyphaAutoscaling) bool {
+	if autoscaling == nil {
+		return true
+	}
+	delay := defaultTyphaScaleDownDelay
+	if autoscaling.ScaleDownDelay != nil {
+		delay = autoscaling.ScaleDownDelay.Duration
+	}
+	now := t.now()
+	if t.scaleDownSince.IsZero() {
+		t.scaleDownSince = now
+	}
+	return now.Sub(t.scaleDownSince) >= delay
+}
+
 // updateReplicas updates the Typha deployment to the expected replicas if the current replica count differs.
 func (t *typhaAutoscaler) updateReplicas(expectedReplicas int32) error {
 	typha, err := t.client.AppsV1().Deployments(common.CalicoNamespace).Get(context.Background(), common.TyphaDeploymentName, metav1.GetOptions{})
@@ -17,6 +17,10 @@ package installation
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -25,6 +29,7 @@ import (
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/ptr"
 	. "github.com/tigera/operator/test"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -164,6 +169,30 @@ var _ = Describe("Test typha autoscaler ", func() {
 		verifyTyphaReplicas(c, 2)
 	})
 
+	It("should not scale Typha while paused", func() {
+		var r int32 = 0
+		typha := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-typha", Namespace: "calico-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &r},
+		}
+		_, err := c.AppsV1().Deployments("calico-system").Create(ctx, typha, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+		_ = CreateNode(c, "node1", map[string]string{"kubernetes.io/os": "linux"}, nil)
+
+		ta := newTyphaAutoscaler(c, nodeIndexInformer, tlw, statusManager, typhaAutoscalerPeriod(10*time.Millisecond))
+		ta.setPaused(true)
+		ta.start(ctx)
+		Consistently(func() int32 {
+			d, err := c.AppsV1().Deployments("calico-system").Get(ctx, "calico-typha", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			return *d.Spec.Replicas
+		}, 200*time.Millisecond).Should(Equal(int32(0)))
+
+		ta.setPaused(false)
+		verifyTyphaReplicas(c, 1)
+	})
+
 	It("should ignore non-migrated nodes in its count", func() {
 		typhaMeta := metav1.ObjectMeta{
 			Name:      "calico-typha",
@@ -267,6 +296,119 @@ var _ = Describe("Test typha autoscaler ", func() {
 
 		statusManager.AssertExpectations(GinkgoT())
 	})
+
+	It("should apply the configured ratio and replica bounds", func() {
+		ta := newTyphaAutoscaler(c, nodeIndexInformer, tlw, statusManager)
+
+		replicas, err := ta.expectedReplicas(&operator.TyphaAutoscaling{NodesPerTypha: ptr.Int32ToPtr(10)}, nil, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(Equal(7))
+
+		replicas, err = ta.expectedReplicas(&operator.TyphaAutoscaling{NodesPerTypha: ptr.Int32ToPtr(10), MaxReplicas: ptr.Int32ToPtr(5)}, nil, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(Equal(5))
+
+		replicas, err = ta.expectedReplicas(&operator.TyphaAutoscaling{MinReplicas: ptr.Int32ToPtr(2)}, nil, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(Equal(2))
+
+		_, err = ta.expectedReplicas(&operator.TyphaAutoscaling{Policy: operator.TyphaAutoscalingPolicyConnectionLoad}, nil, 1)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should scale on connection load and delay scaling down", func() {
+		var r int32 = 0
+		_, err := c.AppsV1().Deployments("calico-system").Create(ctx, &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-typha", Namespace: "calico-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &r},
+		}, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+		for i := 1; i <= 5; i++ {
+			CreateNode(c, fmt.Sprintf("node%d", i), map[string]string{"kubernetes.io/os": "linux"}, nil)
+		}
+		Eventually(func() int {
+			n, _, _ := (&typhaAutoscaler{nodeIndexInformer: nodeIndexInformer}).getNodeCounts()
+			return n
+		}, 5*time.Second).Should(Equal(5))
+
+		connections := 250
+		now := time.Now()
+		ta := newTyphaAutoscaler(c, nodeIndexInformer, tlw, statusManager, typhaAutoscalerConnectionCounter(func(context.Context, int32) (int, error) {
+			return connections, nil
+		}))
+		ta.now = func() time.Time { return now }
+		ta.setConfig(&operator.TyphaAutoscaling{
+			Policy:              operator.TyphaAutoscalingPolicyConnectionLoad,
+			ConnectionsPerTypha: ptr.Int32ToPtr(100),
+			MaxReplicas:         ptr.Int32ToPtr(4),
+		}, ptr.Int32ToPtr(9093))
+
+		// 250 connections need three typhas, plus one for high availability.
+		Expect(ta.autoscaleReplicas()).NotTo(HaveOccurred())
+		verifyTyphaReplicas(c, 4)
+		ta.activeReplicas = 4
+
+		// The maximum is never exceeded.
+		connections = 1000
+		Expect(ta.autoscaleReplicas()).NotTo(HaveOccurred())
+		verifyTyphaReplicas(c, 4)
+
+		// Scaling down waits for the default delay.
+		connections = 50
+		Expect(ta.autoscaleReplicas()).NotTo(HaveOccurred())
+		verifyTyphaReplicas(c, 4)
+		now = now.Add(defaultTyphaScaleDownDelay)
+		Expect(ta.autoscaleReplicas()).NotTo(HaveOccurred())
+		verifyTyphaReplicas(c, 2)
+	})
+
+	It("should read the connections from the typha metrics", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/metrics"))
+			_, _ = w.Write([]byte("# HELP typha_connections_active Number of open client connections.\n# TYPE typha_connections_active gauge\ntypha_connections_active 42\n"))
+		}))
+		defer server.Close()
+
+		u, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(u.Port())
+		Expect(err).NotTo(HaveOccurred())
+
+		connections, err := scrapeTyphaPod(ctx, server.Client(), u.Hostname(), int32(port))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(connections).To(Equal(42))
+	})
+
+	It("should skip the typha pods whose metrics can't be read", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("# TYPE typha_connections_active gauge\ntypha_connections_active 42\n"))
+		}))
+		defer server.Close()
+
+		u, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(u.Port())
+		Expect(err).NotTo(HaveOccurred())
+
+		typhaPod := func(name, ip string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "calico-system", Labels: map[string]string{"k8s-app": "calico-typha"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+			}
+		}
+		// Nothing listens on the second pod's address.
+		_, err = c.CoreV1().Pods("calico-system").Create(ctx, typhaPod("unreachable", "127.0.0.2"), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scrapeTyphaConnections(c)(ctx, int32(port))
+		Expect(err).To(HaveOccurred())
+
+		_, err = c.CoreV1().Pods("calico-system").Create(ctx, typhaPod("reachable", u.Hostname()), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		connections, err := scrapeTyphaConnections(c)(ctx, int32(port))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(connections).To(Equal(42))
+	})
 })
 
 func verifyTyphaReplicas(c kubernetes.Interface, expectedReplicas int) {
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// typhaConnectionsMetric is the gauge in which Typha reports the number of connected clients.
+	typhaConnectionsMetric = "typha_connections_active"
+
+	typhaMetricsTimeout = 5 * time.Second
+)
+
+// typhaConnectionCounter returns the total number of client connections across the Typha replicas. The port is
+// the port on which Typha serves its Prometheus metrics.
+type typhaConnectionCounter func(ctx context.Context, port int32) (int, error)
+
+// scrapeTyphaConnections returns a typhaConnectionCounter that scrapes the metrics endpoint of every running Typha pod.
+// Pods whose metrics can't be read, e.g. because they are being replaced, are skipped. It fails only if none of the
+// running pods could be scraped.
+func scrapeTyphaConnections(cs kubernetes.Interface) typhaConnectionCounter {
+	httpClient := &http.Client{Timeout: typhaMetricsTimeout}
+	return func(ctx context.Context, port int32) (int, error) {
+		pods, err := cs.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", render.AppLabelName, render.TyphaK8sAppName),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		total, scraped := 0, 0
+		var lastErr error
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+				continue
+			}
+			connections, err := scrapeTyphaPod(ctx, httpClient, pod.Status.PodIP, port)
+			if err != nil {
+				typhaLog.V(2).Info("Skipping typha pod whose metrics could not be read", "pod", pod.Name, "error", err.Error())
+				lastErr = fmt.Errorf("could not read the metrics of typha pod %s: %w", pod.Name, err)
+				continue
+			}
+			total += connections
+			scraped++
+		}
+		if scraped == 0 && lastErr != nil {
+			return 0, lastErr
+		}
+		return total, nil
+	}
+}
+
+// scrapeTyphaPod returns the number of client connections that a single Typha pod reports.
+func scrapeTyphaPod(ctx context.Context, httpClient *http.Client, ip string, port int32) (int, error) {
+	url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(ip, strconv.Itoa(int(port))))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	family, ok := families[typhaConnectionsMetric]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found", typhaConnectionsMetric)
+	}
+	connections := 0.0
+	for _, m := range family.GetMetric() {
+		connections += m.GetGauge().GetValue()
+	}
+	return int(connections), nil
+}
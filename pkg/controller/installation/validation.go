@@ -441,6 +441,15 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		}
 	}
 
+	if as := instance.Spec.TyphaDeployment.GetAutoscaling(); as != nil {
+		if as.MinReplicas != nil && as.MaxReplicas != nil && *as.MinReplicas > *as.MaxReplicas {
+			return fmt.Errorf("Installation spec.TyphaDeployment.Spec.Autoscaling.MinReplicas must not exceed MaxReplicas")
+		}
+		if as.Policy == operatorv1.TyphaAutoscalingPolicyConnectionLoad && instance.Spec.TyphaMetricsPort == nil {
+			return fmt.Errorf("Installation spec.TyphaMetricsPort must be set to use the %s typha autoscaling policy", operatorv1.TyphaAutoscalingPolicyConnectionLoad)
+		}
+	}
+
 	// Verify the CSINodeDriverDaemonSet overrides, if specified, is valid.
 	if ds := instance.Spec.CSINodeDriverDaemonSet; ds != nil {
 		err := validation.ValidateReplicatedPodResourceOverrides(ds, csinodedriver.ValidateCSIDaemonsetContainer, validation.NoContainersDefined)
@@ -478,6 +487,66 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		}
 	}
 
+	if cm := instance.Spec.CertificateManagement; cm != nil {
+		if cm.Issuer != nil {
+			if cm.Issuer.Name == "" {
+				return fmt.Errorf("Installation spec.CertificateManagement.Issuer.Name must be set")
+			}
+			if len(cm.CSRApprovals) > 0 {
+				return fmt.Errorf("Installation spec.CertificateManagement.CSRApprovals is not valid when an issuer is configured")
+			}
+		} else if parts := strings.Split(cm.SignerName, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("Installation spec.CertificateManagement.SignerName must be formatted as <my-domain>/<my-signername> unless an issuer is configured")
+		}
+	}
+
+	for i, o := range instance.Spec.ImageOverrides {
+		if (o.Image == "") == (o.Prefix == "") {
+			return fmt.Errorf("Installation spec.ImageOverrides[%d] must set exactly one of image and prefix", i)
+		}
+		if o.Mirror == "" {
+			return fmt.Errorf("Installation spec.ImageOverrides[%d].Mirror must be set", i)
+		}
+		if o.Image != "" && !strings.HasSuffix(o.Mirror, "/") {
+			return fmt.Errorf("Installation spec.ImageOverrides[%d].Mirror must end with a '/' when overriding an image", i)
+		}
+	}
+
+	if v := instance.Spec.ImageSetVerification; v != nil && v.PublicKeySecretName == "" {
+		return fmt.Errorf("Installation spec.ImageSetVerification.PublicKeySecretName must be set")
+	}
+
+	if u := instance.Spec.NodeUpgrade; u != nil {
+		if p := u.GetBatchPercent(); p < 1 || p > 100 {
+			return fmt.Errorf("Installation spec.NodeUpgrade.BatchPercent %d must be between 1 and 100", p)
+		}
+		if u.GetSoakPeriod() < 0 {
+			return fmt.Errorf("Installation spec.NodeUpgrade.SoakPeriod must not be negative")
+		}
+		switch u.GetFailurePolicy() {
+		case operatorv1.NodeUpgradeFailurePolicyPause, operatorv1.NodeUpgradeFailurePolicyRollback:
+		default:
+			return fmt.Errorf("Installation spec.NodeUpgrade.FailurePolicy '%s' is not supported", u.FailurePolicy)
+		}
+	}
+
+	if c := instance.Spec.ImageSetCanary; c != nil {
+		if c.ImageSet == "" {
+			return fmt.Errorf("Installation spec.ImageSetCanary.ImageSet must be set")
+		}
+		if len(c.NodeSelector) == 0 {
+			return fmt.Errorf("Installation spec.ImageSetCanary.NodeSelector must select the canary nodes")
+		}
+		if c.PromoteAfter != nil && c.PromoteAfter.Duration < 0 {
+			return fmt.Errorf("Installation spec.ImageSetCanary.PromoteAfter must not be negative")
+		}
+		// A staged upgrade rolls calico-node back to templates that may predate the canary, and so run calico-node on
+		// the canary nodes too, and the canary's health would pause or roll back the upgrade of every other node.
+		if instance.Spec.NodeUpgrade != nil {
+			return fmt.Errorf("Installation spec.ImageSetCanary cannot be used with spec.NodeUpgrade")
+		}
+	}
+
 	return nil
 }
 
@@ -16,8 +16,10 @@ package installation
 
 import (
 	"path/filepath"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -51,6 +53,117 @@ var _ = Describe("Installation validation tests", func() {
 		}
 	})
 
+	It("should validate the typha autoscaling configuration", func() {
+		var minReplicas, maxReplicas int32 = 3, 2
+		instance.Spec.TyphaDeployment = &operator.TyphaDeployment{
+			Spec: &operator.TyphaDeploymentSpec{
+				Autoscaling: &operator.TyphaAutoscaling{MinReplicas: &minReplicas, MaxReplicas: &maxReplicas},
+			},
+		}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		maxReplicas = 5
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.TyphaDeployment.Spec.Autoscaling.Policy = operator.TyphaAutoscalingPolicyConnectionLoad
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		var port int32 = 9093
+		instance.Spec.TyphaMetricsPort = &port
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should not allow CSR approvals when an issuer is configured", func() {
+		instance.Spec.CertificateManagement = &operator.CertificateManagement{
+			CACert: []byte("ca"),
+			Issuer: &operator.CertificateIssuer{Name: "my-issuer"},
+		}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CertificateManagement.CSRApprovals = []operator.CSRApproval{{SecretName: "my-secret"}}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should require a signer name unless an issuer is configured", func() {
+		instance.Spec.CertificateManagement = &operator.CertificateManagement{CACert: []byte("ca")}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		for _, name := range []string{"example.com", "example.com/", "/signer", "example.com/signer/extra"} {
+			instance.Spec.CertificateManagement.SignerName = name
+			Expect(validateCustomResource(instance)).To(HaveOccurred(), name)
+		}
+
+		instance.Spec.CertificateManagement.SignerName = "example.com/signer"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should validate image overrides", func() {
+		instance.Spec.ImageOverrides = []operator.ImageOverride{{Image: "calico/node", Mirror: "mirror.example.com/patched/"}}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.ImageOverrides[0].Mirror = "mirror.example.com/patched"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.ImageOverrides[0] = operator.ImageOverride{Prefix: "docker.io/", Mirror: "mirror.example.com/docker-"}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.ImageOverrides[0].Image = "calico/node"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.ImageOverrides[0] = operator.ImageOverride{Prefix: "docker.io/"}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should require a key Secret for ImageSet verification", func() {
+		instance.Spec.ImageSetVerification = &operator.ImageSetVerification{}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.ImageSetVerification.PublicKeySecretName = "imageset-keys"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should require an ImageSet and node selector for an ImageSet canary", func() {
+		instance.Spec.ImageSetCanary = &operator.ImageSetCanary{ImageSet: "calico-canary"}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.ImageSetCanary.NodeSelector = map[string]string{"canary": "true"}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.ImageSetCanary.ImageSet = ""
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should not allow a negative canary promotion delay", func() {
+		instance.Spec.ImageSetCanary = &operator.ImageSetCanary{
+			ImageSet:     "calico-canary",
+			NodeSelector: map[string]string{"canary": "true"},
+			PromoteAfter: &metav1.Duration{Duration: -time.Minute},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError("Installation spec.ImageSetCanary.PromoteAfter must not be negative"))
+	})
+
+	It("should not allow an ImageSet canary with a staged node upgrade", func() {
+		instance.Spec.ImageSetCanary = &operator.ImageSetCanary{ImageSet: "calico-canary", NodeSelector: map[string]string{"canary": "true"}}
+		instance.Spec.NodeUpgrade = &operator.NodeUpgrade{}
+		Expect(validateCustomResource(instance)).To(MatchError("Installation spec.ImageSetCanary cannot be used with spec.NodeUpgrade"))
+	})
+
+	It("should validate the staged node upgrade settings", func() {
+		instance.Spec.NodeUpgrade = &operator.NodeUpgrade{}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		var pct int32 = 0
+		instance.Spec.NodeUpgrade.BatchPercent = &pct
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		pct = 25
+		instance.Spec.NodeUpgrade.FailurePolicy = "Ignore"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.NodeUpgrade.FailurePolicy = operator.NodeUpgradeFailurePolicyRollback
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
 	It("should not allow blocksize to exceed the pool size", func() {
 		// Try with an invalid block size.
 		var twentySix int32 = 26
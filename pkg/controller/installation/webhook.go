@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+// Webhook validates Installations at admission time using the same defaulting, overlay merge and validation as the
+// core controller, so that an invalid Installation is rejected rather than reported as degraded after the fact.
+//
+// It doesn't replace the controller's validation: the webhook fails open, so an Installation admitted while the
+// operator is unavailable is only validated when it is reconciled, and the configuration that the controller converts
+// from an existing manifest-based install is not merged in before validating.
+type Webhook struct {
+	// Client is used to read the Installation that the admitted one is merged with, and the cluster configuration that
+	// is merged into it.
+	Client client.Client
+
+	// Provider is the detected Kubernetes provider, used when the Installation doesn't specify one.
+	Provider operatorv1.Provider
+}
+
+func (w *Webhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *Webhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *Webhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+// validate merges the detected provider and the OpenShift, kubeadm and EKS configuration into a copy of the
+// Installation, fills in the defaults and validates it, then merges the 'overlay' Installation over the 'default' one
+// and validates the result, like the core controller does.
+func (w *Webhook) validate(ctx context.Context, obj runtime.Object) error {
+	instance, ok := obj.(*operatorv1.Installation)
+	if !ok {
+		return fmt.Errorf("expected an Installation but got %T", obj)
+	}
+
+	base, overlay := instance.DeepCopy(), (*operatorv1.Installation)(nil)
+	switch instance.Name {
+	case utils.DefaultInstanceKey.Name:
+		o := &operatorv1.Installation{}
+		if err := w.Client.Get(ctx, utils.OverlayInstanceKey, o); err == nil {
+			overlay = o
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+	case utils.OverlayInstanceKey.Name:
+		// The overlay is only used once it is merged over the default Installation.
+		base = &operatorv1.Installation{}
+		if err := w.Client.Get(ctx, utils.DefaultInstanceKey, base); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		overlay = instance
+	}
+
+	if err := updateInstallationWithDefaults(ctx, w.Client, base, w.Provider, nil); err != nil {
+		return err
+	}
+	if overlay == nil {
+		return validateCustomResource(base)
+	}
+	if instance.Name == utils.DefaultInstanceKey.Name {
+		if err := validateCustomResource(base); err != nil {
+			return err
+		}
+	}
+	return MergeOverlay(base, overlay)
+}
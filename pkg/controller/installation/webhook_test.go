@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tigera/operator/pkg/apis"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+var _ = Describe("Installation webhook", func() {
+	var w *Webhook
+	var cli client.Client
+	var instance *operator.Installation
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(clientgoscheme.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		w = &Webhook{Client: cli, Provider: operator.ProviderNone}
+		instance = &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	})
+
+	It("should accept an Installation that only needs defaults", func() {
+		Expect(w.ValidateCreate(context.Background(), instance)).To(Succeed())
+		Expect(w.ValidateUpdate(context.Background(), instance, instance)).To(Succeed())
+	})
+
+	It("should not modify the Installation when validating", func() {
+		Expect(w.ValidateCreate(context.Background(), instance)).To(Succeed())
+		Expect(instance.Spec).To(Equal(operator.InstallationSpec{}))
+	})
+
+	It("should reject an Installation that fails validation", func() {
+		var twentySix int32 = 26
+		instance.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+			IPPools: []operator.IPPool{{CIDR: "192.168.0.0/27", BlockSize: &twentySix}},
+		}
+		Expect(w.ValidateCreate(context.Background(), instance)).To(HaveOccurred())
+
+		old := instance.DeepCopy()
+		instance.Spec.CalicoNetwork.IPPools[0].CIDR = "192.168.0.0/26"
+		Expect(w.ValidateUpdate(context.Background(), old, instance)).To(Succeed())
+	})
+
+	It("should validate the IP pools against the kubeadm pod CIDR", func() {
+		Expect(cli.Create(context.Background(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: kubeadmConfigMap, Namespace: metav1.NamespaceSystem},
+			Data:       map[string]string{"ClusterConfiguration": "podSubnet: 10.0.0.0/8"},
+		})).To(Succeed())
+		Expect(w.ValidateCreate(context.Background(), instance)).To(Succeed())
+
+		instance.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{IPPools: []operator.IPPool{{CIDR: "192.168.0.0/16"}}}
+		Expect(w.ValidateCreate(context.Background(), instance)).To(MatchError(ContainSubstring("not within the platform's configured pod network CIDR")))
+	})
+
+	It("should reject an Installation whose provider doesn't match the detected one", func() {
+		w.Provider = operator.ProviderEKS
+		instance.Spec.KubernetesProvider = operator.ProviderGKE
+		Expect(w.ValidateCreate(context.Background(), instance)).To(HaveOccurred())
+	})
+
+	Context("with an overlay", func() {
+		var overlay *operator.Installation
+
+		BeforeEach(func() {
+			var twentySix int32 = 26
+			overlay = &operator.Installation{
+				ObjectMeta: metav1.ObjectMeta{Name: "overlay"},
+				Spec: operator.InstallationSpec{
+					CalicoNetwork: &operator.CalicoNetworkSpec{
+						IPPools: []operator.IPPool{{CIDR: "192.168.0.0/27", BlockSize: &twentySix}},
+					},
+				},
+			}
+		})
+
+		It("should accept an overlay before there is a default Installation", func() {
+			Expect(w.ValidateCreate(context.Background(), overlay)).To(Succeed())
+		})
+
+		It("should reject an overlay that makes the computed config invalid", func() {
+			Expect(cli.Create(context.Background(), instance)).To(Succeed())
+			Expect(w.ValidateCreate(context.Background(), overlay)).To(MatchError(ContainSubstring("invalid computed config")))
+
+			overlay.Spec = operator.InstallationSpec{Registry: "example.com/"}
+			Expect(w.ValidateCreate(context.Background(), overlay)).To(Succeed())
+		})
+
+		It("should validate the default Installation merged with the overlay", func() {
+			Expect(cli.Create(context.Background(), overlay)).To(Succeed())
+			Expect(w.ValidateUpdate(context.Background(), instance, instance)).To(MatchError(ContainSubstring("invalid computed config")))
+		})
+	})
+
+	It("should allow deletes", func() {
+		instance.Spec.CNI = &operator.CNISpec{Type: "Bogus"}
+		Expect(w.ValidateDelete(context.Background(), instance)).To(Succeed())
+	})
+})
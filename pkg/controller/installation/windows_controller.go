@@ -56,6 +56,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const windowsControllerName = "tigera-windows-controller"
+
 var logw = logf.Log.WithName("controller_windows")
 
 // Add creates a new Tiers Controller and adds it to the Manager.
@@ -66,7 +68,7 @@ func AddWindowsController(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("failed to create Windows Reconciler: %w", err)
 	}
 
-	c, err := controller.New("tigera-windows-controller", mgr, controller.Options{Reconciler: ri})
+	c, err := controller.New(windowsControllerName, mgr, controller.Options{Reconciler: ri})
 	if err != nil {
 		return fmt.Errorf("Failed to create tigera-windows-controller: %w", err)
 	}
@@ -114,7 +116,7 @@ func AddWindowsController(mgr manager.Manager, opts options.AddOptions) error {
 		}
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("tigera-windows-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -198,7 +200,7 @@ type ReconcileWindows struct {
 
 // newWindowsReconciler returns a new reconcile.Reconciler
 func newWindowsReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileWindows, error) {
-	statusManager := status.New(mgr.GetClient(), "calico-windows", opts.KubernetesVersion)
+	statusManager := status.NewForManager(mgr, "calico-windows", opts.KubernetesVersion)
 
 	r := &ReconcileWindows{
 		config:               mgr.GetConfig(),
@@ -244,6 +246,13 @@ func (r *ReconcileWindows) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// Mark CR found so we can report converter problems via tigerastatus
 	r.status.OnCRFound()
+
+	// Pausing the Installation pauses calico-node-windows as well. The paused state is reported by the core controller.
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// FIXME: add logic to merge Installation status metadata
 
 	// FIXME: add logic to update Installation status conditions that doesn't conflict with
@@ -301,7 +310,7 @@ func (r *ReconcileWindows) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 
-	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -425,6 +434,11 @@ func (r *ReconcileWindows) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 
+	if err = imageset.VerifyImageSet(ctx, r.client, imageSet); err != nil {
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error verifying ImageSet signature", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	if err = imageset.ResolveImages(imageSet, component); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceValidationError, "Error resolving ImageSet for components", err, reqLogger)
 		return reconcile.Result{}, err
@@ -432,6 +446,7 @@ func (r *ReconcileWindows) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// Create a component handler to create or update the rendered components.
 	handler := utils.NewComponentHandler(logw, r.client, r.scheme, instance)
+	handler.SetController(windowsControllerName)
 	if err := handler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
 		return reconcile.Result{}, err
@@ -140,7 +140,7 @@ var _ = Describe("windows-controller installation tests", func() {
 				Spec: operator.InstallationSpec{
 					Variant:               operator.Calico,
 					Registry:              "some.registry.org/",
-					CertificateManagement: &operator.CertificateManagement{CACert: cert},
+					CertificateManagement: &operator.CertificateManagement{SignerName: "a.b/c", CACert: cert},
 					WindowsNodes:          &operator.WindowsNodeSpec{},
 					ServiceCIDRs:          []string{"10.96.0.0/12"},
 					// Add a VXLAN IP pool, which is supported by Calico for Windows
@@ -157,7 +157,7 @@ var _ = Describe("windows-controller installation tests", func() {
 					},
 				},
 			}
-			Expect(updateInstallationWithDefaults(ctx, r.client, cr, r.autoDetectedProvider)).NotTo(HaveOccurred())
+			Expect(updateInstallationWithDefaults(ctx, r.client, cr, r.autoDetectedProvider, nil)).NotTo(HaveOccurred())
 			certificateManager, err := certificatemanager.Create(c, nil, "", common.OperatorNamespace(), certificatemanager.AllowCACreation())
 			Expect(err).NotTo(HaveOccurred())
 			prometheusTLS, err := certificateManager.GetOrCreateKeyPair(c, monitor.PrometheusClientTLSSecretName, common.OperatorNamespace(), []string{monitor.PrometheusClientTLSSecretName})
@@ -196,7 +196,7 @@ var _ = Describe("windows-controller installation tests", func() {
 				cr.Status = operator.InstallationStatus{
 					Variant: operator.Calico,
 				}
-				Expect(updateInstallationWithDefaults(ctx, r.client, cr, r.autoDetectedProvider)).NotTo(HaveOccurred())
+				Expect(updateInstallationWithDefaults(ctx, r.client, cr, r.autoDetectedProvider, nil)).NotTo(HaveOccurred())
 
 				// Set serviceCIDRs in the installation (required for Calico for Windows)
 				cr.Spec.ServiceCIDRs = []string{"10.96.0.0/12"}
@@ -621,7 +621,7 @@ var _ = Describe("windows-controller installation tests", func() {
 						Spec: operator.InstallationSpec{
 							Variant:               operator.Calico,
 							Registry:              "some.registry.org/",
-							CertificateManagement: &operator.CertificateManagement{CACert: prometheusTLS.GetCertificatePEM()},
+							CertificateManagement: &operator.CertificateManagement{SignerName: "a.b/c", CACert: prometheusTLS.GetCertificatePEM()},
 							CalicoNetwork: &operator.CalicoNetworkSpec{
 								WindowsDataplane: &winDp,
 								IPPools: []operator.IPPool{
@@ -646,7 +646,7 @@ var _ = Describe("windows-controller installation tests", func() {
 							},
 						},
 					}
-					Expect(updateInstallationWithDefaults(ctx, r.client, instance, r.autoDetectedProvider)).NotTo(HaveOccurred())
+					Expect(updateInstallationWithDefaults(ctx, r.client, instance, r.autoDetectedProvider, nil)).NotTo(HaveOccurred())
 					Expect(c.Create(ctx, instance)).NotTo(HaveOccurred())
 				})
 				AfterEach(func() {
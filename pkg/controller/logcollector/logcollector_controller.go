@@ -17,6 +17,8 @@ package logcollector
 import (
 	"context"
 	"fmt"
+	"net"
+	neturl "net/url"
 	"strings"
 
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
@@ -41,6 +43,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	v1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/status"
@@ -54,6 +57,8 @@ import (
 	"github.com/tigera/operator/pkg/url"
 )
 
+const controllerName = "logcollector-controller"
+
 const ResourceName = "log-collector"
 
 var log = logf.Log.WithName("controller_logcollector")
@@ -73,7 +78,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady, tierWatchReady)
 
 	// Create a new controller
-	controller, err := controller.New("logcollector-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	controller, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
 	if err != nil {
 		return fmt.Errorf("Failed to create logcollector-controller: %v", err)
 	}
@@ -105,7 +110,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		client:          mgr.GetClient(),
 		scheme:          mgr.GetScheme(),
 		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "log-collector", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "log-collector", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
 		licenseAPIReady: licenseAPIReady,
 		tierWatchReady:  tierWatchReady,
@@ -136,7 +141,7 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("logcollector-controller failed to watch Tigera network resource: %v", err)
 	}
 
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("logcollector-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -151,6 +156,17 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		}
 	}
 
+	// Watch the secrets with the credentials of the Kafka, HTTP and OTLP stores, which are named in the LogCollector.
+	if err = utils.AddNamedSecretsWatch(c, common.OperatorNamespace(), func() []string {
+		instance := &operatorv1.LogCollector{}
+		if err := mgr.GetClient().Get(context.Background(), utils.DefaultTSEEInstanceKey, instance); err != nil {
+			return nil
+		}
+		return additionalStoreSecretNames(instance)
+	}); err != nil {
+		return fmt.Errorf("log-collector-controller failed to watch Secrets: %v", err)
+	}
+
 	for _, configMapName := range []string{render.FluentdFilterConfigMapName, relasticsearch.ClusterConfigConfigMapName} {
 		if err = utils.AddConfigMapWatch(c, configMapName, common.OperatorNamespace(), &handler.EnqueueRequestForObject{}); err != nil {
 			return fmt.Errorf("logcollector-controller failed to watch ConfigMap %s: %v", configMapName, err)
@@ -204,12 +220,73 @@ func GetLogCollector(ctx context.Context, cli client.Client) (*operatorv1.LogCol
 				return nil, fmt.Errorf("Syslog config has invalid Endpoint: %s", err)
 			}
 		}
+		if instance.Spec.AdditionalStores.Kafka != nil {
+			for _, broker := range instance.Spec.AdditionalStores.Kafka.Brokers {
+				if _, _, err := net.SplitHostPort(broker); err != nil {
+					return nil, fmt.Errorf("Kafka config has invalid broker %q: %s", broker, err)
+				}
+			}
+		}
+		if instance.Spec.AdditionalStores.HTTP != nil {
+			if err := validateHTTPStore(instance.Spec.AdditionalStores.HTTP.Endpoint, instance.Spec.AdditionalStores.HTTP.Headers); err != nil {
+				return nil, fmt.Errorf("HTTP config is invalid: %s", err)
+			}
+		}
+		if instance.Spec.AdditionalStores.OTLP != nil {
+			if err := validateHTTPStore(instance.Spec.AdditionalStores.OTLP.Endpoint, instance.Spec.AdditionalStores.OTLP.Headers); err != nil {
+				return nil, fmt.Errorf("OTLP config is invalid: %s", err)
+			}
+		}
 	}
 
 	return instance, nil
 }
 
-// fillDefaults sets the default value of CollectProcessPath, syslog LogTypes, if not set.
+// additionalStoreSecretNames returns the names of the secrets in the operator namespace with the credentials of the
+// LogCollector's Kafka, HTTP and OTLP stores.
+func additionalStoreSecretNames(instance *operatorv1.LogCollector) []string {
+	stores := instance.Spec.AdditionalStores
+	if stores == nil {
+		return nil
+	}
+	var names []string
+	if stores.Kafka != nil && stores.Kafka.CredentialsSecretName != "" {
+		names = append(names, stores.Kafka.CredentialsSecretName)
+	}
+	var headers []v1.HTTPHeader
+	if stores.HTTP != nil {
+		headers = append(headers, stores.HTTP.Headers...)
+	}
+	if stores.OTLP != nil {
+		headers = append(headers, stores.OTLP.Headers...)
+	}
+	for _, h := range headers {
+		if h.ValueFrom != nil {
+			names = append(names, h.ValueFrom.Name)
+		}
+	}
+	return names
+}
+
+// validateHTTPStore validates the endpoint and headers of a store that logs are sent to over HTTP.
+func validateHTTPStore(endpoint string, headers []v1.HTTPHeader) error {
+	u, err := neturl.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid Endpoint: %s", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid Endpoint %q: must be an http or https URL", endpoint)
+	}
+	for _, h := range headers {
+		if (h.Value == "") == (h.ValueFrom == nil) {
+			return fmt.Errorf("header %q must have exactly one of value and valueFrom", h.Name)
+		}
+	}
+	return nil
+}
+
+// fillDefaults sets the default value of CollectProcessPath, syslog LogTypes and the LogTypes of the other additional
+// stores, if not set.
 // This function returns the fields which were set to a default value in the logcollector instance.
 func fillDefaults(instance *operatorv1.LogCollector) []string {
 	// Keep track of whether we changed the LogCollector instance during reconcile, so that we know to save it.
@@ -245,10 +322,37 @@ func fillDefaults(instance *operatorv1.LogCollector) []string {
 				modifiedFields = append(modifiedFields, "AdditionalStores.Syslog.Encryption")
 			}
 		}
+		if kafka := instance.Spec.AdditionalStores.Kafka; kafka != nil {
+			if len(kafka.LogTypes) == 0 {
+				kafka.LogTypes = defaultStoreLogTypes()
+				modifiedFields = append(modifiedFields, "AdditionalStores.Kafka.LogTypes")
+			}
+			if len(kafka.Encryption) == 0 {
+				kafka.Encryption = v1.EncryptionNone
+				modifiedFields = append(modifiedFields, "AdditionalStores.Kafka.Encryption")
+			}
+			if kafka.CredentialsSecretName != "" && len(kafka.SASLMechanism) == 0 {
+				kafka.SASLMechanism = v1.KafkaSASLPlain
+				modifiedFields = append(modifiedFields, "AdditionalStores.Kafka.SASLMechanism")
+			}
+		}
+		if http := instance.Spec.AdditionalStores.HTTP; http != nil && len(http.LogTypes) == 0 {
+			http.LogTypes = defaultStoreLogTypes()
+			modifiedFields = append(modifiedFields, "AdditionalStores.HTTP.LogTypes")
+		}
+		if otlp := instance.Spec.AdditionalStores.OTLP; otlp != nil && len(otlp.LogTypes) == 0 {
+			otlp.LogTypes = defaultStoreLogTypes()
+			modifiedFields = append(modifiedFields, "AdditionalStores.OTLP.LogTypes")
+		}
 	}
 	return modifiedFields
 }
 
+// defaultStoreLogTypes returns the log types exported to an additional store that doesn't specify them.
+func defaultStoreLogTypes() []v1.SyslogLogType {
+	return []v1.SyslogLogType{v1.SyslogLogAudit, v1.SyslogLogDNS, v1.SyslogLogFlows}
+}
+
 // Reconcile reads that state of the cluster for a LogCollector object and makes changes based on the state read
 // and what is in the LogCollector.Spec
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
@@ -273,7 +377,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating LogCollector status conditions
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -289,6 +393,11 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Default fields on the LogCollector instance if needed.
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 	modifiedFields := fillDefaults(instance)
@@ -377,7 +486,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
-	certificateManager, err := certificatemanager.Create(r.client, installation, r.clusterDomain, common.OperatorNamespace())
+	certificateManager, err := certificatemanager.Create(r.client, installation, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -488,6 +597,41 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	var kafkaCredential *render.KafkaCredential
+	var httpHeaders, otlpHeaders map[string]string
+	if instance.Spec.AdditionalStores != nil {
+		stores := instance.Spec.AdditionalStores
+		if (stores.Kafka != nil || stores.HTTP != nil || stores.OTLP != nil) && !render.FluentdSupportsAdditionalStores() {
+			r.status.SetDegraded(operatorv1.ResourceValidationError, fmt.Sprintf("The Kafka, HTTP and OTLP stores are not supported by fluentd %s", components.ComponentFluentd.Version), nil, reqLogger)
+			return reconcile.Result{}, nil
+		}
+		if kafka := instance.Spec.AdditionalStores.Kafka; kafka != nil && kafka.CredentialsSecretName != "" {
+			kafkaCredential, err = getKafkaCredential(r.client, kafka.CredentialsSecretName)
+			if err != nil {
+				r.status.SetDegraded(operatorv1.ResourceValidationError, "Error with Kafka credential secret", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+			if kafkaCredential == nil {
+				r.status.SetDegraded(operatorv1.ResourceNotFound, "Kafka credential secret does not exist", nil, reqLogger)
+				return reconcile.Result{}, nil
+			}
+		}
+		if http := instance.Spec.AdditionalStores.HTTP; http != nil {
+			httpHeaders, err = getHeaders(r.client, http.Headers)
+			if err != nil {
+				r.status.SetDegraded(operatorv1.ResourceValidationError, "Error reading the headers of the HTTP store", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+		}
+		if otlp := instance.Spec.AdditionalStores.OTLP; otlp != nil {
+			otlpHeaders, err = getHeaders(r.client, otlp.Headers)
+			if err != nil {
+				r.status.SetDegraded(operatorv1.ResourceValidationError, "Error reading the headers of the OTLP store", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
 	var useSyslogCertificate bool
 	if instance.Spec.AdditionalStores != nil {
 		if instance.Spec.AdditionalStores.Syslog != nil && instance.Spec.AdditionalStores.Syslog.Encryption == v1.EncryptionTLS {
@@ -523,6 +667,32 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 				}
 			}
 		}
+
+		// Likewise, IDS events can't be exported to the other stores from a managed cluster.
+		if managedCluster {
+			var stores []string
+			var logTypes [][]v1.SyslogLogType
+			if instance.Spec.AdditionalStores.Kafka != nil {
+				stores = append(stores, "Kafka")
+				logTypes = append(logTypes, instance.Spec.AdditionalStores.Kafka.LogTypes)
+			}
+			if instance.Spec.AdditionalStores.HTTP != nil {
+				stores = append(stores, "HTTP")
+				logTypes = append(logTypes, instance.Spec.AdditionalStores.HTTP.LogTypes)
+			}
+			if instance.Spec.AdditionalStores.OTLP != nil {
+				stores = append(stores, "OTLP")
+				logTypes = append(logTypes, instance.Spec.AdditionalStores.OTLP.LogTypes)
+			}
+			for i, store := range stores {
+				for _, l := range logTypes[i] {
+					if l == v1.SyslogLogIDSEvents {
+						r.status.SetDegraded(operatorv1.ResourceValidationError, fmt.Sprintf("IDSEvents option is not supported for %s config in a managed cluster", store), nil, reqLogger)
+						return reconcile.Result{}, nil
+					}
+				}
+			}
+		}
 	}
 
 	filters, err := getFluentdFilters(r.client)
@@ -569,12 +739,16 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetController(controllerName)
 
 	fluentdCfg := &render.FluentdConfiguration{
 		LogCollector:           instance,
 		ESClusterConfig:        esClusterConfig,
 		S3Credential:           s3Credential,
 		SplkCredential:         splunkCredential,
+		KafkaCredential:        kafkaCredential,
+		HTTPHeaders:            httpHeaders,
+		OTLPHeaders:            otlpHeaders,
 		Filters:                filters,
 		EKSConfig:              eksConfig,
 		PullSecrets:            pullSecrets,
@@ -616,7 +790,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	}
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, comp); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -639,6 +813,9 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 			ESClusterConfig:        esClusterConfig,
 			S3Credential:           s3Credential,
 			SplkCredential:         splunkCredential,
+			KafkaCredential:        kafkaCredential,
+			HTTPHeaders:            httpHeaders,
+			OTLPHeaders:            otlpHeaders,
 			Filters:                filters,
 			EKSConfig:              eksConfig,
 			PullSecrets:            pullSecrets,
@@ -655,13 +832,10 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		comp = render.Fluentd(fluentdCfg)
 
 		if err = imageset.ApplyImageSet(ctx, r.client, variant, comp); err != nil {
-			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+			r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 			return reconcile.Result{}, err
 		}
 
-		// Create a component handler to manage the rendered component.
-		handler = utils.NewComponentHandler(log, r.client, r.scheme, instance)
-
 		if err := handler.CreateOrUpdateOrDelete(ctx, comp, r.status); err != nil {
 			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
 			return reconcile.Result{}, err
@@ -766,6 +940,70 @@ func getSplunkCredential(client client.Client) (*render.SplunkCredential, error)
 	}, nil
 }
 
+func getKafkaCredential(client client.Client, secretName string) (*render.KafkaCredential, error) {
+	secret := &corev1.Secret{}
+	secretNamespacedName := types.NamespacedName{
+		Name:      secretName,
+		Namespace: common.OperatorNamespace(),
+	}
+	if err := client.Get(context.Background(), secretNamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to read secret %q: %s", secretName, err)
+	}
+
+	var ok bool
+	var username []byte
+	if username, ok = secret.Data[render.KafkaUsernameKey]; !ok || len(username) == 0 {
+		return nil, fmt.Errorf("Expected secret %q to have a field named %q", secretName, render.KafkaUsernameKey)
+	}
+	var password []byte
+	if password, ok = secret.Data[render.KafkaPasswordKey]; !ok || len(password) == 0 {
+		return nil, fmt.Errorf("Expected secret %q to have a field named %q", secretName, render.KafkaPasswordKey)
+	}
+
+	return &render.KafkaCredential{
+		Username: username,
+		Password: password,
+	}, nil
+}
+
+// getHeaders returns the headers of the requests to a store, reading the values of the headers that reference a secret
+// in the operator namespace.
+func getHeaders(client client.Client, headers []v1.HTTPHeader) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	values := map[string]string{}
+	for _, h := range headers {
+		if h.ValueFrom == nil {
+			values[h.Name] = h.Value
+			continue
+		}
+		secret := &corev1.Secret{}
+		secretNamespacedName := types.NamespacedName{
+			Name:      h.ValueFrom.Name,
+			Namespace: common.OperatorNamespace(),
+		}
+		if err := client.Get(context.Background(), secretNamespacedName, secret); err != nil {
+			if errors.IsNotFound(err) && h.ValueFrom.Optional != nil && *h.ValueFrom.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("Failed to read secret %q for header %q: %s", h.ValueFrom.Name, h.Name, err)
+		}
+		value, ok := secret.Data[h.ValueFrom.Key]
+		if !ok || len(value) == 0 {
+			if h.ValueFrom.Optional != nil && *h.ValueFrom.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("Expected secret %q to have a field named %q for header %q", h.ValueFrom.Name, h.ValueFrom.Key, h.Name)
+		}
+		values[h.Name] = string(value)
+	}
+	return values, nil
+}
+
 func getFluentdFilters(client client.Client) (*render.FluentdFilters, error) {
 	cm := &corev1.ConfigMap{}
 	cmNamespacedName := types.NamespacedName{
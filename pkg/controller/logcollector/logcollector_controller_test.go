@@ -549,6 +549,133 @@ var _ = Describe("LogCollector controller tests", func() {
 				Expect(c.Delete(ctx, &v3.LicenseKey{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Status: v3.LicenseKeyStatus{Features: []string{}}})).NotTo(HaveOccurred())
 			})
 		})
+		Context("Forward to Kafka and HTTP", func() {
+			BeforeEach(func() {
+				// Treat the fluentd images under test as a release that exports to the stores.
+				render.FluentdAdditionalStoresVersions[components.ComponentFluentd.Version] = true
+				render.FluentdAdditionalStoresVersions[components.ComponentFluentdWindows.Version] = true
+
+				By("Specify kafka and http log stores")
+				Expect(c.Delete(ctx, &operatorv1.LogCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+				})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &operatorv1.LogCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+					Spec: operatorv1.LogCollectorSpec{
+						AdditionalStores: &operatorv1.AdditionalLogStoreSpec{
+							Kafka: &operatorv1.KafkaStoreSpec{
+								Brokers:               []string{"kafka-0.example.com:9093", "kafka-1.example.com:9093"},
+								Topic:                 "calico",
+								Encryption:            operatorv1.EncryptionTLS,
+								CredentialsSecretName: "kafka-credentials",
+							},
+							HTTP: &operatorv1.HTTPStoreSpec{
+								Endpoint: "https://logs.example.com/ingest",
+								Headers: []operatorv1.HTTPHeader{
+									{Name: "X-Source", Value: "calico"},
+									{Name: "Authorization", ValueFrom: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "http-token"},
+										Key:                  "token",
+									}},
+								},
+							},
+						},
+					},
+				})).NotTo(HaveOccurred())
+				By("Setting the license to export logs")
+				Expect(c.Delete(ctx, &v3.LicenseKey{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Status: v3.LicenseKeyStatus{Features: []string{}}})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &v3.LicenseKey{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Status: v3.LicenseKeyStatus{Features: []string{common.ExportLogsFeature}}})).NotTo(HaveOccurred())
+				By("Creating the http header secret")
+				Expect(c.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "http-token", Namespace: "tigera-operator"},
+					Data:       map[string][]byte{"token": []byte("Bearer secret")},
+				})).NotTo(HaveOccurred())
+			})
+
+			It("should forward logs to kafka and http", func() {
+				Expect(c.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "kafka-credentials", Namespace: "tigera-operator"},
+					Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+				})).NotTo(HaveOccurred())
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				ds := appsv1.DaemonSet{
+					TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fluentd-node",
+						Namespace: render.LogCollectorNamespace,
+					},
+				}
+				Expect(test.GetResource(c, &ds)).To(BeNil())
+				Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+					corev1.EnvVar{Name: "KAFKA_BROKERS", Value: "kafka-0.example.com:9093,kafka-1.example.com:9093"},
+					corev1.EnvVar{Name: "KAFKA_SASL_MECHANISM", Value: "PLAIN"},
+					corev1.EnvVar{Name: "KAFKA_FLOW_LOG", Value: "true"},
+					corev1.EnvVar{Name: "HTTP_ENDPOINT", Value: "https://logs.example.com/ingest"},
+					corev1.EnvVar{Name: "HTTP_DNS_LOG", Value: "true"},
+				))
+
+				kafkaSecret := corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: render.KafkaFluentdSecretName, Namespace: render.LogCollectorNamespace},
+				}
+				Expect(test.GetResource(c, &kafkaSecret)).To(BeNil())
+				Expect(kafkaSecret.Data).To(Equal(map[string][]byte{"username": []byte("user"), "password": []byte("pass")}))
+
+				headersSecret := corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: render.HTTPFluentdHeadersSecretName, Namespace: render.LogCollectorNamespace},
+				}
+				Expect(test.GetResource(c, &headersSecret)).To(BeNil())
+				Expect(headersSecret.Data[render.FluentdHeadersKey]).To(MatchJSON(`{"X-Source": "calico", "Authorization": "Bearer secret"}`))
+			})
+
+			It("should watch the secrets with the credentials of the stores", func() {
+				instance := &operatorv1.LogCollector{}
+				Expect(c.Get(ctx, utils.DefaultTSEEInstanceKey, instance)).NotTo(HaveOccurred())
+				Expect(additionalStoreSecretNames(instance)).To(ConsistOf("kafka-credentials", "http-token"))
+			})
+
+			It("should degrade when the fluentd image doesn't support the stores", func() {
+				version := components.ComponentFluentd.Version
+				defer func() { components.ComponentFluentd.Version = version }()
+				components.ComponentFluentd.Version = "v0.0.0"
+				mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "The Kafka, HTTP and OTLP stores are not supported by fluentd v0.0.0", mock.Anything, mock.Anything).Return()
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceValidationError, "The Kafka, HTTP and OTLP stores are not supported by fluentd v0.0.0", mock.Anything, mock.Anything)
+			})
+
+			It("should degrade when the kafka credentials secret does not exist", func() {
+				mockStatus.On("SetDegraded", operatorv1.ResourceNotFound, "Kafka credential secret does not exist", mock.Anything, mock.Anything).Return()
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceNotFound, "Kafka credential secret does not exist", mock.Anything, mock.Anything)
+
+				ds := appsv1.DaemonSet{
+					TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fluentd-node",
+						Namespace: render.LogCollectorNamespace,
+					},
+				}
+				Expect(test.GetResource(c, &ds)).Should(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				delete(render.FluentdAdditionalStoresVersions, components.ComponentFluentd.Version)
+				delete(render.FluentdAdditionalStoresVersions, components.ComponentFluentdWindows.Version)
+				Expect(c.Delete(ctx, &operatorv1.LogCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+				})).NotTo(HaveOccurred())
+				Expect(c.Delete(ctx, &v3.LicenseKey{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Status: v3.LicenseKeyStatus{Features: []string{}}})).NotTo(HaveOccurred())
+			})
+		})
+
 		Context("reconcile for Status condition update from tigerastatus", func() {
 			generation := int64(2)
 			It("should reconcile with one item ", func() {
@@ -788,5 +915,47 @@ var _ = Describe("LogCollector controller tests", func() {
 			Expect(len(modifiedFields)).To(Equal(0))
 			Expect(logCollector.Spec.AdditionalStores.Syslog.LogTypes).To(Equal(expectedLogTypes))
 		})
+		It("should set default values for the kafka, http and otlp stores", func() {
+			logCollector := operatorv1.LogCollector{Spec: operatorv1.LogCollectorSpec{AdditionalStores: &operatorv1.AdditionalLogStoreSpec{
+				Kafka: &operatorv1.KafkaStoreSpec{CredentialsSecretName: "kafka-credentials"},
+				HTTP:  &operatorv1.HTTPStoreSpec{},
+				OTLP:  &operatorv1.OTLPStoreSpec{LogTypes: []operatorv1.SyslogLogType{operatorv1.SyslogLogFlows}},
+			}}}
+			modifiedFields := fillDefaults(&logCollector)
+			Expect(modifiedFields).To(ConsistOf("CollectProcessPath", "AdditionalStores.Kafka.LogTypes", "AdditionalStores.Kafka.Encryption",
+				"AdditionalStores.Kafka.SASLMechanism", "AdditionalStores.HTTP.LogTypes"))
+			expectedLogTypes := []operatorv1.SyslogLogType{
+				operatorv1.SyslogLogAudit,
+				operatorv1.SyslogLogDNS,
+				operatorv1.SyslogLogFlows,
+			}
+			Expect(logCollector.Spec.AdditionalStores.Kafka.LogTypes).To(Equal(expectedLogTypes))
+			Expect(logCollector.Spec.AdditionalStores.Kafka.Encryption).To(Equal(operatorv1.EncryptionNone))
+			Expect(logCollector.Spec.AdditionalStores.Kafka.SASLMechanism).To(Equal(operatorv1.KafkaSASLPlain))
+			Expect(logCollector.Spec.AdditionalStores.HTTP.LogTypes).To(Equal(expectedLogTypes))
+			Expect(logCollector.Spec.AdditionalStores.OTLP.LogTypes).To(Equal([]operatorv1.SyslogLogType{operatorv1.SyslogLogFlows}))
+		})
+	})
+
+	Context("should validate the additional stores", func() {
+		It("should reject invalid kafka brokers, endpoints and headers", func() {
+			for _, stores := range []*operatorv1.AdditionalLogStoreSpec{
+				{Kafka: &operatorv1.KafkaStoreSpec{Brokers: []string{"kafka.example.com"}}},
+				{HTTP: &operatorv1.HTTPStoreSpec{Endpoint: "logs.example.com/ingest"}},
+				{OTLP: &operatorv1.OTLPStoreSpec{Endpoint: "ftp://otel.example.com"}},
+				{HTTP: &operatorv1.HTTPStoreSpec{
+					Endpoint: "https://logs.example.com/ingest",
+					Headers:  []operatorv1.HTTPHeader{{Name: "Authorization"}},
+				}},
+			} {
+				Expect(c.Delete(ctx, &operatorv1.LogCollector{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+				Expect(c.Create(ctx, &operatorv1.LogCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+					Spec:       operatorv1.LogCollectorSpec{AdditionalStores: stores},
+				})).NotTo(HaveOccurred())
+				_, err := GetLogCollector(ctx, c)
+				Expect(err).To(HaveOccurred())
+			}
+		})
 	})
 })
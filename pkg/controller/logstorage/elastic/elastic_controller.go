@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"reflect"
 
 	cmnv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -60,6 +61,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const controllerName = "log-storage-elastic-controller"
+
 var log = logf.Log.WithName("controller_logstorage_elastic")
 
 const (
@@ -97,7 +100,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		scheme:         mgr.GetScheme(),
 		esCliCreator:   utils.NewElasticClient,
 		tierWatchReady: &utils.ReadyFlag{},
-		status:         status.New(mgr.GetClient(), tigeraStatusName, opts.KubernetesVersion),
+		status:         status.NewForManager(mgr, tigeraStatusName, opts.KubernetesVersion),
 		usePSP:         opts.UsePSP,
 		clusterDomain:  opts.ClusterDomain,
 		provider:       opts.DetectedProvider,
@@ -106,7 +109,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-elastic-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -123,7 +126,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	if err = utils.AddInstallationWatch(c); err != nil {
 		return fmt.Errorf("log-storage-elastic-controller failed to watch Installation resource: %w", err)
 	}
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("log-storage-elastic-controller failed to watch ImageSet: %w", err)
 	}
 	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
@@ -177,6 +180,20 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("log-storage-elastic-controller failed to watch Secret resource: %w", err)
 	}
 
+	// Watch the secret with the credentials of the snapshot repository, which is named in the LogStorage.
+	if err = utils.AddNamedSecretsWatch(c, common.OperatorNamespace(), func() []string {
+		ls := &operatorv1.LogStorage{}
+		if err := mgr.GetClient().Get(opts.ShutdownContext, utils.DefaultTSEEInstanceKey, ls); err != nil {
+			return nil
+		}
+		if ls.Spec.Snapshots == nil || ls.Spec.Snapshots.Repository.S3 == nil {
+			return nil
+		}
+		return []string{ls.Spec.Snapshots.Repository.S3.CredentialsSecretName}
+	}); err != nil {
+		return fmt.Errorf("log-storage-elastic-controller failed to watch Secret resource: %w", err)
+	}
+
 	// Establish watches for secrets in the tigera-operator namespace.
 	for _, secretName := range []string{
 		render.TigeraElasticsearchGatewaySecret,
@@ -273,6 +290,11 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	// We found the LogStorage instance.
 	r.status.OnCRFound()
 
+	if utils.IsPaused(ls) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Wait for the initializing controller to indicate that the LogStorage object is actionable.
 	if ls.Status.State != operatorv1.TigeraStatusReady {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for LogStorage defaulting to occur", nil, reqLogger)
@@ -347,7 +369,7 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	}
 
 	// Get the keypairs we need for rendering components. These are created separately by the ES secrets controller.
-	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace())
+	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -390,15 +412,25 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	flowShards := logstoragecommon.CalculateFlowShards(ls.Spec.Nodes, logstoragecommon.DefaultElasticsearchShards)
 	clusterConfig = relasticsearch.NewClusterConfig(render.DefaultElasticsearchClusterName, ls.Replicas(), logstoragecommon.DefaultElasticsearchShards, flowShards)
 
-	// Check if there is a StorageClass available to run Elasticsearch on.
-	if err = r.client.Get(ctx, client.ObjectKey{Name: ls.Spec.StorageClassName}, &storagev1.StorageClass{}); err != nil {
-		if errors.IsNotFound(err) {
-			err := fmt.Errorf("couldn't find storage class %s, this must be provided", ls.Spec.StorageClassName)
-			r.status.SetDegraded(operatorv1.ResourceNotFound, "Failed to get storage class", err, reqLogger)
-			return reconcile.Result{}, nil
+	// Check if there is a StorageClass available to run Elasticsearch on, including the ones for specific NodeSets.
+	storageClassNames := []string{ls.Spec.StorageClassName}
+	if ls.Spec.Nodes != nil {
+		for _, ns := range ls.Spec.Nodes.NodeSets {
+			if ns.StorageClassName != "" {
+				storageClassNames = append(storageClassNames, ns.StorageClassName)
+			}
+		}
+	}
+	for _, storageClassName := range storageClassNames {
+		if err = r.client.Get(ctx, client.ObjectKey{Name: storageClassName}, &storagev1.StorageClass{}); err != nil {
+			if errors.IsNotFound(err) {
+				err := fmt.Errorf("couldn't find storage class %s, this must be provided", storageClassName)
+				r.status.SetDegraded(operatorv1.ResourceNotFound, "Failed to get storage class", err, reqLogger)
+				return reconcile.Result{}, nil
+			}
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Failed to get storage class", err, reqLogger)
+			return reconcile.Result{}, err
 		}
-		r.status.SetDegraded(operatorv1.ResourceReadError, "Failed to get storage class", err, reqLogger)
-		return reconcile.Result{}, err
 	}
 
 	if operatorv1.IsFIPSModeEnabled(install.FIPSMode) {
@@ -432,6 +464,20 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 		esAdminUserSecret = rsecret.CopyToNamespace(common.OperatorNamespace(), esAdminUserSecret)[0]
 	}
 
+	// Get the credentials of the S3 snapshot repository, which Elasticsearch reads from its keystore.
+	var snapshotCredentialsSecret *corev1.Secret
+	if ls.Spec.Snapshots != nil && ls.Spec.Snapshots.Repository.S3 != nil {
+		name := ls.Spec.Snapshots.Repository.S3.CredentialsSecretName
+		snapshotCredentialsSecret, err = utils.GetSecret(ctx, r.client, name, common.OperatorNamespace())
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, fmt.Sprintf("Failed to get snapshot credentials secret %s", name), err, reqLogger)
+			return reconcile.Result{}, err
+		} else if snapshotCredentialsSecret == nil {
+			r.status.SetDegraded(operatorv1.ResourceNotFound, fmt.Sprintf("Waiting for snapshot credentials secret %s/%s", common.OperatorNamespace(), name), nil, reqLogger)
+			return reconcile.Result{}, nil
+		}
+	}
+
 	esLicenseType, err = utils.GetElasticLicenseType(ctx, r.client, reqLogger)
 	if err != nil {
 		// If ECKLicenseConfigMapName is not found, it means ECK operator is not running yet, log the information and proceed
@@ -442,6 +488,10 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 			return reconcile.Result{}, err
 		}
 	}
+	if err = validateLicense(ls, esLicenseType); err != nil {
+		r.status.SetDegraded(operatorv1.ResourceValidationError, "LogStorage requires a different Elasticsearch license", err, reqLogger)
+		return reconcile.Result{}, nil
+	}
 
 	elasticsearch, err := utils.GetElasticsearch(ctx, r.client)
 	if err != nil {
@@ -449,6 +499,25 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 		return reconcile.Result{}, err
 	}
 
+	// Run the pre-flight checks of an upgrade of Elasticsearch, which keeps running its current version until they pass.
+	upgrade := r.checkUpgrade(ctx, ls, elasticsearch, reqLogger)
+	if upgrade != nil {
+		if err = r.setUpgradeCondition(ctx, ls, upgrade); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Failed to update the Elasticsearch upgrade condition", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The ECK operator keeps its image while the upgrade is held, so it is only needed then.
+	var eckOperator *apps.StatefulSet
+	if upgrade.held() {
+		eckOperator, err = r.getECKOperator(ctx)
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "An error occurred trying to retrieve the ECK operator", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
 	var kibana *kbv1.Kibana
 	if kibanaEnabled {
 		kibana, err = r.getKibana(ctx)
@@ -477,7 +546,7 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	}
 
 	var unusedTLSSecret *corev1.Secret
-	if install.CertificateManagement != nil {
+	if install.CertificateManagement.UseCSRs() {
 		// Eck requires us to provide a TLS secret for Kibana and Elasticsearch. It will also inspect that it has a
 		// certificate and private key. However, when certificate management is enabled, we do not want to use a
 		// private key stored in a secret. For this reason, we mount a dummy that the actual Elasticsearch and Kibana
@@ -517,6 +586,7 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	}
 
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, ls)
+	hdler.SetController(controllerName)
 
 	logStorageCfg := &render.ElasticsearchConfiguration{
 		LogStorage:              ls,
@@ -541,11 +611,15 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 		ApplyTrial:              applyTrial,
 		KeyStoreSecret:          keyStoreSecret,
 		KibanaEnabled:           kibanaEnabled,
+
+		SnapshotCredentialsSecret: snapshotCredentialsSecret,
+		HoldElasticsearchUpgrade:  upgrade.held(),
+		ECKOperator:               eckOperator,
 	}
 
 	component := render.LogStorage(logStorageCfg)
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -567,12 +641,27 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 		return reconcile.Result{}, nil
 	}
 
+	result := reconcile.Result{}
+
 	// In multi-tenant mode, ILM programming is created out of band
 	if !r.multiTenant {
 		if err := r.applyILMPolicies(ls, reqLogger, ctx); err != nil {
 			r.status.SetDegraded(operatorv1.ResourceNotReady, "Error applying ILM policies", nil, reqLogger)
 			return reconcile.Result{}, err
 		}
+		snapshots, err := r.applySnapshots(ctx, ls)
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error applying Elasticsearch snapshot settings", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		if snapshots != nil && snapshots.RestorePhase == operatorv1.SnapshotRestoreFailed {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Failed to restore Elasticsearch snapshot", fmt.Errorf("%s", snapshots.RestoreMessage), reqLogger)
+			return reconcile.Result{}, nil
+		}
+		if snapshots != nil && snapshots.RestorePhase == operatorv1.SnapshotRestoreInProgress && ls.Spec.Snapshots.Restore != nil {
+			// Follow the restore, as its progress doesn't trigger a reconcile.
+			result.RequeueAfter = utils.StandardRetry
+		}
 	}
 
 	if kibanaEnabled && esLicenseType == render.ElasticsearchLicenseTypeBasic {
@@ -586,11 +675,34 @@ func (r *ElasticSubController) Reconcile(ctx context.Context, request reconcile.
 	}
 
 	r.status.ReadyToMonitor()
+	if upgrade.held() {
+		// Check again whether the upgrade can proceed, as neither the cluster health nor the snapshot trigger a reconcile.
+		if upgrade.phase == operatorv1.ElasticsearchUpgradeBlocked {
+			r.status.SetDegraded(operatorv1.ResourceNotReady, "Elasticsearch upgrade is blocked", fmt.Errorf("%s", upgrade.message), reqLogger)
+			return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
+		}
+		r.status.ClearDegraded()
+		return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
+	}
 	r.status.ClearDegraded()
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
 // isTerminating returns true if the LogStorage instance is terminating.
+// validateLicense returns an error if the LogStorage uses a feature that the Elasticsearch license doesn't provide. The
+// searchable snapshots of the frozen phase need an Enterprise license. Nothing is rejected until ECK reports the license.
+func validateLicense(ls *operatorv1.LogStorage, license render.ElasticsearchLicenseType) error {
+	if license != render.ElasticsearchLicenseTypeBasic {
+		return nil
+	}
+	for _, p := range ls.Spec.IndexLifecyclePolicies {
+		if p.FrozenAge != "" {
+			return fmt.Errorf("LogStorage spec.indexLifecyclePolicies for %s sets frozenAge, which needs an Enterprise Elasticsearch license, but the license is %s", p.DataType, license)
+		}
+	}
+	return nil
+}
+
 func isTerminating(ls *operatorv1.LogStorage) bool {
 	return ls != nil && ls.DeletionTimestamp != nil
 }
@@ -670,6 +782,86 @@ func (r *ElasticSubController) applyILMPolicies(ls *operatorv1.LogStorage, reqLo
 	return nil
 }
 
+// applySnapshots applies the LogStorage's snapshot policy, restores the requested snapshot if it has not been restored
+// yet, and reports the most recent snapshots and the progress of the restore in the LogStorage status, which it returns.
+func (r *ElasticSubController) applySnapshots(ctx context.Context, ls *operatorv1.LogStorage) (*operatorv1.ElasticsearchSnapshotStatus, error) {
+	esClient, err := r.esCliCreator(r.client, ctx, relasticsearch.ECKElasticEndpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = esClient.SetSnapshotPolicy(ctx, ls); err != nil {
+		return nil, err
+	}
+
+	var status *operatorv1.ElasticsearchSnapshotStatus
+	if ls.Spec.Snapshots != nil {
+		if status, err = esClient.GetSnapshotStatus(ctx); err != nil {
+			return nil, err
+		}
+		if status == nil {
+			status = &operatorv1.ElasticsearchSnapshotStatus{}
+		}
+		if prev := ls.Status.Snapshots; prev != nil {
+			status.RestoredSnapshot = prev.RestoredSnapshot
+			status.RestoreTime = prev.RestoreTime
+			status.RestorePhase = prev.RestorePhase
+			status.RestoreMessage = prev.RestoreMessage
+		}
+
+		restore := ls.Spec.Snapshots.Restore
+		if restore == nil && status.RestorePhase == operatorv1.SnapshotRestoreFailed {
+			// Forget the failed restore, so that it is tried again if it is set again.
+			status.RestoredSnapshot, status.RestoreTime, status.RestorePhase, status.RestoreMessage = "", nil, "", ""
+		}
+
+		// Each snapshot is only restored once, as restoring replaces any data written to its indices since. The restore
+		// is recorded before any index is closed, so that it is not started again if the operator stops before it
+		// has recorded that the restore has started.
+		if restore != nil && restore.Snapshot != status.RestoredSnapshot {
+			now := metav1.Now()
+			status.RestoredSnapshot = restore.Snapshot
+			status.RestoreTime = &now
+			status.RestorePhase = operatorv1.SnapshotRestoreInProgress
+			status.RestoreMessage = fmt.Sprintf("Starting to restore snapshot %s", restore.Snapshot)
+			if err = r.patchSnapshotStatus(ctx, ls, status); err != nil {
+				return nil, err
+			}
+
+			if err = esClient.RestoreSnapshot(ctx, restore.Snapshot, restore.Indices); err != nil {
+				status.RestorePhase = operatorv1.SnapshotRestoreFailed
+				status.RestoreMessage = err.Error()
+				return status, r.patchSnapshotStatus(ctx, ls, status)
+			}
+		}
+
+		if restore != nil && status.RestorePhase == operatorv1.SnapshotRestoreInProgress {
+			restored, total, err := esClient.GetRestoreProgress(ctx, restore.Snapshot, restore.Indices)
+			if err != nil {
+				return nil, err
+			}
+			if restored == total {
+				status.RestorePhase = operatorv1.SnapshotRestoreSucceeded
+				status.RestoreMessage = fmt.Sprintf("Restored %d indices from snapshot %s", total, restore.Snapshot)
+			} else {
+				status.RestoreMessage = fmt.Sprintf("Restored %d of %d indices from snapshot %s", restored, total, restore.Snapshot)
+			}
+		}
+	}
+
+	return status, r.patchSnapshotStatus(ctx, ls, status)
+}
+
+// patchSnapshotStatus sets the snapshot status of the LogStorage, if it has changed.
+func (r *ElasticSubController) patchSnapshotStatus(ctx context.Context, ls *operatorv1.LogStorage, status *operatorv1.ElasticsearchSnapshotStatus) error {
+	if reflect.DeepEqual(ls.Status.Snapshots, status) {
+		return nil
+	}
+	prePatch := client.MergeFrom(ls.DeepCopy())
+	ls.Status.Snapshots = status.DeepCopy()
+	return r.client.Status().Patch(ctx, ls, prePatch)
+}
+
 func (r *ElasticSubController) getElasticsearchService(ctx context.Context) (*corev1.Service, error) {
 	svc := corev1.Service{}
 	err := r.client.Get(ctx, client.ObjectKey{Name: render.ElasticsearchServiceName, Namespace: render.ElasticsearchNamespace}, &svc)
@@ -682,6 +874,18 @@ func (r *ElasticSubController) getElasticsearchService(ctx context.Context) (*co
 	return &svc, nil
 }
 
+func (r *ElasticSubController) getECKOperator(ctx context.Context) (*apps.StatefulSet, error) {
+	sts := apps.StatefulSet{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: render.ECKOperatorName, Namespace: render.ECKOperatorNamespace}, &sts)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sts, nil
+}
+
 func (r *ElasticSubController) getKibana(ctx context.Context) (*kbv1.Kibana, error) {
 	kb := kbv1.Kibana{}
 	err := r.client.Get(ctx, client.ObjectKey{Name: render.KibanaName, Namespace: render.KibanaNamespace}, &kb)
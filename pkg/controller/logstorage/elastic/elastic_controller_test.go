@@ -34,6 +34,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -426,6 +427,213 @@ var _ = Describe("LogStorage controller", func() {
 				mockStatus.AssertExpectations(GinkgoT())
 			})
 
+			It("should restore the requested snapshot once", func() {
+				Expect(cli.Create(ctx, &storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{Name: storageClassName},
+				})).ShouldNot(HaveOccurred())
+
+				CreateLogStorage(cli, &operatorv1.LogStorage{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+					Spec: operatorv1.LogStorageSpec{
+						Nodes:            &operatorv1.Nodes{Count: int64(1)},
+						StorageClassName: storageClassName,
+						Snapshots: &operatorv1.ElasticsearchSnapshots{
+							Repository: operatorv1.SnapshotRepository{
+								Filesystem: &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "es-snapshots"},
+							},
+							Restore: &operatorv1.SnapshotRestore{Snapshot: "snap-1", Indices: []string{"tigera_secure_ee_flows*"}},
+						},
+					},
+					Status: operatorv1.LogStorageStatus{State: operatorv1.TigeraStatusReady},
+				})
+				Expect(cli.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: render.ECKOperatorNamespace, Name: render.ECKLicenseConfigMapName},
+					Data:       map[string]string{"eck_license_level": string(render.ElasticsearchLicenseTypeEnterprise)},
+				})).ShouldNot(HaveOccurred())
+
+				r, err := NewReconcilerWithShims(cli, scheme, mockStatus, operatorv1.ProviderNone, MockESCLICreator, dns.DefaultClusterDomain, readyFlag)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				mockStatus.On("SetDegraded", operatorv1.ResourceNotReady, "Waiting for Elasticsearch cluster to be operational", mock.Anything, mock.Anything).Return()
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				es := &esv1.Elasticsearch{}
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				es.Status.Phase = esv1.ElasticsearchReadyPhase
+				Expect(cli.Update(ctx, es)).ShouldNot(HaveOccurred())
+
+				kb := &kbv1.Kibana{}
+				Expect(cli.Get(ctx, kbObjKey, kb)).ShouldNot(HaveOccurred())
+				kb.Status.AssociationStatus = cmnv1.AssociationEstablished
+				Expect(cli.Update(ctx, kb)).ShouldNot(HaveOccurred())
+
+				kibanaKeyPair, err := certificateManager.GetOrCreateKeyPair(r.client, render.TigeraKibanaCertSecret, common.OperatorNamespace(), kbDNSNames)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cli.Create(ctx, kibanaKeyPair.Secret(render.KibanaNamespace))).ShouldNot(HaveOccurred())
+				Expect(cli.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: render.ElasticsearchAdminUserSecret, Namespace: render.ElasticsearchNamespace},
+					Data:       map[string][]byte{"elastic": []byte("password")},
+				})).ShouldNot(HaveOccurred())
+
+				mockESClient := &MockESClient{}
+				mockESClient.On("RestoreSnapshot", mock.Anything, "snap-1", []string{"tigera_secure_ee_flows*"}).Return(nil).Once()
+				mockESClient.On("GetRestoreProgress", mock.Anything, "snap-1", []string{"tigera_secure_ee_flows*"}).Return(0, 2, nil).Once()
+				mockESClient.On("GetRestoreProgress", mock.Anything, "snap-1", []string{"tigera_secure_ee_flows*"}).Return(2, 2, nil).Once()
+				esCtx := context.WithValue(ctx, MockESClientKey("mockESClient"), mockESClient)
+
+				mockStatus.On("ClearDegraded")
+				By("starting the restore and following its progress")
+				result, err := r.Reconcile(esCtx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result).Should(Equal(reconcile.Result{RequeueAfter: utils.StandardRetry}))
+
+				ls := &operatorv1.LogStorage{}
+				Expect(cli.Get(ctx, types.NamespacedName{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				Expect(ls.Status.Snapshots.RestoredSnapshot).To(Equal("snap-1"))
+				Expect(ls.Status.Snapshots.RestorePhase).To(Equal(operatorv1.SnapshotRestoreInProgress))
+				Expect(ls.Status.Snapshots.RestoreMessage).To(Equal("Restored 0 of 2 indices from snapshot snap-1"))
+
+				for i := 0; i < 2; i++ {
+					result, err := r.Reconcile(esCtx, reconcile.Request{})
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result).Should(Equal(successResult))
+				}
+				mockESClient.AssertExpectations(GinkgoT())
+
+				Expect(cli.Get(ctx, types.NamespacedName{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				Expect(ls.Status.Snapshots).NotTo(BeNil())
+				Expect(ls.Status.Snapshots.RestoredSnapshot).To(Equal("snap-1"))
+				Expect(ls.Status.Snapshots.RestoreTime).NotTo(BeNil())
+				Expect(ls.Status.Snapshots.RestorePhase).To(Equal(operatorv1.SnapshotRestoreSucceeded))
+
+				By("not retrying a restore that failed")
+				ls.Spec.Snapshots.Restore = &operatorv1.SnapshotRestore{Snapshot: "snap-2"}
+				Expect(cli.Update(ctx, ls)).ShouldNot(HaveOccurred())
+				mockESClient.On("RestoreSnapshot", mock.Anything, "snap-2", []string(nil)).Return(fmt.Errorf("snapshot snap-2 not found")).Once()
+				mockStatus.On("SetDegraded", operatorv1.ResourceUpdateError, "Failed to restore Elasticsearch snapshot", mock.Anything, mock.Anything).Return()
+				for i := 0; i < 2; i++ {
+					result, err := r.Reconcile(esCtx, reconcile.Request{})
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result).Should(Equal(successResult))
+				}
+				mockESClient.AssertExpectations(GinkgoT())
+				Expect(cli.Get(ctx, types.NamespacedName{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				Expect(ls.Status.Snapshots.RestoredSnapshot).To(Equal("snap-2"))
+				Expect(ls.Status.Snapshots.RestorePhase).To(Equal(operatorv1.SnapshotRestoreFailed))
+				Expect(ls.Status.Snapshots.RestoreMessage).To(Equal("snapshot snap-2 not found"))
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				Expect(es.Spec.NodeSets[0].Config.Data["path.repo"]).To(Equal([]interface{}{render.ElasticsearchSnapshotsPath}))
+			})
+
+			It("should take a snapshot and wait for a green cluster before upgrading Elasticsearch", func() {
+				Expect(cli.Create(ctx, &storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{Name: storageClassName},
+				})).ShouldNot(HaveOccurred())
+
+				CreateLogStorage(cli, &operatorv1.LogStorage{
+					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+					Spec: operatorv1.LogStorageSpec{
+						Nodes:            &operatorv1.Nodes{Count: int64(1)},
+						StorageClassName: storageClassName,
+						Snapshots: &operatorv1.ElasticsearchSnapshots{
+							Repository: operatorv1.SnapshotRepository{
+								Filesystem: &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "es-snapshots"},
+							},
+							Indices: []string{"tigera_secure_ee_*"},
+						},
+					},
+					Status: operatorv1.LogStorageStatus{State: operatorv1.TigeraStatusReady},
+				})
+				Expect(cli.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: render.ECKOperatorNamespace, Name: render.ECKLicenseConfigMapName},
+					Data:       map[string]string{"eck_license_level": string(render.ElasticsearchLicenseTypeEnterprise)},
+				})).ShouldNot(HaveOccurred())
+
+				r, err := NewReconcilerWithShims(cli, scheme, mockStatus, operatorv1.ProviderNone, MockESCLICreator, dns.DefaultClusterDomain, readyFlag)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				mockStatus.On("SetDegraded", operatorv1.ResourceNotReady, "Waiting for Elasticsearch cluster to be operational", mock.Anything, mock.Anything).Return()
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				// Pretend the cluster runs an older version of Elasticsearch.
+				es := &esv1.Elasticsearch{}
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				es.Spec.Version = "7.16.3"
+				es.Spec.Image = "tigera/elasticsearch:old"
+				es.Status.Phase = esv1.ElasticsearchReadyPhase
+				es.Status.Version = "7.16.3"
+				Expect(cli.Update(ctx, es)).ShouldNot(HaveOccurred())
+
+				kb := &kbv1.Kibana{}
+				Expect(cli.Get(ctx, kbObjKey, kb)).ShouldNot(HaveOccurred())
+				kb.Status.AssociationStatus = cmnv1.AssociationEstablished
+				Expect(cli.Update(ctx, kb)).ShouldNot(HaveOccurred())
+
+				kibanaKeyPair, err := certificateManager.GetOrCreateKeyPair(r.client, render.TigeraKibanaCertSecret, common.OperatorNamespace(), kbDNSNames)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cli.Create(ctx, kibanaKeyPair.Secret(render.KibanaNamespace))).ShouldNot(HaveOccurred())
+				Expect(cli.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: render.ElasticsearchAdminUserSecret, Namespace: render.ElasticsearchNamespace},
+					Data:       map[string][]byte{"elastic": []byte("password")},
+				})).ShouldNot(HaveOccurred())
+
+				snapshot := "tigera-pre-upgrade-" + components.ComponentEckElasticsearch.Version
+				mockESClient := &MockESClient{}
+				mockESClient.On("GetClusterHealth", mock.Anything).Return("yellow", nil).Once()
+				mockESClient.On("GetClusterHealth", mock.Anything).Return("green", nil)
+				mockESClient.On("GetNodesOverDiskWatermark", mock.Anything).Return([]string{}, nil)
+				mockESClient.On("GetSnapshotState", mock.Anything, snapshot).Return("", nil).Once()
+				mockESClient.On("GetSnapshotState", mock.Anything, snapshot).Return("SUCCESS", nil)
+				mockESClient.On("CreateSnapshot", mock.Anything, snapshot, []string{"tigera_secure_ee_*"}).Return(nil).Once()
+				esCtx := context.WithValue(ctx, MockESClientKey("mockESClient"), mockESClient)
+
+				upgradeReason := func() string {
+					ls := &operatorv1.LogStorage{}
+					Expect(cli.Get(ctx, types.NamespacedName{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+					c := meta.FindStatusCondition(ls.Status.Conditions, operatorv1.ElasticsearchUpgradeCondition)
+					Expect(c).NotTo(BeNil())
+					return c.Reason
+				}
+
+				By("refusing to upgrade while the cluster is yellow")
+				mockStatus.On("SetDegraded", operatorv1.ResourceNotReady, "Elasticsearch upgrade is blocked", mock.Anything, mock.Anything).Return().Once()
+				result, err := r.Reconcile(esCtx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(utils.StandardRetry))
+				Expect(upgradeReason()).To(Equal(string(operatorv1.ElasticsearchUpgradeBlocked)))
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				Expect(es.Spec.Version).To(Equal("7.16.3"))
+				Expect(es.Spec.Image).To(Equal("tigera/elasticsearch:old"))
+
+				By("taking a snapshot once the cluster is green")
+				mockStatus.On("ClearDegraded")
+				result, err = r.Reconcile(esCtx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(utils.StandardRetry))
+				Expect(upgradeReason()).To(Equal(string(operatorv1.ElasticsearchUpgradeSnapshot)))
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				Expect(es.Spec.Version).To(Equal("7.16.3"))
+
+				By("upgrading once the snapshot has succeeded")
+				result, err = r.Reconcile(esCtx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result).Should(Equal(successResult))
+				Expect(upgradeReason()).To(Equal(string(operatorv1.ElasticsearchUpgradeUpgrading)))
+				Expect(cli.Get(ctx, esObjKey, es)).ShouldNot(HaveOccurred())
+				Expect(es.Spec.Version).To(Equal(components.ComponentEckElasticsearch.Version))
+				Expect(es.Spec.Image).NotTo(Equal("tigera/elasticsearch:old"))
+
+				By("reporting the upgrade once all the nodes run the new version")
+				es.Status.Version = components.ComponentEckElasticsearch.Version
+				Expect(cli.Update(ctx, es)).ShouldNot(HaveOccurred())
+				_, err = r.Reconcile(esCtx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(upgradeReason()).To(Equal(string(operatorv1.ElasticsearchUpgradeSucceeded)))
+				mockESClient.AssertExpectations(GinkgoT())
+			})
+
 			It("test LogStorage reconciles successfully for elasticsearch basic license", func() {
 				Expect(cli.Create(ctx, &operatorv1.Authentication{
 					ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
@@ -542,6 +750,46 @@ var _ = Describe("LogStorage controller", func() {
 				mockStatus.AssertExpectations(GinkgoT())
 			})
 
+			It("should not reconcile a frozen phase with an elasticsearch basic license", func() {
+				Expect(cli.Create(ctx, &storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: storageClassName,
+					},
+				})).ShouldNot(HaveOccurred())
+
+				CreateLogStorage(cli, &operatorv1.LogStorage{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "tigera-secure",
+					},
+					Spec: operatorv1.LogStorageSpec{
+						Nodes: &operatorv1.Nodes{
+							Count: int64(1),
+						},
+						StorageClassName: storageClassName,
+						IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+							{DataType: operatorv1.DataTypeFlowLogs, FrozenAge: "2d"},
+						},
+					},
+					Status: operatorv1.LogStorageStatus{
+						State: operatorv1.TigeraStatusReady,
+					},
+				})
+
+				Expect(cli.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: render.ECKOperatorNamespace, Name: render.ECKLicenseConfigMapName},
+					Data:       map[string]string{"eck_license_level": string(render.ElasticsearchLicenseTypeBasic)},
+				})).ShouldNot(HaveOccurred())
+
+				r, err := NewReconcilerWithShims(cli, scheme, mockStatus, operatorv1.ProviderNone, MockESCLICreator, dns.DefaultClusterDomain, readyFlag)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "LogStorage requires a different Elasticsearch license", mock.Anything, mock.Anything).Return()
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cli.Get(ctx, esObjKey, &esv1.Elasticsearch{})).Should(HaveOccurred())
+				mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceValidationError, "LogStorage requires a different Elasticsearch license", mock.Anything, mock.Anything)
+			})
+
 			It("test that LogStorage reconciles if the user-supplied certs have any DNS names", func() {
 				// This test currently just validates that user-provided
 				// certs will reconcile and not return an error and won't be
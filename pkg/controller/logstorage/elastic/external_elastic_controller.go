@@ -38,6 +38,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const externalControllerName = "log-storage-external-es-controller"
+
 type ExternalESController struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
@@ -61,7 +63,7 @@ func AddExternalES(mgr manager.Manager, opts options.AddOptions) error {
 	r := &ExternalESController{
 		client:        mgr.GetClient(),
 		scheme:        mgr.GetScheme(),
-		status:        status.New(mgr.GetClient(), tigeraStatusName, opts.KubernetesVersion),
+		status:        status.NewForManager(mgr, tigeraStatusName, opts.KubernetesVersion),
 		usePSP:        opts.UsePSP,
 		clusterDomain: opts.ClusterDomain,
 		provider:      opts.DetectedProvider,
@@ -69,7 +71,7 @@ func AddExternalES(mgr manager.Manager, opts options.AddOptions) error {
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-external-es-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(externalControllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -81,7 +83,7 @@ func AddExternalES(mgr manager.Manager, opts options.AddOptions) error {
 	if err = utils.AddInstallationWatch(c); err != nil {
 		return fmt.Errorf("log-storage-external-es-controller failed to watch Installation resource: %w", err)
 	}
-	if err = imageset.AddImageSetWatch(c); err != nil {
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
 		return fmt.Errorf("log-storage-external-es-controller failed to watch ImageSet: %w", err)
 	}
 	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
@@ -115,6 +117,11 @@ func (r *ExternalESController) Reconcile(ctx context.Context, request reconcile.
 	}
 	r.status.OnCRFound()
 
+	if utils.IsPaused(ls) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	_, install, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -140,6 +147,7 @@ func (r *ExternalESController) Reconcile(ctx context.Context, request reconcile.
 	clusterConfig := relasticsearch.NewClusterConfig(render.DefaultElasticsearchClusterName, ls.Replicas(), logstoragecommon.DefaultElasticsearchShards, flowShards)
 
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, ls)
+	hdler.SetController(externalControllerName)
 	externalElasticsearch := externalelasticsearch.ExternalElasticsearch(install, clusterConfig, pullSecrets)
 	if err := hdler.CreateOrUpdateOrDelete(ctx, externalElasticsearch, r.status); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
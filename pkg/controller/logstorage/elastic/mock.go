@@ -72,3 +72,41 @@ func (m *MockESClient) GetUsers(ctx context.Context) ([]utils.User, error) {
 	ret := m.Called(ctx)
 	return ret.Get(0).([]utils.User), ret.Error(1)
 }
+
+func (m *MockESClient) SetSnapshotPolicy(_ context.Context, _ *operatorv1.LogStorage) error {
+	return nil
+}
+
+func (m *MockESClient) GetSnapshotStatus(_ context.Context) (*operatorv1.ElasticsearchSnapshotStatus, error) {
+	return nil, nil
+}
+
+func (m *MockESClient) RestoreSnapshot(ctx context.Context, snapshot string, indices []string) error {
+	ret := m.Called(ctx, snapshot, indices)
+	return ret.Error(0)
+}
+
+func (m *MockESClient) GetRestoreProgress(ctx context.Context, snapshot string, indices []string) (int, int, error) {
+	ret := m.Called(ctx, snapshot, indices)
+	return ret.Int(0), ret.Int(1), ret.Error(2)
+}
+
+func (m *MockESClient) CreateSnapshot(ctx context.Context, snapshot string, indices []string) error {
+	ret := m.Called(ctx, snapshot, indices)
+	return ret.Error(0)
+}
+
+func (m *MockESClient) GetSnapshotState(ctx context.Context, snapshot string) (string, error) {
+	ret := m.Called(ctx, snapshot)
+	return ret.String(0), ret.Error(1)
+}
+
+func (m *MockESClient) GetClusterHealth(ctx context.Context) (string, error) {
+	ret := m.Called(ctx)
+	return ret.String(0), ret.Error(1)
+}
+
+func (m *MockESClient) GetNodesOverDiskWatermark(ctx context.Context) ([]string, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).([]string), ret.Error(1)
+}
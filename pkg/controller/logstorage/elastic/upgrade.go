@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elastic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
+)
+
+const (
+	// upgradeSnapshotPrefix is the prefix of the snapshots taken before Elasticsearch is upgraded.
+	upgradeSnapshotPrefix = "tigera-pre-upgrade-"
+
+	snapshotStateSuccess    = "SUCCESS"
+	snapshotStateInProgress = "IN_PROGRESS"
+)
+
+// elasticsearchUpgrade is the phase of an upgrade of Elasticsearch and a message describing it.
+type elasticsearchUpgrade struct {
+	phase   operatorv1.ElasticsearchUpgradePhase
+	message string
+}
+
+// held returns whether Elasticsearch must keep running its current version.
+func (u *elasticsearchUpgrade) held() bool {
+	return u != nil && (u.phase == operatorv1.ElasticsearchUpgradeBlocked || u.phase == operatorv1.ElasticsearchUpgradeSnapshot)
+}
+
+// upgradeSnapshotName returns the name of the snapshot taken before upgrading Elasticsearch to the version.
+func upgradeSnapshotName(version string) string {
+	return upgradeSnapshotPrefix + strings.ToLower(version)
+}
+
+// checkUpgrade returns the phase of the upgrade of Elasticsearch to the operator's version, or nil if there is no
+// upgrade to track. Before the new version is rolled out, the cluster must be ready and green, none of its nodes may be
+// over the high disk watermark, and a snapshot of its indices is taken if the LogStorage has a snapshot repository.
+func (r *ElasticSubController) checkUpgrade(ctx context.Context, ls *operatorv1.LogStorage, es *esv1.Elasticsearch, reqLogger logr.Logger) *elasticsearchUpgrade {
+	target := components.ComponentEckElasticsearch.Version
+	if es == nil || es.Spec.Version == "" {
+		// There is no cluster to upgrade yet.
+		return nil
+	}
+
+	if es.Spec.Version == target {
+		// The new version has been rolled out, track it until all the nodes run it.
+		current := meta.FindStatusCondition(ls.Status.Conditions, operatorv1.ElasticsearchUpgradeCondition)
+		if current == nil {
+			return nil
+		}
+		if es.Status.Version != target || (current.Reason != string(operatorv1.ElasticsearchUpgradeSucceeded) && es.Status.Phase != esv1.ElasticsearchReadyPhase) {
+			return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeUpgrading, fmt.Sprintf("Upgrading Elasticsearch to %s", target)}
+		}
+		return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeSucceeded, fmt.Sprintf("Elasticsearch was upgraded to %s", target)}
+	}
+
+	blocked := func(format string, args ...interface{}) *elasticsearchUpgrade {
+		msg := fmt.Sprintf("Upgrade of Elasticsearch from %s to %s is blocked: %s", es.Spec.Version, target, fmt.Sprintf(format, args...))
+		reqLogger.Info(msg)
+		return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeBlocked, msg}
+	}
+
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+		return blocked("the Elasticsearch cluster is not ready")
+	}
+
+	esClient, err := r.esCliCreator(r.client, ctx, relasticsearch.ECKElasticEndpoint())
+	if err != nil {
+		return blocked("failed to connect to Elasticsearch: %v", err)
+	}
+
+	// Upgrading restarts every node, which loses data if a shard has no replica.
+	health, err := esClient.GetClusterHealth(ctx)
+	if err != nil {
+		return blocked("%v", err)
+	}
+	if health != "green" {
+		return blocked("the cluster health is %s", health)
+	}
+
+	// Shards can't move to a node that is over the high disk watermark while the other nodes restart.
+	nodes, err := esClient.GetNodesOverDiskWatermark(ctx)
+	if err != nil {
+		return blocked("%v", err)
+	}
+	if len(nodes) > 0 {
+		return blocked("nodes %s are over the high disk watermark", strings.Join(nodes, ", "))
+	}
+
+	if ls.Spec.Snapshots != nil {
+		snapshot := upgradeSnapshotName(target)
+		// Make sure the snapshot repository exists, the snapshot is taken before the snapshot policy is applied.
+		if err = esClient.SetSnapshotPolicy(ctx, ls); err != nil {
+			return blocked("%v", err)
+		}
+		state, err := esClient.GetSnapshotState(ctx, snapshot)
+		if err != nil {
+			return blocked("%v", err)
+		}
+		switch state {
+		case "":
+			if err = esClient.CreateSnapshot(ctx, snapshot, ls.Spec.Snapshots.Indices); err != nil {
+				return blocked("%v", err)
+			}
+			return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeSnapshot, fmt.Sprintf("Taking snapshot %s before upgrading Elasticsearch to %s", snapshot, target)}
+		case snapshotStateInProgress:
+			return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeSnapshot, fmt.Sprintf("Taking snapshot %s before upgrading Elasticsearch to %s", snapshot, target)}
+		case snapshotStateSuccess:
+		default:
+			return blocked("snapshot %s is %s, delete it to take it again", snapshot, state)
+		}
+	} else {
+		reqLogger.Info("No snapshot is taken before upgrading Elasticsearch, as the LogStorage has no snapshot repository")
+	}
+
+	return &elasticsearchUpgrade{operatorv1.ElasticsearchUpgradeUpgrading, fmt.Sprintf("Upgrading Elasticsearch from %s to %s", es.Spec.Version, target)}
+}
+
+// setUpgradeCondition sets the LogStorage condition that tracks the phase of the upgrade of Elasticsearch.
+func (r *ElasticSubController) setUpgradeCondition(ctx context.Context, ls *operatorv1.LogStorage, upgrade *elasticsearchUpgrade) error {
+	status := metav1.ConditionFalse
+	if upgrade.phase == operatorv1.ElasticsearchUpgradeSucceeded {
+		status = metav1.ConditionTrue
+	}
+	original := ls.DeepCopy()
+	meta.SetStatusCondition(&ls.Status.Conditions, metav1.Condition{
+		Type:               operatorv1.ElasticsearchUpgradeCondition,
+		Status:             status,
+		Reason:             string(upgrade.phase),
+		Message:            upgrade.message,
+		ObservedGeneration: ls.Generation,
+	})
+	if reflect.DeepEqual(original.Status.Conditions, ls.Status.Conditions) {
+		return nil
+	}
+	return r.client.Status().Patch(ctx, ls, client.MergeFrom(original))
+}
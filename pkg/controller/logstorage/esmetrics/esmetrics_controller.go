@@ -48,6 +48,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const controllerName = "log-storage-esmetrics-controller"
+
 var log = logf.Log.WithName("controller_logstorage_esmetrics")
 
 const (
@@ -79,14 +81,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	r := &ESMetricsSubController{
 		client:         mgr.GetClient(),
 		scheme:         mgr.GetScheme(),
-		status:         status.New(mgr.GetClient(), tigeraStatusName, opts.KubernetesVersion),
+		status:         status.NewForManager(mgr, tigeraStatusName, opts.KubernetesVersion),
 		clusterDomain:  opts.ClusterDomain,
 		provider:       opts.DetectedProvider,
 		tierWatchReady: &utils.ReadyFlag{},
 	}
 	r.status.Run(opts.ShutdownContext)
 
-	c, err := controller.New("log-storage-esmetrics-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return fmt.Errorf("log-storage-esmetrics-controller failed to establish a connection to k8s: %w", err)
 	}
@@ -97,6 +99,9 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	if err = utils.AddInstallationWatch(c); err != nil {
 		return fmt.Errorf("log-storage-esmetrics-controller failed to watch Installation resource: %w", err)
 	}
+	if err = imageset.AddImageSetWatch(c, mgr.GetClient()); err != nil {
+		return fmt.Errorf("log-storage-esmetrics-controller failed to watch ImageSet resource: %w", err)
+	}
 	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagementClusterConnection{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return fmt.Errorf("log-storage-esmetrics-controller failed to watch ManagementClusterConnection resource: %w", err)
 	}
@@ -156,6 +161,11 @@ func (r *ESMetricsSubController) Reconcile(ctx context.Context, request reconcil
 
 	r.status.OnCRFound()
 
+	if utils.IsPaused(logStorage) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Wait for the initializing controller to indicate that the LogStorage object is actionable.
 	if logStorage.Status.State != operatorv1.TigeraStatusReady {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for LogStorage defaulting to occur", nil, reqLogger)
@@ -205,7 +215,7 @@ func (r *ESMetricsSubController) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, err
 	}
 
-	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger))
+	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return reconcile.Result{}, err
@@ -245,11 +255,12 @@ func (r *ESMetricsSubController) Reconcile(ctx context.Context, request reconcil
 	}
 	esMetricsComponent := esmetrics.ElasticsearchMetrics(esMetricsCfg)
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, esMetricsComponent); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, logStorage)
+	hdler.SetController(controllerName)
 
 	if err = hdler.CreateOrUpdateOrDelete(ctx, esMetricsComponent, r.status); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
@@ -48,7 +48,7 @@ func AddConditionsController(mgr manager.Manager, opts options.AddOptions) error
 	}
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-conditions-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("log-storage-conditions-controller", mgr, controller.Options{Reconciler: utils.SerializeTenantReconciles(r), MaxConcurrentReconciles: opts.MaxConcurrentTenantReconciles()})
 	if err != nil {
 		return err
 	}
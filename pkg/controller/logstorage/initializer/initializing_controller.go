@@ -17,6 +17,8 @@ package initializer
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -39,6 +41,8 @@ import (
 	"github.com/tigera/operator/pkg/render"
 )
 
+const controllerName = "log-storage-initializing-controller"
+
 var log = logf.Log.WithName("controller_logstorage")
 
 const (
@@ -59,12 +63,12 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		client:      mgr.GetClient(),
 		scheme:      mgr.GetScheme(),
 		multiTenant: opts.MultiTenant,
-		status:      status.New(mgr.GetClient(), TigeraStatusName, opts.KubernetesVersion),
+		status:      status.NewForManager(mgr, TigeraStatusName, opts.KubernetesVersion),
 	}
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-initializing-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -141,6 +145,18 @@ func FillDefaults(opr *operatorv1.LogStorage) {
 		opr.Spec.Nodes = &operatorv1.Nodes{Count: 1}
 	}
 
+	if snapshots := opr.Spec.Snapshots; snapshots != nil {
+		if snapshots.Schedule == "" {
+			snapshots.Schedule = utils.DefaultSnapshotSchedule
+		}
+		if len(snapshots.Indices) == 0 {
+			snapshots.Indices = []string{utils.DefaultSnapshotIndices}
+		}
+		if snapshots.ExpireAfter == "" {
+			snapshots.ExpireAfter = utils.DefaultSnapshotExpireAfter
+		}
+	}
+
 	if opr.Spec.ComponentResources == nil {
 		limits := corev1.ResourceList{}
 		requests := corev1.ResourceList{}
@@ -174,6 +190,194 @@ func validateComponentResources(spec *operatorv1.LogStorageSpec) error {
 	return nil
 }
 
+// ilmDataTypes are the types of data that have ILM policies. The optional ones only have a policy when the LogStorage
+// configures one.
+var ilmDataTypes = map[operatorv1.DataType]bool{
+	operatorv1.DataTypeAlerts:               false,
+	operatorv1.DataTypeAuditLogs:            false,
+	operatorv1.DataTypeBGPLogs:              false,
+	operatorv1.DataTypeComplianceBenchmarks: false,
+	operatorv1.DataTypeComplianceReports:    false,
+	operatorv1.DataTypeComplianceSnapshots:  false,
+	operatorv1.DataTypeDNSLogs:              false,
+	operatorv1.DataTypeFlowLogs:             false,
+	operatorv1.DataTypeL7Logs:               false,
+	operatorv1.DataTypeRuntimeReports:       true,
+	operatorv1.DataTypeWAFLogs:              true,
+}
+
+// validateIndexLifecyclePolicies validates the LogStorage's IndexLifecyclePolicies. The spec must be defaulted.
+func validateIndexLifecyclePolicies(spec *operatorv1.LogStorageSpec) error {
+	seen := map[operatorv1.DataType]bool{}
+	diskShares, typesWithDiskShare, typesWithIndices := 0, 0, 0
+	for _, optional := range ilmDataTypes {
+		if !optional {
+			typesWithIndices++
+		}
+	}
+
+	for _, p := range spec.IndexLifecyclePolicies {
+		optional, ok := ilmDataTypes[p.DataType]
+		if !ok {
+			return fmt.Errorf("LogStorage spec.indexLifecyclePolicies contains unsupported data type %s", p.DataType)
+		}
+		if seen[p.DataType] {
+			return fmt.Errorf("LogStorage spec.indexLifecyclePolicies contains data type %s more than once", p.DataType)
+		}
+		seen[p.DataType] = true
+		if optional {
+			typesWithIndices++
+		}
+		if p.DiskShare != nil {
+			diskShares += int(*p.DiskShare)
+			typesWithDiskShare++
+		}
+
+		if p.RolloverAge != "" {
+			if _, err := utils.ParseILMAge(p.RolloverAge); err != nil {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies rolloverAge for %s is invalid: %v", p.DataType, err)
+			}
+		}
+		if p.SnapshotRepository != "" && p.SnapshotRepository != utils.SnapshotRepositoryName {
+			return fmt.Errorf("LogStorage spec.indexLifecyclePolicies snapshotRepository for %s must be %s, the repository configured by spec.snapshots", p.DataType, utils.SnapshotRepositoryName)
+		}
+		if p.FrozenAge != "" {
+			// The searchable snapshots of the frozen phase are taken in the operator's snapshot repository and can
+			// only be mounted on frozen nodes.
+			if spec.Snapshots == nil {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies for %s sets frozenAge without spec.snapshots", p.DataType)
+			}
+			if !hasFrozenNodes(spec) {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies for %s sets frozenAge without a nodeSet with the Frozen role", p.DataType)
+			}
+		}
+
+		// The phases must start in order, and before the indices are deleted at the end of the retention period.
+		retention := utils.ILMRetention(spec, p.DataType)
+		var previous *time.Duration
+		for _, phase := range []struct{ name, age string }{{"warmAge", p.WarmAge}, {"coldAge", p.ColdAge}, {"frozenAge", p.FrozenAge}} {
+			if phase.age == "" {
+				continue
+			}
+			age, err := utils.ParseILMAge(phase.age)
+			if err != nil {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies %s for %s is invalid: %v", phase.name, p.DataType, err)
+			}
+			if previous != nil && age <= *previous {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies %s for %s is not later than the previous phase", phase.name, p.DataType)
+			}
+			if age >= time.Duration(retention)*24*time.Hour {
+				return fmt.Errorf("LogStorage spec.indexLifecyclePolicies %s for %s is not earlier than the retention of %d days", phase.name, p.DataType, retention)
+			}
+			previous = &age
+		}
+	}
+
+	if diskShares > 100 || (diskShares == 100 && typesWithDiskShare < typesWithIndices) {
+		return fmt.Errorf("LogStorage spec.indexLifecyclePolicies disk shares add up to %d, which leaves no disk for the other data types", diskShares)
+	}
+	return nil
+}
+
+// validateNodeSets validates the counts and roles of the LogStorage's NodeSets.
+func validateNodeSets(spec *operatorv1.LogStorageSpec) error {
+	if spec.Nodes == nil || len(spec.Nodes.NodeSets) == 0 {
+		return nil
+	}
+
+	var count, withoutCount int64
+	var withRoles, master, hot bool
+	for i, ns := range spec.Nodes.NodeSets {
+		count += ns.Count
+		if ns.Count < 1 {
+			withoutCount++
+		}
+		if len(ns.Roles) == 0 {
+			// Nodes without roles have every role.
+			master, hot = true, true
+			continue
+		}
+		withRoles = true
+		seen := map[operatorv1.ElasticsearchNodeRole]bool{}
+		for _, role := range ns.Roles {
+			if seen[role] {
+				return fmt.Errorf("LogStorage spec.nodes.nodeSets[%d] contains role %s more than once", i, role)
+			}
+			seen[role] = true
+		}
+		// Elasticsearch only sizes the cache of the searchable snapshots for nodes that have no role but frozen.
+		if seen[operatorv1.ElasticsearchNodeRoleFrozen] && len(ns.Roles) > 1 {
+			return fmt.Errorf("LogStorage spec.nodes.nodeSets[%d] combines the Frozen role with other roles", i)
+		}
+		master = master || seen[operatorv1.ElasticsearchNodeRoleMaster]
+		hot = hot || seen[operatorv1.ElasticsearchNodeRoleHot]
+	}
+
+	if count > spec.Nodes.Count {
+		return fmt.Errorf("LogStorage spec.nodes.nodeSets counts add up to %d, which is more than spec.nodes.count %d", count, spec.Nodes.Count)
+	}
+	// The NodeSets without a count share the nodes that are left, and would not be created if there are none left
+	// for them.
+	if count > 0 && spec.Nodes.Count-count < withoutCount {
+		return fmt.Errorf("LogStorage spec.nodes.nodeSets counts add up to %d, which leaves %d of spec.nodes.count %d for the %d nodeSets without a count", count, spec.Nodes.Count-count, spec.Nodes.Count, withoutCount)
+	}
+	if withRoles && !master {
+		return fmt.Errorf("LogStorage spec.nodes.nodeSets must include Master nodes")
+	}
+	if withRoles && !hot {
+		return fmt.Errorf("LogStorage spec.nodes.nodeSets must include Hot nodes")
+	}
+	return nil
+}
+
+// hasFrozenNodes returns true if one of the LogStorage's NodeSets has the Frozen role.
+func hasFrozenNodes(spec *operatorv1.LogStorageSpec) bool {
+	if spec.Nodes == nil {
+		return false
+	}
+	for _, ns := range spec.Nodes.NodeSets {
+		for _, role := range ns.Roles {
+			if role == operatorv1.ElasticsearchNodeRoleFrozen {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateSnapshots validates the snapshot repository and restore in the LogStorage spec.
+func validateSnapshots(spec *operatorv1.LogStorageSpec) error {
+	if spec.Snapshots == nil {
+		return nil
+	}
+	repo := spec.Snapshots.Repository
+	if (repo.Filesystem == nil) == (repo.S3 == nil) {
+		return fmt.Errorf("LogStorage spec.snapshots.repository must set exactly one of filesystem or s3")
+	}
+	if repo.Filesystem != nil && repo.Filesystem.PersistentVolumeClaimName == "" {
+		return fmt.Errorf("LogStorage spec.snapshots.repository.filesystem must set persistentVolumeClaimName")
+	}
+	if repo.S3 != nil {
+		if repo.S3.Bucket == "" || repo.S3.CredentialsSecretName == "" {
+			return fmt.Errorf("LogStorage spec.snapshots.repository.s3 must set bucket and credentialsSecretName")
+		}
+		if repo.S3.Endpoint != "" {
+			if u, err := url.Parse(repo.S3.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("LogStorage spec.snapshots.repository.s3 endpoint %q must be an http or https URL", repo.S3.Endpoint)
+			}
+		}
+	}
+	if spec.Snapshots.ExpireAfter != "" {
+		if _, err := utils.ParseILMAge(spec.Snapshots.ExpireAfter); err != nil {
+			return fmt.Errorf("LogStorage spec.snapshots.expireAfter is invalid: %v", err)
+		}
+	}
+	if spec.Snapshots.Restore != nil && spec.Snapshots.Restore.Snapshot == "" {
+		return fmt.Errorf("LogStorage spec.snapshots.restore must set snapshot")
+	}
+	return nil
+}
+
 func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling LogStorage")
@@ -226,6 +430,15 @@ func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile
 	// Default and validate the object.
 	FillDefaults(ls)
 	err = validateComponentResources(&ls.Spec)
+	if err == nil {
+		err = validateIndexLifecyclePolicies(&ls.Spec)
+	}
+	if err == nil {
+		err = validateSnapshots(&ls.Spec)
+	}
+	if err == nil {
+		err = validateNodeSets(&ls.Spec)
+	}
 	if err != nil {
 		// Invalid - mark it as such and return.
 		r.setConditionDegraded(ctx, ls, reqLogger)
@@ -235,6 +448,7 @@ func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile
 
 	// Before we can create secrets, we need to ensure the tigera-elasticsearch namespace exists.
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, ls)
+	hdler.SetController(controllerName)
 	esNamespace := render.CreateNamespace(render.ElasticsearchNamespace, install.KubernetesProvider, render.PSSPrivileged)
 	if err = hdler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(esNamespace), r.status); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
@@ -258,7 +472,13 @@ func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Failed to update LogStorage status", err, reqLogger)
 		return reconcile.Result{}, err
 	}
-	defer r.status.SetMetaData(&ls.ObjectMeta)
+	defer r.status.SetMetaData(ls)
+
+	// Pausing LogStorage pauses each of the LogStorage controllers, but only this one reports it in its TigeraStatus.
+	if utils.IsPaused(ls) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
 
 	// Mark the status as available.
 	r.status.ReadyToMonitor()
@@ -299,6 +299,198 @@ var _ = Describe("LogStorage Initializing controller", func() {
 		})
 	})
 
+	Context("validateIndexLifecyclePolicies", func() {
+		share := func(v int32) *int32 { return &v }
+		defaulted := func(spec operatorv1.LogStorageSpec) *operatorv1.LogStorageSpec {
+			ls := &operatorv1.LogStorage{Spec: spec}
+			FillDefaults(ls)
+			return &ls.Spec
+		}
+
+		frozen := func(spec operatorv1.LogStorageSpec) operatorv1.LogStorageSpec {
+			spec.Snapshots = &operatorv1.ElasticsearchSnapshots{Repository: operatorv1.SnapshotRepository{
+				Filesystem: &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "snapshots"},
+			}}
+			spec.Nodes = &operatorv1.Nodes{Count: 2, NodeSets: []operatorv1.NodeSet{
+				{},
+				{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleFrozen}},
+			}}
+			return spec
+		}
+
+		It("should accept policies for each data type", func() {
+			spec := defaulted(frozen(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeFlowLogs, DiskShare: share(5), RolloverAge: "12h"},
+				{DataType: operatorv1.DataTypeL7Logs, DiskShare: share(60), Retention: share(30), WarmAge: "1d", ColdAge: "3d", FrozenAge: "7d", SnapshotRepository: "tigera-snapshots"},
+				{DataType: operatorv1.DataTypeWAFLogs, WarmShards: share(1)},
+			}}))
+			Expect(validateIndexLifecyclePolicies(spec)).To(BeNil())
+		})
+
+		It("should reject duplicate and unsupported data types", func() {
+			spec := defaulted(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeFlowLogs}, {DataType: operatorv1.DataTypeFlowLogs},
+			}})
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+
+			spec.IndexLifecyclePolicies = []operatorv1.IndexLifecyclePolicy{{DataType: operatorv1.DataTypeThreatFeedsIPSet}}
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+		})
+
+		It("should reject phases that are out of order", func() {
+			spec := defaulted(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeDNSLogs, WarmAge: "2d", ColdAge: "36h"},
+			}})
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+		})
+
+		It("should reject phases that start at the same age", func() {
+			spec := defaulted(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeDNSLogs, WarmAge: "2d", ColdAge: "48h"},
+			}})
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+		})
+
+		It("should reject phases that don't start before the end of the retention period", func() {
+			spec := defaulted(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeDNSLogs, ColdAge: "8d"},
+			}})
+			Expect(validateIndexLifecyclePolicies(spec)).To(MatchError(ContainSubstring("retention of 8 days")))
+
+			spec.IndexLifecyclePolicies[0].Retention = share(9)
+			Expect(validateIndexLifecyclePolicies(spec)).To(BeNil())
+
+			spec.IndexLifecyclePolicies = []operatorv1.IndexLifecyclePolicy{{DataType: operatorv1.DataTypeL7Logs, WarmAge: "1d"}}
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+		})
+
+		It("should require the LogStorage's snapshot repository and frozen nodes for the frozen phase", func() {
+			spec := defaulted(frozen(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeDNSLogs, FrozenAge: "5d"},
+			}}))
+			Expect(validateIndexLifecyclePolicies(spec)).To(BeNil())
+
+			spec.IndexLifecyclePolicies[0].SnapshotRepository = "logs"
+			Expect(validateIndexLifecyclePolicies(spec)).To(MatchError(ContainSubstring("must be tigera-snapshots")))
+
+			spec.IndexLifecyclePolicies[0].SnapshotRepository = ""
+			spec.Nodes.NodeSets[1].Roles = []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleCold}
+			Expect(validateIndexLifecyclePolicies(spec)).To(MatchError(ContainSubstring("without a nodeSet with the Frozen role")))
+
+			spec.Nodes.NodeSets[1].Roles = []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleFrozen}
+			spec.Snapshots = nil
+			Expect(validateIndexLifecyclePolicies(spec)).To(MatchError(ContainSubstring("without spec.snapshots")))
+		})
+
+		It("should reject disk shares that leave no disk for the other data types", func() {
+			spec := defaulted(operatorv1.LogStorageSpec{IndexLifecyclePolicies: []operatorv1.IndexLifecyclePolicy{
+				{DataType: operatorv1.DataTypeFlowLogs, DiskShare: share(50)},
+				{DataType: operatorv1.DataTypeL7Logs, DiskShare: share(50)},
+			}})
+			Expect(validateIndexLifecyclePolicies(spec)).NotTo(BeNil())
+
+			spec.IndexLifecyclePolicies[1].DiskShare = share(49)
+			Expect(validateIndexLifecyclePolicies(spec)).To(BeNil())
+		})
+	})
+
+	Context("validateSnapshots", func() {
+		var spec *operatorv1.LogStorageSpec
+		BeforeEach(func() {
+			spec = &operatorv1.LogStorageSpec{Snapshots: &operatorv1.ElasticsearchSnapshots{
+				Repository: operatorv1.SnapshotRepository{
+					S3: &operatorv1.S3SnapshotRepository{Bucket: "backups", Endpoint: "https://s3.example.com", CredentialsSecretName: "s3-creds"},
+				},
+				ExpireAfter: "14d",
+				Restore:     &operatorv1.SnapshotRestore{Snapshot: "snap-1"},
+			}}
+		})
+
+		It("should accept a valid S3 repository", func() {
+			Expect(validateSnapshots(spec)).To(BeNil())
+			Expect(validateSnapshots(&operatorv1.LogStorageSpec{})).To(BeNil())
+		})
+
+		It("should require exactly one repository", func() {
+			spec.Snapshots.Repository.Filesystem = &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "snapshots"}
+			Expect(validateSnapshots(spec)).NotTo(BeNil())
+
+			spec.Snapshots.Repository = operatorv1.SnapshotRepository{}
+			Expect(validateSnapshots(spec)).NotTo(BeNil())
+		})
+
+		It("should reject an S3 repository without credentials or with an invalid endpoint", func() {
+			spec.Snapshots.Repository.S3.CredentialsSecretName = ""
+			Expect(validateSnapshots(spec)).NotTo(BeNil())
+
+			spec.Snapshots.Repository.S3.CredentialsSecretName = "s3-creds"
+			spec.Snapshots.Repository.S3.Endpoint = "s3.example.com"
+			Expect(validateSnapshots(spec)).NotTo(BeNil())
+		})
+
+		It("should reject a restore without a snapshot", func() {
+			spec.Snapshots.Restore.Snapshot = ""
+			Expect(validateSnapshots(spec)).NotTo(BeNil())
+		})
+	})
+
+	Context("validateNodeSets", func() {
+		var spec *operatorv1.LogStorageSpec
+		BeforeEach(func() {
+			spec = &operatorv1.LogStorageSpec{Nodes: &operatorv1.Nodes{
+				Count: 3,
+				NodeSets: []operatorv1.NodeSet{
+					{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleMaster, operatorv1.ElasticsearchNodeRoleHot}},
+					{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleWarm}, Count: 1},
+				},
+			}}
+		})
+
+		It("should accept NodeSets with master and hot nodes", func() {
+			Expect(validateNodeSets(spec)).To(BeNil())
+			Expect(validateNodeSets(&operatorv1.LogStorageSpec{})).To(BeNil())
+		})
+
+		It("should accept NodeSets without roles in place of master or hot nodes", func() {
+			spec.Nodes.NodeSets[0].Roles = nil
+			Expect(validateNodeSets(spec)).To(BeNil())
+		})
+
+		It("should reject NodeSets without master or hot nodes", func() {
+			spec.Nodes.NodeSets[0].Roles = []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleHot}
+			Expect(validateNodeSets(spec)).NotTo(BeNil())
+
+			spec.Nodes.NodeSets[0].Roles = []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleMaster}
+			Expect(validateNodeSets(spec)).NotTo(BeNil())
+		})
+
+		It("should reject the Frozen role combined with other roles", func() {
+			spec.Nodes.NodeSets[1].Roles = []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleFrozen}
+			Expect(validateNodeSets(spec)).To(BeNil())
+
+			spec.Nodes.NodeSets[1].Roles = append(spec.Nodes.NodeSets[1].Roles, operatorv1.ElasticsearchNodeRoleCold)
+			Expect(validateNodeSets(spec)).NotTo(BeNil())
+		})
+
+		It("should reject a role that is repeated", func() {
+			spec.Nodes.NodeSets[1].Roles = append(spec.Nodes.NodeSets[1].Roles, operatorv1.ElasticsearchNodeRoleWarm)
+			Expect(validateNodeSets(spec)).NotTo(BeNil())
+		})
+
+		It("should reject NodeSet counts that add up to more than the node count", func() {
+			spec.Nodes.NodeSets[0].Count = 3
+			Expect(validateNodeSets(spec)).NotTo(BeNil())
+		})
+
+		It("should reject NodeSet counts that leave no nodes for the NodeSets without a count", func() {
+			spec.Nodes.NodeSets[1].Count = 3
+			Expect(validateNodeSets(spec)).To(MatchError(ContainSubstring("leaves 0 of spec.nodes.count 3 for the 1 nodeSets without a count")))
+
+			spec.Nodes.NodeSets[1].Count = 2
+			Expect(validateNodeSets(spec)).To(BeNil())
+		})
+	})
+
 	Context("FillDefaults", func() {
 		It("should set the replica values to the default settings", func() {
 			retain8 := int32(8)
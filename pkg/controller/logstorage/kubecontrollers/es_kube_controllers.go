@@ -52,6 +52,8 @@ import (
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 )
 
+const controllerName = "log-storage-kubecontrollers-controller"
+
 var log = logf.Log.WithName("controller_logstorage_kube-controllers")
 
 type ESKubeControllersController struct {
@@ -78,14 +80,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		client:          mgr.GetClient(),
 		scheme:          mgr.GetScheme(),
 		clusterDomain:   opts.ClusterDomain,
-		status:          status.New(mgr.GetClient(), "log-storage-kubecontrollers", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "log-storage-kubecontrollers", opts.KubernetesVersion),
 		elasticExternal: opts.ElasticExternal,
 		tierWatchReady:  &utils.ReadyFlag{},
 	}
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-kubecontrollers-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -100,6 +102,9 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	if err = c.Watch(&source.Kind{Type: &operatorv1.Installation{}}, eventHandler); err != nil {
 		return fmt.Errorf("log-storage-kubecontrollers failed to watch Installation resource: %w", err)
 	}
+	if err = imageset.AddImageSetWatchWithHandler(c, mgr.GetClient(), eventHandler); err != nil {
+		return fmt.Errorf("log-storage-kubecontrollers failed to watch ImageSet resource: %w", err)
+	}
 	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, eventHandler); err != nil {
 		return fmt.Errorf("log-storage-kubecontrollers failed to watch ManagementCluster resource: %w", err)
 	}
@@ -177,6 +182,11 @@ func (r *ESKubeControllersController) Reconcile(ctx context.Context, request rec
 	// We found the LogStorage instance (and Tenant instance if in multi-tenant mode).
 	r.status.OnCRFound()
 
+	if utils.IsPaused(logStorage) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Wait for the initializing controller to indicate that the LogStorage object is actionable.
 	if logStorage.Status.State != operatorv1.TigeraStatusReady {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for LogStorage defaulting to occur", nil, reqLogger)
@@ -250,6 +260,7 @@ func (r *ESKubeControllersController) Reconcile(ctx context.Context, request rec
 
 	// Collect the certificates we need to provision es-kube-controllers. These will have been provisioned already by the ES secrets controller.
 	opts := []certificatemanager.Option{
+		certificatemanager.WithContext(ctx),
 		certificatemanager.WithLogger(reqLogger),
 	}
 	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, helper.TruthNamespace(), opts...)
@@ -266,6 +277,7 @@ func (r *ESKubeControllersController) Reconcile(ctx context.Context, request rec
 	}
 
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, logStorage)
+	hdler.SetController(controllerName)
 
 	// Get the Authentication resource.
 	authentication, err := utils.GetAuthentication(ctx, r.client)
@@ -327,6 +339,11 @@ func (r *ESKubeControllersController) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	if err = imageset.VerifyImageSet(ctx, r.client, imageSet); err != nil {
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error verifying ImageSet signature", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	if err = imageset.ResolveImages(imageSet, esKubeControllerComponents); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceValidationError, "Error resolving ImageSet for elasticsearch kube-controllers components", err, reqLogger)
 		return reconcile.Result{}, err
@@ -73,7 +73,7 @@ func (r *ESKubeControllersController) createESGateway(
 	}
 
 	// Collect the certificates we need to provision ESGW. These will have been provisioned already by the ES secrets controller.
-	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, helper.TruthNamespace())
+	cm, err := certificatemanager.Create(r.client, install, r.clusterDomain, helper.TruthNamespace(), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to create the Tigera CA", err, reqLogger)
 		return err
@@ -114,7 +114,7 @@ func (r *ESKubeControllersController) createESGateway(
 
 	esGatewayComponent := esgateway.EsGateway(cfg)
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, esGatewayComponent); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return err
 	}
 
@@ -55,6 +55,8 @@ import (
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 )
 
+const controllerName = "log-storage-access-controller"
+
 var log = logf.Log.WithName("controller_logstorage_linseed")
 
 type LinseedSubController struct {
@@ -82,13 +84,13 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		tierWatchReady:  &utils.ReadyFlag{},
 		dpiAPIReady:     &utils.ReadyFlag{},
 		multiTenant:     opts.MultiTenant,
-		status:          status.New(mgr.GetClient(), "log-storage-access", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "log-storage-access", opts.KubernetesVersion),
 		elasticExternal: opts.ElasticExternal,
 	}
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-access-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -107,6 +109,9 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	if err = c.Watch(&source.Kind{Type: &operatorv1.Installation{}}, eventHandler); err != nil {
 		return fmt.Errorf("log-storage-access-controller failed to watch Installation resource: %w", err)
 	}
+	if err = imageset.AddImageSetWatchWithHandler(c, mgr.GetClient(), eventHandler); err != nil {
+		return fmt.Errorf("log-storage-access-controller failed to watch ImageSet resource: %w", err)
+	}
 	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, eventHandler); err != nil {
 		return fmt.Errorf("log-storage-access-controller failed to watch ManagementCluster resource: %w", err)
 	}
@@ -235,6 +240,11 @@ func (r *LinseedSubController) Reconcile(ctx context.Context, request reconcile.
 	// We found the LogStorage instance (and Tenant instance if in multi-tenant mode).
 	r.status.OnCRFound()
 
+	if utils.IsPaused(logStorage) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Wait for the initializing controller to indicate that the LogStorage object is actionable.
 	if logStorage.Status.State != operatorv1.TigeraStatusReady {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for LogStorage defaulting to occur", nil, reqLogger)
@@ -346,6 +356,7 @@ func (r *LinseedSubController) Reconcile(ctx context.Context, request reconcile.
 
 	// Collect the certificates we need to provision Linseed. These will have been provisioned already by the ES secrets controller.
 	opts := []certificatemanager.Option{
+		certificatemanager.WithContext(ctx),
 		certificatemanager.WithLogger(reqLogger),
 		certificatemanager.WithTenant(tenant),
 	}
@@ -436,7 +447,7 @@ func (r *LinseedSubController) Reconcile(ctx context.Context, request reconcile.
 	linseedComponent := linseed.Linseed(cfg)
 
 	if err := imageset.ApplyImageSet(ctx, r.client, variant, linseedComponent); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, reqLogger)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, reqLogger)
 		return reconcile.Result{}, err
 	}
 
@@ -447,6 +458,7 @@ func (r *LinseedSubController) Reconcile(ctx context.Context, request reconcile.
 	} else {
 		hdler = utils.NewComponentHandler(reqLogger, r.client, r.scheme, logStorage)
 	}
+	hdler.SetController(controllerName)
 	if err := hdler.CreateOrUpdateOrDelete(ctx, linseedComponent, r.status); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating / deleting resource", err, reqLogger)
 		return reconcile.Result{}, err
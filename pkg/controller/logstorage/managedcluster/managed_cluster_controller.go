@@ -35,6 +35,8 @@ import (
 	"github.com/tigera/operator/pkg/render"
 )
 
+const controllerName = "log-storage-managedcluster-controller"
+
 var log = logf.Log.WithName("controller_logstorage_managed")
 
 // LogStorageManagedClusterController reconciles resources needed by managed clusters in order to
@@ -61,7 +63,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	}
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-managedcluster-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -141,6 +143,7 @@ func (r *LogStorageManagedClusterController) Reconcile(ctx context.Context, requ
 	}
 	component := render.NewManagedClusterLogStorage(cfg)
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, managementClusterConnection)
+	hdler.SetController(controllerName)
 	if err := hdler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -48,6 +48,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const controllerName = "log-storage-secrets-controller"
+
 var log = logf.Log.WithName("controller_logstorage_secrets")
 
 // SecretSubController is a sub controller for managing secrets related to Elasticsearch and log storage components.
@@ -72,13 +74,13 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		scheme:          mgr.GetScheme(),
 		clusterDomain:   opts.ClusterDomain,
 		multiTenant:     opts.MultiTenant,
-		status:          status.New(mgr.GetClient(), "log-storage-secrets", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "log-storage-secrets", opts.KubernetesVersion),
 		elasticExternal: opts.ElasticExternal,
 	}
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("log-storage-secrets-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -186,6 +188,11 @@ func (r *SecretSubController) Reconcile(ctx context.Context, request reconcile.R
 	// We found the LogStorage instance.
 	r.status.OnCRFound()
 
+	if utils.IsPaused(ls) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// We skip requests without a namespace specified in multi-tenant setups.
 	if r.multiTenant && request.Namespace == "" {
 		return reconcile.Result{}, nil
@@ -241,7 +248,7 @@ func (r *SecretSubController) Reconcile(ctx context.Context, request reconcile.R
 	var clusterCM, appCM certificatemanager.CertificateManager
 
 	// Cluster-scoped certificate manager, used for managing Elasticsearch secrets.
-	clusterCM, err = certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger))
+	clusterCM, err = certificatemanager.Create(r.client, install, r.clusterDomain, common.OperatorNamespace(), certificatemanager.WithLogger(reqLogger), certificatemanager.WithContext(ctx))
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceReadError, "Error building certificate manager", err, reqLogger)
 		return reconcile.Result{}, err
@@ -262,6 +269,7 @@ func (r *SecretSubController) Reconcile(ctx context.Context, request reconcile.R
 	if r.multiTenant {
 		// Override with a tenant-scoped certificate manager which uses the CA in the tenant's namespace.
 		opts := []certificatemanager.Option{
+			certificatemanager.WithContext(ctx),
 			certificatemanager.WithLogger(reqLogger),
 			certificatemanager.WithTenant(tenant),
 		}
@@ -275,6 +283,7 @@ func (r *SecretSubController) Reconcile(ctx context.Context, request reconcile.R
 
 	// Provision secrets and the trusted bundle into the cluster.
 	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, ls)
+	hdler.SetController(controllerName)
 
 	// Create Elasticsearch secrets.
 	esTrustedBundle := elasticKeys.trustedBundle(clusterCM)
@@ -51,6 +51,8 @@ import (
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 )
 
+const controllerName = "cmanager-controller"
+
 const ResourceName = "manager"
 
 var log = logf.Log.WithName("controller_manager")
@@ -70,7 +72,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady, tierWatchReady)
 
 	// Create a new controller
-	managerController, err := controller.New("cmanager-controller", mgr, controller.Options{Reconciler: reconciler})
+	managerController, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconciler})
 	if err != nil {
 		return fmt.Errorf("failed to create manager-controller: %w", err)
 	}
@@ -112,6 +114,9 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	if err = managerController.Watch(&source.Kind{Type: &operatorv1.Installation{}}, eventHandler); err != nil {
 		return fmt.Errorf("manager-controller failed to watch Installation resource: %w", err)
 	}
+	if err = imageset.AddImageSetWatchWithHandler(managerController, mgr.GetClient(), eventHandler); err != nil {
+		return fmt.Errorf("manager-controller failed to watch ImageSet resource: %w", err)
+	}
 	if err = managerController.Watch(&source.Kind{Type: &operatorv1.APIServer{}}, eventHandler); err != nil {
 		return fmt.Errorf("manager-controller failed to watch APIServer resource: %w", err)
 	}
@@ -183,7 +188,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 		client:          mgr.GetClient(),
 		scheme:          mgr.GetScheme(),
 		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "manager", opts.KubernetesVersion),
+		status:          status.NewForManager(mgr, "manager", opts.KubernetesVersion),
 		clusterDomain:   opts.ClusterDomain,
 		licenseAPIReady: licenseAPIReady,
 		tierWatchReady:  tierWatchReady,
@@ -275,7 +280,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating Manager status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -291,6 +296,11 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		}
 	}
 
+	if utils.IsPaused(instance) {
+		logc.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	if !utils.IsAPIServerReady(r.client, logc) {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for Tigera API server to be ready", nil, logc)
 		return reconcile.Result{}, nil
@@ -344,6 +354,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// When creating the certificate manager, pass in the logger and tenant (if one exists).
 	opts := []certificatemanager.Option{
+		certificatemanager.WithContext(ctx),
 		certificatemanager.WithLogger(logc),
 		certificatemanager.WithTenant(tenant),
 	}
@@ -607,6 +618,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// Create a component handler to manage the rendered component.
 	componentHandler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	componentHandler.SetController(controllerName)
 
 	// Set replicas to 1 for management or managed clusters.
 	// TODO Remove after MCM tigera-manager HA deployment is supported.
@@ -674,7 +686,7 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 	}
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, logc)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, logc)
 		return reconcile.Result{}, err
 	}
 
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics defines the operator's Prometheus metrics. They are registered with the controller-runtime metrics
+// registry, so they are served alongside the controller-runtime metrics when METRICS_HOST or METRICS_PORT is set.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+const namespace = "tigera_operator"
+
+// Actions recorded by ObjectsTotal.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+var (
+	// TigeraStatusCondition is 1 if the condition of the TigeraStatus is true, and 0 otherwise. There is a single
+	// series for each component and condition, labeled with the reason for the condition's current status.
+	TigeraStatusCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tigerastatus_condition",
+		Help:      "Whether the TigeraStatus condition of a component is true, labeled by the reason for the condition.",
+	}, []string{"component", "condition", "reason"})
+
+	// ObjectsTotal counts the objects that the component handler has created, updated and deleted.
+	ObjectsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "objects_total",
+		Help:      "Number of objects created, updated or deleted by the operator.",
+	}, []string{"kind", "action"})
+
+	// CertificateExpiry is the expiry time of each certificate that is issued or loaded by the certificate manager.
+	CertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "The time at which the certificate in the secret expires, in seconds since the epoch.",
+	}, []string{"namespace", "name"})
+
+	// TyphaReplicas is the number of Typha replicas that the Typha autoscaler wants (desired), and the number that
+	// the Typha deployment currently has (actual).
+	TyphaReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "typha_replicas",
+		Help:      "Desired and actual number of Typha replicas.",
+	}, []string{"type"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(TigeraStatusCondition, ObjectsTotal, CertificateExpiry, TyphaReplicas)
+}
+
+// SetTigeraStatusConditions records the conditions of the component's TigeraStatus.
+func SetTigeraStatusConditions(component string, conditions []operatorv1.TigeraStatusCondition) {
+	for _, c := range conditions {
+		// Remove the series for the condition's previous reason.
+		TigeraStatusCondition.DeletePartialMatch(prometheus.Labels{"component": component, "condition": string(c.Type)})
+		value := 0.0
+		if c.Status == operatorv1.ConditionTrue {
+			value = 1
+		}
+		TigeraStatusCondition.WithLabelValues(component, string(c.Type), string(c.Reason)).Set(value)
+	}
+}
+
+// DeleteTigeraStatusConditions removes the series for the component's TigeraStatus.
+func DeleteTigeraStatusConditions(component string) {
+	TigeraStatusCondition.DeletePartialMatch(prometheus.Labels{"component": component})
+}
+
+// RecordObject counts an object created, updated or deleted by the operator.
+func RecordObject(kind, action string) {
+	ObjectsTotal.WithLabelValues(kind, action).Inc()
+}
+
+// SetCertificateExpiry records the expiry time of the certificate in the given secret.
+func SetCertificateExpiry(secretNamespace, secretName string, notAfter time.Time) {
+	CertificateExpiry.WithLabelValues(secretNamespace, secretName).Set(float64(notAfter.Unix()))
+}
+
+// SetTyphaDesiredReplicas records the number of replicas the Typha autoscaler wants.
+func SetTyphaDesiredReplicas(replicas int) {
+	TyphaReplicas.WithLabelValues("desired").Set(float64(replicas))
+}
+
+// SetTyphaActualReplicas records the number of replicas the Typha deployment has.
+func SetTyphaActualReplicas(replicas int32) {
+	TyphaReplicas.WithLabelValues("actual").Set(float64(replicas))
+}
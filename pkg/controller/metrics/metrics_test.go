@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+var _ = Describe("Operator metrics", func() {
+	BeforeEach(func() {
+		TigeraStatusCondition.Reset()
+		ObjectsTotal.Reset()
+	})
+
+	It("keeps a single series per TigeraStatus condition", func() {
+		SetTigeraStatusConditions("calico", []operatorv1.TigeraStatusCondition{
+			{Type: operatorv1.ComponentAvailable, Status: operatorv1.ConditionFalse, Reason: string(operatorv1.ResourceNotReady)},
+			{Type: operatorv1.ComponentDegraded, Status: operatorv1.ConditionTrue, Reason: string(operatorv1.ResourceReadError)},
+		})
+		Expect(testutil.CollectAndCount(TigeraStatusCondition)).To(Equal(2))
+		Expect(testutil.ToFloat64(TigeraStatusCondition.WithLabelValues("calico", "Degraded", "ResourceReadError"))).To(Equal(1.0))
+
+		SetTigeraStatusConditions("calico", []operatorv1.TigeraStatusCondition{
+			{Type: operatorv1.ComponentAvailable, Status: operatorv1.ConditionTrue, Reason: string(operatorv1.AllObjectsAvailable)},
+			{Type: operatorv1.ComponentDegraded, Status: operatorv1.ConditionFalse, Reason: string(operatorv1.AllObjectsAvailable)},
+		})
+		Expect(testutil.CollectAndCount(TigeraStatusCondition)).To(Equal(2))
+		Expect(testutil.ToFloat64(TigeraStatusCondition.WithLabelValues("calico", "Available", "AllObjectsAvailable"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(TigeraStatusCondition.WithLabelValues("calico", "Degraded", "AllObjectsAvailable"))).To(Equal(0.0))
+
+		DeleteTigeraStatusConditions("calico")
+		Expect(testutil.CollectAndCount(TigeraStatusCondition)).To(Equal(0))
+	})
+
+	It("counts objects by kind and action", func() {
+		RecordObject("Deployment", ActionCreate)
+		RecordObject("Deployment", ActionUpdate)
+		RecordObject("Deployment", ActionUpdate)
+		Expect(testutil.ToFloat64(ObjectsTotal.WithLabelValues("Deployment", ActionCreate))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(ObjectsTotal.WithLabelValues("Deployment", ActionUpdate))).To(Equal(2.0))
+	})
+
+	It("records certificate expiry and typha replicas", func() {
+		expiry := time.Unix(1700000000, 0)
+		SetCertificateExpiry("calico-system", "typha-certs", expiry)
+		Expect(testutil.ToFloat64(CertificateExpiry.WithLabelValues("calico-system", "typha-certs"))).To(Equal(1700000000.0))
+
+		SetTyphaDesiredReplicas(3)
+		SetTyphaActualReplicas(2)
+		Expect(testutil.ToFloat64(TyphaReplicas.WithLabelValues("desired"))).To(Equal(3.0))
+		Expect(testutil.ToFloat64(TyphaReplicas.WithLabelValues("actual"))).To(Equal(2.0))
+	})
+})
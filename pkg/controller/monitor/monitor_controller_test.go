@@ -157,6 +157,24 @@ var _ = Describe("Monitor controller tests", func() {
 			Expect(cli.Get(ctx, client.ObjectKey{Name: monitor.FluentdMetrics, Namespace: common.TigeraPrometheusNamespace}, sm)).NotTo(HaveOccurred())
 		})
 
+		It("should not create or update anything while paused", func() {
+			monitorCR.Annotations = map[string]string{common.PausedAnnotation: "true"}
+			Expect(cli.Update(ctx, monitorCR)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: monitor.CalicoNodePrometheus, Namespace: common.TigeraPrometheusNamespace}, p)).To(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "SetMetaData", mock.Anything)
+			mockStatus.AssertNotCalled(GinkgoT(), "ReadyToMonitor")
+
+			By("resuming reconciliation")
+			monitorCR.Annotations = nil
+			Expect(cli.Update(ctx, monitorCR)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: monitor.CalicoNodePrometheus, Namespace: common.TigeraPrometheusNamespace}, p)).NotTo(HaveOccurred())
+		})
+
 		It("should render allow-tigera policy when tier and policy watch are ready", func() {
 			_, err := r.Reconcile(ctx, reconcile.Request{})
 			Expect(err).ShouldNot(HaveOccurred())
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operatorconfig
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerLevel is the level of the loggers matching a LoggerLogLevel.
+type loggerLevel struct {
+	name   string
+	prefix bool
+	level  zapcore.Level
+}
+
+func (l loggerLevel) matches(name string) bool {
+	if l.prefix {
+		return strings.HasPrefix(name, l.name)
+	}
+	return name == l.name || strings.HasPrefix(name, l.name+".")
+}
+
+var (
+	loggerLevelsLock sync.RWMutex
+	loggerLevels     []loggerLevel
+)
+
+func setLoggerLevels(levels []loggerLevel) {
+	loggerLevelsLock.Lock()
+	defer loggerLevelsLock.Unlock()
+	loggerLevels = levels
+}
+
+// levelFor returns the level of the named logger. The most specific LoggerLogLevel that matches the name is used,
+// falling back to the operator's log level.
+func levelFor(name string) zapcore.Level {
+	loggerLevelsLock.RLock()
+	defer loggerLevelsLock.RUnlock()
+
+	level, best := Level.Level(), -1
+	for _, l := range loggerLevels {
+		if l.matches(name) && len(l.name) > best {
+			level, best = l.level, len(l.name)
+		}
+	}
+	return level
+}
+
+// NewLogger wraps the given logger so that its messages are filtered by the level configured for each logger name.
+// The wrapped logger must be created with a level that enables all of its messages.
+func NewLogger(l logr.Logger) logr.Logger {
+	sink := l.GetSink()
+	if cd, ok := sink.(logr.CallDepthLogSink); ok {
+		// Skip the levelSink's frame when reporting the caller.
+		sink = cd.WithCallDepth(1)
+	}
+	return logr.New(&levelSink{sink: sink})
+}
+
+// levelSink is a logr.LogSink that tracks the name of its logger so that it can be given its own level.
+type levelSink struct {
+	sink logr.LogSink
+	name string
+}
+
+var _ logr.LogSink = &levelSink{}
+
+// Init does nothing, as the wrapped sink has already been initialized by its own logger.
+func (s *levelSink) Init(logr.RuntimeInfo) {}
+
+func (s *levelSink) Enabled(level int) bool {
+	return zapcore.Level(-level) >= levelFor(s.name) && s.sink.Enabled(level)
+}
+
+func (s *levelSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+// Error messages are always logged.
+func (s *levelSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *levelSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &levelSink{sink: s.sink.WithValues(keysAndValues...), name: s.name}
+}
+
+func (s *levelSink) WithName(name string) logr.LogSink {
+	full := name
+	if s.name != "" {
+		full = s.name + "." + name
+	}
+	return &levelSink{sink: s.sink.WithName(name), name: full}
+}
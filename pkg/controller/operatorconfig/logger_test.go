@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operatorconfig
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+var _ = Describe("Logger levels", func() {
+	var buf *bytes.Buffer
+	var root logr.Logger
+
+	debug := operatorv1.LogLevelDebug
+	errorLevel := operatorv1.LogLevelError
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		root = NewLogger(zap.New(zap.WriteTo(buf), zap.Level(zapcore.Level(math.MinInt8))))
+		InitLogLevel(zapcore.InfoLevel)
+		setLoggerLevels(nil)
+	})
+
+	AfterEach(func() {
+		InitLogLevel(zapcore.InfoLevel)
+		setLoggerLevels(nil)
+	})
+
+	logged := func(l logr.Logger, v int) bool {
+		buf.Reset()
+		l.V(v).Info(fmt.Sprintf("message at V(%d)", v))
+		return buf.Len() > 0
+	}
+
+	It("should use the operator's log level for loggers without a level", func() {
+		installation := root.WithName("controller_installation")
+		Expect(logged(installation, 0)).To(BeTrue())
+		Expect(logged(installation, 1)).To(BeFalse())
+
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{LogLevel: &debug}})
+		Expect(logged(installation, 2)).To(BeTrue())
+		Expect(logged(installation, 3)).To(BeFalse())
+	})
+
+	It("should apply a logger's level to it and the loggers created from it", func() {
+		installation := root.WithName("controller_installation")
+		child := installation.WithValues("Request.Name", "default").WithName("windows")
+		logstorage := root.WithName("controller_logstorage")
+
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+			LogLevel: &errorLevel,
+			LoggerLogLevels: []operatorv1.LoggerLogLevel{
+				{Logger: "controller_installation", LogLevel: operatorv1.LogLevelDebug},
+			},
+		}})
+		Expect(logged(installation, 2)).To(BeTrue())
+		Expect(logged(child, 2)).To(BeTrue())
+		Expect(buf.String()).To(ContainSubstring("controller_installation.windows"))
+		Expect(logged(logstorage, 0)).To(BeFalse())
+
+		buf.Reset()
+		logstorage.Error(fmt.Errorf("boom"), "errors are always logged")
+		Expect(buf.Len()).To(BeNumerically(">", 0))
+	})
+
+	It("should use the most specific matching level", func() {
+		elastic := root.WithName("controller_logstorage_elastic")
+		users := root.WithName("controller_logstorage_users")
+
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+			LoggerLogLevels: []operatorv1.LoggerLogLevel{
+				{Logger: "controller_logstorage*", LogLevel: operatorv1.LogLevelTrace},
+				{Logger: "controller_logstorage_users", LogLevel: operatorv1.LogLevelError},
+			},
+		}})
+		Expect(logged(elastic, 5)).To(BeTrue())
+		Expect(logged(users, 0)).To(BeFalse())
+
+		// Removing the levels restores the operator's level.
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{})
+		Expect(logged(elastic, 1)).To(BeFalse())
+		Expect(logged(users, 0)).To(BeTrue())
+	})
+
+	It("should apply levels to loggers created before the logger was set", func() {
+		delegating := logf.NewDelegatingLogSink(logf.NullLogSink{})
+		tiers := logr.New(delegating).WithName("controller_tiers")
+		delegating.Fulfill(root.GetSink())
+
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+			LoggerLogLevels: []operatorv1.LoggerLogLevel{{Logger: "controller_tiers", LogLevel: operatorv1.LogLevelError}},
+		}})
+		Expect(logged(tiers, 0)).To(BeFalse())
+		Expect(logged(root.WithName("controller_installation"), 0)).To(BeTrue())
+	})
+})
@@ -0,0 +1,328 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+var log = logf.Log.WithName("controller_operatorconfig")
+
+// Level is the level of the operator's loggers that don't have a level of their own. The logger returned by NewLogger
+// reads it on every message, so that changes to the OperatorConfiguration's log level take effect without a restart.
+var Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// flagLevel is the level from the --zap-log-level flag, used when the OperatorConfiguration doesn't set one.
+var flagLevel = zapcore.InfoLevel
+
+// traceLevel enables all of the operator's verbose logs. The most verbose messages are logged at V(5).
+const traceLevel = zapcore.Level(-5)
+
+const (
+	conditionReady        = "Ready"
+	conditionDegraded     = "Degraded"
+	reasonApplied         = "Applied"
+	reasonInvalid         = "InvalidConfiguration"
+	reasonRestartRequired = "RestartRequired"
+)
+
+// InitLogLevel records the log level from the operator's flags and makes it the current level.
+func InitLogLevel(l zapcore.Level) {
+	flagLevel = l
+	Level.SetLevel(l)
+}
+
+// Get returns the OperatorConfiguration, or nil if there isn't one or its CRD is not installed.
+func Get(ctx context.Context, cli client.Client) (*operatorv1.OperatorConfiguration, error) {
+	cfg := &operatorv1.OperatorConfiguration{}
+	if err := cli.Get(ctx, utils.DefaultInstanceKey, cfg); err != nil {
+		if errors.IsNotFound(err) || runtime.IsNotRegisteredError(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate returns an error if the OperatorConfiguration cannot be applied.
+func Validate(cfg *operatorv1.OperatorConfiguration) error {
+	if cfg == nil {
+		return nil
+	}
+	spec := cfg.Spec
+	if spec.LogLevel != nil {
+		if _, err := zapLevel(*spec.LogLevel); err != nil {
+			return err
+		}
+	}
+	loggers := map[string]bool{}
+	for _, l := range spec.LoggerLogLevels {
+		if l.Logger == "" {
+			return fmt.Errorf("loggerLogLevels contains an entry without a logger name")
+		}
+		if loggers[l.Logger] {
+			return fmt.Errorf("loggerLogLevels contains %q more than once", l.Logger)
+		}
+		loggers[l.Logger] = true
+		if _, err := zapLevel(l.LogLevel); err != nil {
+			return fmt.Errorf("loggerLogLevels entry for %q is invalid: %w", l.Logger, err)
+		}
+	}
+	if spec.MetricsPort != nil && (*spec.MetricsPort < 1 || *spec.MetricsPort > 65535) {
+		return fmt.Errorf("metricsPort %d is not a valid port", *spec.MetricsPort)
+	}
+	if spec.MaxConcurrentReconciles != nil && *spec.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("maxConcurrentReconciles must be at least 1")
+	}
+	seen := map[operatorv1.OperatorControllerName]bool{}
+	for _, name := range spec.EnabledControllers {
+		if !knownControllers[name] {
+			return fmt.Errorf("enabledControllers contains unknown controller %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("enabledControllers contains %q more than once", name)
+		}
+		seen[name] = true
+	}
+	ssa := map[operatorv1.ServerSideApplyComponentName]bool{}
+	for _, name := range spec.ServerSideApplyComponents {
+		if name != operatorv1.ServerSideApplyComponentCSI {
+			return fmt.Errorf("serverSideApplyComponents contains unknown component %q", name)
+		}
+		if ssa[name] {
+			return fmt.Errorf("serverSideApplyComponents contains %q more than once", name)
+		}
+		ssa[name] = true
+	}
+	return nil
+}
+
+var knownControllers = map[operatorv1.OperatorControllerName]bool{
+	operatorv1.OperatorControllerInstallation:                true,
+	operatorv1.OperatorControllerAPIServer:                   true,
+	operatorv1.OperatorControllerLogStorage:                  true,
+	operatorv1.OperatorControllerIntrusionDetection:          true,
+	operatorv1.OperatorControllerLogCollector:                true,
+	operatorv1.OperatorControllerCompliance:                  true,
+	operatorv1.OperatorControllerApplicationLayer:            true,
+	operatorv1.OperatorControllerMonitor:                     true,
+	operatorv1.OperatorControllerManager:                     true,
+	operatorv1.OperatorControllerManagementClusterConnection: true,
+	operatorv1.OperatorControllerAmazonCloudIntegration:      true,
+	operatorv1.OperatorControllerAuthentication:              true,
+	operatorv1.OperatorControllerTiers:                       true,
+	operatorv1.OperatorControllerPolicyRecommendation:        true,
+	operatorv1.OperatorControllerEgressGateway:               true,
+	operatorv1.OperatorControllerSecrets:                     true,
+	operatorv1.OperatorControllerWindows:                     true,
+	operatorv1.OperatorControllerCertificateSigningRequest:   true,
+}
+
+func zapLevel(l operatorv1.LogLevel) (zapcore.Level, error) {
+	switch l {
+	case operatorv1.LogLevelError:
+		return zapcore.ErrorLevel, nil
+	case operatorv1.LogLevelInfo:
+		return zapcore.InfoLevel, nil
+	case operatorv1.LogLevelDebug:
+		// Debug includes the V(1) and V(2) messages.
+		return zapcore.Level(-2), nil
+	case operatorv1.LogLevelTrace:
+		return traceLevel, nil
+	}
+	return 0, fmt.Errorf("logLevel %q is not supported", l)
+}
+
+// ApplyLogLevel sets the operator's log levels from the OperatorConfiguration, falling back to the level from the
+// operator's flags.
+func ApplyLogLevel(cfg *operatorv1.OperatorConfiguration) {
+	l := flagLevel
+	var levels []loggerLevel
+	if cfg != nil {
+		if cfg.Spec.LogLevel != nil {
+			if lvl, err := zapLevel(*cfg.Spec.LogLevel); err == nil {
+				l = lvl
+			}
+		}
+		for _, ll := range cfg.Spec.LoggerLogLevels {
+			lvl, err := zapLevel(ll.LogLevel)
+			if err != nil {
+				continue
+			}
+			name := strings.TrimSuffix(ll.Logger, "*")
+			levels = append(levels, loggerLevel{name: name, prefix: name != ll.Logger, level: lvl})
+		}
+	}
+	if Level.Level() != l {
+		log.Info("Setting log level", "level", l.String())
+		Level.SetLevel(l)
+	}
+	setLoggerLevels(levels)
+}
+
+// restartRequired returns true if the operator must restart to apply the change from the initial configuration.
+// Only the log levels can change while the operator is running.
+func restartRequired(initial, current *operatorv1.OperatorConfiguration) bool {
+	specOf := func(cfg *operatorv1.OperatorConfiguration) operatorv1.OperatorConfigurationSpec {
+		if cfg == nil {
+			return operatorv1.OperatorConfigurationSpec{}
+		}
+		spec := *cfg.Spec.DeepCopy()
+		spec.LogLevel = nil
+		spec.LoggerLogLevels = nil
+		if len(spec.EnabledControllers) == 0 {
+			spec.EnabledControllers = nil
+		}
+		if len(spec.ServerSideApplyComponents) == 0 {
+			spec.ServerSideApplyComponents = nil
+		}
+		return spec
+	}
+	return !reflect.DeepEqual(specOf(initial), specOf(current))
+}
+
+// Add creates a new OperatorConfiguration controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	r := &ReconcileOperatorConfiguration{
+		client:  mgr.GetClient(),
+		initial: opts.OperatorConfiguration,
+		restart: opts.Restart,
+	}
+
+	c, err := controller.New("operator-configuration-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// The CRD is only installed when the operator manages CRDs, so don't watch for a kind that doesn't exist.
+	gk := operatorv1.GroupVersion.WithKind("OperatorConfiguration").GroupKind()
+	if _, err := mgr.GetRESTMapper().RESTMapping(gk, operatorv1.GroupVersion.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.V(1).Info("OperatorConfiguration CRD is not installed, not watching for changes")
+			return nil
+		}
+		return fmt.Errorf("operator-configuration-controller failed to look up OperatorConfiguration resource: %w", err)
+	}
+
+	if err = c.Watch(&source.Kind{Type: &operatorv1.OperatorConfiguration{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("operator-configuration-controller failed to watch OperatorConfiguration resource: %w", err)
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileOperatorConfiguration{}
+
+// ReconcileOperatorConfiguration applies changes to the OperatorConfiguration.
+type ReconcileOperatorConfiguration struct {
+	client client.Client
+
+	// initial is the configuration the operator started with, or nil if it started with the defaults.
+	initial *operatorv1.OperatorConfiguration
+
+	// restart stops the operator so that it starts again with the new configuration.
+	restart func()
+}
+
+func (r *ReconcileOperatorConfiguration) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name)
+	if request.Name != utils.DefaultInstanceKey.Name {
+		reqLogger.V(1).Info("Ignoring OperatorConfiguration that is not named default")
+		return reconcile.Result{}, nil
+	}
+	reqLogger.V(2).Info("Reconciling OperatorConfiguration")
+
+	cfg, err := Get(ctx, r.client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to query OperatorConfiguration")
+		return reconcile.Result{}, err
+	}
+
+	// An invalid configuration is handled as it is when the operator starts: the operator runs with the defaults
+	// and reports that it is degraded.
+	effective := cfg
+	invalidErr := Validate(cfg)
+	if invalidErr != nil {
+		reqLogger.Error(invalidErr, "Invalid OperatorConfiguration, using the default configuration")
+		effective = nil
+	}
+
+	ApplyLogLevel(effective)
+
+	if restartRequired(r.initial, effective) {
+		reqLogger.Info("OperatorConfiguration changed, restarting the operator to apply it")
+		if err := r.setStatus(ctx, cfg, invalidErr, reasonRestartRequired, "The operator is restarting to apply the configuration"); err != nil {
+			reqLogger.Error(err, "Failed to update OperatorConfiguration status")
+		}
+		r.restart()
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.setStatus(ctx, cfg, invalidErr, reasonApplied, "The configuration has been applied")
+}
+
+// setStatus sets the Ready and Degraded conditions on the OperatorConfiguration, writing the status only if it
+// changed. The OperatorConfiguration is degraded if invalidErr is set, and ready if it is not and reason is
+// reasonApplied.
+func (r *ReconcileOperatorConfiguration) setStatus(ctx context.Context, cfg *operatorv1.OperatorConfiguration, invalidErr error, reason, message string) error {
+	if cfg == nil {
+		return nil
+	}
+	ready := metav1.Condition{Type: conditionReady, Status: metav1.ConditionFalse, Reason: reason, Message: message}
+	degraded := metav1.Condition{Type: conditionDegraded, Status: metav1.ConditionFalse, Reason: reason, Message: message}
+	if invalidErr != nil {
+		message = fmt.Sprintf("Invalid configuration, using the defaults: %s", invalidErr)
+		ready.Reason, ready.Message = reasonInvalid, message
+		degraded.Status, degraded.Reason, degraded.Message = metav1.ConditionTrue, reasonInvalid, message
+	} else if reason == reasonApplied {
+		ready.Status = metav1.ConditionTrue
+	}
+
+	changed := false
+	for _, cond := range []metav1.Condition{ready, degraded} {
+		cond.ObservedGeneration = cfg.Generation
+		if c := meta.FindStatusCondition(cfg.Status.Conditions, cond.Type); c != nil &&
+			c.Status == cond.Status && c.Reason == cond.Reason && c.Message == cond.Message && c.ObservedGeneration == cond.ObservedGeneration {
+			continue
+		}
+		meta.SetStatusCondition(&cfg.Status.Conditions, cond)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return r.client.Status().Update(ctx, cfg)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operatorconfig
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+var _ = Describe("OperatorConfiguration controller", func() {
+	var cli client.Client
+	var ctx context.Context
+	var r *ReconcileOperatorConfiguration
+	var restarted bool
+
+	request := reconcile.Request{NamespacedName: client.ObjectKey{Name: "default"}}
+
+	readyCondition := func() *metav1.Condition {
+		cfg := &operatorv1.OperatorConfiguration{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, cfg)).To(Succeed())
+		return meta.FindStatusCondition(cfg.Status.Conditions, conditionReady)
+	}
+
+	degradedCondition := func() *metav1.Condition {
+		cfg := &operatorv1.OperatorConfiguration{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, cfg)).To(Succeed())
+		return meta.FindStatusCondition(cfg.Status.Conditions, conditionDegraded)
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+		restarted = false
+		r = &ReconcileOperatorConfiguration{client: cli, restart: func() { restarted = true }}
+		InitLogLevel(zapcore.InfoLevel)
+		setLoggerLevels(nil)
+	})
+
+	It("should apply a change to the log levels without restarting", func() {
+		cfg := &operatorv1.OperatorConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.OperatorConfigurationSpec{MaxConcurrentReconciles: ptr.Int32ToPtr(2)},
+		}
+		r.initial = cfg.DeepCopy()
+		debug := operatorv1.LogLevelDebug
+		cfg.Spec.LogLevel = &debug
+		cfg.Spec.LoggerLogLevels = []operatorv1.LoggerLogLevel{{Logger: "controller_logstorage*", LogLevel: operatorv1.LogLevelError}}
+		Expect(cli.Create(ctx, cfg)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(BeFalse())
+		Expect(Level.Enabled(zapcore.Level(-2))).To(BeTrue())
+		Expect(levelFor("controller_logstorage_elastic")).To(Equal(zapcore.ErrorLevel))
+		Expect(readyCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should restore the flag's log level when the log level is removed", func() {
+		trace := operatorv1.LogLevelTrace
+		ApplyLogLevel(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{LogLevel: &trace}})
+		Expect(Level.Enabled(zapcore.Level(-5))).To(BeTrue())
+
+		Expect(cli.Create(ctx, &operatorv1.OperatorConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Level.Level()).To(Equal(zapcore.InfoLevel))
+	})
+
+	It("should restart when any other setting changes", func() {
+		Expect(cli.Create(ctx, &operatorv1.OperatorConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.OperatorConfigurationSpec{EnabledControllers: []operatorv1.OperatorControllerName{operatorv1.OperatorControllerInstallation}},
+		})).To(Succeed())
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(BeTrue())
+		Expect(readyCondition().Reason).To(Equal(reasonRestartRequired))
+	})
+
+	It("should fall back to the defaults and report Degraded when the configuration is invalid", func() {
+		debug := operatorv1.LogLevelDebug
+		r.initial = &operatorv1.OperatorConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.OperatorConfigurationSpec{LogLevel: &debug, MaxConcurrentReconciles: ptr.Int32ToPtr(2)},
+		}
+		ApplyLogLevel(r.initial)
+		Expect(cli.Create(ctx, &operatorv1.OperatorConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: operatorv1.OperatorConfigurationSpec{
+				LogLevel:                &debug,
+				MaxConcurrentReconciles: ptr.Int32ToPtr(2),
+				EnabledControllers: []operatorv1.OperatorControllerName{
+					operatorv1.OperatorControllerInstallation, operatorv1.OperatorControllerInstallation,
+				},
+			},
+		})).To(Succeed())
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(BeTrue())
+		Expect(Level.Level()).To(Equal(zapcore.InfoLevel))
+		c := readyCondition()
+		Expect(c.Status).To(Equal(metav1.ConditionFalse))
+		Expect(c.Reason).To(Equal(reasonInvalid))
+		Expect(degradedCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should report Degraded without restarting when the operator started with the defaults", func() {
+		Expect(cli.Create(ctx, &operatorv1.OperatorConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.OperatorConfigurationSpec{MaxConcurrentReconciles: ptr.Int32ToPtr(0)},
+		})).To(Succeed())
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(BeFalse())
+		c := degradedCondition()
+		Expect(c.Status).To(Equal(metav1.ConditionTrue))
+		Expect(c.Reason).To(Equal(reasonInvalid))
+		Expect(c.Message).To(ContainSubstring("maxConcurrentReconciles must be at least 1"))
+
+		// Fixing the configuration clears the Degraded condition.
+		cfg := &operatorv1.OperatorConfiguration{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, cfg)).To(Succeed())
+		cfg.Spec.MaxConcurrentReconciles = nil
+		Expect(cli.Update(ctx, cfg)).To(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(degradedCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(readyCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should do nothing when there is no OperatorConfiguration", func() {
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(BeFalse())
+	})
+
+	Context("Validate", func() {
+		It("should reject unknown controllers and log levels", func() {
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+				EnabledControllers: []operatorv1.OperatorControllerName{"Bogus"},
+			}})).To(HaveOccurred())
+			warn := operatorv1.LogLevelWarn
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{LogLevel: &warn}})).To(HaveOccurred())
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{MaxConcurrentReconciles: ptr.Int32ToPtr(0)}})).To(HaveOccurred())
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{LoggerLogLevels: []operatorv1.LoggerLogLevel{
+				{Logger: "controller_installation", LogLevel: operatorv1.LogLevelDebug},
+				{Logger: "controller_installation", LogLevel: operatorv1.LogLevelTrace},
+			}}})).To(HaveOccurred())
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+				ServerSideApplyComponents: []operatorv1.ServerSideApplyComponentName{"Node"},
+			}})).To(HaveOccurred())
+			Expect(Validate(&operatorv1.OperatorConfiguration{Spec: operatorv1.OperatorConfigurationSpec{
+				ServerSideApplyComponents: []operatorv1.ServerSideApplyComponentName{operatorv1.ServerSideApplyComponentCSI},
+			}})).To(Succeed())
+			Expect(Validate(nil)).To(Succeed())
+		})
+	})
+})
@@ -46,4 +46,55 @@ type AddOptions struct {
 
 	// Whether or not the cluster supports PodSecurityPolicies.
 	UsePSP bool
+
+	// The controllers to run, from the OperatorConfiguration. All of the controllers run when this is empty.
+	EnabledControllers []v1.OperatorControllerName
+
+	// The components whose objects are written with server-side apply, from the OperatorConfiguration.
+	ServerSideApplyComponents []v1.ServerSideApplyComponentName
+
+	// The number of reconciles the controllers that reconcile each tenant on its own may run at once. See
+	// MaxConcurrentTenantReconciles.
+	MaxConcurrentReconciles int
+
+	// The OperatorConfiguration the operator was started with, or nil if there was none or it was invalid. The
+	// operator restarts when the settings that can't be applied at runtime change.
+	OperatorConfiguration *v1.OperatorConfiguration
+
+	// Restart stops the manager, so that the operator exits and is started again with its new configuration.
+	Restart func()
+}
+
+// ControllerEnabled returns true if the named controller should run.
+func (o AddOptions) ControllerEnabled(name v1.OperatorControllerName) bool {
+	if len(o.EnabledControllers) == 0 {
+		return true
+	}
+	for _, n := range o.EnabledControllers {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerSideApply returns true if the named component's objects should be written with server-side apply.
+func (o AddOptions) ServerSideApply(name v1.ServerSideApplyComponentName) bool {
+	for _, n := range o.ServerSideApplyComponents {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxConcurrentTenantReconciles returns the number of reconciles a controller that reconciles each tenant's namespace on
+// its own may run at once. Only the controllers whose reconciles for different tenants share no state may use it, and
+// only in multi-tenant mode. The controllers that report through a status manager share it between tenants, and so
+// run a single reconcile at a time, as does every other controller and every controller in single-tenant mode.
+func (o AddOptions) MaxConcurrentTenantReconciles() int {
+	if !o.MultiTenant || o.MaxConcurrentReconciles < 1 {
+		return 1
+	}
+	return o.MaxConcurrentReconciles
 }
@@ -102,7 +102,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("policy-recommendation-controller failed to watch Installation resource: %w", err)
 	}
 
-	if err = imageset.AddImageSetWatch(policyRecController); err != nil {
+	if err = imageset.AddImageSetWatch(policyRecController, mgr.GetClient()); err != nil {
 		return fmt.Errorf("policy-recommendation-controller failed to watch ImageSet: %w", err)
 	}
 
@@ -154,7 +154,7 @@ func newReconciler(
 		client:                   mgr.GetClient(),
 		scheme:                   mgr.GetScheme(),
 		provider:                 opts.DetectedProvider,
-		status:                   status.New(mgr.GetClient(), "policy-recommendation", opts.KubernetesVersion),
+		status:                   status.NewForManager(mgr, "policy-recommendation", opts.KubernetesVersion),
 		clusterDomain:            opts.ClusterDomain,
 		licenseAPIReady:          licenseAPIReady,
 		tierWatchReady:           tierWatchReady,
@@ -246,7 +246,12 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 	logc.V(2).Info("Loaded config", "config", policyRecommendation)
 
 	// SetMetaData in the TigeraStatus such as observedGenerations
-	defer r.status.SetMetaData(&policyRecommendation.ObjectMeta)
+	defer r.status.SetMetaData(policyRecommendation)
+
+	if utils.IsPaused(policyRecommendation) {
+		logc.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
 
 	if !utils.IsAPIServerReady(r.client, logc) {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for Tigera API server to be ready", nil, logc)
@@ -331,6 +336,7 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, policyRecommendation)
+	handler.SetController(PolicyRecommendationControllerName)
 
 	// Determine the namespaces to which we must bind the cluster role.
 	// For multi-tenant, the cluster role will be bind to the service account in the tenant namespace
@@ -359,7 +365,7 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 	component := render.PolicyRecommendation(policyRecommendationCfg)
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
-		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error with images from ImageSet", err, logc)
+		r.status.SetDegraded(operatorv1.ImageSetError, "Error with images from ImageSet", err, logc)
 		return reconcile.Result{}, err
 	}
 
@@ -369,6 +375,7 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 
 	if !isManagedCluster {
 		opts := []certificatemanager.Option{
+			certificatemanager.WithContext(ctx),
 			certificatemanager.WithLogger(logc),
 			certificatemanager.WithTenant(tenant),
 		}
@@ -17,6 +17,8 @@ package secrets
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
 	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	rcertificatemanagement "github.com/tigera/operator/pkg/render/certificatemanagement"
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
@@ -39,11 +42,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const clusterCAControllerName = "cluster-ca-controller"
+
 type ClusterCAController struct {
 	client        client.Client
 	scheme        *runtime.Scheme
 	clusterDomain string
 	log           logr.Logger
+	status        status.StatusManager
 }
 
 func AddClusterCAController(mgr manager.Manager, opts options.AddOptions) error {
@@ -52,10 +58,12 @@ func AddClusterCAController(mgr manager.Manager, opts options.AddOptions) error
 		scheme:        mgr.GetScheme(),
 		clusterDomain: opts.ClusterDomain,
 		log:           logf.Log.WithName("controller_cluster_ca"),
+		status:        status.NewForManager(mgr, "certificates", opts.KubernetesVersion),
 	}
+	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("cluster-ca-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(clusterCAControllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -85,20 +93,31 @@ func (r *ClusterCAController) Reconcile(ctx context.Context, request reconcile.R
 	instance := &operatorv1.Installation{}
 	if err := r.client.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
 		if errors.IsNotFound(err) {
+			r.status.OnCRNotFound()
 			return reconcile.Result{}, nil
 		}
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying installation", err, logc)
 		return reconcile.Result{}, err
 	}
+	r.status.OnCRFound()
+	defer r.status.SetMetaData(instance)
+
+	if utils.IsPaused(instance) {
+		logc.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
 
 	// Create the cluster CA. This is done implicitly by initializing a certificate manager instance
 	// and passing the "AllowCACreation" option. The cluster CA is used in single-tenant mode to sign all other certificates.
 	// In multi-tenant mode, this certificate is used to sign certificates for components that do not belong to any one tenant.
 	opts := []certificatemanager.Option{
+		certificatemanager.WithContext(ctx),
 		certificatemanager.AllowCACreation(),
 		certificatemanager.WithLogger(logc),
 	}
 	cm, err := certificatemanager.Create(r.client, &instance.Spec, r.clusterDomain, common.OperatorNamespace(), opts...)
 	if err != nil {
+		r.status.SetDegraded(operatorv1.CertificateError, "Unable to create the Tigera CA", err, logc)
 		return reconcile.Result{}, err
 	}
 
@@ -109,9 +128,37 @@ func (r *ClusterCAController) Reconcile(ctx context.Context, request reconcile.R
 	})
 
 	hdler := utils.NewComponentHandler(logc, r.client, r.scheme, instance)
-	if err = hdler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
+	hdler.SetController(clusterCAControllerName)
+	if err = hdler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
+		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, logc)
+		return reconcile.Result{}, err
+	}
+	r.status.ReadyToMonitor()
+
+	// Once the external issuer has been removed, delete the Certificates that requested key pairs from it, along with
+	// the key pairs, so that the controllers that use them are issued new ones.
+	if instance.Spec.CertificateManagement == nil || instance.Spec.CertificateManagement.Issuer == nil {
+		if err := certificatemanager.DeleteIssuedCertificates(ctx, r.client); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error deleting the Certificates requested from the removed issuer", err, logc)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Report the certificates in the operator namespace that are about to expire. Certificates issued by the operator
+	// are renewed well before they expire, so these are either provided by the user or failing to be renewed.
+	deadline := time.Now().Add(certificatemanager.ExpiryWarningDuration(&instance.Spec))
+	expiring, err := certificatemanager.ExpiringCertificates(ctx, r.client, common.OperatorNamespace(), deadline)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Error checking certificate expiry", err, logc)
 		return reconcile.Result{}, err
 	}
+	// Reconcile again in time to rotate the CA, or to stop trusting the previous CA.
+	result := reconcile.Result{RequeueAfter: certificatemanager.RequeueAfter(cm)}
+	if len(expiring) > 0 {
+		r.status.SetDegraded(operatorv1.CertificateExpiring, strings.Join(expiring, "; "), nil, logc)
+		return result, nil
+	}
+	r.status.ClearDegraded()
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
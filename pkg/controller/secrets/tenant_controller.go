@@ -17,6 +17,8 @@ package secrets
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -42,6 +44,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const tenantControllerName = "tenant-secrets-controller"
+
 // TenantControllers runs in multi-tenant mode and provisions a CA per-tenant, as well as generating
 // a trusted bundle to place in each tenant's namespace.
 type TenantController struct {
@@ -61,13 +65,13 @@ func AddTenantController(mgr manager.Manager, opts options.AddOptions) error {
 		client:        mgr.GetClient(),
 		scheme:        mgr.GetScheme(),
 		clusterDomain: opts.ClusterDomain,
-		status:        status.New(mgr.GetClient(), "secrets", opts.KubernetesVersion),
+		status:        status.NewForManager(mgr, "secrets", opts.KubernetesVersion),
 		log:           logf.Log.WithName("controller_tenant_secrets"),
 	}
 	r.status.Run(opts.ShutdownContext)
 
 	// Create a controller using the reconciler and register it with the manager to receive reconcile calls.
-	c, err := controller.New("tenant-secrets-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(tenantControllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -140,6 +144,7 @@ func (r *TenantController) Reconcile(ctx context.Context, request reconcile.Requ
 	// Create a certificate manager for this tenant. This certificate manager will load the CA for this tenant, creating it if needed,
 	// and can be used to sign any certificates needed for this tenant's components.
 	opts := []certificatemanager.Option{
+		certificatemanager.WithContext(ctx),
 		certificatemanager.AllowCACreation(),
 		certificatemanager.WithLogger(logc),
 		certificatemanager.WithTenant(tenant),
@@ -197,6 +202,7 @@ func (r *TenantController) Reconcile(ctx context.Context, request reconcile.Requ
 	})
 
 	hdler := utils.NewComponentHandler(logc, r.client, r.scheme, tenant)
+	hdler.SetController(tenantControllerName)
 	if err = hdler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, logc)
 		return reconcile.Result{}, err
@@ -206,5 +212,25 @@ func (r *TenantController) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	// Report the certificates in the tenants' namespaces that are about to expire. The status is shared by all of the
+	// tenants, so every tenant's namespace is checked rather than only this one's.
+	deadline := time.Now().Add(certificatemanager.ExpiryWarningDuration(installation))
+	var expiring []string
+	for _, t := range tenants.Items {
+		msgs, err := certificatemanager.ExpiringCertificates(ctx, r.client, t.Namespace, deadline)
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error checking certificate expiry", err, logc)
+			return reconcile.Result{}, err
+		}
+		expiring = append(expiring, msgs...)
+	}
+	// Reconcile again in time to rotate the tenant's CA, or to stop trusting its previous CA.
+	result := reconcile.Result{RequeueAfter: certificatemanager.RequeueAfter(cm)}
+	if len(expiring) > 0 {
+		r.status.SetDegraded(operatorv1.CertificateExpiring, strings.Join(expiring, "; "), nil, logc)
+		return result, nil
+	}
+	r.status.ClearDegraded()
+
+	return result, nil
 }
@@ -16,6 +16,14 @@ package secrets
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -167,4 +175,43 @@ var _ = Describe("Tenant controller", func() {
 		// A trusted bundle ConfigMap with system roots should also have been created.
 		Expect(cli.Get(ctx, types.NamespacedName{Name: certificatemanagement.TrustedCertConfigMapNamePublic, Namespace: tenantNS}, trustedBundle)).ShouldNot(HaveOccurred())
 	})
+
+	It("should report certificates that are about to expire in any tenant's namespace", func() {
+		// Create a second tenant with a certificate that expires tomorrow.
+		otherNS := "other-tenant-namespace"
+		Expect(cli.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: otherNS}})).ShouldNot(HaveOccurred())
+		other := &operatorv1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: otherNS}}
+		other.Spec.ID = "other-tenant-id"
+		Expect(cli.Create(ctx, other)).ShouldNot(HaveOccurred())
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "expiring"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(24 * time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "expiring", Namespace: otherNS},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+				corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			},
+		})).ShouldNot(HaveOccurred())
+
+		expiring := mock.MatchedBy(func(msg string) bool {
+			return strings.HasPrefix(msg, "Certificate "+otherNS+"/expiring expires at ")
+		})
+		mockStatus.On("SetDegraded", operatorv1.CertificateExpiring, expiring, mock.Anything, mock.Anything).Return()
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "default", Namespace: tenantNS}})
+		Expect(err).ShouldNot(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.CertificateExpiring, expiring, mock.Anything, mock.Anything)
+		mockStatus.AssertNotCalled(GinkgoT(), "ClearDegraded")
+
+		// The reconcile is requeued in time to rotate the tenant's CA.
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	})
 })
@@ -20,7 +20,7 @@ import (
 
 	operator "github.com/tigera/operator/api/v1"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/types"
@@ -123,6 +123,6 @@ func (m *MockStatus) WasCalled(method string, arguments ...interface{}) bool {
 	return false
 }
 
-func (m *MockStatus) SetMetaData(meta *metav1.ObjectMeta) {
-	m.Called(meta)
+func (m *MockStatus) SetMetaData(obj client.Object) {
+	m.Called(obj)
 }
@@ -16,6 +16,7 @@ package status
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -26,6 +27,7 @@ import (
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/metrics"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	certV1 "k8s.io/api/certificates/v1"
@@ -36,8 +38,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 var log = logf.Log.WithName("status_manager")
@@ -76,7 +80,7 @@ type StatusManager interface {
 	IsProgressing() bool
 	IsDegraded() bool
 	ReadyToMonitor()
-	SetMetaData(meta *metav1.ObjectMeta)
+	SetMetaData(obj client.Object)
 }
 
 type statusManager struct {
@@ -96,6 +100,9 @@ type statusManager struct {
 	explicitDegradedMsg    string
 	explicitDegradedReason operator.TigeraStatusReason
 
+	// Track what the controller is waiting on, from the waiting errors passed to SetDegraded.
+	explicitProgressingMsg string
+
 	// Keep track of currently calculated status.
 	progressing []string
 	failing     []string
@@ -111,11 +118,33 @@ type statusManager struct {
 	crExists bool
 
 	observedGeneration int64
+
+	// paused tracks whether reconciliation of the component has been paused through the PausedAnnotation on its CR,
+	// and pausedReported whether the TigeraStatus has a Paused condition that may need to be cleared.
+	paused         bool
+	pausedReported bool
+
+	// recorder, if set, is used to emit Events whenever one of the TigeraStatus conditions changes. The Events are
+	// recorded against the TigeraStatus and the owner, which is the CR last passed to SetMetaData.
+	recorder record.EventRecorder
+	owner    client.Object
+}
+
+// eventSource is the component that the Events emitted by the status managers are reported from.
+const eventSource = "tigera-operator"
+
+// NewForManager returns a StatusManager that uses the manager's client, and that emits Events through the manager's
+// event recorder whenever the Available, Progressing or Degraded conditions change.
+func NewForManager(mgr manager.Manager, component string, kubernetesVersion *common.VersionInfo) StatusManager {
+	m := New(mgr.GetClient(), component, kubernetesVersion).(*statusManager)
+	m.recorder = mgr.GetEventRecorderFor(eventSource)
+	return m
 }
 
 func New(client client.Client, component string, kubernetesVersion *common.VersionInfo) StatusManager {
 	// Best-effort initialization of CR status by checking for its existence.
 	crExists := true
+	pausedReported := false
 	ts := &operator.TigeraStatus{}
 	err := client.Get(context.TODO(), types.NamespacedName{Name: component}, ts)
 	if err != nil && errors.IsNotFound(err) {
@@ -123,9 +152,15 @@ func New(client client.Client, component string, kubernetesVersion *common.Versi
 		// exist. This may result in one unnecessary delete call if the resource in fact doesn't exist,
 		// but we can't assume the object has been deleted without hard evidence.
 		crExists = false
+	} else if err == nil {
+		for _, c := range ts.Status.Conditions {
+			if c.Type == operator.ComponentPaused && c.Status == operator.ConditionTrue {
+				pausedReported = true
+			}
+		}
 	}
 
-	return &statusManager{
+	m := &statusManager{
 		client:                    client,
 		component:                 component,
 		daemonsets:                make(map[string]types.NamespacedName),
@@ -135,7 +170,9 @@ func New(client client.Client, component string, kubernetesVersion *common.Versi
 		certificatestatusrequests: make(map[string]map[string]string),
 		kubernetesVersion:         kubernetesVersion,
 		crExists:                  crExists,
+		pausedReported:            pausedReported,
 	}
+	return m
 }
 
 func (m *statusManager) updateStatus() {
@@ -152,6 +189,12 @@ func (m *statusManager) updateStatus() {
 	}
 	// This status manager is enabled. Perform a sync.
 
+	if m.isPaused() {
+		m.setPaused()
+	} else {
+		m.clearPaused()
+	}
+
 	// Unless we've been given an explicit degraded reason we are not ready to start reporting statuses until
 	// ReadyToMonitor has been called by the owner of the status manager. This means there's no point in syncing
 	// the state.
@@ -196,9 +239,19 @@ func (m *statusManager) updateStatus() {
 		} else {
 			m.clearDegraded()
 		}
+		// The same goes for what the controller is waiting on.
+		if m.IsProgressing() {
+			m.setProgressing(operator.ResourceNotReady, m.progressingMessage())
+		}
 	}
 }
 
+func (m *statusManager) isPaused() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.paused
+}
+
 func (m *statusManager) isExplicitlyDegraded() bool {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -351,8 +404,24 @@ func (m *statusManager) RemoveCertificateSigningRequests(name string) {
 	delete(m.certificatestatusrequests, name)
 }
 
-// SetDegraded sets degraded state with the provided reason and message.
+// waitingError is implemented by errors that mean that the controller is waiting for something that it has requested,
+// such as a certificate from an external issuer, rather than failing.
+type waitingError interface {
+	error
+	Waiting() bool
+}
+
+// SetDegraded sets degraded state with the provided reason and message. An error that the controller is only waiting
+// on sets the progressing state instead, until ClearDegraded is called.
 func (m *statusManager) SetDegraded(reason operator.TigeraStatusReason, msg string, err error, log logr.Logger) {
+	var waiting waitingError
+	if goerrors.As(err, &waiting) && waiting.Waiting() {
+		log.WithValues("reason", string(reason)).Info(msg, "waiting", err.Error())
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		m.explicitProgressingMsg = fmt.Sprintf("%s: %s", msg, err.Error())
+		return
+	}
 	log.WithValues("reason", string(reason)).Error(err, msg)
 	errormsg := ""
 	if err != nil {
@@ -372,6 +441,7 @@ func (m *statusManager) ClearDegraded() {
 	m.degraded = false
 	m.explicitDegradedReason = ""
 	m.explicitDegradedMsg = ""
+	m.explicitProgressingMsg = ""
 }
 
 // IsAvailable returns true if the component is available and false otherwise.
@@ -385,7 +455,7 @@ func (m *statusManager) IsAvailable() bool {
 		return false
 	}
 
-	if m.degraded {
+	if m.degraded || m.explicitProgressingMsg != "" {
 		return false
 	}
 
@@ -398,6 +468,11 @@ func (m *statusManager) IsProgressing() bool {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	// Controllers can be waiting on something, which can be the reason why they are not yet ready to monitor.
+	if m.explicitProgressingMsg != "" {
+		return true
+	}
+
 	// If we're not ready to monitor or haven't synced then we're not ready to report a status base on the status of the
 	// known resources.
 	if !m.readyToMonitor || !m.hasSynced {
@@ -609,6 +684,7 @@ func (m *statusManager) removeTigeraStatus() {
 	}
 
 	// Status manager is explicitly disabled. Delete the TigeraStatus CR if it exists.
+	metrics.DeleteTigeraStatusConditions(m.component)
 	ts := &operator.TigeraStatus{ObjectMeta: metav1.ObjectMeta{Name: m.component}}
 	err := m.client.Delete(context.TODO(), ts)
 	if err != nil && !errors.IsNotFound(err) {
@@ -616,40 +692,59 @@ func (m *statusManager) removeTigeraStatus() {
 	} else {
 		// CR no longer exists.
 		m.crExists = false
+		m.pausedReported = false
 	}
 }
 
 // podsFailing takes a selector and returns if any of the pods that match it are failing. Failing pods are defined
 // to be in CrashLoopBackOff state.
 func (m *statusManager) podsFailing(selector *metav1.LabelSelector, namespace string) (string, error) {
-	l := corev1.PodList{}
 	s, err := metav1.LabelSelectorAsMap(selector)
 	if err != nil {
 		panic(err)
 	}
-	err = m.client.List(context.TODO(), &l, client.MatchingLabels(s), client.InNamespace(namespace))
-	if err != nil {
+	failures, err := PodFailures(context.TODO(), m.client, namespace, client.MatchingLabels(s))
+	if err != nil || len(failures) == 0 {
 		return "", err
 	}
+	return failures[0], nil
+}
+
+// PodFailures returns a message for each pod in the namespace that is failing, i.e. that has failed, or that has a
+// container that is crash looping, cannot pull its image or has terminated with an error.
+func PodFailures(ctx context.Context, cli client.Client, namespace string, opts ...client.ListOption) ([]string, error) {
+	l := corev1.PodList{}
+	err := cli.List(ctx, &l, append(opts, client.InNamespace(namespace))...)
+	if err != nil {
+		return nil, err
+	}
+	var failures []string
 	for _, p := range l.Items {
-		if p.Status.Phase == corev1.PodFailed {
-			return fmt.Sprintf("Pod %s/%s has failed", p.Namespace, p.Name), nil
+		if msg := podErrorMessage(p); msg != "" {
+			failures = append(failures, msg)
 		}
-		for _, c := range p.Status.InitContainerStatuses {
-			if msg := m.containerErrorMessage(p, c); msg != "" {
-				return msg, nil
-			}
+	}
+	return failures, nil
+}
+
+func podErrorMessage(p corev1.Pod) string {
+	if p.Status.Phase == corev1.PodFailed {
+		return fmt.Sprintf("Pod %s/%s has failed", p.Namespace, p.Name)
+	}
+	for _, c := range p.Status.InitContainerStatuses {
+		if msg := containerErrorMessage(p, c); msg != "" {
+			return msg
 		}
-		for _, c := range p.Status.ContainerStatuses {
-			if msg := m.containerErrorMessage(p, c); msg != "" {
-				return msg, nil
-			}
+	}
+	for _, c := range p.Status.ContainerStatuses {
+		if msg := containerErrorMessage(p, c); msg != "" {
+			return msg
 		}
 	}
-	return "", nil
+	return ""
 }
 
-func (m *statusManager) containerErrorMessage(p corev1.Pod, c corev1.ContainerStatus) string {
+func containerErrorMessage(p corev1.Pod, c corev1.ContainerStatus) string {
 	if c.State.Waiting != nil {
 		// Check well-known error states here and report an appropriate mesage to the end user.
 		if c.State.Waiting.Reason == "CrashLoopBackOff" {
@@ -714,6 +809,8 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		}
 	}
 
+	metrics.SetTigeraStatusConditions(m.component, ts.Status.Conditions)
+
 	// If nothing has changed, we don't need to update in the API.
 	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) {
 		return
@@ -736,6 +833,49 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		}
 	}
 	m.crExists = true
+
+	if err == nil {
+		m.recordConditionEvents(&ts, old.Status.Conditions)
+	}
+}
+
+// recordConditionEvents emits an Event for each condition of the TigeraStatus whose status differs from the
+// previous conditions. Changes to only the reason or message of a condition do not result in an Event.
+func (m *statusManager) recordConditionEvents(ts *operator.TigeraStatus, previous []operator.TigeraStatusCondition) {
+	if m.recorder == nil {
+		return
+	}
+
+	for _, c := range ts.Status.Conditions {
+		changed := true
+		for _, p := range previous {
+			if p.Type == c.Type {
+				changed = p.Status != c.Status
+				break
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		eventType := corev1.EventTypeNormal
+		if c.Type == operator.ComponentDegraded && c.Status == operator.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		reason := c.Reason
+		if reason == "" {
+			reason = string(c.Type)
+		}
+		msg := fmt.Sprintf("%s %s is now %s", m.component, c.Type, c.Status)
+		if c.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, c.Message)
+		}
+
+		m.recorder.Event(ts, eventType, reason, msg)
+		if m.owner != nil {
+			m.recorder.Event(m.owner, eventType, reason, msg)
+		}
+	}
 }
 
 func (m *statusManager) setAvailable(reason operator.TigeraStatusReason, msg string) {
@@ -798,9 +938,39 @@ func (m *statusManager) clearAvailable() {
 	m.set(true, conditions...)
 }
 
+func (m *statusManager) setPaused() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	conditions := []operator.TigeraStatusCondition{
+		{Type: operator.ComponentPaused, Status: operator.ConditionTrue, Reason: string(operator.ReconciliationPaused), Message: fmt.Sprintf("Reconciliation is paused by the %s annotation", common.PausedAnnotation)},
+	}
+	m.set(true, conditions...)
+	m.pausedReported = true
+}
+
+// clearPaused sets the Paused condition to False once a paused component is resumed. Components that have never been
+// paused have no Paused condition.
+func (m *statusManager) clearPaused() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.pausedReported {
+		return
+	}
+	conditions := []operator.TigeraStatusCondition{
+		{Type: operator.ComponentPaused, Status: operator.ConditionFalse, Reason: string(operator.Unknown), Message: ""},
+	}
+	m.set(true, conditions...)
+	m.pausedReported = false
+}
+
 func (m *statusManager) progressingMessage() string {
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	if m.explicitProgressingMsg != "" {
+		return strings.Join(append([]string{m.explicitProgressingMsg}, m.progressing...), "\n")
+	}
 	return strings.Join(m.progressing, "\n")
 }
 
@@ -854,10 +1024,15 @@ func (m *statusManager) clearProgressingWithReason(reason operator.TigeraStatusR
 	m.set(true, conditions...)
 }
 
-func (m *statusManager) SetMetaData(meta *metav1.ObjectMeta) {
+// SetMetaData records the generation of the CR that owns this status manager, so that it can be reported as the
+// observedGeneration of the TigeraStatus conditions, and the CR itself so that condition Events can be emitted for it.
+// Whether the component has been paused through the PausedAnnotation on the CR is reported as the Paused condition.
+func (m *statusManager) SetMetaData(obj client.Object) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.observedGeneration = meta.Generation
+	m.observedGeneration = obj.GetGeneration()
+	m.paused = common.IsPaused(obj)
+	m.owner = obj.DeepCopyObject().(client.Object)
 }
 
 func hasPendingCSR(ctx context.Context, m *statusManager, labelMap map[string]string) (bool, error) {
@@ -964,7 +1139,8 @@ func UpdateStatusCondition(statuscondition []metav1.Condition, conditions []oper
 		for i, c := range statuscondition {
 			if condition.Type == operator.ComponentAvailable && c.Type == string(operator.ComponentReady) ||
 				condition.Type == operator.ComponentDegraded && c.Type == string(operator.ComponentDegraded) ||
-				condition.Type == operator.ComponentProgressing && c.Type == string(operator.ComponentProgressing) {
+				condition.Type == operator.ComponentProgressing && c.Type == string(operator.ComponentProgressing) ||
+				condition.Type == operator.ComponentPaused && c.Type == string(operator.ComponentPaused) {
 				if !reflect.DeepEqual(c.Status, condition.Status) {
 					ic.LastTransitionTime = metav1.NewTime(time.Now())
 				}
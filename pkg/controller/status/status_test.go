@@ -16,6 +16,7 @@ package status
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -29,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	controllerRuntimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -133,6 +135,19 @@ var _ = Describe("Status reporting tests", func() {
 					Expect(sm.IsDegraded()).To(BeTrue())
 				})
 			})
+			When("it is waiting on something it has requested", func() {
+				It("should be progressing rather than degraded until the degraded state is cleared", func() {
+					sm.SetDegraded(operator.ResourceReadError, "Waiting for key pair", waitingErr{}, log)
+
+					Expect(sm.IsAvailable()).To(BeFalse())
+					Expect(sm.IsProgressing()).To(BeTrue())
+					Expect(sm.IsDegraded()).To(BeFalse())
+					Expect(sm.progressingMessage()).To(Equal("Waiting for key pair: waiting for the issuer"))
+
+					sm.ClearDegraded()
+					Expect(sm.IsProgressing()).To(BeFalse())
+				})
+			})
 			When("it is progressing", func() {
 				It("should not be available, progressing or degraded", func() {
 					sm.progressing = []string{"progressing message"}
@@ -458,6 +473,131 @@ var _ = Describe("Status reporting tests", func() {
 			Expect(sm.degradedMessage()).To(Equal("Controller set us degraded: \nThis pod has died"))
 		})
 
+		It("should emit events when conditions change", func() {
+			recorder := record.NewFakeRecorder(20)
+			sm.recorder = recorder
+			sm.SetMetaData(&operator.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure", Generation: 2}})
+			Expect(sm.observedGeneration).To(Equal(int64(2)))
+
+			replicas := int32(1)
+			dep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "tigera-system", Name: "foo", Generation: 1},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "foo"}},
+					Replicas: &replicas,
+				},
+				Status: appsv1.DeploymentStatus{ObservedGeneration: 1, UnavailableReplicas: 1},
+			}
+			Expect(client.Create(ctx, dep)).NotTo(HaveOccurred())
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "tigera-system", Name: "foo", Labels: map[string]string{"k8s-app": "foo"}},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "bar",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					}},
+				},
+			}
+			Expect(client.Create(ctx, pod)).NotTo(HaveOccurred())
+			sm.AddDeployments([]types.NamespacedName{{Namespace: "tigera-system", Name: "foo"}})
+			sm.ReadyToMonitor()
+			sm.updateStatus()
+
+			Expect(recorder.Events).To(HaveLen(6))
+			events := []string{}
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement("Warning PodFailure test-component Degraded is now True: Pod tigera-system/foo has crash looping container: bar"))
+			Expect(events).To(ContainElement("Normal Unknown test-component Available is now False"))
+
+			By("not emitting events when nothing flips")
+			sm.updateStatus()
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("emitting events when the component recovers")
+			Expect(client.Delete(ctx, pod)).NotTo(HaveOccurred())
+			dep.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, AvailableReplicas: 1, ReadyReplicas: 1}
+			Expect(client.Update(ctx, dep)).NotTo(HaveOccurred())
+			sm.updateStatus()
+			Expect(recorder.Events).To(HaveLen(4))
+			events = []string{}
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ConsistOf(
+				"Normal AllObjectsAvailable test-component Available is now True: All objects available",
+				"Normal AllObjectsAvailable test-component Available is now True: All objects available",
+				"Normal AllObjectsAvailable test-component Degraded is now False: All Objects Available",
+				"Normal AllObjectsAvailable test-component Degraded is now False: All Objects Available",
+			))
+		})
+
+		It("should report when the component is paused", func() {
+			pausedCondition := func() *operator.TigeraStatusCondition {
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				for _, c := range ts.Status.Conditions {
+					if c.Type == operator.ComponentPaused {
+						return &c
+					}
+				}
+				return nil
+			}
+			cr := &operator.Monitor{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}}
+			sm.ReadyToMonitor()
+			sm.SetMetaData(cr)
+			sm.updateStatus()
+			Expect(pausedCondition()).To(BeNil())
+
+			By("pausing the component")
+			cr.Annotations = map[string]string{common.PausedAnnotation: "true"}
+			sm.SetMetaData(cr)
+			sm.updateStatus()
+			Expect(pausedCondition().Status).To(Equal(operator.ConditionTrue))
+			Expect(pausedCondition().Reason).To(Equal(string(operator.ReconciliationPaused)))
+
+			By("not reporting a CR that is being deleted as paused")
+			deleting := cr.DeepCopy()
+			deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			sm.SetMetaData(deleting)
+			sm.updateStatus()
+			Expect(pausedCondition().Status).To(Equal(operator.ConditionFalse))
+			sm.SetMetaData(cr)
+			sm.updateStatus()
+			Expect(pausedCondition().Status).To(Equal(operator.ConditionTrue))
+
+			By("remembering the paused state across restarts")
+			sm = New(client, "test-component", &common.VersionInfo{Major: 1, Minor: 19}).(*statusManager)
+			sm.OnCRFound()
+			sm.ReadyToMonitor()
+
+			By("resuming the component")
+			cr.Annotations = nil
+			sm.SetMetaData(cr)
+			sm.updateStatus()
+			Expect(pausedCondition().Status).To(Equal(operator.ConditionFalse))
+		})
+
+		It("should keep a single Paused condition on the CR across updates", func() {
+			conditions := []operator.TigeraStatusCondition{
+				{Type: operator.ComponentAvailable, Status: operator.ConditionFalse},
+				{Type: operator.ComponentPaused, Status: operator.ConditionTrue, Reason: string(operator.ReconciliationPaused)},
+			}
+			statusConditions := UpdateStatusCondition(nil, conditions)
+			statusConditions = UpdateStatusCondition(statusConditions, conditions)
+
+			var paused []metav1.Condition
+			for _, c := range statusConditions {
+				if c.Type == string(operator.ComponentPaused) {
+					paused = append(paused, c)
+				}
+			}
+			Expect(paused).To(HaveLen(1))
+			Expect(paused[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(statusConditions).To(HaveLen(2))
+		})
+
 		It("should contain all the NamespacesNames for all the resources added by multiple calls to Set<Resources>", func() {
 			sm.AddStatefulSets([]types.NamespacedName{{Namespace: "NS1", Name: "SS1"}})
 			sm.AddStatefulSets([]types.NamespacedName{{Namespace: "NS1", Name: "SS2"}})
@@ -644,3 +784,10 @@ var _ = Describe("Status reporting tests", func() {
 		)
 	})
 })
+
+// waitingErr is an error that the controller is waiting on, like the certificate manager's ErrWaitingForIssuer.
+type waitingErr struct{}
+
+func (waitingErr) Error() string { return "waiting for the issuer" }
+
+func (waitingErr) Waiting() bool { return true }
@@ -48,6 +48,8 @@ import (
 // The Tiers controller reconciles Tiers and NetworkPolicies that are shared across components or do not directly
 // relate to any particular component.
 
+const controllerName = "tiers-controller"
+
 var log = logf.Log.WithName("controller_tiers")
 
 // Add creates a new Tiers Controller and adds it to the Manager.
@@ -60,7 +62,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 
 	reconciler := newReconciler(mgr, opts)
 
-	c, err := controller.New("tiers-controller", mgr, controller.Options{Reconciler: reconciler})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconciler})
 	if err != nil {
 		return err
 	}
@@ -93,7 +95,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Recon
 		client:      mgr.GetClient(),
 		scheme:      mgr.GetScheme(),
 		provider:    opts.DetectedProvider,
-		status:      status.New(mgr.GetClient(), "tiers", opts.KubernetesVersion),
+		status:      status.NewForManager(mgr, "tiers", opts.KubernetesVersion),
 		multiTenant: opts.MultiTenant,
 	}
 	r.status.Run(opts.ShutdownContext)
@@ -138,6 +140,17 @@ func (r *ReconcileTiers) Reconcile(ctx context.Context, request reconcile.Reques
 		return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
 	}
 
+	// The tiers are served by the API server, so pausing the APIServer pauses them as well.
+	apiServer, msg, err := utils.GetAPIServer(ctx, r.client)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.ResourceReadError, msg, err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	if utils.IsPaused(apiServer) {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
 	// Ensure a license is present that enables this controller to create/manage tiers.
 	license, err := utils.FetchLicenseKey(ctx, r.client)
 	if err != nil {
@@ -161,6 +174,7 @@ func (r *ReconcileTiers) Reconcile(ctx context.Context, request reconcile.Reques
 	component := tiers.Tiers(tiersConfig)
 
 	componentHandler := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+	componentHandler.SetController(controllerName)
 	err = componentHandler.CreateOrUpdateOrDelete(ctx, component, nil)
 	if err != nil {
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
@@ -122,6 +122,17 @@ var _ = Describe("tier controller tests", func() {
 		Expect(c.Get(ctx, client.ObjectKey{Name: "allow-tigera"}, &tier)).To(BeNil())
 	})
 
+	It("should not reconcile the tiers while the APIServer is paused", func() {
+		apiServer := &operatorv1.APIServer{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, apiServer)).NotTo(HaveOccurred())
+		apiServer.Annotations = map[string]string{common.PausedAnnotation: "true"}
+		Expect(c.Update(ctx, apiServer)).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "allow-tigera"}, &v3.Tier{})).To(HaveOccurred())
+	})
+
 	It("waits for API server to be available before reconciling", func() {
 		err := c.Delete(ctx, &operatorv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})
 		Expect(err).ShouldNot(HaveOccurred())
@@ -0,0 +1,140 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/metrics"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// FieldManager is the field manager the operator uses when it writes objects with server-side apply.
+const FieldManager = "tigera-operator"
+
+// updateFieldManager is the field manager of the objects the operator writes with updates. The operator doesn't set a
+// field manager on updates, so the API server uses the start of the client's user agent.
+var updateFieldManager = strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+
+// useServerSideApply returns true if the component has opted in to having its objects written with server-side apply.
+func useServerSideApply(component render.Component) bool {
+	ssa, ok := component.(render.ServerSideApplyComponent)
+	return ok && ssa.ServerSideApply()
+}
+
+// applyObject writes the object with server-side apply. Unlike createOrUpdateObject, there is no need to merge the
+// object with its current state: the API server only updates the fields that the operator sets, and leaves fields
+// that are owned by other field managers as they are.
+func (c componentHandler) applyObject(ctx context.Context, obj client.Object, osType rmeta.OSType) error {
+	if err := c.prepareObject(obj, osType); err != nil {
+		return err
+	}
+	multipleOwners := checkIfMultipleOwnersLabel(obj)
+	if multipleOwners {
+		labels := obj.GetLabels()
+		delete(labels, common.MultipleOwnersLabel)
+		obj.SetLabels(labels)
+	}
+
+	logCtx := ContextLoggerForResource(c.log, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	// Apply patches must include the object's type.
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	action := metrics.ActionUpdate
+	cur := obj.DeepCopyObject().(client.Object)
+	if err := c.client.Get(ctx, key, cur); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		action = metrics.ActionCreate
+	} else if IgnoreObject(cur) {
+		logCtx.Info("Ignoring annotated object")
+		return nil
+	} else if multipleOwners {
+		// Every owner applies the object with the same field manager, so the applied owner references replace the
+		// ones that were applied for the other owners unless they are carried over.
+		obj.SetOwnerReferences(common.MergeOwnerReferences(obj.GetOwnerReferences(), cur.GetOwnerReferences()))
+	}
+	if action == metrics.ActionUpdate {
+		if err := migrateManagedFields(ctx, c.client, cur); err != nil {
+			logCtx.WithValues("key", key).Error(err, "Failed to migrate the object's managed fields.")
+			return err
+		}
+	}
+
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	err = c.client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+	if errors.IsInvalid(err) && recreateOnInvalid(obj) {
+		// The object has a change to an immutable field, it can only be deleted then applied again.
+		logCtx.WithValues("key", key).Info("Object has a change to an immutable field, recreating it.", "error", err)
+		if err := c.client.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			logCtx.WithValues("key", key).Error(err, "Failed to delete object for recreation.")
+			return err
+		}
+		c.recordObject(obj, metrics.ActionDelete)
+		action = metrics.ActionCreate
+		obj.SetResourceVersion("")
+		err = c.client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		logCtx.WithValues("key", key).Error(err, "Failed to apply object.")
+		return err
+	}
+	c.recordObject(obj, action)
+	return nil
+}
+
+// migrateManagedFields moves the fields of an object that the operator wrote with updates to its apply field manager.
+// An apply only removes the fields that its field manager owns and no longer sets, so without this the fields that the
+// operator set before the object was applied, and then stops setting, would never be removed.
+func migrateManagedFields(ctx context.Context, cli client.Client, obj client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(obj, sets.New(updateFieldManager), FieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return cli.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// recreateOnInvalid returns true for the kinds that createOrUpdateObject deletes and recreates when an immutable
+// field changes.
+func recreateOnInvalid(obj client.Object) bool {
+	switch obj.(type) {
+	case *batchv1.Job, *v1.Secret, *v1.Service, *rbacv1.RoleBinding, *rbacv1.ClusterRoleBinding:
+		return true
+	}
+	return false
+}
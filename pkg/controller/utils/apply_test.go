@@ -0,0 +1,254 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+var _ = Describe("Server-side apply tests", func() {
+	var (
+		c        *ApplyClient
+		ctx      context.Context
+		instance *operatorv1.Manager
+		handler  ComponentHandler
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(apps.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(storagev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		c = &ApplyClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+		ctx = context.Background()
+
+		instance = &operatorv1.Manager{
+			TypeMeta:   metav1.TypeMeta{Kind: "Manager", APIVersion: "operator.tigera.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+		}
+		handler = NewComponentHandler(logf.Log.WithName("test_apply_logger"), c, scheme, instance)
+	})
+
+	It("applies the objects of components that opt in with the operator's field manager", func() {
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+					Data:       map[string]string{"a": "1"},
+				},
+			},
+		}}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+
+		Expect(c.Applied).To(HaveLen(1))
+		Expect(c.Applied[0].GetObjectKind().GroupVersionKind().Kind).To(Equal("ConfigMap"))
+		opts := &client.PatchOptions{}
+		opts.ApplyOptions(c.Opts[0])
+		Expect(opts.FieldManager).To(Equal(FieldManager))
+		Expect(*opts.Force).To(BeTrue())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data).To(Equal(map[string]string{"a": "1"}))
+		Expect(cm.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("applies the CSI component's objects when server-side apply is enabled for it", func() {
+		csi := render.CSI(&render.CSIConfiguration{
+			Installation:    &operatorv1.InstallationSpec{KubeletVolumePluginPath: "/var/lib/kubelet"},
+			ServerSideApply: true,
+		})
+		Expect(csi.ResolveImages(nil)).NotTo(HaveOccurred())
+		Expect(handler.CreateOrUpdateOrDelete(ctx, csi, nil)).NotTo(HaveOccurred())
+
+		Expect(c.Applied).To(HaveLen(2))
+		Expect(c.Applied[0].GetObjectKind().GroupVersionKind().Kind).To(Equal("CSIDriver"))
+		Expect(c.Applied[1].GetObjectKind().GroupVersionKind().Kind).To(Equal("DaemonSet"))
+
+		ds := &apps.DaemonSet{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.CSIDaemonSetName, Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/os", "linux"))
+	})
+
+	It("does not use server-side apply for components that do not opt in", func() {
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(c.Applied).To(BeEmpty())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})).NotTo(HaveOccurred())
+	})
+
+	It("keeps the owner references of the other owners of objects with multiple owners", func() {
+		other := metav1.OwnerReference{APIVersion: "operator.tigera.io/v1", Kind: "Manager", Name: "other", UID: "other-uid"}
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default", OwnerReferences: []metav1.OwnerReference{other}},
+		})).NotTo(HaveOccurred())
+
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "default",
+					Labels:    map[string]string{common.MultipleOwnersLabel: "true"},
+				}},
+			},
+		}}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.OwnerReferences).To(HaveLen(2))
+		Expect(cm.OwnerReferences).To(ContainElement(other))
+		Expect(cm.Labels).NotTo(HaveKey(common.MultipleOwnersLabel))
+	})
+
+	It("moves the fields the operator wrote with updates to the apply field manager", func() {
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cm",
+				Namespace: "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{
+						Manager:    updateFieldManager,
+						Operation:  metav1.ManagedFieldsOperationUpdate,
+						APIVersion: "v1",
+						FieldsType: "FieldsV1",
+						FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{},"f:b":{}}}`)},
+					},
+					{
+						Manager:    "kubectl-edit",
+						Operation:  metav1.ManagedFieldsOperationUpdate,
+						APIVersion: "v1",
+						FieldsType: "FieldsV1",
+						FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:c":{}}}`)},
+					},
+				},
+			},
+			Data: map[string]string{"a": "1", "b": "2", "c": "3"},
+		})).NotTo(HaveOccurred())
+
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}, Data: map[string]string{"a": "1"}},
+			},
+		}}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, cm)).NotTo(HaveOccurred())
+		var managers []string
+		for _, f := range cm.ManagedFields {
+			managers = append(managers, f.Manager+"/"+string(f.Operation))
+		}
+		Expect(managers).To(ConsistOf(FieldManager+"/Apply", "kubectl-edit/Update"))
+	})
+
+	It("skips objects that are marked as ignored", func() {
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cm",
+				Namespace:   "default",
+				Annotations: map[string]string{unsupportedIgnoreAnnotation: "true"},
+			},
+		})).NotTo(HaveOccurred())
+
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(c.Applied).To(BeEmpty())
+	})
+
+	It("recreates a secret when an immutable field changes", func() {
+		Expect(c.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+		})).NotTo(HaveOccurred())
+		c.Errs = []error{errors.NewInvalid(schema.GroupKind{Kind: "Secret"}, "test-secret", field.ErrorList{
+			field.Invalid(field.NewPath("type"), corev1.SecretTypeTLS, "field is immutable"),
+		})}
+
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "default"},
+					Type:       corev1.SecretTypeTLS,
+				},
+			},
+		}}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(c.Applied).To(HaveLen(2))
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-secret", Namespace: "default"}, secret)).NotTo(HaveOccurred())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+	})
+
+	It("returns invalid errors for kinds that are not recreated", func() {
+		c.Errs = []error{errors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "test-cm", field.ErrorList{})}
+
+		fc := &fakeSSAComponent{fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}}
+		err := handler.CreateOrUpdateOrDelete(ctx, fc, nil)
+		Expect(errors.IsInvalid(err)).To(BeTrue())
+	})
+})
+
+// fakeSSAComponent is a fakeComponent that opts in to server-side apply.
+type fakeSSAComponent struct {
+	fakeComponent
+}
+
+func (c *fakeSSAComponent) ServerSideApply() bool {
+	return true
+}
@@ -38,6 +38,7 @@ import (
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/metrics"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
@@ -45,6 +46,10 @@ import (
 
 type ComponentHandler interface {
 	CreateOrUpdateOrDelete(context.Context, render.Component, status.StatusManager) error
+
+	// SetController sets the name of the controller that the handler reconciles components for. While plan mode is
+	// enabled, the handler's OperatorPlans are named after it.
+	SetController(name string)
 }
 
 // cr is allowed to be nil in the case we don't want to put ownership on a resource,
@@ -55,17 +60,26 @@ func NewComponentHandler(log logr.Logger, client client.Client, scheme *runtime.
 		scheme: scheme,
 		cr:     cr,
 		log:    log,
+		plan:   newPlanState(cr),
 	}
 }
 
 type componentHandler struct {
-	client client.Client
-	scheme *runtime.Scheme
-	cr     metav1.Object
-	log    logr.Logger
+	client     client.Client
+	scheme     *runtime.Scheme
+	cr         metav1.Object
+	log        logr.Logger
+	controller string
+	plan       *planState
 }
 
-func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.Object, osType rmeta.OSType) error {
+func (c *componentHandler) SetController(name string) {
+	c.controller = name
+}
+
+// prepareObject sets the owner reference and the standard defaults that the handler applies to every object
+// before it is written to the cluster.
+func (c componentHandler) prepareObject(obj client.Object, osType rmeta.OSType) error {
 	om, ok := obj.(metav1.ObjectMetaAccessor)
 	if !ok {
 		return fmt.Errorf("object is not ObjectMetaAccessor")
@@ -90,9 +104,6 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 		}
 	}
 
-	logCtx := ContextLoggerForResource(c.log, obj)
-	key := client.ObjectKeyFromObject(obj)
-
 	// Ensure that if the object is something the creates a pod that it is scheduled on nodes running the operating
 	// system as specified by the osType.
 	ensureOSSchedulingRestrictions(obj, osType)
@@ -105,6 +116,22 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 
 	// Make sure we have our standard selector and pod labels
 	setStandardSelectorAndLabels(obj)
+	return nil
+}
+
+func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.Object, osType rmeta.OSType) error {
+	om, ok := obj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return fmt.Errorf("object is not ObjectMetaAccessor")
+	}
+
+	multipleOwners := checkIfMultipleOwnersLabel(om.GetObjectMeta())
+	if err := c.prepareObject(obj, osType); err != nil {
+		return err
+	}
+
+	logCtx := ContextLoggerForResource(c.log, obj)
+	key := client.ObjectKeyFromObject(obj)
 
 	cur, ok := obj.DeepCopyObject().(client.Object)
 	if !ok {
@@ -131,6 +158,7 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 			logCtx.WithValues("key", key).Error(err, "Failed to create object.")
 			return err
 		}
+		c.recordObject(obj, metrics.ActionCreate)
 		return nil
 	}
 
@@ -157,6 +185,8 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 				logCtx.WithValues("key", key).Error(err, "Failed to create Job.")
 				return err
 			}
+			c.recordObject(obj, metrics.ActionDelete)
+			c.recordObject(obj, metrics.ActionCreate)
 			return nil
 		case *v1.Secret:
 			objSecret := obj.(*v1.Secret)
@@ -176,6 +206,8 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 					logCtx.WithValues("key", key).Error(err, "Failed to create Secret.")
 					return err
 				}
+				c.recordObject(obj, metrics.ActionDelete)
+				c.recordObject(obj, metrics.ActionCreate)
 				return nil
 			}
 		case *v1.Service:
@@ -196,6 +228,8 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 					logCtx.WithValues("key", key).Error(err, "Failed to recreate service.", "obj", obj)
 					return err
 				}
+				c.recordObject(obj, metrics.ActionDelete)
+				c.recordObject(obj, metrics.ActionCreate)
 				return nil
 			}
 		case *rbacv1.RoleBinding:
@@ -214,6 +248,8 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 					logCtx.WithValues("key", key).Error(err, "Failed to recreate RoleBinding")
 					return err
 				}
+				c.recordObject(obj, metrics.ActionDelete)
+				c.recordObject(obj, metrics.ActionCreate)
 				return nil
 			}
 		case *rbacv1.ClusterRoleBinding:
@@ -232,6 +268,8 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 					logCtx.WithValues("key", key).Error(err, "Failed to recreate ClusterRoleBinding")
 					return err
 				}
+				c.recordObject(obj, metrics.ActionDelete)
+				c.recordObject(obj, metrics.ActionCreate)
 				return nil
 			}
 		}
@@ -239,16 +277,52 @@ func (c componentHandler) createOrUpdateObject(ctx context.Context, obj client.O
 			logCtx.WithValues("key", key).Info("Failed to update object.")
 			return err
 		}
+		c.recordObject(obj, metrics.ActionUpdate)
 	}
 	return nil
 }
 
+// recordObject counts an object created, updated or deleted by the handler.
+func (c componentHandler) recordObject(obj client.Object, action string) {
+	kind := c.objectKind(obj).Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	metrics.RecordObject(kind, action)
+}
+
 func resetMetadataForCreate(obj client.Object) {
 	obj.SetResourceVersion("")
 	obj.SetUID("")
 	obj.SetCreationTimestamp(metav1.Time{})
 }
 
+// RenderObjects returns the objects that CreateOrUpdateOrDelete would create and delete for the given component, with
+// the owner references and defaults that the handler applies. No requests are made to the API server. As with
+// CreateOrUpdateOrDelete, cr may be nil.
+func RenderObjects(scheme *runtime.Scheme, cr metav1.Object, component render.Component) (objsToCreate, objsToDelete []client.Object, err error) {
+	c := componentHandler{scheme: scheme, cr: cr}
+
+	toCreate, toDelete := component.Objects()
+	osType := component.SupportedOSType()
+	for _, obj := range toCreate {
+		obj = obj.DeepCopyObject().(client.Object)
+		if err := c.prepareObject(obj, osType); err != nil {
+			return nil, nil, err
+		}
+		if checkIfMultipleOwnersLabel(obj) {
+			labels := obj.GetLabels()
+			delete(labels, common.MultipleOwnersLabel)
+			obj.SetLabels(labels)
+		}
+		objsToCreate = append(objsToCreate, obj)
+	}
+	for _, obj := range toDelete {
+		objsToDelete = append(objsToDelete, obj.DeepCopyObject().(client.Object))
+	}
+	return objsToCreate, objsToDelete, nil
+}
+
 func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component render.Component, status status.StatusManager) error {
 	// Before creating the component, make sure that it is ready. This provides a hook to do
 	// dependency checking for the component.
@@ -258,6 +332,15 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 		cmpLog.Info("Component is not ready, skipping")
 		return nil
 	}
+
+	planMode, err := c.planModeEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if planMode {
+		cmpLog.V(2).Info("Plan mode is enabled, recording changes instead of applying them")
+		return c.planComponent(ctx, component)
+	}
 	cmpLog.V(2).Info("Reconciling")
 
 	// Iterate through each object that comprises the component and attempt to create it,
@@ -270,16 +353,22 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 	objsToCreate, objsToDelete := component.Objects()
 	osType := component.SupportedOSType()
 
+	createOrUpdate := c.createOrUpdateObject
+	if useServerSideApply(component) {
+		cmpLog.V(2).Info("Using server-side apply")
+		createOrUpdate = c.applyObject
+	}
+
 	for _, obj := range objsToCreate {
 		key := client.ObjectKeyFromObject(obj)
 
-		// Pass in a DeepCopy so any modifications made by createOrUpdateObject won't be included
+		// Pass in a DeepCopy so any modifications made by createOrUpdate won't be included
 		// if we need to retry the function
-		err := c.createOrUpdateObject(ctx, obj.DeepCopyObject().(client.Object), osType)
+		err := createOrUpdate(ctx, obj.DeepCopyObject().(client.Object), osType)
 		if err != nil && errors.IsConflict(err) {
 			// If the error is a resource Conflict, try the update again
 			cmpLog.WithValues("key", key, "conflict_message", err).Info("Failed to update object, retrying.")
-			err = c.createOrUpdateObject(ctx, obj, osType)
+			err = createOrUpdate(ctx, obj, osType)
 			if err != nil {
 				return err
 			}
@@ -325,6 +414,8 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 			logCtx := ContextLoggerForResource(c.log, obj)
 			logCtx.Error(err, fmt.Sprintf("Error deleting object %v", obj))
 			return err
+		} else if err == nil {
+			c.recordObject(obj, metrics.ActionDelete)
 		}
 
 		key := client.ObjectKeyFromObject(obj)
@@ -1926,6 +1926,10 @@ type mockClient struct {
 }
 
 func (mc *mockClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*operatorv1.Installation); ok {
+		// The handler looks up the Installation to check for plan mode on every call, treat it as not found.
+		return errors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
 	defer func() { mc.Index++ }()
 	funcName := "Get"
 	if len(mc.Info) <= mc.Index {
@@ -22,6 +22,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
@@ -34,10 +41,25 @@ import (
 	"github.com/tigera/operator/pkg/render"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// SnapshotRepositoryName and SnapshotPolicyName are the names of the Elasticsearch snapshot repository and the
+	// snapshot lifecycle management (SLM) policy the operator manages for the LogStorage's snapshots.
+	SnapshotRepositoryName = "tigera-snapshots"
+	SnapshotPolicyName     = "tigera-snapshots"
+
+	DefaultSnapshotSchedule    = "0 30 1 * * ?"
+	DefaultSnapshotIndices     = "tigera_secure_ee_*"
+	DefaultSnapshotExpireAfter = "30d"
+
+	// snapshotMinCount is the number of snapshots that are kept regardless of their age.
+	snapshotMinCount = 5
+)
+
 const (
 	ElasticsearchRetentionFactor = 4
 	DefaultMaxIndexSizeGi        = 30
@@ -55,17 +77,60 @@ type Policy struct {
 				}
 			}
 		}
+		Warm *struct {
+			MinAge  string `json:"min_age"`
+			Actions struct {
+				Shrink *struct {
+					NumberOfShards int `json:"number_of_shards"`
+				} `json:"shrink"`
+			}
+		}
+		Cold *struct {
+			MinAge string `json:"min_age"`
+		}
+		Frozen *struct {
+			MinAge  string `json:"min_age"`
+			Actions struct {
+				SearchableSnapshot *struct {
+					SnapshotRepository string `json:"snapshot_repository"`
+				} `json:"searchable_snapshot"`
+			}
+		}
 		Delete struct {
 			MinAge string `json:"min_age"`
 		}
 	}
 }
 
+// ilmPolicySettings are the settings of an ILM policy that the operator manages. An empty phase age means the phase
+// is not in the policy, except for the warm phase, which always starts immediately after rollover by default.
+type ilmPolicySettings struct {
+	rolloverAge        string
+	rolloverSize       string
+	warmAge            string
+	warmShards         int
+	coldAge            string
+	frozenAge          string
+	snapshotRepository string
+	deleteAge          string
+}
+
 type policyDetail struct {
-	rolloverAge  string
-	rolloverSize string
-	deleteAge    string
-	policy       map[string]interface{}
+	settings ilmPolicySettings
+	policy   map[string]interface{}
+}
+
+// ilmIndex is a time series index that the operator creates an ILM policy for.
+type ilmIndex struct {
+	name     string
+	dataType operatorv1.DataType
+
+	// The default share of the disk for the index is diskPercentage * diskForLogType.
+	diskPercentage float64
+	diskForLogType float64
+
+	// optional indices only have a policy when the LogStorage configures one for their data type.
+	optional bool
 }
 
 type logrWrappedESLogger struct{}
@@ -110,6 +175,14 @@ type ElasticClient interface {
 	CreateUser(context.Context, *User) error
 	DeleteUser(context.Context, *User) error
 	GetUsers(ctx context.Context) ([]User, error)
+	SetSnapshotPolicy(context.Context, *operatorv1.LogStorage) error
+	GetSnapshotStatus(context.Context) (*operatorv1.ElasticsearchSnapshotStatus, error)
+	RestoreSnapshot(ctx context.Context, snapshot string, indices []string) error
+	GetRestoreProgress(ctx context.Context, snapshot string, indices []string) (int, int, error)
+	CreateSnapshot(ctx context.Context, snapshot string, indices []string) error
+	GetSnapshotState(ctx context.Context, snapshot string) (string, error)
+	GetClusterHealth(context.Context) (string, error)
+	GetNodesOverDiskWatermark(context.Context) ([]string, error)
 }
 
 type esClient struct {
@@ -353,11 +426,14 @@ func (es *esClient) SetILMPolicies(ctx context.Context, ls *operatorv1.LogStorag
 	return es.createOrUpdatePolicies(ctx, policyList)
 }
 
-// listILMPolicies generates ILM policies based on disk space and retention in LogStorage
+// listILMPolicies generates ILM policies based on disk space and retention in LogStorage, and the policies configured
+// for each data type. By default:
 // Allocate 70% of ES disk space to flows, dns and bgp logs [majorPctOfTotalDisk]
 // Allocate 90% of the 70% ES disk space to flow logs, 5% of the 70% ES disk space to each dns and bgp logs.
 // Allocate 10% of ES disk space to logs that are NOT flows, dns or bgp [minorPctOfTotalDisk]
 // Equally distribute 10% of the ES disk space among these other log types
+// When the LogStorage sets the disk share of some data types, the other indices divide the rest of the disk in
+// proportion to their default shares.
 func (es *esClient) listILMPolicies(ls *operatorv1.LogStorage) map[string]policyDetail {
 	totalEsStorage := getTotalEsDisk(ls)
 	majorPctOfTotalDisk := 0.7
@@ -368,20 +444,153 @@ func (es *esClient) listILMPolicies(ls *operatorv1.LogStorage) map[string]policy
 	minorPctOfTotalDisk := 0.1
 	pctOfDisk := minorPctOfTotalDisk / float64(numOfIndicesWithMinorSpace)
 
-	// Retention is not set in LogStorage for l7, benchmark and events logs
-	return map[string]policyDetail{
-		"tigera_secure_ee_flows": buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.85, int(*ls.Spec.Retention.Flows)),
-		"tigera_secure_ee_dns":   buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.05, int(*ls.Spec.Retention.DNSLogs)),
-		"tigera_secure_ee_bgp":   buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.05, int(*ls.Spec.Retention.BGPLogs)),
-		"tigera_secure_ee_l7":    buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.05, 1),
+	indices := []ilmIndex{
+		{"tigera_secure_ee_flows", operatorv1.DataTypeFlowLogs, majorPctOfTotalDisk, 0.85, false},
+		{"tigera_secure_ee_dns", operatorv1.DataTypeDNSLogs, majorPctOfTotalDisk, 0.05, false},
+		{"tigera_secure_ee_bgp", operatorv1.DataTypeBGPLogs, majorPctOfTotalDisk, 0.05, false},
+		{"tigera_secure_ee_l7", operatorv1.DataTypeL7Logs, majorPctOfTotalDisk, 0.05, false},
+
+		{"tigera_secure_ee_audit_ee", operatorv1.DataTypeAuditLogs, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_audit_kube", operatorv1.DataTypeAuditLogs, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_snapshots", operatorv1.DataTypeComplianceSnapshots, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_compliance_reports", operatorv1.DataTypeComplianceReports, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_benchmark_results", operatorv1.DataTypeComplianceBenchmarks, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_events", operatorv1.DataTypeAlerts, minorPctOfTotalDisk, pctOfDisk, false},
+		{"tigera_secure_ee_waf", operatorv1.DataTypeWAFLogs, minorPctOfTotalDisk, pctOfDisk, true},
+		{"tigera_secure_ee_runtime", operatorv1.DataTypeRuntimeReports, minorPctOfTotalDisk, pctOfDisk, true},
+	}
+
+	configured := map[operatorv1.DataType]operatorv1.IndexLifecyclePolicy{}
+	for _, p := range ls.Spec.IndexLifecyclePolicies {
+		configured[p.DataType] = p
+	}
+
+	// Work out the share of the disk left for the indices whose data type doesn't have a disk share.
+	var active []ilmIndex
+	indicesPerType := map[operatorv1.DataType]int{}
+	for _, idx := range indices {
+		if _, ok := configured[idx.dataType]; idx.optional && !ok {
+			continue
+		}
+		active = append(active, idx)
+		indicesPerType[idx.dataType]++
+	}
+	scale, remainingShare, defaultShare := false, 1.0, 0.0
+	for _, idx := range active {
+		if p, ok := configured[idx.dataType]; ok && p.DiskShare != nil {
+			scale = true
+			remainingShare -= float64(*p.DiskShare) / 100 / float64(indicesPerType[idx.dataType])
+		} else {
+			defaultShare += idx.diskPercentage * idx.diskForLogType
+		}
+	}
+
+	// The warm and cold phases move indices to the Warm and Cold nodes. The warm phase starts at rollover by default, so
+	// with a cold tier the indices that don't configure their phases move to the Cold nodes halfway through retention.
+	coldTier := hasNodeRole(ls, operatorv1.ElasticsearchNodeRoleCold)
+
+	policies := map[string]policyDetail{}
+	for _, idx := range active {
+		p := configured[idx.dataType]
+		settings := ilmPolicySettings{
+			warmAge:            p.WarmAge,
+			coldAge:            p.ColdAge,
+			frozenAge:          p.FrozenAge,
+			snapshotRepository: p.SnapshotRepository,
+		}
+		if settings.frozenAge != "" && settings.snapshotRepository == "" {
+			settings.snapshotRepository = SnapshotRepositoryName
+		}
+		if p.WarmShards != nil {
+			settings.warmShards = int(*p.WarmShards)
+		}
+
+		retention := ILMRetention(&ls.Spec, idx.dataType)
+		settings.deleteAge = fmt.Sprintf("%dd", retention)
+		if coldTier && p.WarmAge == "" && p.ColdAge == "" && retention/2 > 0 && !frozenBefore(p.FrozenAge, retention/2) {
+			settings.coldAge = fmt.Sprintf("%dd", retention/2)
+		}
+
+		settings.rolloverAge = p.RolloverAge
+		if settings.rolloverAge == "" {
+			settings.rolloverAge = calculateRolloverAge(retention)
+		}
+
+		switch {
+		case p.RolloverSize != nil:
+			settings.rolloverSize = fmt.Sprintf("%db", p.RolloverSize.Value())
+		case p.DiskShare != nil:
+			share := float64(*p.DiskShare) / 100 / float64(indicesPerType[idx.dataType])
+			settings.rolloverSize = calculateRolloverSize(totalEsStorage, share, 1)
+		case scale && defaultShare > 0:
+			share := idx.diskPercentage * idx.diskForLogType * remainingShare / defaultShare
+			settings.rolloverSize = calculateRolloverSize(totalEsStorage, share, 1)
+		default:
+			settings.rolloverSize = calculateRolloverSize(totalEsStorage, idx.diskPercentage, idx.diskForLogType)
+		}
+
+		policies[idx.name] = newPolicyDetail(settings)
+	}
+	return policies
+}
+
+var ilmAgeRegexp = regexp.MustCompile(`^([0-9]+)(d|h|m|s)$`)
 
-		"tigera_secure_ee_audit_ee":           buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, int(*ls.Spec.Retention.AuditReports)),
-		"tigera_secure_ee_audit_kube":         buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, int(*ls.Spec.Retention.AuditReports)),
-		"tigera_secure_ee_snapshots":          buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, int(*ls.Spec.Retention.Snapshots)),
-		"tigera_secure_ee_compliance_reports": buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, int(*ls.Spec.Retention.ComplianceReports)),
-		"tigera_secure_ee_benchmark_results":  buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, 91),
-		"tigera_secure_ee_events":             buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, 91),
+// ParseILMAge parses an Elasticsearch time unit, as used for the ages in an IndexLifecyclePolicy.
+func ParseILMAge(age string) (time.Duration, error) {
+	m := ilmAgeRegexp.FindStringSubmatch(age)
+	if m == nil {
+		return 0, fmt.Errorf("%q is not a valid age, expected a number followed by d, h, m or s", age)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, err
 	}
+	unit := map[string]time.Duration{"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute, "s": time.Second}[m[2]]
+	return time.Duration(n) * unit, nil
+}
+
+// ILMRetention returns the number of days that the data of the given type is kept: the retention of the data type's
+// IndexLifecyclePolicy if it sets one, and otherwise the retention in spec.retention. Retention is not set in spec.retention
+// for L7 logs, compliance benchmarks, alerts, WAF logs and runtime reports. The spec's retention must be defaulted.
+func ILMRetention(spec *operatorv1.LogStorageSpec, dataType operatorv1.DataType) int {
+	for _, p := range spec.IndexLifecyclePolicies {
+		if p.DataType == dataType && p.Retention != nil {
+			return int(*p.Retention)
+		}
+	}
+
+	switch dataType {
+	case operatorv1.DataTypeFlowLogs:
+		return int(*spec.Retention.Flows)
+	case operatorv1.DataTypeDNSLogs:
+		return int(*spec.Retention.DNSLogs)
+	case operatorv1.DataTypeBGPLogs:
+		return int(*spec.Retention.BGPLogs)
+	case operatorv1.DataTypeAuditLogs:
+		return int(*spec.Retention.AuditReports)
+	case operatorv1.DataTypeComplianceSnapshots:
+		return int(*spec.Retention.Snapshots)
+	case operatorv1.DataTypeComplianceReports:
+		return int(*spec.Retention.ComplianceReports)
+	case operatorv1.DataTypeL7Logs:
+		return 1
+	case operatorv1.DataTypeComplianceBenchmarks, operatorv1.DataTypeAlerts:
+		return 91
+	default:
+		// WAF logs and runtime reports.
+		return 8
+	}
+}
+
+// frozenBefore returns true if the frozen phase age is set and is not later than the given number of days, in which
+// case the indices move straight to the frozen phase rather than through a default cold phase.
+func frozenBefore(frozenAge string, days int) bool {
+	if frozenAge == "" {
+		return false
+	}
+	age, err := ParseILMAge(frozenAge)
+	return err == nil && age <= time.Duration(days)*24*time.Hour
 }
 
 func (es *esClient) createOrUpdatePolicies(ctx context.Context, listPolicy map[string]policyDetail) error {
@@ -392,63 +601,104 @@ func (es *esClient) createOrUpdatePolicies(ctx context.Context, listPolicy map[s
 		if err != nil {
 			if elastic.IsNotFound(err) {
 				// If policy doesn't exist, create one
-				return applyILMPolicy(ctx, es.client, indexName, pd.policy)
+				if err = applyILMPolicy(ctx, es.client, indexName, pd.policy); err != nil {
+					return err
+				}
+				continue
 			}
 			return err
 		}
 
 		// If policy exists, check if it needs to be updated
-		currentMaxAge, currentMaxSize, currentMinAge, err := extractPolicyDetails(res[policyName].Policy)
+		current, err := extractPolicySettings(res[policyName].Policy)
 		if err != nil {
 			return err
 		}
-		if currentMaxAge != pd.rolloverAge ||
-			currentMaxSize != pd.rolloverSize ||
-			currentMinAge != pd.deleteAge {
-			return applyILMPolicy(ctx, es.client, indexName, pd.policy)
+		if current != pd.settings {
+			if err = applyILMPolicy(ctx, es.client, indexName, pd.policy); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 func buildILMPolicy(totalEsStorage int64, totalDiskPercentage float64, percentOfDiskForLogType float64, retention int) policyDetail {
-	pd := policyDetail{}
-	pd.rolloverSize = calculateRolloverSize(totalEsStorage, totalDiskPercentage, percentOfDiskForLogType)
-	pd.rolloverAge = calculateRolloverAge(retention)
-	pd.deleteAge = fmt.Sprintf("%dd", retention)
-
-	pd.policy = map[string]interface{}{
-		"policy": map[string]interface{}{
-			"phases": map[string]interface{}{
-				"hot": map[string]interface{}{
-					"actions": map[string]interface{}{
-						"rollover": map[string]interface{}{
-							"max_size": pd.rolloverSize,
-							"max_age":  pd.rolloverAge,
-						},
-						"set_priority": map[string]interface{}{
-							"priority": 100,
-						},
-					},
+	return newPolicyDetail(ilmPolicySettings{
+		rolloverSize: calculateRolloverSize(totalEsStorage, totalDiskPercentage, percentOfDiskForLogType),
+		rolloverAge:  calculateRolloverAge(retention),
+		deleteAge:    fmt.Sprintf("%dd", retention),
+	})
+}
+
+// newPolicyDetail builds the ILM policy with the given settings.
+func newPolicyDetail(settings ilmPolicySettings) policyDetail {
+	warm := map[string]interface{}{
+		"actions": map[string]interface{}{
+			"readonly": map[string]interface{}{},
+			"set_priority": map[string]interface{}{
+				"priority": 50,
+			},
+		},
+	}
+	if settings.warmAge != "" {
+		warm["min_age"] = settings.warmAge
+	}
+	if settings.warmShards > 0 {
+		warm["actions"].(map[string]interface{})["shrink"] = map[string]interface{}{
+			"number_of_shards": settings.warmShards,
+		}
+	}
+
+	phases := map[string]interface{}{
+		"hot": map[string]interface{}{
+			"actions": map[string]interface{}{
+				"rollover": map[string]interface{}{
+					"max_size": settings.rolloverSize,
+					"max_age":  settings.rolloverAge,
 				},
-				"warm": map[string]interface{}{
-					"actions": map[string]interface{}{
-						"readonly": map[string]interface{}{},
-						"set_priority": map[string]interface{}{
-							"priority": 50,
-						},
-					},
+				"set_priority": map[string]interface{}{
+					"priority": 100,
 				},
-				"delete": map[string]interface{}{
-					"min_age": pd.deleteAge,
-					"actions": map[string]interface{}{
-						"delete": map[string]interface{}{},
-					},
+			},
+		},
+		"warm": warm,
+		"delete": map[string]interface{}{
+			"min_age": settings.deleteAge,
+			"actions": map[string]interface{}{
+				"delete": map[string]interface{}{},
+			},
+		},
+	}
+	if settings.coldAge != "" {
+		phases["cold"] = map[string]interface{}{
+			"min_age": settings.coldAge,
+			"actions": map[string]interface{}{
+				"set_priority": map[string]interface{}{
+					"priority": 0,
+				},
+			},
+		}
+	}
+	if settings.frozenAge != "" {
+		phases["frozen"] = map[string]interface{}{
+			"min_age": settings.frozenAge,
+			"actions": map[string]interface{}{
+				"searchable_snapshot": map[string]interface{}{
+					"snapshot_repository": settings.snapshotRepository,
 				},
 			},
+		}
+	}
+
+	return policyDetail{
+		settings: settings,
+		policy: map[string]interface{}{
+			"policy": map[string]interface{}{
+				"phases": phases,
+			},
 		},
 	}
-	return pd
 }
 
 func applyILMPolicy(ctx context.Context, esClient *elastic.Client, indexName string, policy map[string]interface{}) error {
@@ -548,20 +798,42 @@ func getESRoots(ctx context.Context, client client.Client) (*x509.CertPool, erro
 	return roots, nil
 }
 
-func extractPolicyDetails(policy map[string]interface{}) (string, string, string, error) {
+// extractPolicySettings returns the settings of an existing ILM policy.
+func extractPolicySettings(policy map[string]interface{}) (ilmPolicySettings, error) {
 	jsonPolicy, err := json.Marshal(policy)
 	if err != nil {
-		return "", "", "", err
+		return ilmPolicySettings{}, err
 	}
 	existingPolicy := Policy{}
 	if err = json.Unmarshal(jsonPolicy, &existingPolicy); err != nil {
-		return "", "", "", err
+		return ilmPolicySettings{}, err
 	}
 
-	currentMaxAge := existingPolicy.Phases.Hot.Actions.Rollover.MaxAge
-	currentMaxSize := existingPolicy.Phases.Hot.Actions.Rollover.MaxSize
-	currentMinAge := existingPolicy.Phases.Delete.MinAge
-	return currentMaxAge, currentMaxSize, currentMinAge, nil
+	phases := existingPolicy.Phases
+	settings := ilmPolicySettings{
+		rolloverAge:  phases.Hot.Actions.Rollover.MaxAge,
+		rolloverSize: phases.Hot.Actions.Rollover.MaxSize,
+		deleteAge:    phases.Delete.MinAge,
+	}
+	if phases.Warm != nil {
+		// Elasticsearch reports a phase without a min_age as starting at 0ms.
+		if phases.Warm.MinAge != "0ms" {
+			settings.warmAge = phases.Warm.MinAge
+		}
+		if phases.Warm.Actions.Shrink != nil {
+			settings.warmShards = phases.Warm.Actions.Shrink.NumberOfShards
+		}
+	}
+	if phases.Cold != nil {
+		settings.coldAge = phases.Cold.MinAge
+	}
+	if phases.Frozen != nil {
+		settings.frozenAge = phases.Frozen.MinAge
+		if phases.Frozen.Actions.SearchableSnapshot != nil {
+			settings.snapshotRepository = phases.Frozen.Actions.SearchableSnapshot.SnapshotRepository
+		}
+	}
+	return settings, nil
 }
 
 func getTotalEsDisk(ls *operatorv1.LogStorage) int64 {
@@ -572,5 +844,440 @@ func getTotalEsDisk(ls *operatorv1.LogStorage) int64 {
 			totalEsStorage = val.Value()
 		}
 	}
+	// Indices are rolled over while they are on the Hot nodes, so their disk is the one that matters.
+	for _, ns := range ls.Spec.Nodes.NodeSets {
+		if ns.ResourceRequirements != nil && hasRole(ns.Roles, operatorv1.ElasticsearchNodeRoleHot) {
+			if val, ok := ns.ResourceRequirements.Requests["storage"]; ok {
+				totalEsStorage = val.Value()
+				break
+			}
+		}
+	}
 	return totalEsStorage
 }
+
+// hasNodeRole returns whether any of the LogStorage's NodeSets has the role.
+func hasNodeRole(ls *operatorv1.LogStorage, role operatorv1.ElasticsearchNodeRole) bool {
+	if ls.Spec.Nodes == nil {
+		return false
+	}
+	for _, ns := range ls.Spec.Nodes.NodeSets {
+		if hasRole(ns.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRole(roles []operatorv1.ElasticsearchNodeRole, role operatorv1.ElasticsearchNodeRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSnapshotPolicy registers the snapshot repository configured in the LogStorage and creates or updates the SLM
+// policy that takes the snapshots. When snapshots are not configured, the SLM policy is removed. The repository is
+// left registered, as removing it would not remove the snapshots stored in it.
+func (es *esClient) SetSnapshotPolicy(ctx context.Context, ls *operatorv1.LogStorage) error {
+	if ls.Spec.Snapshots == nil {
+		_, err := es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+			Method: http.MethodDelete,
+			Path:   "/_slm/policy/" + SnapshotPolicyName,
+		})
+		if err != nil && !elastic.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	repoType, settings := snapshotRepositorySettings(ls.Spec.Snapshots.Repository)
+	if _, err := es.client.SnapshotCreateRepository(SnapshotRepositoryName).Type(repoType).Settings(settings).Do(ctx); err != nil {
+		return fmt.Errorf("failed to create the snapshot repository: %w", err)
+	}
+
+	policy := snapshotPolicy(ls.Spec.Snapshots)
+	res, err := es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   "/_slm/policy/" + SnapshotPolicyName,
+	})
+	if err != nil && !elastic.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		var current map[string]struct {
+			Policy map[string]interface{} `json:"policy"`
+		}
+		if err = json.Unmarshal(res.Body, &current); err != nil {
+			return err
+		}
+		if equalJSON(current[SnapshotPolicyName].Policy, policy) {
+			return nil
+		}
+	}
+
+	if _, err = es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodPut,
+		Path:   "/_slm/policy/" + SnapshotPolicyName,
+		Body:   policy,
+	}); err != nil {
+		return fmt.Errorf("failed to apply the snapshot policy: %w", err)
+	}
+	return nil
+}
+
+// snapshotRepositorySettings returns the type and settings of the Elasticsearch snapshot repository.
+func snapshotRepositorySettings(repo operatorv1.SnapshotRepository) (string, map[string]interface{}) {
+	if repo.S3 != nil {
+		settings := map[string]interface{}{"bucket": repo.S3.Bucket}
+		if repo.S3.BasePath != "" {
+			settings["base_path"] = repo.S3.BasePath
+		}
+		return "s3", settings
+	}
+	return "fs", map[string]interface{}{"location": render.ElasticsearchSnapshotsPath}
+}
+
+// snapshotPolicy returns the SLM policy for the LogStorage's snapshots, filling in the defaults.
+func snapshotPolicy(snapshots *operatorv1.ElasticsearchSnapshots) map[string]interface{} {
+	schedule := snapshots.Schedule
+	if schedule == "" {
+		schedule = DefaultSnapshotSchedule
+	}
+	indices := snapshots.Indices
+	if len(indices) == 0 {
+		indices = []string{DefaultSnapshotIndices}
+	}
+	expireAfter := snapshots.ExpireAfter
+	if expireAfter == "" {
+		expireAfter = DefaultSnapshotExpireAfter
+	}
+
+	return map[string]interface{}{
+		// SLM adds a unique suffix to the name of each snapshot.
+		"name":       "<tigera-snapshot-{now/d}>",
+		"schedule":   schedule,
+		"repository": SnapshotRepositoryName,
+		"config": map[string]interface{}{
+			"indices":              indices,
+			"include_global_state": false,
+		},
+		"retention": map[string]interface{}{
+			"expire_after": expireAfter,
+			"min_count":    snapshotMinCount,
+		},
+	}
+}
+
+// equalJSON returns whether a and b have the same JSON representation, ignoring the Go types used to build them.
+func equalJSON(a, b interface{}) bool {
+	var na, nb interface{}
+	ja, err := json.Marshal(a)
+	if err != nil || json.Unmarshal(ja, &na) != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil || json.Unmarshal(jb, &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+type slmInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	Time         int64  `json:"time"`
+	Details      string `json:"details"`
+}
+
+// GetSnapshotStatus returns the most recent successful and failed snapshots taken by the SLM policy, or nil if there is
+// no SLM policy.
+func (es *esClient) GetSnapshotStatus(ctx context.Context) (*operatorv1.ElasticsearchSnapshotStatus, error) {
+	res, err := es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   "/_slm/policy/" + SnapshotPolicyName,
+	})
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var policies map[string]struct {
+		LastSuccess *slmInvocation `json:"last_success"`
+		LastFailure *slmInvocation `json:"last_failure"`
+	}
+	if err = json.Unmarshal(res.Body, &policies); err != nil {
+		return nil, err
+	}
+
+	p := policies[SnapshotPolicyName]
+	status := &operatorv1.ElasticsearchSnapshotStatus{}
+	if p.LastSuccess != nil {
+		status.LastSuccessfulSnapshot = p.LastSuccess.SnapshotName
+		t := metav1.NewTime(time.UnixMilli(p.LastSuccess.Time))
+		status.LastSuccessfulSnapshotTime = &t
+	}
+	if p.LastFailure != nil {
+		status.LastFailedSnapshot = p.LastFailure.SnapshotName
+		t := metav1.NewTime(time.UnixMilli(p.LastFailure.Time))
+		status.LastFailedSnapshotTime = &t
+		status.LastFailure = p.LastFailure.Details
+	}
+	return status, nil
+}
+
+// RestoreSnapshot starts to restore the indices of the snapshot that match the given patterns, or all of its indices
+// if no patterns are given. Existing indices are closed first so that the restore can replace them, and are reopened if
+// the restore can't be started. Use GetRestoreProgress to follow the restore.
+func (es *esClient) RestoreSnapshot(ctx context.Context, snapshot string, patterns []string) error {
+	indices, err := es.restoreIndices(ctx, snapshot, patterns)
+	if err != nil {
+		return err
+	}
+
+	_, err = es.client.CloseIndex(strings.Join(indices, ",")).IgnoreUnavailable(true).AllowNoIndices(true).Do(ctx)
+	if err != nil {
+		return es.reopenIndices(ctx, indices, fmt.Errorf("failed to close the indices to restore: %w", err))
+	}
+
+	_, err = es.client.SnapshotRestore(SnapshotRepositoryName, snapshot).
+		Indices(indices...).
+		IncludeGlobalState(false).
+		WaitForCompletion(false).
+		Do(ctx)
+	if err != nil {
+		return es.reopenIndices(ctx, indices, fmt.Errorf("failed to restore snapshot %s: %w", snapshot, err))
+	}
+	return nil
+}
+
+// reopenIndices opens the indices that were closed for a restore that failed, and returns the error of the restore.
+func (es *esClient) reopenIndices(ctx context.Context, indices []string, restoreErr error) error {
+	_, err := es.client.OpenIndex(strings.Join(indices, ",")).IgnoreUnavailable(true).AllowNoIndices(true).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("%v, and failed to reopen the indices: %w", restoreErr, err)
+	}
+	return restoreErr
+}
+
+// restoreIndices returns the indices of the snapshot that match the given patterns, or all of its indices if no
+// patterns are given.
+func (es *esClient) restoreIndices(ctx context.Context, snapshot string, patterns []string) ([]string, error) {
+	res, err := es.client.SnapshotGet(SnapshotRepositoryName).Snapshot(snapshot).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot %s: %w", snapshot, err)
+	}
+	if len(res.Snapshots) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found", snapshot)
+	}
+
+	indices := snapshotIndices(res.Snapshots[0].Indices, patterns)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("snapshot %s has no indices matching %v", snapshot, patterns)
+	}
+	return indices, nil
+}
+
+// GetRestoreProgress returns how many of the indices being restored from the snapshot have been restored, and how
+// many indices are being restored. An index is restored once all of its primary shards have been recovered from the
+// snapshot.
+func (es *esClient) GetRestoreProgress(ctx context.Context, snapshot string, patterns []string) (int, int, error) {
+	indices, err := es.restoreIndices(ctx, snapshot, patterns)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	res, err := es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   "/" + strings.Join(indices, ",") + "/_recovery",
+		Params: url.Values{"ignore_unavailable": []string{"true"}},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the recovery of the indices being restored: %w", err)
+	}
+
+	var recoveries map[string]struct {
+		Shards []struct {
+			Type   string `json:"type"`
+			Stage  string `json:"stage"`
+			Source struct {
+				Snapshot string `json:"snapshot"`
+			} `json:"source"`
+		} `json:"shards"`
+	}
+	if err = json.Unmarshal(res.Body, &recoveries); err != nil {
+		return 0, 0, err
+	}
+
+	restored := 0
+	for _, index := range indices {
+		fromSnapshot, done := 0, 0
+		for _, shard := range recoveries[index].Shards {
+			if shard.Type != "SNAPSHOT" || shard.Source.Snapshot != snapshot {
+				continue
+			}
+			fromSnapshot++
+			if shard.Stage == "DONE" {
+				done++
+			}
+		}
+		if fromSnapshot > 0 && done == fromSnapshot {
+			restored++
+		}
+	}
+	return restored, len(indices), nil
+}
+
+// snapshotIndices returns the indices that match any of the patterns, which may contain * wildcards.
+func snapshotIndices(indices, patterns []string) []string {
+	if len(patterns) == 0 {
+		return indices
+	}
+	var matched []string
+	for _, index := range indices {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, index); ok {
+				matched = append(matched, index)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// CreateSnapshot starts a snapshot of the indices that match the given patterns in the snapshot repository. It does not
+// wait for the snapshot to complete, use GetSnapshotState to follow it.
+func (es *esClient) CreateSnapshot(ctx context.Context, snapshot string, indices []string) error {
+	body := map[string]interface{}{
+		"include_global_state": false,
+	}
+	if len(indices) > 0 {
+		body["indices"] = strings.Join(indices, ",")
+	}
+	_, err := es.client.SnapshotCreate(SnapshotRepositoryName, snapshot).BodyJson(body).WaitForCompletion(false).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot %s: %w", snapshot, err)
+	}
+	return nil
+}
+
+// GetSnapshotState returns the state of the snapshot, such as IN_PROGRESS, SUCCESS or FAILED, or an empty string if
+// the snapshot does not exist.
+func (es *esClient) GetSnapshotState(ctx context.Context, snapshot string) (string, error) {
+	res, err := es.client.SnapshotGet(SnapshotRepositoryName).Snapshot(snapshot).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get snapshot %s: %w", snapshot, err)
+	}
+	if len(res.Snapshots) == 0 {
+		return "", nil
+	}
+	return res.Snapshots[0].State, nil
+}
+
+// GetClusterHealth returns the health of the Elasticsearch cluster: green, yellow or red.
+func (es *esClient) GetClusterHealth(ctx context.Context) (string, error) {
+	res, err := es.client.ClusterHealth().Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the cluster health: %w", err)
+	}
+	return res.Status, nil
+}
+
+// GetNodesOverDiskWatermark returns the names of the Elasticsearch nodes whose disk usage is over the high disk
+// watermark, above which Elasticsearch stops allocating shards to them.
+func (es *esClient) GetNodesOverDiskWatermark(ctx context.Context) ([]string, error) {
+	res, err := es.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   "/_cluster/settings",
+		Params: url.Values{"include_defaults": []string{"true"}, "flat_settings": []string{"true"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cluster settings: %w", err)
+	}
+	var settings map[string]map[string]interface{}
+	if err = json.Unmarshal(res.Body, &settings); err != nil {
+		return nil, err
+	}
+	// Transient settings take precedence over persistent settings, which take precedence over the defaults.
+	watermark := defaultDiskWatermarkHigh
+	for _, scope := range []string{"defaults", "persistent", "transient"} {
+		if v, ok := settings[scope][diskWatermarkHighSetting].(string); ok {
+			watermark = v
+		}
+	}
+
+	stats, err := es.client.NodesStats().Metric("fs").Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the disk usage of the nodes: %w", err)
+	}
+	var nodes []string
+	for _, node := range stats.Nodes {
+		if node.FS == nil || node.FS.Total == nil {
+			continue
+		}
+		over, err := overDiskWatermark(watermark, node.FS.Total.TotalInBytes, node.FS.Total.AvailableInBytes)
+		if err != nil {
+			return nil, err
+		}
+		if over {
+			nodes = append(nodes, node.Name)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+const (
+	diskWatermarkHighSetting = "cluster.routing.allocation.disk.watermark.high"
+	defaultDiskWatermarkHigh = "90%"
+)
+
+// overDiskWatermark returns whether a disk is over the watermark, which is either the maximum used share of the disk
+// as a percentage or a ratio, or the minimum free space as a byte value such as 10gb.
+func overDiskWatermark(watermark string, total, available int64) (bool, error) {
+	if total <= 0 {
+		return false, nil
+	}
+	used := float64(total-available) / float64(total)
+	if strings.HasSuffix(watermark, "%") {
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(watermark, "%"), 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid disk watermark %q: %w", watermark, err)
+		}
+		return used*100 > pct, nil
+	}
+	if ratio, err := strconv.ParseFloat(watermark, 64); err == nil {
+		return used > ratio, nil
+	}
+	free, err := parseByteSize(watermark)
+	if err != nil {
+		return false, fmt.Errorf("invalid disk watermark %q: %w", watermark, err)
+	}
+	return available < free, nil
+}
+
+// parseByteSize parses an Elasticsearch byte size such as 500mb or 10gb.
+func parseByteSize(size string) (int64, error) {
+	size = strings.ToLower(strings.TrimSpace(size))
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{
+		{"pb", 1 << 50}, {"tb", 1 << 40}, {"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10}, {"b", 1},
+	} {
+		if strings.HasSuffix(size, unit.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(size, unit.suffix), 64)
+			if err != nil {
+				return 0, err
+			}
+			return int64(v * float64(unit.bytes)), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown unit")
+}
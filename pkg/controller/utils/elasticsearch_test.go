@@ -22,13 +22,17 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	elastic "github.com/olivere/elastic/v7"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
 )
 
 const (
@@ -91,6 +95,303 @@ var _ = Describe("Elasticsearch tests", func() {
 			})
 			Expect(err).To(BeNil())
 		})
+
+		Context("policies per data type", func() {
+			var ls *operatorv1.LogStorage
+			int32Ptr := func(v int32) *int32 { return &v }
+			totalDiskSize := resource.MustParse("100Gi")
+
+			BeforeEach(func() {
+				ls = &operatorv1.LogStorage{Spec: operatorv1.LogStorageSpec{
+					Nodes: &operatorv1.Nodes{ResourceRequirements: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{"storage": totalDiskSize},
+					}},
+					Retention: &operatorv1.Retention{
+						Flows: int32Ptr(8), AuditReports: int32Ptr(91), Snapshots: int32Ptr(91),
+						ComplianceReports: int32Ptr(91), DNSLogs: int32Ptr(8), BGPLogs: int32Ptr(8),
+					},
+				}}
+			})
+
+			It("should use the default disk split and retention", func() {
+				policies := eClient.listILMPolicies(ls)
+				Expect(policies).To(HaveLen(10))
+				Expect(policies).NotTo(HaveKey("tigera_secure_ee_waf"))
+				Expect(policies["tigera_secure_ee_flows"]).To(Equal(buildILMPolicy(totalDiskSize.Value(), 0.7, 0.85, 8)))
+				Expect(policies["tigera_secure_ee_l7"]).To(Equal(buildILMPolicy(totalDiskSize.Value(), 0.7, 0.05, 1)))
+				Expect(policies["tigera_secure_ee_events"]).To(Equal(buildILMPolicy(totalDiskSize.Value(), 0.1, 0.1/6, 91)))
+			})
+
+			It("should divide the rest of the disk among the data types without a disk share", func() {
+				ls.Spec.IndexLifecyclePolicies = []operatorv1.IndexLifecyclePolicy{
+					{DataType: operatorv1.DataTypeFlowLogs, DiskShare: int32Ptr(5)},
+					{DataType: operatorv1.DataTypeL7Logs, DiskShare: int32Ptr(60), Retention: int32Ptr(30)},
+				}
+				policies := eClient.listILMPolicies(ls)
+				Expect(policies["tigera_secure_ee_flows"].settings.rolloverSize).To(Equal(calculateRolloverSize(totalDiskSize.Value(), 0.05, 1)))
+				Expect(policies["tigera_secure_ee_l7"].settings.rolloverSize).To(Equal(calculateRolloverSize(totalDiskSize.Value(), 0.6, 1)))
+				Expect(policies["tigera_secure_ee_l7"].settings.deleteAge).To(Equal("30d"))
+				Expect(policies["tigera_secure_ee_l7"].settings.rolloverAge).To(Equal("7d"))
+
+				var total int64
+				for _, pd := range policies {
+					var size int64
+					_, err := fmt.Sscanf(pd.settings.rolloverSize, "%db", &size)
+					Expect(err).NotTo(HaveOccurred())
+					total += size * ElasticsearchRetentionFactor
+				}
+				Expect(total).To(BeNumerically("~", totalDiskSize.Value(), 100))
+			})
+
+			It("should move indices to the cold tier halfway through their retention when there are cold nodes", func() {
+				coldStorage := resource.MustParse("500Gi")
+				ls.Spec.Nodes.NodeSets = []operatorv1.NodeSet{
+					{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleMaster, operatorv1.ElasticsearchNodeRoleHot}},
+					{
+						Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleCold},
+						ResourceRequirements: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{"storage": coldStorage},
+						},
+					},
+				}
+				ls.Spec.IndexLifecyclePolicies = []operatorv1.IndexLifecyclePolicy{
+					{DataType: operatorv1.DataTypeDNSLogs, ColdAge: "2d"},
+					{DataType: operatorv1.DataTypeBGPLogs, FrozenAge: "3d"},
+					{DataType: operatorv1.DataTypeAuditLogs, FrozenAge: "60d"},
+				}
+				policies := eClient.listILMPolicies(ls)
+				Expect(policies["tigera_secure_ee_flows"].settings.coldAge).To(Equal("4d"))
+				Expect(policies["tigera_secure_ee_dns"].settings.coldAge).To(Equal("2d"))
+				Expect(policies["tigera_secure_ee_l7"].settings.coldAge).To(BeEmpty())
+
+				// The default cold phase is left out when the frozen phase starts first.
+				Expect(policies["tigera_secure_ee_bgp"].settings.coldAge).To(BeEmpty())
+				Expect(policies["tigera_secure_ee_bgp"].settings.frozenAge).To(Equal("3d"))
+				Expect(policies["tigera_secure_ee_audit_ee"].settings.coldAge).To(Equal("45d"))
+
+				// The rollover size is based on the disk of the hot nodes.
+				Expect(policies["tigera_secure_ee_flows"].settings.rolloverSize).To(Equal(calculateRolloverSize(totalDiskSize.Value(), 0.7, 0.85)))
+			})
+
+			It("should add the configured phases to the policy", func() {
+				rolloverSize := resource.MustParse("5Gi")
+				ls.Spec.IndexLifecyclePolicies = []operatorv1.IndexLifecyclePolicy{{
+					DataType:     operatorv1.DataTypeWAFLogs,
+					RolloverSize: &rolloverSize,
+					RolloverAge:  "12h",
+					WarmAge:      "1d",
+					WarmShards:   int32Ptr(1),
+					ColdAge:      "7d",
+					FrozenAge:    "14d",
+				}}
+				policies := eClient.listILMPolicies(ls)
+				Expect(policies).To(HaveLen(11))
+				pd := policies["tigera_secure_ee_waf"]
+				Expect(pd.settings).To(Equal(ilmPolicySettings{
+					rolloverAge:        "12h",
+					rolloverSize:       fmt.Sprintf("%db", rolloverSize.Value()),
+					warmAge:            "1d",
+					warmShards:         1,
+					coldAge:            "7d",
+					frozenAge:          "14d",
+					snapshotRepository: SnapshotRepositoryName,
+					deleteAge:          "8d",
+				}))
+
+				// Reading the policy back gives the same settings, so it isn't updated on every reconcile.
+				current, err := extractPolicySettings(pd.policy["policy"].(map[string]interface{}))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(current).To(Equal(pd.settings))
+			})
+		})
+	})
+	Context("Snapshots", func() {
+		var (
+			eClient *esClient
+			rt      *snapshotRoundTripper
+			ctx     context.Context
+			ls      *operatorv1.LogStorage
+		)
+		BeforeEach(func() {
+			rt = &snapshotRoundTripper{responses: map[string]string{}, bodies: map[string]string{}}
+			eClient = mockElasticClient(&http.Client{Transport: rt}, baseURI)
+			ctx = context.Background()
+			ls = &operatorv1.LogStorage{Spec: operatorv1.LogStorageSpec{
+				Snapshots: &operatorv1.ElasticsearchSnapshots{
+					Repository: operatorv1.SnapshotRepository{
+						S3: &operatorv1.S3SnapshotRepository{Bucket: "backups", BasePath: "calico", CredentialsSecretName: "s3-creds"},
+					},
+				},
+			}}
+		})
+
+		It("should create the repository and the SLM policy with the defaults", func() {
+			rt.responses["PUT /_snapshot/tigera-snapshots"] = `{"acknowledged":true}`
+			rt.responses["PUT /_slm/policy/tigera-snapshots"] = `{"acknowledged":true}`
+
+			Expect(eClient.SetSnapshotPolicy(ctx, ls)).To(Succeed())
+			Expect(rt.bodies["PUT /_snapshot/tigera-snapshots"]).To(MatchJSON(`{"type":"s3","settings":{"bucket":"backups","base_path":"calico"}}`))
+			Expect(rt.bodies["PUT /_slm/policy/tigera-snapshots"]).To(MatchJSON(`{
+				"name": "<tigera-snapshot-{now/d}>",
+				"schedule": "0 30 1 * * ?",
+				"repository": "tigera-snapshots",
+				"config": {"indices": ["tigera_secure_ee_*"], "include_global_state": false},
+				"retention": {"expire_after": "30d", "min_count": 5}
+			}`))
+		})
+
+		It("should not update an SLM policy that has not changed", func() {
+			ls.Spec.Snapshots.Repository = operatorv1.SnapshotRepository{
+				Filesystem: &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "snapshots"},
+			}
+			ls.Spec.Snapshots.Schedule = "0 0 * * * ?"
+			rt.responses["PUT /_snapshot/tigera-snapshots"] = `{"acknowledged":true}`
+			rt.responses["GET /_slm/policy/tigera-snapshots"] = `{"tigera-snapshots": {"version": 2, "policy": {
+				"name": "<tigera-snapshot-{now/d}>",
+				"schedule": "0 0 * * * ?",
+				"repository": "tigera-snapshots",
+				"config": {"indices": ["tigera_secure_ee_*"], "include_global_state": false},
+				"retention": {"expire_after": "30d", "min_count": 5}
+			}}}`
+
+			Expect(eClient.SetSnapshotPolicy(ctx, ls)).To(Succeed())
+			Expect(rt.bodies["PUT /_snapshot/tigera-snapshots"]).To(MatchJSON(`{"type":"fs","settings":{"location":"/usr/share/elasticsearch/snapshots"}}`))
+			Expect(rt.bodies).NotTo(HaveKey("PUT /_slm/policy/tigera-snapshots"))
+		})
+
+		It("should remove the SLM policy when snapshots are not configured", func() {
+			rt.responses["DELETE /_slm/policy/tigera-snapshots"] = `{"acknowledged":true}`
+			Expect(eClient.SetSnapshotPolicy(ctx, &operatorv1.LogStorage{})).To(Succeed())
+			Expect(rt.bodies).To(HaveKey("DELETE /_slm/policy/tigera-snapshots"))
+		})
+
+		It("should report the most recent snapshots", func() {
+			rt.responses["GET /_slm/policy/tigera-snapshots"] = `{"tigera-snapshots": {
+				"last_success": {"snapshot_name": "tigera-snapshot-2024.03.02-abc", "time": 1709343000000},
+				"last_failure": {"snapshot_name": "tigera-snapshot-2024.03.01-def", "time": 1709256600000, "details": "repository is missing"}
+			}}`
+
+			status, err := eClient.GetSnapshotStatus(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.LastSuccessfulSnapshot).To(Equal("tigera-snapshot-2024.03.02-abc"))
+			Expect(status.LastSuccessfulSnapshotTime.UTC().Format(time.RFC3339)).To(Equal("2024-03-02T01:30:00Z"))
+			Expect(status.LastFailedSnapshot).To(Equal("tigera-snapshot-2024.03.01-def"))
+			Expect(status.LastFailure).To(Equal("repository is missing"))
+		})
+
+		It("should close and restore the matching indices of a snapshot", func() {
+			rt.responses["GET /_snapshot/tigera-snapshots/snap-1"] = `{"snapshots": [{"snapshot": "snap-1", "indices": [
+				"tigera_secure_ee_flows.cluster.fluentd-1", "tigera_secure_ee_dns.cluster.fluentd-1", "tigera_secure_ee_audit_kube.cluster.fluentd-1"
+			]}]}`
+			rt.responses["POST /tigera_secure_ee_flows.cluster.fluentd-1,tigera_secure_ee_dns.cluster.fluentd-1/_close"] = `{"acknowledged":true}`
+			rt.responses["POST /_snapshot/tigera-snapshots/snap-1/_restore"] = `{"accepted":true}`
+
+			Expect(eClient.RestoreSnapshot(ctx, "snap-1", []string{"tigera_secure_ee_flows*", "tigera_secure_ee_dns*"})).To(Succeed())
+			Expect(rt.bodies["POST /_snapshot/tigera-snapshots/snap-1/_restore"]).To(MatchJSON(`{
+				"indices": "tigera_secure_ee_flows.cluster.fluentd-1,tigera_secure_ee_dns.cluster.fluentd-1",
+				"include_global_state": false
+			}`))
+		})
+
+		It("should reopen the indices when the restore can't be started", func() {
+			rt.responses["GET /_snapshot/tigera-snapshots/snap-1"] = `{"snapshots": [{"snapshot": "snap-1", "indices": ["tigera_secure_ee_flows.cluster.fluentd-1"]}]}`
+			rt.responses["POST /tigera_secure_ee_flows.cluster.fluentd-1/_close"] = `{"acknowledged":true}`
+			rt.responses["POST /tigera_secure_ee_flows.cluster.fluentd-1/_open"] = `{"acknowledged":true}`
+
+			Expect(eClient.RestoreSnapshot(ctx, "snap-1", nil)).To(MatchError(ContainSubstring("failed to restore snapshot snap-1")))
+			Expect(rt.bodies).To(HaveKey("POST /tigera_secure_ee_flows.cluster.fluentd-1/_open"))
+		})
+
+		It("should report how many indices have been restored", func() {
+			rt.responses["GET /_snapshot/tigera-snapshots/snap-1"] = `{"snapshots": [{"snapshot": "snap-1", "indices": [
+				"tigera_secure_ee_flows.cluster.fluentd-1", "tigera_secure_ee_dns.cluster.fluentd-1", "tigera_secure_ee_audit_kube.cluster.fluentd-1"
+			]}]}`
+			rt.responses["GET /tigera_secure_ee_flows.cluster.fluentd-1,tigera_secure_ee_dns.cluster.fluentd-1,tigera_secure_ee_audit_kube.cluster.fluentd-1/_recovery"] = `{
+				"tigera_secure_ee_flows.cluster.fluentd-1": {"shards": [
+					{"type": "SNAPSHOT", "stage": "DONE", "source": {"snapshot": "snap-1"}},
+					{"type": "PEER", "stage": "INDEX", "source": {}}
+				]},
+				"tigera_secure_ee_dns.cluster.fluentd-1": {"shards": [
+					{"type": "SNAPSHOT", "stage": "DONE", "source": {"snapshot": "snap-1"}},
+					{"type": "SNAPSHOT", "stage": "INDEX", "source": {"snapshot": "snap-1"}}
+				]},
+				"tigera_secure_ee_audit_kube.cluster.fluentd-1": {"shards": [
+					{"type": "SNAPSHOT", "stage": "DONE", "source": {"snapshot": "snap-0"}}
+				]}
+			}`
+
+			restored, total, err := eClient.GetRestoreProgress(ctx, "snap-1", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored).To(Equal(1))
+			Expect(total).To(Equal(3))
+		})
+	})
+
+	Context("Upgrade pre-flight checks", func() {
+		var (
+			eClient *esClient
+			rt      *snapshotRoundTripper
+			ctx     context.Context
+		)
+		BeforeEach(func() {
+			rt = &snapshotRoundTripper{responses: map[string]string{}, bodies: map[string]string{}}
+			eClient = mockElasticClient(&http.Client{Transport: rt}, baseURI)
+			ctx = context.Background()
+		})
+
+		It("should report the nodes over the high disk watermark", func() {
+			rt.responses["GET /_cluster/settings"] = `{
+				"persistent": {"cluster.routing.allocation.disk.watermark.high": "80%"},
+				"transient": {},
+				"defaults": {"cluster.routing.allocation.disk.watermark.high": "90%"}
+			}`
+			rt.responses["GET /_nodes/stats/fs"] = `{"nodes": {
+				"a": {"name": "es-0", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 15}}},
+				"b": {"name": "es-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 50}}}
+			}}`
+
+			nodes, err := eClient.GetNodesOverDiskWatermark(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(Equal([]string{"es-0"}))
+		})
+
+		It("should compare the disk usage with each form of watermark", func() {
+			for _, tc := range []struct {
+				watermark        string
+				total, available int64
+				over             bool
+			}{
+				{"90%", 100, 5, true},
+				{"90%", 100, 10, false},
+				{"0.5", 100, 40, true},
+				{"1gb", 4 << 30, 512 << 20, true},
+				{"1gb", 4 << 30, 2 << 30, false},
+			} {
+				over, err := overDiskWatermark(tc.watermark, tc.total, tc.available)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(over).To(Equal(tc.over), tc.watermark)
+			}
+			_, err := overDiskWatermark("lots", 100, 5)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should start a snapshot and report its state", func() {
+			state, err := eClient.GetSnapshotState(ctx, "tigera-pre-upgrade-7.17.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(BeEmpty())
+
+			rt.responses["PUT /_snapshot/tigera-snapshots/tigera-pre-upgrade-7.17.14"] = `{"accepted":true}`
+			Expect(eClient.CreateSnapshot(ctx, "tigera-pre-upgrade-7.17.14", []string{"tigera_secure_ee_*"})).To(Succeed())
+			Expect(rt.bodies["PUT /_snapshot/tigera-snapshots/tigera-pre-upgrade-7.17.14"]).To(MatchJSON(`{
+				"indices": "tigera_secure_ee_*",
+				"include_global_state": false
+			}`))
+
+			rt.responses["GET /_snapshot/tigera-snapshots/tigera-pre-upgrade-7.17.14"] = `{"snapshots": [{"snapshot": "tigera-pre-upgrade-7.17.14", "state": "IN_PROGRESS"}]}`
+			state, err = eClient.GetSnapshotState(ctx, "tigera-pre-upgrade-7.17.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal("IN_PROGRESS"))
+		})
 	})
 })
 
@@ -203,3 +504,28 @@ func mockElasticClient(h *http.Client, url string) *esClient {
 	ecl.client = client
 	return &ecl
 }
+
+// snapshotRoundTripper responds to the requests in responses, keyed by method and path, and records their bodies.
+type snapshotRoundTripper struct {
+	responses map[string]string
+	bodies    map[string]string
+}
+
+func (t *snapshotRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "HEAD" {
+		return &http.Response{StatusCode: 200, Request: req, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	key := req.Method + " " + req.URL.Path
+	body := ""
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		Expect(err).NotTo(HaveOccurred())
+		body = string(b)
+	}
+	resp, ok := t.responses[key]
+	if !ok {
+		return &http.Response{StatusCode: 404, Request: req, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	t.bodies[key] = body
+	return &http.Response{StatusCode: 200, Request: req, Body: io.NopCloser(strings.NewReader(resp))}, nil
+}
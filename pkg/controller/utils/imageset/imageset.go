@@ -25,31 +25,59 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/render"
 )
 
-// ApplyImageSet gets the appropriate ImageSet, validates the ImageSet, and calls ResolveImages
-// passing in the ImageSet on each of the comps.
+// ApplyImageSet gets the appropriate ImageSet, validates the ImageSet and its signature, and calls
+// ResolveImages passing in the ImageSet on each of the comps.
 func ApplyImageSet(ctx context.Context, c client.Client, v operator.ProductVariant, comps ...render.Component) error {
-	imageSet, err := GetImageSet(ctx, c, v)
+	imageSet, err := GetVerifiedImageSet(ctx, c, v)
 	if err != nil {
 		return err
 	}
 
+	return ResolveImages(imageSet, comps...)
+}
+
+// GetVerifiedImageSet finds the ImageSet for the variant, validates it and verifies its signature. It returns nil if
+// there is no ImageSet for the variant.
+func GetVerifiedImageSet(ctx context.Context, c client.Client, v operator.ProductVariant) (*operator.ImageSet, error) {
+	imageSet, err := GetImageSet(ctx, c, v)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = ValidateImageSet(imageSet); err != nil {
-		return err
+		return nil, err
 	}
 
-	return ResolveImages(imageSet, comps...)
+	if err = VerifyImageSet(ctx, c, imageSet); err != nil {
+		return nil, err
+	}
+
+	return imageSet, nil
+}
+
+// AddImageSetWatch adds a watch on ImageSet resources and on the Secret with the keys that they are verified with.
+// The client is used to read the Installation that names the Secret.
+func AddImageSetWatch(c controller.Controller, cli client.Client) error {
+	return AddImageSetWatchWithHandler(c, cli, &handler.EnqueueRequestForObject{})
 }
 
-// Utility function to add a watch on ImageSet resources.
-func AddImageSetWatch(c controller.Controller) error {
-	return c.Watch(&source.Kind{Type: &operator.ImageSet{}}, &handler.EnqueueRequestForObject{})
+func AddImageSetWatchWithHandler(c controller.Controller, cli client.Client, h handler.EventHandler) error {
+	if err := c.Watch(&source.Kind{Type: &operator.ImageSet{}}, h); err != nil {
+		return err
+	}
+	return utils.AddNamedSecretsWatchWithHandler(c, common.OperatorNamespace(), h, func() []string {
+		return verificationSecretNames(context.Background(), cli)
+	})
 }
 
-func getSetName(v operator.ProductVariant) string {
+// SetName returns the name that the ImageSet for the variant must have for the operator to use it.
+func SetName(v operator.ProductVariant) string {
 	if v == operator.TigeraSecureEnterprise {
 		return fmt.Sprintf("enterprise-%s", components.EnterpriseRelease)
 	}
@@ -73,7 +101,7 @@ func GetImageSet(ctx context.Context, cli client.Client, v operator.ProductVaria
 		return nil, nil
 	}
 
-	setName := getSetName(v)
+	setName := SetName(v)
 
 	for _, is := range isl.Items {
 		if is.Name == setName {
@@ -84,6 +112,21 @@ func GetImageSet(ctx context.Context, cli client.Client, v operator.ProductVaria
 	return nil, fmt.Errorf("ImageSets exist but none with the expected name %s", setName)
 }
 
+// GetCanaryImageSet gets the named ImageSet for a canary rollout, and validates and verifies it.
+func GetCanaryImageSet(ctx context.Context, cli client.Client, name string) (*operator.ImageSet, error) {
+	is := &operator.ImageSet{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name}, is); err != nil {
+		return nil, fmt.Errorf("failed to get canary ImageSet %s: %w", name, err)
+	}
+	if err := ValidateImageSet(is); err != nil {
+		return nil, err
+	}
+	if err := VerifyImageSet(ctx, cli, is); err != nil {
+		return nil, err
+	}
+	return is, nil
+}
+
 // ValidateImageSet validates that all the images in an ImageSet are images the operator uses
 // and that the Digest is in an allowed format.
 func ValidateImageSet(is *operator.ImageSet) error {
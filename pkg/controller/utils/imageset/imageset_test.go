@@ -112,4 +112,29 @@ var _ = Describe("imageset tests", func() {
 			Entry("Enterprise variant", operator.TigeraSecureEnterprise),
 		)
 	})
+
+	Context("canary ImageSets", func() {
+		It("should get and validate the named ImageSet", func() {
+			c := fake.NewClientBuilder().WithScheme(kscheme.Scheme).WithObjects(
+				&operator.ImageSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "calico-canary"},
+					Spec:       operator.ImageSetSpec{Images: []operator.Image{{Image: "calico/node", Digest: "sha256:xxxxxxxxx"}}},
+				},
+				&operator.ImageSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "calico-bad-canary"},
+					Spec:       operator.ImageSetSpec{Images: []operator.Image{{Image: "calico/node", Digest: "xxxxxxxxx"}}},
+				},
+			).Build()
+
+			is, err := GetCanaryImageSet(context.Background(), c, "calico-canary")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(is.Spec.Images).To(HaveLen(1))
+
+			_, err = GetCanaryImageSet(context.Background(), c, "calico-bad-canary")
+			Expect(err).To(MatchError(ContainSubstring("bad digest images")))
+
+			_, err = GetCanaryImageSet(context.Background(), c, "missing")
+			Expect(err).To(MatchError(ContainSubstring("failed to get canary ImageSet missing")))
+		})
+	})
 })
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+// SignaturePayload returns the content that the signature of an ImageSet is computed over: one `<image>@<digest>`
+// line per image, sorted by image.
+func SignaturePayload(is *operator.ImageSet) []byte {
+	lines := make([]string, 0, len(is.Spec.Images))
+	for _, img := range is.Spec.Images {
+		lines = append(lines, fmt.Sprintf("%s@%s\n", img.Image, img.Digest))
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, ""))
+}
+
+// VerifyImageSet checks that the ImageSet is signed by one of the keys trusted by the ImageSetVerification of the
+// Installation, with the 'overlay' Installation merged over the 'default' one. If there is no ImageSet, no
+// Installation or verification is not enabled, there is nothing to verify.
+func VerifyImageSet(ctx context.Context, cli client.Client, is *operator.ImageSet) error {
+	if is == nil {
+		return nil
+	}
+	verification, err := getVerification(ctx, cli)
+	if err != nil {
+		return err
+	}
+	if verification == nil {
+		return nil
+	}
+
+	name := verification.PublicKeySecretName
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: common.OperatorNamespace()}, secret); err != nil {
+		return fmt.Errorf("failed to get ImageSet verification Secret %s/%s: %w", common.OperatorNamespace(), name, err)
+	}
+	keys, err := parsePublicKeys(secret)
+	if err != nil {
+		return fmt.Errorf("ImageSet verification Secret %s/%s: %w", common.OperatorNamespace(), name, err)
+	}
+	if err := verifySignature(is, keys); err != nil {
+		return fmt.Errorf("ImageSet %s: %w", is.Name, err)
+	}
+	return nil
+}
+
+// getVerification returns the ImageSetVerification of the Installation, with the 'overlay' Installation merged over
+// the 'default' one, or nil if there is no Installation or verification is not enabled.
+func getVerification(ctx context.Context, cli client.Client) (*operator.ImageSetVerification, error) {
+	_, spec, err := utils.GetInstallation(ctx, cli)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Installation: %w", err)
+	}
+	return spec.ImageSetVerification, nil
+}
+
+// verificationSecretNames returns the name of the Secret with the keys that ImageSets are verified with, if any.
+func verificationSecretNames(ctx context.Context, cli client.Client) []string {
+	verification, err := getVerification(ctx, cli)
+	if err != nil || verification == nil {
+		return nil
+	}
+	return []string{verification.PublicKeySecretName}
+}
+
+// parsePublicKeys returns every PEM encoded public key in the Secret's data, in key order.
+func parsePublicKeys(secret *corev1.Secret) ([]crypto.PublicKey, error) {
+	names := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var keys []crypto.PublicKey
+	for _, n := range names {
+		rest := secret.Data[n]
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "PUBLIC KEY" {
+				continue
+			}
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse public key in %s: %w", n, err)
+			}
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM encoded public keys found")
+	}
+	return keys, nil
+}
+
+// verifySignature checks the ImageSet's signature against each of the keys, succeeding if any of them match.
+// ECDSA and RSA signatures are over the SHA-256 digest of the payload, as written by cosign, while Ed25519
+// signatures are over the payload itself.
+func verifySignature(is *operator.ImageSet, keys []crypto.PublicKey) error {
+	if is.Spec.Signature == "" {
+		return fmt.Errorf("ImageSet is not signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(is.Spec.Signature))
+	if err != nil {
+		return fmt.Errorf("signature is not valid base64: %w", err)
+	}
+
+	payload := SignaturePayload(is)
+	digest := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, digest[:], sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, sig) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("signature does not match the digest list or was not made by a trusted key")
+}
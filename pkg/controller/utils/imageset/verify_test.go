@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+)
+
+func publicKeyPEM(key any) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+var _ = Describe("imageset signature verification", func() {
+	var c client.Client
+	var ctx context.Context
+	var is *operator.ImageSet
+	var ecKey *ecdsa.PrivateKey
+
+	signECDSA := func(key *ecdsa.PrivateKey) string {
+		digest := sha256.Sum256(SignaturePayload(is))
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		Expect(err).NotTo(HaveOccurred())
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(kscheme.Scheme)).NotTo(HaveOccurred())
+		ctx = context.Background()
+
+		var err error
+		ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		is = &operator.ImageSet{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("calico-%s", components.CalicoRelease)},
+			Spec: operator.ImageSetSpec{
+				Images: []operator.Image{
+					{Image: "calico/typha", Digest: "sha256:bbbb"},
+					{Image: "calico/cni", Digest: "sha256:aaaa"},
+				},
+			},
+		}
+		c = fake.NewClientBuilder().WithScheme(kscheme.Scheme).WithObjects(
+			&operator.Installation{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: operator.InstallationSpec{
+					ImageSetVerification: &operator.ImageSetVerification{PublicKeySecretName: "imageset-keys"},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "imageset-keys", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"cosign.pub": publicKeyPEM(&ecKey.PublicKey)},
+			},
+		).Build()
+	})
+
+	It("should sign the sorted digest list", func() {
+		Expect(string(SignaturePayload(is))).To(Equal("calico/cni@sha256:aaaa\ncalico/typha@sha256:bbbb\n"))
+	})
+
+	It("should accept an ImageSet signed by a trusted key", func() {
+		is.Spec.Signature = signECDSA(ecKey)
+		Expect(VerifyImageSet(ctx, c, is)).To(Succeed())
+	})
+
+	It("should reject an unsigned ImageSet", func() {
+		Expect(VerifyImageSet(ctx, c, is)).To(MatchError(ContainSubstring("ImageSet is not signed")))
+	})
+
+	It("should reject an ImageSet whose digests changed after signing", func() {
+		is.Spec.Signature = signECDSA(ecKey)
+		is.Spec.Images[0].Digest = "sha256:cccc"
+		Expect(VerifyImageSet(ctx, c, is)).To(MatchError(ContainSubstring("signature does not match")))
+	})
+
+	It("should reject an ImageSet signed by an untrusted key", func() {
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		is.Spec.Signature = signECDSA(other)
+		Expect(VerifyImageSet(ctx, c, is)).To(MatchError(ContainSubstring("signature does not match")))
+	})
+
+	It("should trust every key in the Secret", func() {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "imageset-keys", Namespace: common.OperatorNamespace()}, secret)).To(Succeed())
+		secret.Data["second.pub"] = publicKeyPEM(pub)
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		is.Spec.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignaturePayload(is)))
+		Expect(VerifyImageSet(ctx, c, is)).To(Succeed())
+	})
+
+	It("should error when the key Secret doesn't exist", func() {
+		Expect(c.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "imageset-keys", Namespace: common.OperatorNamespace()}})).To(Succeed())
+		is.Spec.Signature = signECDSA(ecKey)
+		Expect(VerifyImageSet(ctx, c, is)).To(MatchError(ContainSubstring("failed to get ImageSet verification Secret")))
+	})
+
+	It("should not require a signature when verification is not enabled", func() {
+		inst := &operator.Installation{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, inst)).To(Succeed())
+		inst.Spec.ImageSetVerification = nil
+		Expect(c.Update(ctx, inst)).To(Succeed())
+		Expect(VerifyImageSet(ctx, c, is)).To(Succeed())
+	})
+
+	It("should use the verification set in the overlay Installation", func() {
+		inst := &operator.Installation{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, inst)).To(Succeed())
+		inst.Spec.ImageSetVerification = nil
+		Expect(c.Update(ctx, inst)).To(Succeed())
+		Expect(verificationSecretNames(ctx, c)).To(BeEmpty())
+
+		Expect(c.Create(ctx, &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "overlay"},
+			Spec: operator.InstallationSpec{
+				ImageSetVerification: &operator.ImageSetVerification{PublicKeySecretName: "imageset-keys"},
+			},
+		})).To(Succeed())
+		Expect(verificationSecretNames(ctx, c)).To(Equal([]string{"imageset-keys"}))
+		Expect(VerifyImageSet(ctx, c, is)).To(MatchError(ContainSubstring("ImageSet is not signed")))
+
+		is.Spec.Signature = signECDSA(ecKey)
+		Expect(VerifyImageSet(ctx, c, is)).To(Succeed())
+	})
+
+	It("should refuse to apply an unsigned ImageSet", func() {
+		Expect(c.Create(ctx, is)).To(Succeed())
+		Expect(ApplyImageSet(ctx, c, operator.Calico)).To(MatchError(ContainSubstring("ImageSet is not signed")))
+	})
+})
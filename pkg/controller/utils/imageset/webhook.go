@@ -0,0 +1,54 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageset
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+// Webhook validates ImageSets at admission time with ValidateImageSet and, when the Installation enables it,
+// rejects ImageSets that are not signed by a trusted key.
+type Webhook struct {
+	Client client.Client
+}
+
+func (w *Webhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *Webhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *Webhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *Webhook) validate(ctx context.Context, obj runtime.Object) error {
+	is, ok := obj.(*operator.ImageSet)
+	if !ok {
+		return fmt.Errorf("expected an ImageSet but got %T", obj)
+	}
+	if err := ValidateImageSet(is); err != nil {
+		return err
+	}
+	return VerifyImageSet(ctx, w.Client, is)
+}
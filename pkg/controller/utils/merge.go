@@ -54,6 +54,22 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		inst.ImagePrefix = override.ImagePrefix
 	}
 
+	switch compareFields(inst.ImageOverrides, override.ImageOverrides) {
+	case BOnlySet, Different:
+		inst.ImageOverrides = make([]operatorv1.ImageOverride, len(override.ImageOverrides))
+		copy(inst.ImageOverrides, override.ImageOverrides)
+	}
+
+	switch compareFields(inst.ImageSetVerification, override.ImageSetVerification) {
+	case BOnlySet, Different:
+		inst.ImageSetVerification = override.ImageSetVerification.DeepCopy()
+	}
+
+	switch compareFields(inst.ImageSetCanary, override.ImageSetCanary) {
+	case BOnlySet, Different:
+		inst.ImageSetCanary = override.ImageSetCanary.DeepCopy()
+	}
+
 	switch compareFields(inst.ImagePullSecrets, override.ImagePullSecrets) {
 	case BOnlySet, Different:
 		inst.ImagePullSecrets = make([]v1.LocalObjectReference, len(override.ImagePullSecrets))
@@ -123,6 +139,11 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		override.NodeUpdateStrategy.DeepCopyInto(&inst.NodeUpdateStrategy)
 	}
 
+	switch compareFields(inst.NodeUpgrade, override.NodeUpgrade) {
+	case BOnlySet, Different:
+		inst.NodeUpgrade = override.NodeUpgrade.DeepCopy()
+	}
+
 	switch compareFields(inst.ComponentResources, override.ComponentResources) {
 	case BOnlySet, Different:
 		inst.ComponentResources = make([]operatorv1.ComponentResource, len(override.ComponentResources))
@@ -141,6 +162,11 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		override.CertificateManagement.DeepCopyInto(inst.CertificateManagement)
 	}
 
+	switch compareFields(inst.CertificateRotation, override.CertificateRotation) {
+	case BOnlySet, Different:
+		inst.CertificateRotation = override.CertificateRotation.DeepCopy()
+	}
+
 	switch compareFields(inst.NonPrivileged, override.NonPrivileged) {
 	case BOnlySet, Different:
 		inst.NonPrivileged = override.NonPrivileged
@@ -752,6 +778,11 @@ func mergeTyphaDeployment(cfg, override *operatorv1.TyphaDeployment) *operatorv1
 			out.Strategy = override.Strategy.DeepCopy()
 		}
 
+		switch compareFields(out.Autoscaling, override.Autoscaling) {
+		case BOnlySet, Different:
+			out.Autoscaling = override.Autoscaling.DeepCopy()
+		}
+
 		return out
 	}
 
@@ -97,6 +97,63 @@ var _ = Describe("Installation merge tests", func() {
 		Entry("Both set not matching", "pathx", "pathy", "pathy"),
 	)
 
+	DescribeTable("merge ImageOverrides", func(main, second, expect []opv1.ImageOverride) {
+		m := opv1.InstallationSpec{}
+		s := opv1.InstallationSpec{}
+		if main != nil {
+			m.ImageOverrides = main
+		}
+		if second != nil {
+			s.ImageOverrides = second
+		}
+		inst := OverrideInstallationSpec(m, s)
+		Expect(inst.ImageOverrides).To(ConsistOf(expect))
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}, nil, []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}),
+		Entry("Second only set", nil, []opv1.ImageOverride{{Prefix: "docker.io/", Mirror: "a/"}}, []opv1.ImageOverride{{Prefix: "docker.io/", Mirror: "a/"}}),
+		Entry("Both set equal", []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}, []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}, []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}),
+		Entry("Both set not matching", []opv1.ImageOverride{{Image: "calico/node", Mirror: "a/"}}, []opv1.ImageOverride{{Image: "calico/node", Mirror: "b/"}}, []opv1.ImageOverride{{Image: "calico/node", Mirror: "b/"}}),
+	)
+
+	DescribeTable("merge ImageSetVerification", func(main, second, expect *opv1.ImageSetVerification) {
+		m := opv1.InstallationSpec{}
+		s := opv1.InstallationSpec{}
+		if main != nil {
+			m.ImageSetVerification = main
+		}
+		if second != nil {
+			s.ImageSetVerification = second
+		}
+		inst := OverrideInstallationSpec(m, s)
+		Expect(inst.ImageSetVerification).To(Equal(expect))
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", &opv1.ImageSetVerification{PublicKeySecretName: "a"}, nil, &opv1.ImageSetVerification{PublicKeySecretName: "a"}),
+		Entry("Second only set", nil, &opv1.ImageSetVerification{PublicKeySecretName: "b"}, &opv1.ImageSetVerification{PublicKeySecretName: "b"}),
+		Entry("Both set equal", &opv1.ImageSetVerification{PublicKeySecretName: "a"}, &opv1.ImageSetVerification{PublicKeySecretName: "a"}, &opv1.ImageSetVerification{PublicKeySecretName: "a"}),
+		Entry("Both set not matching", &opv1.ImageSetVerification{PublicKeySecretName: "a"}, &opv1.ImageSetVerification{PublicKeySecretName: "b"}, &opv1.ImageSetVerification{PublicKeySecretName: "b"}),
+	)
+
+	DescribeTable("merge ImageSetCanary", func(main, second, expect *opv1.ImageSetCanary) {
+		m := opv1.InstallationSpec{}
+		s := opv1.InstallationSpec{}
+		if main != nil {
+			m.ImageSetCanary = main
+		}
+		if second != nil {
+			s.ImageSetCanary = second
+		}
+		inst := OverrideInstallationSpec(m, s)
+		Expect(inst.ImageSetCanary).To(Equal(expect))
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}, nil, &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}),
+		Entry("Second only set", nil, &opv1.ImageSetCanary{ImageSet: "b", NodeSelector: map[string]string{"canary": "true"}}, &opv1.ImageSetCanary{ImageSet: "b", NodeSelector: map[string]string{"canary": "true"}}),
+		Entry("Both set equal", &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}, &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}, &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}),
+		Entry("Both set not matching", &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"canary": "true"}}, &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"zone": "a"}}, &opv1.ImageSetCanary{ImageSet: "a", NodeSelector: map[string]string{"zone": "a"}}),
+	)
+
 	DescribeTable("merge imagePullSecrets", func(main, second, expect []v1.LocalObjectReference) {
 		m := opv1.InstallationSpec{}
 		s := opv1.InstallationSpec{}
@@ -594,6 +651,25 @@ var _ = Describe("Installation merge tests", func() {
 		Entry("Both set not matching", &_roll1, &_roll2, &_roll2),
 	)
 
+	DescribeTable("merge NodeUpgrade", func(main, second, expect *opv1.NodeUpgrade) {
+		m := opv1.InstallationSpec{}
+		s := opv1.InstallationSpec{}
+		if main != nil {
+			m.NodeUpgrade = main
+		}
+		if second != nil {
+			s.NodeUpgrade = second
+		}
+		inst := OverrideInstallationSpec(m, s)
+		Expect(inst.NodeUpgrade).To(Equal(expect))
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", &opv1.NodeUpgrade{ZoneLabel: "zone"}, nil, &opv1.NodeUpgrade{ZoneLabel: "zone"}),
+		Entry("Second only set", nil, &opv1.NodeUpgrade{FailurePolicy: opv1.NodeUpgradeFailurePolicyRollback}, &opv1.NodeUpgrade{FailurePolicy: opv1.NodeUpgradeFailurePolicyRollback}),
+		Entry("Both set equal", &opv1.NodeUpgrade{ZoneLabel: "zone"}, &opv1.NodeUpgrade{ZoneLabel: "zone"}, &opv1.NodeUpgrade{ZoneLabel: "zone"}),
+		Entry("Both set not matching", &opv1.NodeUpgrade{ZoneLabel: "zone"}, &opv1.NodeUpgrade{ZoneLabel: "rack"}, &opv1.NodeUpgrade{ZoneLabel: "rack"}),
+	)
+
 	_nodeComp := opv1.ComponentResource{
 		ComponentName: opv1.ComponentNameNode,
 		ResourceRequirements: &v1.ResourceRequirements{
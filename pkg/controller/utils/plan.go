@@ -0,0 +1,358 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	// PlanModeAnnotation enables plan mode when it is set to PlanModeEnabled on the default Installation. While plan
+	// mode is enabled the component handler does not create, update or delete any objects. Instead, the changes it
+	// would have made are recorded in an OperatorPlan per component so that they can be reviewed before plan mode is
+	// turned off and the changes are rolled out. The Installation controller also holds back its other writes, such as
+	// Installation snapshots, rollbacks, its status and Typha scaling, and deletes the plans once plan mode is turned off.
+	PlanModeAnnotation = "operator.tigera.io/plan-mode"
+	PlanModeEnabled    = "Enabled"
+)
+
+var planNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// IsPlanModeEnabled returns true if plan mode has been enabled on the given Installation.
+func IsPlanModeEnabled(installation *operatorv1.Installation) bool {
+	return installation != nil && installation.Annotations[PlanModeAnnotation] == PlanModeEnabled
+}
+
+// planState is the plan mode state of a handler. Handlers are created for each reconcile, so the Installation is read
+// at most once per reconcile, and not at all if the handler was given the Installation.
+type planState struct {
+	once    sync.Once
+	enabled bool
+	err     error
+
+	// planned counts the components of each type that have been planned, so that a controller that renders the same
+	// component type more than once (e.g. a passthrough) gets a plan for each of them.
+	planned map[string]int
+}
+
+func newPlanState(cr metav1.Object) *planState {
+	p := &planState{planned: map[string]int{}}
+	if installation, ok := cr.(*operatorv1.Installation); ok {
+		p.once.Do(func() { p.enabled = IsPlanModeEnabled(installation) })
+	}
+	return p
+}
+
+// planModeEnabled returns true if plan mode has been enabled on the default Installation.
+func (c componentHandler) planModeEnabled(ctx context.Context) (bool, error) {
+	c.plan.once.Do(func() {
+		instance := &operatorv1.Installation{}
+		if err := c.client.Get(ctx, DefaultInstanceKey, instance); err != nil {
+			if !errors.IsNotFound(err) && !runtime.IsNotRegisteredError(err) && !meta.IsNoMatchError(err) {
+				c.plan.err = err
+			}
+			return
+		}
+		c.plan.enabled = IsPlanModeEnabled(instance)
+	})
+	return c.plan.enabled, c.plan.err
+}
+
+// DeleteOperatorPlans deletes all of the OperatorPlans. It is used once plan mode has been disabled, as the plans
+// recorded while it was enabled no longer describe the changes that the operator makes.
+func DeleteOperatorPlans(ctx context.Context, cli client.Client) error {
+	plans := &operatorv1.OperatorPlanList{}
+	if err := cli.List(ctx, plans); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for i := range plans.Items {
+		if err := cli.Delete(ctx, &plans.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// planComponent computes the changes that CreateOrUpdateOrDelete would make for the component and records them in
+// the component's OperatorPlan. Nothing else is written to the cluster.
+func (c componentHandler) planComponent(ctx context.Context, component render.Component) error {
+	objsToCreate, objsToDelete := component.Objects()
+	osType := component.SupportedOSType()
+
+	changes := []operatorv1.PlannedChange{}
+	for _, obj := range objsToCreate {
+		change, err := c.planObject(ctx, obj.DeepCopyObject().(client.Object), osType)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, obj := range objsToDelete {
+		cur := obj.DeepCopyObject().(client.Object)
+		if err := c.client.Get(ctx, client.ObjectKeyFromObject(obj), cur); err != nil {
+			if errors.IsNotFound(err) {
+				// Nothing to delete.
+				continue
+			}
+			return err
+		}
+		changes = append(changes, c.plannedChange(operatorv1.PlannedActionDelete, obj))
+	}
+
+	return c.writePlan(ctx, component, changes)
+}
+
+// planObject returns the change that createOrUpdateObject would make for the object, or nil if the object is
+// up to date.
+func (c componentHandler) planObject(ctx context.Context, obj client.Object, osType rmeta.OSType) (*operatorv1.PlannedChange, error) {
+	if err := c.prepareObject(obj, osType); err != nil {
+		return nil, err
+	}
+
+	cur := obj.DeepCopyObject().(client.Object)
+	if err := c.client.Get(ctx, client.ObjectKeyFromObject(obj), cur); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		change := c.plannedChange(operatorv1.PlannedActionCreate, obj)
+		return &change, nil
+	}
+
+	if IgnoreObject(cur) {
+		return nil, nil
+	}
+
+	mobj := mergeState(obj, cur)
+	if mobj == nil {
+		return nil, nil
+	}
+	patch, err := MergePatch(cur, mobj)
+	if err != nil {
+		return nil, err
+	}
+	if patch == "" {
+		return nil, nil
+	}
+	change := c.plannedChange(operatorv1.PlannedActionUpdate, obj)
+	change.Patch = patch
+	return &change, nil
+}
+
+func (c componentHandler) plannedChange(action operatorv1.PlannedAction, obj client.Object) operatorv1.PlannedChange {
+	gvk := c.objectKind(obj)
+	return operatorv1.PlannedChange{
+		Action:     action,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+func (c componentHandler) objectKind(obj runtime.Object) schema.GroupVersionKind {
+	if c.scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+			return gvk
+		}
+	}
+	return obj.GetObjectKind().GroupVersionKind()
+}
+
+// writePlan creates or updates the OperatorPlan for the component with the given changes. The plan is owned by the
+// handler's custom resource so that it is cleaned up along with it.
+func (c componentHandler) writePlan(ctx context.Context, component render.Component, changes []operatorv1.PlannedChange) error {
+	plan := &operatorv1.OperatorPlan{ObjectMeta: metav1.ObjectMeta{Name: c.planName(component)}}
+	if err := c.client.Get(ctx, client.ObjectKeyFromObject(plan), plan); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if c.cr != nil && !skipAddingOwnerReference(c.cr, plan) {
+			if err := controllerutil.SetControllerReference(c.cr, plan, c.scheme); err != nil {
+				return err
+			}
+		}
+		if err := c.client.Create(ctx, plan); err != nil {
+			return err
+		}
+	}
+
+	plan.Status = operatorv1.OperatorPlanStatus{
+		Component: reflect.TypeOf(component).String(),
+		Changes:   changes,
+	}
+	return c.client.Status().Update(ctx, plan)
+}
+
+// planName returns the name of the OperatorPlan for the component. It is made up of the name of the controller that
+// owns the handler, the kind and, for a namespaced resource such as a tenant's, the namespace of the handler's custom
+// resource and the component type. A component type that the handler has already planned is suffixed with the number
+// of times it has been planned.
+func (c componentHandler) planName(component render.Component) string {
+	parts := []string{}
+	if c.controller != "" {
+		parts = append(parts, c.controller)
+	}
+	if o, ok := c.cr.(runtime.Object); ok {
+		if kind := c.objectKind(o).Kind; kind != "" {
+			parts = append(parts, kind)
+		}
+	}
+	if c.cr != nil && c.cr.GetNamespace() != "" {
+		parts = append(parts, c.cr.GetNamespace())
+	}
+
+	t := reflect.TypeOf(component)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	parts = append(parts, path.Base(t.PkgPath()), t.Name())
+
+	key := strings.Join(parts, "-")
+	c.plan.planned[key]++
+	if n := c.plan.planned[key]; n > 1 {
+		parts = append(parts, strconv.Itoa(n))
+	}
+
+	name := planNameInvalidChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	return strings.Trim(name, "-")
+}
+
+// MergePatch returns a JSON merge patch of the fields that updating current to desired would set. Fields that are
+// present on current but not on desired are not included, since those are usually defaulted by the API server and
+// are defaulted again on update.
+func MergePatch(current, desired client.Object) (string, error) {
+	cur, err := planFields(current)
+	if err != nil {
+		return "", err
+	}
+	des, err := planFields(desired)
+	if err != nil {
+		return "", err
+	}
+
+	patch := diffFields(cur, des)
+	if len(patch) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// planFields converts the object to a map, dropping the status and the metadata that is managed by the API server.
+func planFields(obj client.Object) (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "apiVersion")
+	delete(fields, "kind")
+	delete(fields, "status")
+	if md, ok := fields["metadata"].(map[string]interface{}); ok {
+		for _, f := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink"} {
+			delete(md, f)
+		}
+		if labels, ok := md["labels"].(map[string]interface{}); ok {
+			delete(labels, common.MultipleOwnersLabel)
+		}
+	}
+	return fields, nil
+}
+
+// diffFields returns the fields of desired that are missing from or different in current.
+func diffFields(current, desired map[string]interface{}) map[string]interface{} {
+	diff := map[string]interface{}{}
+	for k, dv := range desired {
+		cv, ok := current[k]
+		if ok && subsetOf(dv, cv) {
+			continue
+		}
+		dm, dok := dv.(map[string]interface{})
+		cm, cok := cv.(map[string]interface{})
+		if dok && cok {
+			if d := diffFields(cm, dm); len(d) > 0 {
+				diff[k] = d
+			}
+			continue
+		}
+		if dv == nil || (dok && len(dm) == 0) {
+			continue
+		}
+		diff[k] = dv
+	}
+	return diff
+}
+
+// subsetOf returns true if every field set in desired has the same value in current. Lists must be the same length,
+// and their elements are compared in order.
+func subsetOf(desired, current interface{}) bool {
+	switch d := desired.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && current == nil
+		}
+		for k, dv := range d {
+			if !subsetOf(dv, c[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok || len(c) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !subsetOf(d[i], c[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, current)
+	}
+}
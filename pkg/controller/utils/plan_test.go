@@ -0,0 +1,294 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+var _ = Describe("Plan mode tests", func() {
+	var (
+		c        client.Client
+		scheme   *runtime.Scheme
+		ctx      context.Context
+		instance *operatorv1.Installation
+		handler  ComponentHandler
+	)
+
+	getPlan := func() *operatorv1.OperatorPlan {
+		plans := &operatorv1.OperatorPlanList{}
+		Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+		Expect(plans.Items).To(HaveLen(1))
+		return &plans.Items[0]
+	}
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(apps.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+
+		instance = &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "default",
+				Annotations: map[string]string{PlanModeAnnotation: PlanModeEnabled},
+			},
+		}
+		Expect(c.Create(ctx, instance)).NotTo(HaveOccurred())
+		handler = NewComponentHandler(logf.Log.WithName("test_plan_logger"), c, scheme, instance)
+	})
+
+	It("reports whether plan mode is enabled", func() {
+		Expect(IsPlanModeEnabled(instance)).To(BeTrue())
+		Expect(IsPlanModeEnabled(&operatorv1.Installation{})).To(BeFalse())
+		Expect(IsPlanModeEnabled(nil)).To(BeFalse())
+	})
+
+	It("uses the Installation it was given", func() {
+		// The Installation in the cluster doesn't enable plan mode, but the handler was given one that does.
+		Expect(c.Delete(ctx, instance)).NotTo(HaveOccurred())
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		err := c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("reads the Installation once for other custom resources", func() {
+		ls := &operatorv1.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}}
+		Expect(c.Create(ctx, ls)).NotTo(HaveOccurred())
+		handler = NewComponentHandler(logf.Log.WithName("test_plan_logger"), c, scheme, ls)
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		err := c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// Disabling plan mode takes effect for the next handler, i.e. on the next reconcile.
+		instance.Annotations = nil
+		Expect(c.Update(ctx, instance)).NotTo(HaveOccurred())
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		err = c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		handler = NewComponentHandler(logf.Log.WithName("test_plan_logger"), c, scheme, ls)
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})).NotTo(HaveOccurred())
+	})
+
+	It("names plans after the controller and the component", func() {
+		handler.SetController("test-controller")
+		first := render.NewPassthrough(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}})
+		second := render.NewPassthrough(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default"}})
+		Expect(handler.CreateOrUpdateOrDelete(ctx, first, nil)).NotTo(HaveOccurred())
+		Expect(handler.CreateOrUpdateOrDelete(ctx, second, nil)).NotTo(HaveOccurred())
+
+		plans := &operatorv1.OperatorPlanList{}
+		Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+		names := map[string]string{}
+		for _, p := range plans.Items {
+			Expect(p.Status.Changes).To(HaveLen(1))
+			names[p.Name] = p.Status.Changes[0].Name
+		}
+		Expect(names).To(Equal(map[string]string{
+			"test-controller-installation-render-passthroughcomponent":   "first",
+			"test-controller-installation-render-passthroughcomponent-2": "second",
+		}))
+	})
+
+	It("names the plans of namespaced resources after their namespace", func() {
+		// The OperatorPlans are cluster scoped, so each tenant's resources are planned separately.
+		for _, ns := range []string{"tenant-a", "tenant-b"} {
+			m := &operatorv1.Manager{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure", Namespace: ns}}
+			Expect(c.Create(ctx, m)).NotTo(HaveOccurred())
+			handler = NewComponentHandler(logf.Log.WithName("test_plan_logger"), c, scheme, m)
+			handler.SetController("test-controller")
+			cm := render.NewPassthrough(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: ns}})
+			Expect(handler.CreateOrUpdateOrDelete(ctx, cm, nil)).NotTo(HaveOccurred())
+		}
+
+		plans := &operatorv1.OperatorPlanList{}
+		Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+		names := map[string]string{}
+		for _, p := range plans.Items {
+			Expect(p.Status.Changes).To(HaveLen(1))
+			names[p.Name] = p.Status.Changes[0].Namespace
+		}
+		Expect(names).To(Equal(map[string]string{
+			"test-controller-manager-tenant-a-render-passthroughcomponent": "tenant-a",
+			"test-controller-manager-tenant-b-render-passthroughcomponent": "tenant-b",
+		}))
+	})
+
+	It("records creates without creating the objects", func() {
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+
+		err := c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		plan := getPlan()
+		Expect(plan.Name).To(Equal("installation-utils-fakecomponent"))
+		Expect(plan.OwnerReferences).To(HaveLen(1))
+		Expect(plan.OwnerReferences[0].Kind).To(Equal("Installation"))
+		Expect(plan.Status.Component).To(Equal("*utils.fakeComponent"))
+		Expect(plan.Status.Changes).To(ConsistOf(operatorv1.PlannedChange{
+			Action:     operatorv1.PlannedActionCreate,
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "default",
+			Name:       "test-cm",
+		}))
+	})
+
+	It("deletes the plans recorded while plan mode was enabled", func() {
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(getPlan()).NotTo(BeNil())
+
+		Expect(DeleteOperatorPlans(ctx, c)).NotTo(HaveOccurred())
+		plans := &operatorv1.OperatorPlanList{}
+		Expect(c.List(ctx, plans)).NotTo(HaveOccurred())
+		Expect(plans.Items).To(BeEmpty())
+	})
+
+	It("records the fields that an update would change", func() {
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default", Labels: map[string]string{"user": "label"}},
+			Data:       map[string]string{"a": "1", "b": "2"},
+		})).NotTo(HaveOccurred())
+
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+					Data:       map[string]string{"a": "1", "b": "3"},
+				},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data["b"]).To(Equal("2"))
+
+		plan := getPlan()
+		Expect(plan.Status.Changes).To(HaveLen(1))
+		change := plan.Status.Changes[0]
+		Expect(change.Action).To(Equal(operatorv1.PlannedActionUpdate))
+		Expect(change.Kind).To(Equal("ConfigMap"))
+		Expect(change.Patch).To(MatchJSON(`{"data":{"b":"3"},"metadata":{"ownerReferences":[{"apiVersion":"operator.tigera.io/v1","blockOwnerDeletion":true,"controller":true,"kind":"Installation","name":"default","uid":""}]}}`))
+
+		// Once the object matches, there is nothing left to change.
+		cm.Data["b"] = "3"
+		cm.OwnerReferences = plan.OwnerReferences
+		Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+		handler = NewComponentHandler(logf.Log.WithName("test_plan_logger"), c, scheme, instance)
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, nil)).NotTo(HaveOccurred())
+		Expect(getPlan().Status.Changes).To(BeEmpty())
+	})
+
+	It("records deletes only for objects that exist", func() {
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+		})).NotTo(HaveOccurred())
+
+		component := render.NewDeletionPassthrough(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing-cm", Namespace: "default"}},
+		)
+		Expect(handler.CreateOrUpdateOrDelete(ctx, component, nil)).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-cm", Namespace: "default"}, &corev1.ConfigMap{})).NotTo(HaveOccurred())
+
+		plan := getPlan()
+		Expect(plan.Status.Changes).To(ConsistOf(operatorv1.PlannedChange{
+			Action:     operatorv1.PlannedActionDelete,
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "default",
+			Name:       "test-cm",
+		}))
+	})
+
+	It("does not report fields that are only set on the current object", func() {
+		cur := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "c", "image": "a", "terminationMessagePath": "/dev/termination-log"},
+				},
+				"dnsPolicy": "ClusterFirst",
+			},
+		}
+		desired := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "c", "image": "a"},
+				},
+			},
+		}
+		Expect(diffFields(cur, desired)).To(BeEmpty())
+
+		desired["spec"].(map[string]interface{})["containers"] = []interface{}{
+			map[string]interface{}{"name": "c", "image": "b"},
+		}
+		Expect(diffFields(cur, desired)).To(Equal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "c", "image": "b"},
+				},
+			},
+		}))
+	})
+})
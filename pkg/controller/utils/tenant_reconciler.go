@@ -0,0 +1,55 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SerializeTenantReconciles wraps the reconciler of a controller that reconciles each tenant's namespace on its own, so
+// that it never runs two reconciles for the same namespace at once. The work queue only keeps a request from being
+// processed twice at once, and a tenant is queued under the name of each of its objects that changed, so without this
+// a controller that runs several reconciles at once would reconcile the same tenant in parallel.
+func SerializeTenantReconciles(r reconcile.Reconciler) reconcile.Reconciler {
+	return &tenantSerializedReconciler{Reconciler: r, locks: map[string]*sync.Mutex{}}
+}
+
+type tenantSerializedReconciler struct {
+	reconcile.Reconciler
+
+	lock  sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func (r *tenantSerializedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	l := r.namespaceLock(request.Namespace)
+	l.Lock()
+	defer l.Unlock()
+	return r.Reconciler.Reconcile(ctx, request)
+}
+
+func (r *tenantSerializedReconciler) namespaceLock(namespace string) *sync.Mutex {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	l, ok := r.locks[namespace]
+	if !ok {
+		l = &sync.Mutex{}
+		r.locks[namespace] = l
+	}
+	return l
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tigera/operator/pkg/controller/options"
+)
+
+// tenantStateReconciler keeps unsynchronized state per namespace, so that reconciles of the same namespace running at
+// once are caught when the tests are run with -race.
+type tenantStateReconciler struct {
+	reconciles  map[string]*int
+	inFlight    map[string]*int32
+	maxInFlight int32
+	running     int32
+	maxRunning  int32
+}
+
+func (r *tenantStateReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	n := atomic.AddInt32(r.inFlight[request.Namespace], 1)
+	defer atomic.AddInt32(r.inFlight[request.Namespace], -1)
+	storeMax(&r.maxInFlight, n)
+	running := atomic.AddInt32(&r.running, 1)
+	defer atomic.AddInt32(&r.running, -1)
+	storeMax(&r.maxRunning, running)
+
+	*r.reconciles[request.Namespace]++
+	time.Sleep(time.Millisecond)
+	return reconcile.Result{}, nil
+}
+
+func storeMax(max *int32, n int32) {
+	for {
+		cur := atomic.LoadInt32(max)
+		if n <= cur || atomic.CompareAndSwapInt32(max, cur, n) {
+			return
+		}
+	}
+}
+
+var _ = Describe("SerializeTenantReconciles", func() {
+	It("never reconciles a namespace twice at once but reconciles different namespaces in parallel", func() {
+		namespaces := []string{"tenant-a", "tenant-b", "tenant-c"}
+		inner := &tenantStateReconciler{reconciles: map[string]*int{}, inFlight: map[string]*int32{}}
+		for _, ns := range namespaces {
+			inner.reconciles[ns] = new(int)
+			inner.inFlight[ns] = new(int32)
+		}
+		r := SerializeTenantReconciles(inner)
+
+		// Reconcile each namespace from several workers at once, under the names of different objects, as the work
+		// queue of a controller with several workers would.
+		const perNamespace = 20
+		var wg sync.WaitGroup
+		for _, ns := range namespaces {
+			for i := 0; i < perNamespace; i++ {
+				wg.Add(1)
+				go func(ns string, i int) {
+					defer wg.Done()
+					defer GinkgoRecover()
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: fmt.Sprintf("object-%d", i)}}
+					_, err := r.Reconcile(context.Background(), req)
+					Expect(err).NotTo(HaveOccurred())
+				}(ns, i)
+			}
+		}
+		wg.Wait()
+
+		Expect(atomic.LoadInt32(&inner.maxInFlight)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&inner.maxRunning)).To(BeNumerically(">", 1))
+		for _, ns := range namespaces {
+			Expect(*inner.reconciles[ns]).To(Equal(perNamespace))
+		}
+	})
+})
+
+var _ = DescribeTable("MaxConcurrentTenantReconciles",
+	func(opts options.AddOptions, expected int) {
+		Expect(opts.MaxConcurrentTenantReconciles()).To(Equal(expected))
+	},
+	Entry("single-tenant mode", options.AddOptions{MaxConcurrentReconciles: 5}, 1),
+	Entry("multi-tenant mode", options.AddOptions{MultiTenant: true, MaxConcurrentReconciles: 5}, 5),
+	Entry("multi-tenant mode without a setting", options.AddOptions{MultiTenant: true}, 1),
+)
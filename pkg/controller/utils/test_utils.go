@@ -23,7 +23,9 @@ import (
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/controller/status"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -57,3 +59,38 @@ func ExpectWaitForWatch(ctx context.Context, r reconcile.Reconciler, mockStatus
 	Expect(err).ShouldNot(HaveOccurred())
 	mockStatus.AssertExpectations(GinkgoT())
 }
+
+// ApplyClient wraps a client that does not implement server-side apply, e.g. the controller-runtime fake client, for
+// tests that reconcile components which are written with server-side apply. Apply patches are emulated by creating the
+// object if it does not exist and otherwise replacing it, apart from its managed fields. The applied objects and the
+// options of each apply are recorded, and Errs, if set, are returned by the next applies instead.
+type ApplyClient struct {
+	client.Client
+	Applied []client.Object
+	Opts    [][]client.PatchOption
+	Errs    []error
+}
+
+func (c *ApplyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	c.Applied = append(c.Applied, obj.DeepCopyObject().(client.Object))
+	c.Opts = append(c.Opts, opts)
+	if len(c.Errs) > 0 {
+		err := c.Errs[0]
+		c.Errs = c.Errs[1:]
+		return err
+	}
+
+	cur := obj.DeepCopyObject().(client.Object)
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), cur); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return c.Client.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(cur.GetResourceVersion())
+	obj.SetManagedFields(cur.GetManagedFields())
+	return c.Client.Update(ctx, obj)
+}
@@ -100,6 +100,12 @@ func IgnoreObject(obj runtime.Object) bool {
 	return false
 }
 
+// IsPaused returns true if reconciliation of the component that owns the given CR has been paused by the user.
+// See common.IsPaused.
+func IsPaused(obj metav1.Object) bool {
+	return common.IsPaused(obj)
+}
+
 func AddInstallationWatch(c controller.Controller) error {
 	return c.Watch(&source.Kind{Type: &operatorv1.Installation{}}, &handler.EnqueueRequestForObject{})
 }
@@ -136,6 +142,26 @@ func AddSecretsWatchWithHandler(c controller.Controller, name, namespace string,
 	return AddNamespacedWatch(c, s, h, metaMatches...)
 }
 
+// AddNamedSecretsWatch watches the secrets in the namespace whose names are returned by names. It is called for each
+// event, so that secrets that are named in a CR can be watched without reconciling for every other secret.
+func AddNamedSecretsWatch(c controller.Controller, namespace string, names func() []string) error {
+	return AddNamedSecretsWatchWithHandler(c, namespace, &handler.EnqueueRequestForObject{}, names)
+}
+
+func AddNamedSecretsWatchWithHandler(c controller.Controller, namespace string, h handler.EventHandler, names func() []string) error {
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, h, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		if obj.GetNamespace() != namespace {
+			return false
+		}
+		for _, name := range names() {
+			if name == obj.GetName() {
+				return true
+			}
+		}
+		return false
+	}))
+}
+
 func AddConfigMapWatch(c controller.Controller, name, namespace string, h handler.EventHandler) error {
 	cm := &corev1.ConfigMap{
 		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "V1"},
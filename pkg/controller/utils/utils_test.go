@@ -432,3 +432,17 @@ var _ = Describe("CreatePredicateForObject", func() {
 		})
 	})
 })
+
+var _ = Describe("IsPaused", func() {
+	It("should only pause CRs with the paused annotation that are not being deleted", func() {
+		obj := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+		Expect(IsPaused(obj)).To(BeFalse())
+
+		obj.Annotations = map[string]string{common.PausedAnnotation: "true"}
+		Expect(IsPaused(obj)).To(BeTrue())
+
+		now := metav1.Now()
+		obj.DeletionTimestamp = &now
+		Expect(IsPaused(obj)).To(BeFalse())
+	})
+})
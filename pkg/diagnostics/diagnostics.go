@@ -0,0 +1,543 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diagnostics collects the state of the operator and the objects it manages into a support bundle.
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/active"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/installation"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/offline"
+)
+
+const redacted = "REDACTED"
+
+// Collect gathers the operator.tigera.io custom resources, the TigeraStatus conditions, the ImageSet resolution, the
+// operator's ConfigMaps, the objects the operator would render along with how they differ from the cluster, and any
+// failing pods, and writes them to a gzipped tarball in dir. The path of the tarball is returned. Problems collecting
+// individual items are recorded in the bundle's errors.txt rather than returned, so that as much as possible is
+// collected from a broken cluster.
+func Collect(ctx context.Context, cli client.Client, scheme *runtime.Scheme, dir string) (string, error) {
+	b := &bundle{files: map[string][]byte{}}
+
+	b.collectCustomResources(ctx, cli, scheme)
+	b.collectTigeraStatus(ctx, cli)
+	b.collectConfigMap(ctx, cli, active.ActiveConfigMapName)
+	b.collectConfigMap(ctx, cli, common.BootstrapConfigMapName)
+	b.collectRendered(ctx, cli, scheme)
+	b.collectPodFailures(ctx, cli)
+
+	if len(b.errors) > 0 {
+		b.add("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n"))
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("tigera-operator-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102-150405")))
+	if err := b.write(path); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// renderedLimits describes what the rendered objects and their diff cover. It is written to the bundle alongside them
+// so that the absence of an object from them isn't mistaken for the operator not managing it.
+const renderedLimits = `The rendered objects and their differences from the cluster are computed in the same way as "operator render",
+which only supports a subset of the operator's controllers:
+
+- For the Installation, only the namespaces, certificates, Typha, calico-node, CSI and kube-controllers components of
+  the core controller are rendered.
+- The APIServer is rendered.
+- No other custom resource is rendered. The objects their controllers manage are not in objects.yaml or diff.txt, but
+  the custom resources themselves are collected under custom-resources/.
+- As the FelixConfiguration is not read, the default Felix health and node reporter ports are used.
+- Certificates that are not in the cluster are generated, so they differ from the ones the operator would create.
+`
+
+// renderedKinds are the kinds of custom resource that collectRendered renders, or that are only inputs to it.
+var renderedKinds = map[string]bool{
+	"Installation": true,
+	"APIServer":    true,
+	"ImageSet":     true,
+	"TigeraStatus": true,
+}
+
+// bundle holds the contents of the files that are written to the tarball, keyed by their path in the tarball.
+type bundle struct {
+	files  map[string][]byte
+	errors []string
+
+	// notRendered lists the custom resources in the cluster whose objects are not rendered.
+	notRendered []string
+}
+
+func (b *bundle) add(name string, data []byte) {
+	b.files[name] = data
+}
+
+func (b *bundle) errorf(format string, args ...interface{}) {
+	b.errors = append(b.errors, fmt.Sprintf(format, args...))
+}
+
+// addYAML adds the objects to the bundle as a multi-document YAML stream.
+func (b *bundle) addYAML(name string, objs ...interface{}) {
+	buf := &bytes.Buffer{}
+	for i, obj := range objs {
+		y, err := yaml.Marshal(obj)
+		if err != nil {
+			b.errorf("failed to marshal %s: %v", name, err)
+			return
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(y)
+	}
+	b.add(name, buf.Bytes())
+}
+
+// write writes the files to a gzipped tarball at path, in name order.
+func (b *bundle) write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		data := b.files[name]
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// collectCustomResources adds every custom resource in the operator.tigera.io group, one file per kind.
+func (b *bundle) collectCustomResources(ctx context.Context, cli client.Client, scheme *runtime.Scheme) {
+	var listGVKs []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.Group == operatorv1.GroupVersion.Group && strings.HasSuffix(gvk.Kind, "List") {
+			listGVKs = append(listGVKs, gvk)
+		}
+	}
+	sort.Slice(listGVKs, func(i, j int) bool { return listGVKs[i].String() < listGVKs[j].String() })
+
+	for _, listGVK := range listGVKs {
+		gvk := listGVK.GroupVersion().WithKind(strings.TrimSuffix(listGVK.Kind, "List"))
+		obj, err := scheme.New(listGVK)
+		if err != nil {
+			b.errorf("failed to create list for %s: %v", gvk, err)
+			continue
+		}
+		list, ok := obj.(client.ObjectList)
+		if !ok {
+			continue
+		}
+		if err := cli.List(ctx, list); err != nil {
+			// The CRDs for some kinds are only installed for some variants.
+			if !meta.IsNoMatchError(err) {
+				b.errorf("failed to list %s: %v", gvk, err)
+			}
+			continue
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			b.errorf("failed to extract %s items: %v", gvk, err)
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		var objs []interface{}
+		for _, item := range items {
+			item.GetObjectKind().SetGroupVersionKind(gvk)
+			if o, ok := item.(client.Object); ok {
+				o.SetManagedFields(nil)
+				if !renderedKinds[gvk.Kind] {
+					b.notRendered = append(b.notRendered, fmt.Sprintf("%s %s", gvk.Kind, objectName(o)))
+				}
+			}
+			objs = append(objs, item)
+		}
+		b.addYAML(fmt.Sprintf("custom-resources/%s/%s.yaml", gvk.Version, strings.ToLower(gvk.Kind)), objs...)
+	}
+}
+
+// collectTigeraStatus adds a summary of the conditions of every TigeraStatus.
+func (b *bundle) collectTigeraStatus(ctx context.Context, cli client.Client) {
+	list := &operatorv1.TigeraStatusList{}
+	if err := cli.List(ctx, list); err != nil {
+		b.errorf("failed to list TigeraStatus: %v", err)
+		return
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+
+	buf := &bytes.Buffer{}
+	for _, ts := range list.Items {
+		fmt.Fprintf(buf, "%s:\n", ts.Name)
+		for _, c := range ts.Status.Conditions {
+			fmt.Fprintf(buf, "  %s=%s since %s reason=%q message=%q\n",
+				c.Type, c.Status, c.LastTransitionTime.UTC().Format(time.RFC3339), c.Reason, c.Message)
+		}
+	}
+	b.add("tigerastatus.txt", buf.Bytes())
+}
+
+// collectConfigMap adds the named ConfigMap from the operator namespace, if it exists.
+func (b *bundle) collectConfigMap(ctx context.Context, cli client.Client, name string) {
+	cm := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: common.OperatorNamespace()}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			b.errorf("ConfigMap %s/%s does not exist", common.OperatorNamespace(), name)
+		} else {
+			b.errorf("failed to get ConfigMap %s/%s: %v", common.OperatorNamespace(), name, err)
+		}
+		return
+	}
+	cm.TypeMeta = metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"}
+	cm.SetManagedFields(nil)
+	b.addYAML(fmt.Sprintf("configmaps/%s.yaml", name), cm)
+}
+
+// imageResolution describes which ImageSet applies to the Installation and the images the rendered objects use.
+type imageResolution struct {
+	Variant    operatorv1.ProductVariant `json:"variant"`
+	ImageSet   string                    `json:"imageSet,omitempty"`
+	Validation string                    `json:"validation,omitempty"`
+	Images     []string                  `json:"images"`
+}
+
+// collectRendered renders the custom resources in the cluster in the same way as the controllers would, and adds
+// the rendered objects, the ImageSet resolution and the difference between each object and its live counterpart.
+// Certificates that are not in the cluster are generated, and secret data is redacted. The limits of the rendering,
+// and the custom resources in the cluster that aren't rendered, are recorded in rendered/README.txt.
+func (b *bundle) collectRendered(ctx context.Context, cli client.Client, scheme *runtime.Scheme) {
+	readme := &bytes.Buffer{}
+	readme.WriteString(renderedLimits)
+	if len(b.notRendered) > 0 {
+		readme.WriteString("\nThe following custom resources in the cluster are not rendered:\n\n")
+		for _, name := range b.notRendered {
+			fmt.Fprintf(readme, "- %s\n", name)
+		}
+	}
+	b.add("rendered/README.txt", readme.Bytes())
+
+	instance := &operatorv1.Installation{}
+	if err := cli.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		b.errorf("failed to get Installation: %v", err)
+		return
+	}
+
+	// Compute the Installation that the core controller renders from: the defaulted Installation with the
+	// 'overlay' Installation merged over it.
+	if err := installation.FillDefaults(instance); err != nil {
+		b.errorf("invalid Installation: %v", err)
+		return
+	}
+	overlay := &operatorv1.Installation{}
+	if err := cli.Get(ctx, utils.OverlayInstanceKey, overlay); err == nil {
+		if err := installation.MergeOverlay(instance, overlay); err != nil {
+			b.errorf("invalid Installation: %v", err)
+			return
+		}
+	} else if !apierrors.IsNotFound(err) {
+		b.errorf("failed to get overlay Installation: %v", err)
+		return
+	}
+	objs := []client.Object{instance}
+
+	apiServer := &operatorv1.APIServer{}
+	if err := cli.Get(ctx, utils.DefaultInstanceKey, apiServer); err == nil {
+		objs = append(objs, apiServer)
+	} else if !apierrors.IsNotFound(err) {
+		b.errorf("failed to get APIServer: %v", err)
+	}
+
+	// The CA and any pull secrets are read from the operator namespace, as they would be by the controllers.
+	imageSets := &operatorv1.ImageSetList{}
+	secrets := &corev1.SecretList{}
+	configMaps := &corev1.ConfigMapList{}
+	if err := cli.List(ctx, imageSets); err != nil {
+		b.errorf("failed to list ImageSets: %v", err)
+		return
+	}
+	if err := cli.List(ctx, secrets, client.InNamespace(common.OperatorNamespace())); err != nil {
+		b.errorf("failed to list Secrets: %v", err)
+		return
+	}
+	if err := cli.List(ctx, configMaps, client.InNamespace(common.OperatorNamespace())); err != nil {
+		b.errorf("failed to list ConfigMaps: %v", err)
+		return
+	}
+	for i := range imageSets.Items {
+		objs = append(objs, &imageSets.Items[i])
+	}
+	for i := range secrets.Items {
+		objs = append(objs, &secrets.Items[i])
+	}
+	for i := range configMaps.Items {
+		objs = append(objs, &configMaps.Items[i])
+	}
+
+	res := imageResolution{Variant: instance.Spec.Variant}
+	if res.Variant == "" {
+		res.Variant = operatorv1.Calico
+	}
+	is, err := imageset.GetImageSet(ctx, cli, res.Variant)
+	if err != nil {
+		res.Validation = err.Error()
+	} else if is != nil {
+		res.ImageSet = is.Name
+		if err := imageset.ValidateImageSet(is); err != nil {
+			res.Validation = err.Error()
+		} else if err := imageset.VerifyImageSet(ctx, cli, is); err != nil {
+			res.Validation = err.Error()
+		}
+	}
+
+	result, err := offline.Render(ctx, scheme, objs, offline.Options{})
+	if err != nil {
+		b.errorf("failed to render objects: %v", err)
+		b.addYAML("imageset.yaml", res)
+		return
+	}
+
+	for _, obj := range result.ObjsToCreate {
+		redactSecret(obj)
+	}
+	for _, obj := range result.ObjsToDelete {
+		redactSecret(obj)
+	}
+	buf := &bytes.Buffer{}
+	if err := offline.Write(buf, result); err != nil {
+		b.errorf("failed to write rendered objects: %v", err)
+	} else {
+		b.add("rendered/objects.yaml", buf.Bytes())
+	}
+
+	res.Images = renderedImages(result.ObjsToCreate)
+	b.addYAML("imageset.yaml", res)
+
+	b.add("rendered/diff.txt", b.diff(ctx, cli, result))
+}
+
+// diff returns, for each rendered object, the change that the operator would make to the live object.
+func (b *bundle) diff(ctx context.Context, cli client.Client, result *offline.Result) []byte {
+	buf := &bytes.Buffer{}
+	for _, obj := range result.ObjsToCreate {
+		live, err := getLive(ctx, cli, obj)
+		fmt.Fprintf(buf, "# %s %s\n", obj.GetObjectKind().GroupVersionKind().Kind, objectName(obj))
+		switch {
+		case apierrors.IsNotFound(err):
+			buf.WriteString("would be created\n")
+		case err != nil:
+			fmt.Fprintf(buf, "failed to get live object: %v\n", err)
+		default:
+			redactSecret(live)
+			patch, err := utils.MergePatch(live, obj)
+			if err != nil {
+				fmt.Fprintf(buf, "failed to compare with live object: %v\n", err)
+			} else if patch == "" {
+				buf.WriteString("up to date\n")
+			} else {
+				fmt.Fprintf(buf, "would be updated: %s\n", patch)
+			}
+		}
+	}
+	for _, obj := range result.ObjsToDelete {
+		_, err := getLive(ctx, cli, obj)
+		fmt.Fprintf(buf, "# %s %s\n", obj.GetObjectKind().GroupVersionKind().Kind, objectName(obj))
+		switch {
+		case apierrors.IsNotFound(err):
+			buf.WriteString("not present\n")
+		case err != nil:
+			fmt.Fprintf(buf, "failed to get live object: %v\n", err)
+		default:
+			buf.WriteString("would be deleted\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// collectPodFailures adds a message for each failing pod in the namespaces the operator manages.
+func (b *bundle) collectPodFailures(ctx context.Context, cli client.Client) {
+	namespaces := &corev1.NamespaceList{}
+	if err := cli.List(ctx, namespaces); err != nil {
+		b.errorf("failed to list namespaces: %v", err)
+		return
+	}
+
+	var failures []string
+	for _, ns := range namespaces.Items {
+		if !isOperatorNamespace(ns.Name) {
+			continue
+		}
+		f, err := status.PodFailures(ctx, cli, ns.Name)
+		if err != nil {
+			b.errorf("failed to list pods in %s: %v", ns.Name, err)
+			continue
+		}
+		failures = append(failures, f...)
+	}
+	sort.Strings(failures)
+	b.add("pod-failures.txt", []byte(strings.Join(failures, "\n")+"\n"))
+}
+
+// isOperatorNamespace returns true for the namespaces that the operator or its components run in.
+func isOperatorNamespace(name string) bool {
+	switch name {
+	case common.OperatorNamespace(), common.CalicoNamespace, "calico-apiserver":
+		return true
+	}
+	return strings.HasPrefix(name, "tigera-")
+}
+
+func getLive(ctx context.Context, cli client.Client, obj client.Object) (client.Object, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	err := cli.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	return live, err
+}
+
+// redactSecret replaces the values of a secret's data so that it can be included in the bundle.
+func redactSecret(obj client.Object) {
+	switch s := obj.(type) {
+	case *corev1.Secret:
+		for k := range s.Data {
+			s.Data[k] = []byte(redacted)
+		}
+		for k := range s.StringData {
+			s.StringData[k] = redacted
+		}
+	case *unstructured.Unstructured:
+		if s.GetKind() != "Secret" {
+			return
+		}
+		for _, field := range []string{"data", "stringData"} {
+			m, ok := s.Object[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for k := range m {
+				// Data values are base64 encoded.
+				if field == "data" {
+					m[k] = base64.StdEncoding.EncodeToString([]byte(redacted))
+				} else {
+					m[k] = redacted
+				}
+			}
+		}
+	}
+}
+
+// renderedImages returns the images of every container in the objects.
+func renderedImages(objs []client.Object) []string {
+	images := map[string]bool{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				if k == "containers" || k == "initContainers" {
+					if cs, ok := child.([]interface{}); ok {
+						for _, c := range cs {
+							if cm, ok := c.(map[string]interface{}); ok {
+								if image, ok := cm["image"].(string); ok && image != "" {
+									images[image] = true
+								}
+							}
+						}
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			continue
+		}
+		walk(u)
+	}
+
+	list := make([]string, 0, len(images))
+	for image := range images {
+		list = append(list, image)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func objectName(obj client.Object) string {
+	if obj.GetNamespace() != "" {
+		return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+	}
+	return obj.GetName()
+}
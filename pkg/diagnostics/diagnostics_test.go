@@ -0,0 +1,195 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/active"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/offline"
+)
+
+// readBundle returns the contents of each file in the tarball, keyed by name.
+func readBundle(path string) map[string]string {
+	f, err := os.Open(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	Expect(err).NotTo(HaveOccurred())
+
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+		data, err := io.ReadAll(tr)
+		Expect(err).NotTo(HaveOccurred())
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
+
+var _ = Describe("diagnostics", func() {
+	var cli client.Client
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "diagnostics")
+		Expect(err).NotTo(HaveOccurred())
+
+		cli = fake.NewClientBuilder().WithScheme(offline.NewScheme()).WithObjects(
+			&operatorv1.Installation{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec:       operatorv1.InstallationSpec{Registry: "my-registry.io/"},
+			},
+			&operatorv1.TigeraStatus{
+				ObjectMeta: metav1.ObjectMeta{Name: "calico"},
+				Status: operatorv1.TigeraStatusStatus{Conditions: []operatorv1.TigeraStatusCondition{{
+					Type:    operatorv1.ComponentDegraded,
+					Status:  operatorv1.ConditionTrue,
+					Reason:  "PodFailure",
+					Message: "calico-node is crash looping",
+				}}},
+			},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: active.ActiveConfigMapName, Namespace: common.OperatorNamespace()}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: common.CalicoNamespace}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "calico-node-abcde", Namespace: common.CalicoNamespace},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "calico-node",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"},
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: common.KubeControllersDeploymentName, Namespace: common.CalicoNamespace},
+			},
+		).Build()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should write a bundle with the operator state", func() {
+		path, err := Collect(context.Background(), cli, offline.NewScheme(), dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(HavePrefix(dir))
+
+		files := readBundle(path)
+		Expect(files).To(HaveKey("custom-resources/v1/installation.yaml"))
+		Expect(files["custom-resources/v1/installation.yaml"]).To(ContainSubstring("registry: my-registry.io/"))
+		Expect(files).To(HaveKey("custom-resources/v1/tigerastatus.yaml"))
+		Expect(files["tigerastatus.txt"]).To(ContainSubstring(`Degraded=True`))
+		Expect(files["tigerastatus.txt"]).To(ContainSubstring(`message="calico-node is crash looping"`))
+
+		Expect(files).To(HaveKey("configmaps/active-operator.yaml"))
+		Expect(files).NotTo(HaveKey("configmaps/operator-bootstrap-config.yaml"))
+		Expect(files["errors.txt"]).To(ContainSubstring("operator-bootstrap-config does not exist"))
+
+		Expect(files["imageset.yaml"]).To(ContainSubstring("variant: Calico"))
+		Expect(files["imageset.yaml"]).To(ContainSubstring("my-registry.io/"))
+
+		Expect(files["rendered/objects.yaml"]).To(ContainSubstring("# create-or-update DaemonSet calico-system/calico-node"))
+		Expect(files["rendered/objects.yaml"]).NotTo(ContainSubstring("PRIVATE KEY"))
+		Expect(files["rendered/diff.txt"]).To(ContainSubstring("# DaemonSet calico-system/calico-node\nwould be created\n"))
+		Expect(files["rendered/diff.txt"]).To(MatchRegexp(`# Deployment calico-system/calico-kube-controllers\nwould be updated: \{.*\}\n`))
+
+		Expect(files["pod-failures.txt"]).To(Equal("Pod calico-system/calico-node-abcde has crash looping container: calico-node\n"))
+
+		Expect(files["rendered/README.txt"]).To(ContainSubstring("No other custom resource is rendered"))
+		Expect(files["rendered/README.txt"]).NotTo(ContainSubstring("are not rendered:"))
+	})
+
+	It("should list the custom resources that are not rendered", func() {
+		Expect(cli.Create(context.Background(), &operatorv1.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).To(Succeed())
+		Expect(cli.Create(context.Background(), &operatorv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+
+		path, err := Collect(context.Background(), cli, offline.NewScheme(), dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(path)
+		Expect(files).To(HaveKey("custom-resources/v1/logstorage.yaml"))
+		Expect(files["rendered/README.txt"]).To(ContainSubstring("are not rendered:\n\n- LogStorage tigera-secure\n"))
+		Expect(files["rendered/README.txt"]).NotTo(ContainSubstring("- APIServer"))
+		Expect(files["rendered/README.txt"]).NotTo(ContainSubstring("- Installation"))
+	})
+
+	It("should render with the overlay Installation merged over the default", func() {
+		Expect(cli.Create(context.Background(), &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "overlay"},
+			Spec:       operatorv1.InstallationSpec{Registry: "overlay-registry.io/"},
+		})).To(Succeed())
+
+		path, err := Collect(context.Background(), cli, offline.NewScheme(), dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(path)
+		Expect(files["imageset.yaml"]).To(ContainSubstring("overlay-registry.io/"))
+		Expect(files["imageset.yaml"]).NotTo(ContainSubstring("my-registry.io/"))
+		Expect(files["rendered/objects.yaml"]).To(ContainSubstring("image: overlay-registry.io/"))
+	})
+
+	It("should record an error when the overlay Installation is invalid", func() {
+		Expect(cli.Create(context.Background(), &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "overlay"},
+			Spec: operatorv1.InstallationSpec{
+				CertificateManagement: &operatorv1.CertificateManagement{CACert: []byte("ca"), SignerName: "not-a-signer-name"},
+			},
+		})).To(Succeed())
+
+		path, err := Collect(context.Background(), cli, offline.NewScheme(), dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(path)
+		Expect(files["errors.txt"]).To(ContainSubstring("invalid computed config"))
+		Expect(files).NotTo(HaveKey("rendered/objects.yaml"))
+	})
+
+	It("should record an error when there is no Installation", func() {
+		Expect(cli.Delete(context.Background(), &operatorv1.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+
+		path, err := Collect(context.Background(), cli, offline.NewScheme(), dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		files := readBundle(path)
+		Expect(files["errors.txt"]).To(ContainSubstring("failed to get Installation"))
+		Expect(files).NotTo(HaveKey("rendered/objects.yaml"))
+		Expect(files).To(HaveKey("tigerastatus.txt"))
+	})
+})
@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+)
+
+// Annotations on the manifests in an OCI image layout's index that hold the name of the image. containerd records the
+// full reference of the image in its own annotation. The standard annotation holds whatever name the tool that wrote
+// the layout was given, which for skopeo is the part after the directory of an oci:<dir>:<name> destination. Only
+// names that include the image's name can be resolved, e.g. oci:<dir>:calico/node:v3.27.0, and not a tag alone.
+const (
+	ociRefNameAnnotation    = "org.opencontainers.image.ref.name"
+	containerdImageNameAnno = "io.containerd.image.name"
+)
+
+// manifestMediaTypes are the media types accepted when resolving a digest from a registry. Indexes and manifest lists
+// are preferred so that the digest covers every platform.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// challengeParamRe matches the key="value" parameters of a WWW-Authenticate challenge.
+var challengeParamRe = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// DigestResolver returns the digest of a version of an image, where the image is named as in an ImageSet, e.g.
+// calico/node.
+type DigestResolver interface {
+	Resolve(ctx context.Context, image, version string) (string, error)
+}
+
+// NewDigestResolver returns a resolver for the source, which is either a directory in OCI image layout format or a
+// registry, optionally with a path, that mirrors the images, e.g. registry.example.com/mirror/. A registry is
+// accessed over HTTPS unless it is prefixed with http://.
+func NewDigestResolver(source string) (DigestResolver, error) {
+	if _, err := os.Stat(filepath.Join(source, "oci-layout")); err == nil {
+		return newOCILayoutResolver(source)
+	}
+	return newRegistryResolver(source), nil
+}
+
+// GenerateImageSet resolves the digest of every image the operator deploys for the variant and returns an ImageSet
+// with the name the operator expects for its release.
+func GenerateImageSet(ctx context.Context, variant operatorv1.ProductVariant, r DigestResolver) (*operatorv1.ImageSet, error) {
+	is := &operatorv1.ImageSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: operatorv1.GroupVersion.String(), Kind: "ImageSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: imageset.SetName(variant)},
+	}
+
+	// An ImageSet holds one digest per image, so the FIPS variants of an image, which share its name, are covered by
+	// the first entry for the image.
+	seen := map[string]bool{}
+	var errMsgs []string
+	add := func(image, version string) {
+		if seen[image] {
+			return
+		}
+		seen[image] = true
+
+		digest, err := r.Resolve(ctx, image, version)
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+			return
+		}
+		is.Spec.Images = append(is.Spec.Images, operatorv1.Image{Image: image, Digest: digest})
+	}
+
+	cmpnts := components.CalicoImages
+	if variant == operatorv1.TigeraSecureEnterprise {
+		cmpnts = components.EnterpriseImages
+		// The operator's init image is listed with the Calico images but is used by both variants.
+		add(components.ComponentOperatorInit.Image, components.ComponentOperatorInit.Version)
+	}
+	for _, c := range cmpnts {
+		add(c.Image, c.Version)
+	}
+	for _, c := range components.CommonImages {
+		add(c.Image, c.Version)
+	}
+	if len(errMsgs) != 0 {
+		return nil, fmt.Errorf("failed to resolve images: %s", strings.Join(errMsgs, "; "))
+	}
+	return is, nil
+}
+
+type ociLayoutResolver struct {
+	// digests maps the image names recorded in the layout's index to the digests of their manifests. The same name
+	// may be recorded for more than one manifest.
+	digests map[string][]string
+
+	// tagOnly is set if any of the names is a tag alone, which doesn't say which image it is.
+	tagOnly bool
+}
+
+type ociIndex struct {
+	Manifests []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"manifests"`
+}
+
+func newOCILayoutResolver(dir string) (*ociLayoutResolver, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	idx := ociIndex{}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index.json: %w", err)
+	}
+
+	r := &ociLayoutResolver{digests: map[string][]string{}}
+	for _, m := range idx.Manifests {
+		for _, a := range []string{ociRefNameAnnotation, containerdImageNameAnno} {
+			name := m.Annotations[a]
+			if name == "" {
+				continue
+			}
+			if !strings.ContainsAny(name, ":/@") {
+				r.tagOnly = true
+				continue
+			}
+			r.digests[name] = append(r.digests[name], m.Digest)
+		}
+	}
+	return r, nil
+}
+
+// Resolve finds the image in the layout by its name and tag, ignoring the registry and any path that precedes the
+// image's name, so that a layout of images pulled from any mirror can be used. It is an error for the image to have
+// different digests, e.g. because it was copied from two mirrors that hold different builds of it.
+func (r *ociLayoutResolver) Resolve(_ context.Context, image, version string) (string, error) {
+	ref := fmt.Sprintf("%s:%s", image, version)
+
+	names := make([]string, 0, len(r.digests))
+	for name := range r.digests {
+		if name == ref || strings.HasSuffix(name, "/"+ref) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var digest string
+	var found []string
+	for _, name := range names {
+		for _, d := range r.digests[name] {
+			if digest == "" {
+				digest = d
+			}
+			found = append(found, fmt.Sprintf("%s@%s", name, d))
+			if d != digest {
+				return "", fmt.Errorf("%s has more than one digest in OCI layout: %s", ref, strings.Join(found, ", "))
+			}
+		}
+	}
+	if digest == "" {
+		if r.tagOnly {
+			return "", fmt.Errorf("%s not found in OCI layout, which names some images by their tag alone: copy images with their name, e.g. oci:<dir>:%s", ref, ref)
+		}
+		return "", fmt.Errorf("%s not found in OCI layout", ref)
+	}
+	return digest, nil
+}
+
+type registryResolver struct {
+	scheme string
+	host   string
+	path   string
+	client *http.Client
+}
+
+func newRegistryResolver(source string) *registryResolver {
+	r := &registryResolver{scheme: "https", client: http.DefaultClient}
+	if s, ok := strings.CutPrefix(source, "http://"); ok {
+		r.scheme = "http"
+		source = s
+	}
+	source = strings.TrimPrefix(source, "https://")
+	r.host, r.path, _ = strings.Cut(strings.TrimSuffix(source, "/"), "/")
+	return r
+}
+
+// Resolve asks the registry for the digest of the image's manifest using the registry v2 API. Registries that
+// require a bearer token are supported as long as they issue tokens for anonymous pulls.
+func (r *registryResolver) Resolve(ctx context.Context, image, version string) (string, error) {
+	repo := image
+	if r.path != "" {
+		repo = r.path + "/" + image
+	}
+	u := url.URL{Scheme: r.scheme, Host: r.host, Path: fmt.Sprintf("/v2/%s/manifests/%s", repo, version)}
+
+	resp, err := r.get(ctx, u.String(), "")
+	if err != nil {
+		return "", fmt.Errorf("%s:%s: %w", image, version, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		token, err := r.token(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", fmt.Errorf("%s:%s: %w", image, version, err)
+		}
+		if resp, err = r.get(ctx, u.String(), token); err != nil {
+			return "", fmt.Errorf("%s:%s: %w", image, version, err)
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s:%s: registry returned %s", image, version, resp.Status)
+	}
+
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+	// The header is optional, in which case the digest is that of the manifest's content.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s:%s: %w", image, version, err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(body)), nil
+}
+
+func (r *registryResolver) get(ctx context.Context, u, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r.client.Do(req)
+}
+
+// token requests an anonymous bearer token from the realm in a WWW-Authenticate challenge.
+func (r *registryResolver) token(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+	q := url.Values{}
+	var realm string
+	for _, m := range challengeParamRe.FindAllStringSubmatch(params, -1) {
+		switch m[1] {
+		case "realm":
+			realm = m[2]
+		case "service", "scope":
+			q.Set(m[1], m[2])
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("registry authentication challenge has no realm")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned %s", resp.Status)
+	}
+	t := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return "", fmt.Errorf("failed to parse token: %w", err)
+	}
+	if t.Token != "" {
+		return t.Token, nil
+	}
+	return t.AccessToken, nil
+}
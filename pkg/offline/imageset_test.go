@@ -0,0 +1,227 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+)
+
+// fakeDigest returns a stable digest for an image reference.
+func fakeDigest(ref string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(ref)))
+}
+
+// calicoRefs returns the image:version of each image in a Calico ImageSet.
+func calicoRefs() map[string]string {
+	refs := map[string]string{}
+	for _, c := range append(components.CalicoImages, components.CommonImages...) {
+		if _, ok := refs[c.Image]; !ok {
+			refs[c.Image] = fmt.Sprintf("%s:%s", c.Image, c.Version)
+		}
+	}
+	return refs
+}
+
+var _ = Describe("ImageSet generation", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Context("from an OCI layout", func() {
+		var dir string
+
+		writeLayout := func(refs map[string]string) {
+			type manifest struct {
+				MediaType   string            `json:"mediaType"`
+				Digest      string            `json:"digest"`
+				Annotations map[string]string `json:"annotations"`
+			}
+			idx := struct {
+				SchemaVersion int        `json:"schemaVersion"`
+				Manifests     []manifest `json:"manifests"`
+			}{SchemaVersion: 2}
+			for image, ref := range refs {
+				annotations := map[string]string{ociRefNameAnnotation: "docker.io/" + ref}
+				if image == "calico/node" {
+					// Images exported by containerd carry the name in their own annotation.
+					annotations = map[string]string{ociRefNameAnnotation: "master", containerdImageNameAnno: "mirror.example.com/" + ref}
+				}
+				idx.Manifests = append(idx.Manifests, manifest{
+					MediaType:   "application/vnd.oci.image.index.v1+json",
+					Digest:      fakeDigest(ref),
+					Annotations: annotations,
+				})
+			}
+			data, err := json.Marshal(idx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "index.json"), data, 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "oci-layout")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should resolve every image for the release", func() {
+			refs := calicoRefs()
+			writeLayout(refs)
+
+			r, err := NewDigestResolver(dir)
+			Expect(err).NotTo(HaveOccurred())
+			is, err := GenerateImageSet(ctx, operatorv1.Calico, r)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(is.Name).To(Equal(imageset.SetName(operatorv1.Calico)))
+			Expect(is.Kind).To(Equal("ImageSet"))
+			Expect(is.Spec.Images).To(HaveLen(len(refs)))
+			for _, img := range is.Spec.Images {
+				Expect(img.Digest).To(Equal(fakeDigest(refs[img.Image])), img.Image)
+			}
+			Expect(imageset.ValidateImageSet(is)).To(Succeed())
+		})
+
+		It("should report the images missing from the layout", func() {
+			refs := calicoRefs()
+			delete(refs, "calico/typha")
+			delete(refs, "calico/cni")
+			writeLayout(refs)
+
+			r, err := NewDigestResolver(dir)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = GenerateImageSet(ctx, operatorv1.Calico, r)
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("calico/typha:%s not found in OCI layout", components.ComponentCalicoTypha.Version))))
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("calico/cni:%s not found in OCI layout", components.ComponentCalicoCNI.Version))))
+		})
+
+		writeIndex := func(index string) {
+			Expect(os.WriteFile(filepath.Join(dir, "index.json"), []byte(index), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644)).To(Succeed())
+		}
+
+		It("should report an image with different digests from different mirrors", func() {
+			writeIndex(`{"schemaVersion":2,"manifests":[
+				{"digest":"sha256:bbbb","annotations":{"org.opencontainers.image.ref.name":"mirror-b.example.com/calico/node:v1"}},
+				{"digest":"sha256:aaaa","annotations":{"org.opencontainers.image.ref.name":"mirror-a.example.com/calico/node:v1"}},
+				{"digest":"sha256:cccc","annotations":{"org.opencontainers.image.ref.name":"mirror-a.example.com/calico/typha:v1"}},
+				{"digest":"sha256:cccc","annotations":{"io.containerd.image.name":"mirror-b.example.com/calico/typha:v1"}}
+			]}`)
+			r, err := NewDigestResolver(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = r.Resolve(ctx, "calico/node", "v1")
+			Expect(err).To(MatchError("calico/node:v1 has more than one digest in OCI layout: " +
+				"mirror-a.example.com/calico/node:v1@sha256:aaaa, mirror-b.example.com/calico/node:v1@sha256:bbbb"))
+
+			digest, err := r.Resolve(ctx, "calico/typha", "v1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal("sha256:cccc"))
+		})
+
+		It("should explain that images named by their tag alone can't be resolved", func() {
+			writeIndex(`{"schemaVersion":2,"manifests":[
+				{"digest":"sha256:aaaa","annotations":{"org.opencontainers.image.ref.name":"v1"}}
+			]}`)
+			r, err := NewDigestResolver(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = r.Resolve(ctx, "calico/node", "v1")
+			Expect(err).To(MatchError(ContainSubstring("names some images by their tag alone")))
+		})
+	})
+
+	Context("from a registry", func() {
+		var server *httptest.Server
+		var missing string
+
+		BeforeEach(func() {
+			missing = ""
+			mux := http.NewServeMux()
+			mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Query().Get("service") != "registry.example.com" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			})
+			mux.HandleFunc("/v2/", func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Bearer anonymous" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.example.com",scope="repository:x:pull"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				repo, tag, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/mirror/"), "/manifests/")
+				if repo == missing {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				// Only answer with the digest header for some images, so that both ways of resolving are covered.
+				body := []byte(repo + ":" + tag)
+				if repo == "calico/node" {
+					w.Header().Set("Docker-Content-Digest", fakeDigest(repo+":"+tag))
+				}
+				_, _ = w.Write(body)
+			})
+			server = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should resolve every image for the release", func() {
+			r, err := NewDigestResolver(server.URL + "/mirror/")
+			Expect(err).NotTo(HaveOccurred())
+			is, err := GenerateImageSet(ctx, operatorv1.Calico, r)
+			Expect(err).NotTo(HaveOccurred())
+
+			refs := calicoRefs()
+			Expect(is.Spec.Images).To(HaveLen(len(refs)))
+			for _, img := range is.Spec.Images {
+				Expect(img.Digest).To(Equal(fakeDigest(refs[img.Image])), img.Image)
+			}
+		})
+
+		It("should report images the registry doesn't have", func() {
+			missing = "calico/typha"
+			r, err := NewDigestResolver(server.URL + "/mirror/")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = GenerateImageSet(ctx, operatorv1.Calico, r)
+			Expect(err).To(MatchError(ContainSubstring("calico/typha:%s: registry returned 404 Not Found", components.ComponentCalicoTypha.Version)))
+		})
+	})
+})
@@ -0,0 +1,387 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package offline renders the objects that the operator would apply for a set of custom resources, and generates
+// ImageSets for mirrored images, without requiring access to an API server.
+package offline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/controller/installation"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+	rcertificatemanagement "github.com/tigera/operator/pkg/render/certificatemanagement"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/kubecontrollers"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+// These match the defaults the core controller uses when the FelixConfiguration and KubeControllersConfiguration
+// don't override them.
+const (
+	defaultFelixHealthPort          = 9099
+	defaultFelixHealthPortOpenShift = 9199
+	defaultNodeReporterPort         = 9081
+)
+
+var yamlDelimRe = regexp.MustCompile(`(?m)^---\s*$`)
+
+// Options configures how the custom resources are rendered.
+type Options struct {
+	// ClusterDomain is the DNS domain of the cluster, used to generate certificate DNS names.
+	ClusterDomain string
+
+	// UsePSP renders PodSecurityPolicies for the components.
+	UsePSP bool
+}
+
+// Result holds the objects that the operator would create or update, and delete.
+type Result struct {
+	ObjsToCreate []client.Object
+	ObjsToDelete []client.Object
+}
+
+// NewScheme returns a scheme with all the types the operator renders registered.
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1.AddToScheme(scheme))
+	utilruntime.Must(apis.AddToScheme(scheme))
+	return scheme
+}
+
+// Decode parses a multi-document YAML stream into typed objects.
+func Decode(scheme *runtime.Scheme, data []byte) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objs []client.Object
+	for _, doc := range yamlDelimRe.Split(string(data), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		// Skip documents that only contain comments.
+		j, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return nil, err
+		}
+		if string(j) == "null" {
+			continue
+		}
+		obj, _, err := decoder.Decode(j, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		cobj, ok := obj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unsupported object %T", obj)
+		}
+		objs = append(objs, cobj)
+	}
+	return objs, nil
+}
+
+// Render runs the render pipeline used by the controllers against the given objects and returns the objects that
+// would be applied to the cluster. The objects must include the default Installation. Secrets and ConfigMaps, such as
+// an existing tigera-ca-private CA or image pull secrets, may be included and are used as if they were in the cluster.
+// Any certificates that don't exist are generated, so their contents will differ from those in a live cluster.
+//
+// Only the Installation and APIServer are rendered, and for the Installation only the namespaces, certificates, Typha,
+// calico-node, CSI and kube-controllers components of the core controller. Any other custom resource is rejected. As
+// there is no FelixConfiguration, the components use the default Felix health and node reporter ports.
+func Render(ctx context.Context, scheme *runtime.Scheme, objs []client.Object, opts Options) (*Result, error) {
+	if opts.ClusterDomain == "" {
+		opts.ClusterDomain = dns.DefaultClusterDomain
+	}
+
+	var instance *operatorv1.Installation
+	var apiServer *operatorv1.APIServer
+	var others []client.Object
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *operatorv1.Installation:
+			if o.Name == utils.DefaultInstanceKey.Name {
+				instance = o
+			}
+		case *operatorv1.APIServer:
+			if o.Name == utils.DefaultInstanceKey.Name {
+				apiServer = o
+			}
+		case *operatorv1.ImageSet, *corev1.Secret, *corev1.ConfigMap:
+			others = append(others, obj)
+		default:
+			gvk, _ := apiutil.GVKForObject(obj, scheme)
+			return nil, fmt.Errorf("rendering %s %s is not supported", gvk.Kind, obj.GetName())
+		}
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("an Installation named %q is required", utils.DefaultInstanceKey.Name)
+	}
+	if err := installation.FillDefaults(instance); err != nil {
+		return nil, fmt.Errorf("invalid Installation: %w", err)
+	}
+
+	// The Installation is stored too, as the ImageSet is verified against its ImageSetVerification.
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(others, instance)...).Build()
+
+	// Create the CA in the same way the secrets controller would and store it so that the keypairs
+	// created below are all signed by it.
+	certificateManager, err := certificatemanager.Create(cli, &instance.Spec, opts.ClusterDomain, common.OperatorNamespace(), certificatemanager.AllowCACreation())
+	if err != nil {
+		return nil, err
+	}
+	if err = cli.Create(ctx, certificateManager.KeyPair().Secret(common.OperatorNamespace())); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	imageSet, err := imageset.GetVerifiedImageSet(ctx, cli, instance.Spec.Variant)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	add := func(cr client.Object, components []render.Component) error {
+		if err := imageset.ResolveImages(imageSet, components...); err != nil {
+			return err
+		}
+		for _, component := range components {
+			if !component.Ready() {
+				continue
+			}
+			toCreate, toDelete, err := utils.RenderObjects(scheme, cr, component)
+			if err != nil {
+				return err
+			}
+			result.ObjsToCreate = append(result.ObjsToCreate, toCreate...)
+			result.ObjsToDelete = append(result.ObjsToDelete, toDelete...)
+		}
+		return nil
+	}
+
+	components, err := installationComponents(cli, certificateManager, instance, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = add(instance, components); err != nil {
+		return nil, err
+	}
+
+	if apiServer != nil {
+		components, err := apiServerComponents(cli, certificateManager, instance, apiServer, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err = add(apiServer, components); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, objs := range [][]client.Object{result.ObjsToCreate, result.ObjsToDelete} {
+		for _, obj := range objs {
+			gvk, err := apiutil.GVKForObject(obj, scheme)
+			if err != nil {
+				return nil, err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+	}
+	return result, nil
+}
+
+// installationComponents builds the components rendered by the core controller.
+func installationComponents(cli client.Client, certificateManager certificatemanager.CertificateManager, instance *operatorv1.Installation, opts Options) ([]render.Component, error) {
+	typhaNodeTLS, err := installation.GetOrCreateTyphaNodeTLSConfig(cli, certificateManager)
+	if err != nil {
+		return nil, err
+	}
+
+	pullSecrets, err := utils.GetNetworkingPullSecrets(&instance.Spec, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	felixHealthPort := defaultFelixHealthPort
+	if instance.Spec.KubernetesProvider == operatorv1.ProviderOpenShift {
+		felixHealthPort = defaultFelixHealthPortOpenShift
+	}
+
+	var nodePrometheusTLS, kubeControllerTLS certificatemanagement.KeyPairInterface
+	if instance.Spec.Variant == operatorv1.TigeraSecureEnterprise {
+		nodePrometheusTLS, err = certificateManager.GetOrCreateKeyPair(cli, render.NodePrometheusTLSServerSecret, common.OperatorNamespace(), dns.GetServiceDNSNames(render.CalicoNodeMetricsService, common.CalicoNamespace, opts.ClusterDomain))
+		if err != nil {
+			return nil, err
+		}
+		kubeControllerTLS, err = certificateManager.GetOrCreateKeyPair(cli, kubecontrollers.KubeControllerPrometheusTLSSecret, common.OperatorNamespace(), dns.GetServiceDNSNames(kubecontrollers.KubeControllerMetrics, common.CalicoNamespace, opts.ClusterDomain))
+		if err != nil {
+			return nil, err
+		}
+		typhaNodeTLS.TrustedBundle.AddCertificates(nodePrometheusTLS, kubeControllerTLS)
+	}
+
+	kubeControllersCfg := kubecontrollers.KubeControllersConfiguration{
+		K8sServiceEp:      k8sapi.Endpoint,
+		Installation:      &instance.Spec,
+		ClusterDomain:     opts.ClusterDomain,
+		UsePSP:            opts.UsePSP,
+		MetricsServerTLS:  kubeControllerTLS,
+		TrustedBundle:     typhaNodeTLS.TrustedBundle,
+		Namespace:         common.CalicoNamespace,
+		BindingNamespaces: []string{common.CalicoNamespace},
+	}
+
+	return []render.Component{
+		render.Namespaces(&render.NamespaceConfiguration{
+			Installation: &instance.Spec,
+			PullSecrets:  pullSecrets,
+		}),
+		rcertificatemanagement.CertificateManagement(&rcertificatemanagement.Config{
+			Namespace:       common.CalicoNamespace,
+			ServiceAccounts: []string{render.CalicoNodeObjectName, render.TyphaServiceAccountName, kubecontrollers.KubeControllerServiceAccount},
+			KeyPairOptions: []rcertificatemanagement.KeyPairOption{
+				rcertificatemanagement.NewKeyPairOption(typhaNodeTLS.NodeSecret, true, true),
+				rcertificatemanagement.NewKeyPairOption(nodePrometheusTLS, true, true),
+				rcertificatemanagement.NewKeyPairOption(typhaNodeTLS.TyphaSecret, true, true),
+				rcertificatemanagement.NewKeyPairOption(kubeControllerTLS, true, true),
+			},
+			TrustedBundle: typhaNodeTLS.TrustedBundle,
+		}),
+		render.Typha(&render.TyphaConfiguration{
+			K8sServiceEp:    k8sapi.Endpoint,
+			Installation:    &instance.Spec,
+			TLS:             typhaNodeTLS,
+			ClusterDomain:   opts.ClusterDomain,
+			FelixHealthPort: felixHealthPort,
+			UsePSP:          opts.UsePSP,
+		}),
+		render.Node(&render.NodeConfiguration{
+			K8sServiceEp:            k8sapi.Endpoint,
+			Installation:            &instance.Spec,
+			TLS:                     typhaNodeTLS,
+			ClusterDomain:           opts.ClusterDomain,
+			NodeReporterMetricsPort: defaultNodeReporterPort,
+			PrometheusServerTLS:     nodePrometheusTLS,
+			FelixHealthPort:         felixHealthPort,
+			UsePSP:                  opts.UsePSP,
+		}),
+		render.CSI(&render.CSIConfiguration{
+			Installation: &instance.Spec,
+			UsePSP:       opts.UsePSP,
+			OpenShift:    instance.Spec.KubernetesProvider == operatorv1.ProviderOpenShift,
+		}),
+		kubecontrollers.NewCalicoKubeControllers(&kubeControllersCfg),
+	}, nil
+}
+
+// apiServerComponents builds the components rendered by the apiserver controller.
+func apiServerComponents(cli client.Client, certificateManager certificatemanager.CertificateManager, instance *operatorv1.Installation, apiServer *operatorv1.APIServer, opts Options) ([]render.Component, error) {
+	variant := instance.Spec.Variant
+	tlsSecret, err := certificateManager.GetOrCreateKeyPair(
+		cli,
+		render.ProjectCalicoAPIServerTLSSecretName(variant),
+		common.OperatorNamespace(),
+		dns.GetServiceDNSNames(render.ProjectCalicoAPIServerServiceName(variant), rmeta.APIServerNamespace(variant), opts.ClusterDomain))
+	if err != nil {
+		return nil, err
+	}
+
+	pullSecrets, err := utils.GetNetworkingPullSecrets(&instance.Spec, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	component, err := render.APIServer(&render.APIServerConfiguration{
+		K8SServiceEndpoint: k8sapi.Endpoint,
+		Installation:       &instance.Spec,
+		APIServer:          &apiServer.Spec,
+		TLSKeyPair:         tlsSecret,
+		PullSecrets:        pullSecrets,
+		Openshift:          instance.Spec.KubernetesProvider == operatorv1.ProviderOpenShift,
+		UsePSP:             opts.UsePSP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []render.Component{
+		component,
+		rcertificatemanagement.CertificateManagement(&rcertificatemanagement.Config{
+			Namespace:       rmeta.APIServerNamespace(variant),
+			ServiceAccounts: []string{render.APIServerServiceAccountName(variant)},
+			KeyPairOptions: []rcertificatemanagement.KeyPairOption{
+				rcertificatemanagement.NewKeyPairOption(tlsSecret, true, true),
+			},
+		}),
+	}, nil
+}
+
+// Write prints the result as a multi-document YAML stream. Each document is preceded by a comment that
+// indicates whether the object would be created or updated, or deleted.
+func Write(w io.Writer, result *Result) error {
+	buf := &bytes.Buffer{}
+	first := true
+	write := func(action string, obj client.Object) error {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %v", obj.GetName(), err)
+		}
+		if !first {
+			buf.WriteString("---\n")
+		}
+		first = false
+
+		name := obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+		}
+		fmt.Fprintf(buf, "# %s %s %s\n", action, obj.GetObjectKind().GroupVersionKind().Kind, name)
+		buf.Write(b)
+		return nil
+	}
+
+	for _, obj := range result.ObjsToCreate {
+		if err := write("create-or-update", obj); err != nil {
+			return err
+		}
+	}
+	for _, obj := range result.ObjsToDelete {
+		if err := write("delete", obj); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+)
+
+const installationYAML = `
+# A comment-only document should be ignored.
+---
+apiVersion: operator.tigera.io/v1
+kind: Installation
+metadata:
+  name: default
+spec:
+  registry: my-registry.io
+---
+apiVersion: operator.tigera.io/v1
+kind: APIServer
+metadata:
+  name: default
+`
+
+func findObject(objs []client.Object, kind, ns, name string) client.Object {
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Kind == kind && obj.GetNamespace() == ns && obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+var _ = Describe("offline rendering", func() {
+	It("should decode multi-document YAML", func() {
+		objs, err := Decode(NewScheme(), []byte(installationYAML))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(2))
+		Expect(objs[0]).To(BeAssignableToTypeOf(&operatorv1.Installation{}))
+		Expect(objs[1]).To(BeAssignableToTypeOf(&operatorv1.APIServer{}))
+	})
+
+	It("should render the core and apiserver components", func() {
+		s := NewScheme()
+		objs, err := Decode(s, []byte(installationYAML))
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := Render(context.Background(), s, objs, Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		ds := findObject(result.ObjsToCreate, "DaemonSet", common.CalicoNamespace, common.NodeDaemonSetName)
+		Expect(ds).NotTo(BeNil())
+		node := ds.(*appsv1.DaemonSet)
+		Expect(node.Spec.Template.Spec.Containers[0].Image).To(Equal(
+			fmt.Sprintf("my-registry.io/%s:%s", components.ComponentCalicoNode.Image, components.ComponentCalicoNode.Version)))
+
+		// The handler's defaults and owner references should have been applied.
+		Expect(node.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/os", "linux"))
+		Expect(node.OwnerReferences).To(HaveLen(1))
+		Expect(node.OwnerReferences[0].Kind).To(Equal("Installation"))
+
+		Expect(findObject(result.ObjsToCreate, "Deployment", common.CalicoNamespace, common.TyphaDeploymentName)).NotTo(BeNil())
+		Expect(findObject(result.ObjsToCreate, "Deployment", "calico-apiserver", "calico-apiserver")).NotTo(BeNil())
+
+		var buf bytes.Buffer
+		Expect(Write(&buf, result)).NotTo(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring("# create-or-update DaemonSet calico-system/calico-node\n"))
+		Expect(buf.String()).To(ContainSubstring("kind: DaemonSet"))
+	})
+
+	It("should honor an ImageSet in the input", func() {
+		s := NewScheme()
+		objs, err := Decode(s, []byte(installationYAML))
+		Expect(err).NotTo(HaveOccurred())
+
+		is := &operatorv1.ImageSet{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("calico-%s", components.CalicoRelease)}}
+		for _, c := range append(components.CalicoImages, components.CommonImages...) {
+			is.Spec.Images = append(is.Spec.Images, operatorv1.Image{Image: c.Image, Digest: "sha256:0123456789"})
+		}
+		objs = append(objs, is)
+
+		result, err := Render(context.Background(), s, objs, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		node := findObject(result.ObjsToCreate, "DaemonSet", common.CalicoNamespace, common.NodeDaemonSetName).(*appsv1.DaemonSet)
+		Expect(node.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix("@sha256:0123456789"))
+	})
+
+	It("should reject an ImageSet that isn't signed by a trusted key", func() {
+		s := NewScheme()
+		objs, err := Decode(s, []byte(installationYAML))
+		Expect(err).NotTo(HaveOccurred())
+		objs[0].(*operatorv1.Installation).Spec.ImageSetVerification = &operatorv1.ImageSetVerification{PublicKeySecretName: "imageset-keys"}
+
+		is := &operatorv1.ImageSet{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("calico-%s", components.CalicoRelease)}}
+		for _, c := range append(components.CalicoImages, components.CommonImages...) {
+			is.Spec.Images = append(is.Spec.Images, operatorv1.Image{Image: c.Image, Digest: "sha256:0123456789"})
+		}
+		objs = append(objs, is)
+
+		_, err = Render(context.Background(), s, objs, Options{})
+		Expect(err).To(MatchError(ContainSubstring("imageset-keys")))
+	})
+
+	It("should require an Installation", func() {
+		_, err := Render(context.Background(), NewScheme(), []client.Object{&operatorv1.APIServer{}}, Options{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject unsupported resources", func() {
+		_, err := Render(context.Background(), NewScheme(), []client.Object{&operatorv1.LogStorage{}}, Options{})
+		Expect(err).To(MatchError(ContainSubstring("not supported")))
+	})
+})
@@ -51,8 +51,9 @@ func (c *awsSGSetupComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 	var err error
-	c.image, err = components.GetReference(components.ComponentOperatorInit, reg, path, prefix, is)
+	c.image, err = components.GetReference(components.ComponentOperatorInit, reg, path, prefix, overrides, is)
 	return err
 }
 
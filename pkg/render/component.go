@@ -1,4 +1,4 @@
-// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+// Copyright (c) 2021,2023 Tigera, Inc. All rights reserved.
 
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -39,3 +39,12 @@ type Component interface {
 	// that create pods. Return OSTypeAny means that no node selector should be set for the "kubernetes.io/os" label.
 	SupportedOSType() rmeta.OSType
 }
+
+// ServerSideApplyComponent is an optional interface for a Component. If ServerSideApply returns true, the
+// "componentHandler" writes the component's objects with server-side apply, rather than merging them with the current
+// state of the objects and updating them. Fields that are set by other field managers, e.g. replicas set by an HPA or
+// containers injected by an admission webhook, are then left alone. The fields of existing objects that the operator
+// wrote with updates are moved to its apply field manager, so that fields it stops setting are removed.
+type ServerSideApplyComponent interface {
+	ServerSideApply() bool
+}
@@ -48,6 +48,9 @@ type CSIConfiguration struct {
 	Terminating  bool
 	UsePSP       bool
 	OpenShift    bool
+
+	// Whether the CSI objects are written with server-side apply, from the OperatorConfiguration.
+	ServerSideApply bool
 }
 
 type csiComponent struct {
@@ -357,29 +360,30 @@ func (c *csiComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 	var err error
 
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
-		c.csiImage, err = components.GetReference(components.ComponentCSIPrivate, reg, path, prefix, is)
+		c.csiImage, err = components.GetReference(components.ComponentCSIPrivate, reg, path, prefix, overrides, is)
 		if err != nil {
 			return err
 		}
 
-		c.csiRegistrarImage, err = components.GetReference(components.ComponentCSINodeDriverRegistrarPrivate, reg, path, prefix, is)
+		c.csiRegistrarImage, err = components.GetReference(components.ComponentCSINodeDriverRegistrarPrivate, reg, path, prefix, overrides, is)
 	} else {
 		if operatorv1.IsFIPSModeEnabled(c.cfg.Installation.FIPSMode) {
-			c.csiImage, err = components.GetReference(components.ComponentCalicoCSIFIPS, reg, path, prefix, is)
+			c.csiImage, err = components.GetReference(components.ComponentCalicoCSIFIPS, reg, path, prefix, overrides, is)
 			if err != nil {
 				return err
 			}
-			c.csiRegistrarImage, err = components.GetReference(components.ComponentCalicoCSIRegistrarFIPS, reg, path, prefix, is)
+			c.csiRegistrarImage, err = components.GetReference(components.ComponentCalicoCSIRegistrarFIPS, reg, path, prefix, overrides, is)
 		} else {
-			c.csiImage, err = components.GetReference(components.ComponentCalicoCSI, reg, path, prefix, is)
+			c.csiImage, err = components.GetReference(components.ComponentCalicoCSI, reg, path, prefix, overrides, is)
 			if err != nil {
 				return err
 			}
 
-			c.csiRegistrarImage, err = components.GetReference(components.ComponentCalicoCSIRegistrar, reg, path, prefix, is)
+			c.csiRegistrarImage, err = components.GetReference(components.ComponentCalicoCSIRegistrar, reg, path, prefix, overrides, is)
 		}
 	}
 
@@ -414,6 +418,13 @@ func (c *csiComponent) Ready() bool {
 	return true
 }
 
+// ServerSideApply opts the CSI objects in to server-side apply when it is enabled for the CSI component, so that
+// fields other field managers set on the csi-node-driver DaemonSet, e.g. tolerations or containers added by an
+// admission webhook, are not reverted.
+func (c *csiComponent) ServerSideApply() bool {
+	return c.cfg.ServerSideApply
+}
+
 func (c *csiComponent) SupportedOSType() rmeta.OSType {
 	return rmeta.OSTypeLinux
 }
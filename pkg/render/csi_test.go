@@ -133,6 +133,16 @@ var _ = Describe("CSI rendering tests", func() {
 		Expect(ds.Spec.Template.Spec.PriorityClassName).To(Equal("system-node-critical"))
 	})
 
+	It("should be written with server-side apply only when it is enabled", func() {
+		comp, ok := render.CSI(&cfg).(render.ServerSideApplyComponent)
+		Expect(ok).To(BeTrue())
+		Expect(comp.ServerSideApply()).To(BeFalse())
+
+		cfg.ServerSideApply = true
+		comp = render.CSI(&cfg).(render.ServerSideApplyComponent)
+		Expect(comp.ServerSideApply()).To(BeTrue())
+	})
+
 	It("should propagate imagePullSecrets and registry Installation field changes to DaemonSet", func() {
 		privatePullSecret := []corev1.LocalObjectReference{
 			{
@@ -87,15 +87,16 @@ func (c *dexComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 	var err error
-	c.image, err = components.GetReference(components.ComponentDex, reg, path, prefix, is)
+	c.image, err = components.GetReference(components.ComponentDex, reg, path, prefix, overrides, is)
 
 	var errMsgs []string
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
 
-	if c.cfg.Installation.CertificateManagement != nil {
+	if c.cfg.Installation.CertificateManagement.UseCSRs() {
 		c.csrInitImage, err = certificatemanagement.ResolveCSRInitImage(c.cfg.Installation, is)
 		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
@@ -134,7 +135,7 @@ func (c *dexComponent) Objects() ([]client.Object, []client.Object) {
 	objs = append(objs, secret.ToRuntimeObjects(c.cfg.DexConfig.RequiredSecrets(DexNamespace)...)...)
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(DexNamespace, c.cfg.PullSecrets...)...)...)
 
-	if c.cfg.Installation.CertificateManagement != nil {
+	if c.cfg.Installation.CertificateManagement.UseCSRs() {
 		objs = append(objs, certificatemanagement.CSRClusterRoleBinding(DexObjectName, DexNamespace))
 	}
 
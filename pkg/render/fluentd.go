@@ -16,7 +16,9 @@ package render
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -79,6 +81,15 @@ const (
 	SysLogPublicCertKey                      = "ca-bundle.crt"
 	SysLogPublicCAPath                       = SysLogPublicCADir + SysLogPublicCertKey
 	SyslogCAConfigMapName                    = "syslog-ca"
+	KafkaFluentdSecretName                   = "logcollector-kafka-credentials"
+	KafkaUsernameKey                         = "username"
+	KafkaPasswordKey                         = "password"
+	HTTPFluentdHeadersSecretName             = "logcollector-http-headers"
+	OTLPFluentdHeadersSecretName             = "logcollector-otlp-headers"
+	FluentdHeadersKey                        = "headers"
+	kafkaCredentialHashAnnotation            = "hash.operator.tigera.io/kafka-credentials"
+	httpHeadersHashAnnotation                = "hash.operator.tigera.io/http-headers"
+	otlpHeadersHashAnnotation                = "hash.operator.tigera.io/otlp-headers"
 
 	// Constants for Linseed token volume mounting in managed clusters.
 	LinseedTokenVolumeName = "linseed-token"
@@ -129,6 +140,23 @@ type SplunkCredential struct {
 	Certificate []byte
 }
 
+// FluentdAdditionalStoresVersions are the released versions of the fluentd images that export logs to the Kafka, HTTP
+// and OTLP stores, which they are configured for by the KAFKA_*, HTTP_* and OTLP_* env vars. No released image reads
+// these env vars yet, so the stores are reported as unsupported until the first release that does, along with a link
+// to the fluentd change that added them, is listed here. Development tags are never listed, as what they ship changes.
+var FluentdAdditionalStoresVersions = map[string]bool{}
+
+// FluentdSupportsAdditionalStores returns true if the fluentd images export logs to the Kafka, HTTP and OTLP stores.
+func FluentdSupportsAdditionalStores() bool {
+	return FluentdAdditionalStoresVersions[components.ComponentFluentd.Version] &&
+		FluentdAdditionalStoresVersions[components.ComponentFluentdWindows.Version]
+}
+
+type KafkaCredential struct {
+	Username []byte
+	Password []byte
+}
+
 func Fluentd(cfg *FluentdConfiguration) Component {
 	return &fluentdComponent{
 		cfg:          cfg,
@@ -151,7 +179,13 @@ type FluentdConfiguration struct {
 	LogCollector   *operatorv1.LogCollector
 	S3Credential   *S3Credential
 	SplkCredential *SplunkCredential
-	Filters        *FluentdFilters
+	// KafkaCredential is the SASL credential of the Kafka store, if it has one.
+	KafkaCredential *KafkaCredential
+	// HTTPHeaders and OTLPHeaders are the headers of the requests to the HTTP and OTLP stores, with the values of the
+	// headers read from Secrets.
+	HTTPHeaders map[string]string
+	OTLPHeaders map[string]string
+	Filters     *FluentdFilters
 	// ESClusterConfig is only populated for when EKSConfig
 	// is also defined
 	ESClusterConfig *relasticsearch.ClusterConfig
@@ -188,15 +222,16 @@ func (c *fluentdComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 
 	if c.cfg.OSType == rmeta.OSTypeWindows {
 		var err error
-		c.image, err = components.GetReference(components.ComponentFluentdWindows, reg, path, prefix, is)
+		c.image, err = components.GetReference(components.ComponentFluentdWindows, reg, path, prefix, overrides, is)
 		return err
 	}
 
 	var err error
-	c.image, err = components.GetReference(components.ComponentFluentd, reg, path, prefix, is)
+	c.image, err = components.GetReference(components.ComponentFluentd, reg, path, prefix, overrides, is)
 	if err != nil {
 		return err
 	}
@@ -290,6 +325,23 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 	if c.cfg.SplkCredential != nil {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(LogCollectorNamespace, c.splunkCredentialSecret()...)...)...)
 	}
+	// The copies of the credentials of the Kafka, HTTP and OTLP stores are removed once their store, or its
+	// credentials, are removed from the LogCollector.
+	if c.cfg.KafkaCredential != nil {
+		objs = append(objs, c.kafkaCredentialSecret())
+	} else {
+		toDelete = append(toDelete, logCollectorSecret(KafkaFluentdSecretName))
+	}
+	if len(c.cfg.HTTPHeaders) > 0 {
+		objs = append(objs, c.headersSecret(HTTPFluentdHeadersSecretName, c.cfg.HTTPHeaders))
+	} else {
+		toDelete = append(toDelete, logCollectorSecret(HTTPFluentdHeadersSecretName))
+	}
+	if len(c.cfg.OTLPHeaders) > 0 {
+		objs = append(objs, c.headersSecret(OTLPFluentdHeadersSecretName, c.cfg.OTLPHeaders))
+	} else {
+		toDelete = append(toDelete, logCollectorSecret(OTLPFluentdHeadersSecretName))
+	}
 	if c.cfg.Filters != nil {
 		objs = append(objs, c.filtersConfigMap())
 	}
@@ -432,6 +484,41 @@ func (c *fluentdComponent) splunkCredentialSecret() []*corev1.Secret {
 	return splunkSecrets
 }
 
+func (c *fluentdComponent) kafkaCredentialSecret() *corev1.Secret {
+	if c.cfg.KafkaCredential == nil {
+		return nil
+	}
+	s := logCollectorSecret(KafkaFluentdSecretName)
+	s.Data = map[string][]byte{
+		KafkaUsernameKey: c.cfg.KafkaCredential.Username,
+		KafkaPasswordKey: c.cfg.KafkaCredential.Password,
+	}
+	return s
+}
+
+// headersSecret returns a secret with the headers of the requests to a store as a JSON object, as some of their values
+// are credentials.
+func (c *fluentdComponent) headersSecret(name string, headers map[string]string) *corev1.Secret {
+	// Marshalling a map of strings can't fail.
+	data, _ := json.Marshal(headers)
+	s := logCollectorSecret(name)
+	s.Data = map[string][]byte{
+		FluentdHeadersKey: data,
+	}
+	return s
+}
+
+// logCollectorSecret returns an empty secret with the name in the fluentd namespace.
+func logCollectorSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: LogCollectorNamespace,
+		},
+	}
+}
+
 func (c *fluentdComponent) fluentdServiceAccount() *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
@@ -507,6 +594,15 @@ func (c *fluentdComponent) daemonset() *appsv1.DaemonSet {
 	if c.cfg.SplkCredential != nil {
 		annots[splunkCredentialHashAnnotation] = rmeta.AnnotationHash(c.cfg.SplkCredential)
 	}
+	if c.cfg.KafkaCredential != nil {
+		annots[kafkaCredentialHashAnnotation] = rmeta.AnnotationHash(c.cfg.KafkaCredential)
+	}
+	if len(c.cfg.HTTPHeaders) > 0 {
+		annots[httpHeadersHashAnnotation] = rmeta.AnnotationHash(c.cfg.HTTPHeaders)
+	}
+	if len(c.cfg.OTLPHeaders) > 0 {
+		annots[otlpHeadersHashAnnotation] = rmeta.AnnotationHash(c.cfg.OTLPHeaders)
+	}
 	if c.cfg.Filters != nil {
 		annots[filterHashAnnotation] = rmeta.AnnotationHash(c.cfg.Filters)
 	}
@@ -809,6 +905,56 @@ func (c *fluentdComponent) envvars() []corev1.EnvVar {
 				)
 			}
 		}
+		kafka := c.cfg.LogCollector.Spec.AdditionalStores.Kafka
+		if kafka != nil {
+			envs = append(envs,
+				corev1.EnvVar{Name: "KAFKA_BROKERS", Value: strings.Join(kafka.Brokers, ",")},
+				corev1.EnvVar{Name: "KAFKA_TOPIC", Value: kafka.Topic},
+				corev1.EnvVar{Name: "KAFKA_FLUSH_INTERVAL", Value: fluentdDefaultFlush},
+			)
+			envs = append(envs, logTypeEnvVars("KAFKA", kafka.LogTypes)...)
+			if kafka.Encryption == operatorv1.EncryptionTLS {
+				envs = append(envs,
+					corev1.EnvVar{Name: "KAFKA_TLS", Value: "true"},
+					corev1.EnvVar{Name: "KAFKA_CA_FILE", Value: c.trustedBundlePath()},
+				)
+			}
+			if c.cfg.KafkaCredential != nil {
+				mechanism := kafka.SASLMechanism
+				if mechanism == "" {
+					mechanism = operatorv1.KafkaSASLPlain
+				}
+				envs = append(envs,
+					corev1.EnvVar{Name: "KAFKA_SASL_MECHANISM", Value: string(mechanism)},
+					secretEnvVar("KAFKA_SASL_USERNAME", KafkaFluentdSecretName, KafkaUsernameKey),
+					secretEnvVar("KAFKA_SASL_PASSWORD", KafkaFluentdSecretName, KafkaPasswordKey),
+				)
+			}
+		}
+		http := c.cfg.LogCollector.Spec.AdditionalStores.HTTP
+		if http != nil {
+			envs = append(envs,
+				corev1.EnvVar{Name: "HTTP_ENDPOINT", Value: http.Endpoint},
+				corev1.EnvVar{Name: "HTTP_CA_FILE", Value: c.trustedBundlePath()},
+				corev1.EnvVar{Name: "HTTP_FLUSH_INTERVAL", Value: fluentdDefaultFlush},
+			)
+			envs = append(envs, logTypeEnvVars("HTTP", http.LogTypes)...)
+			if len(c.cfg.HTTPHeaders) > 0 {
+				envs = append(envs, secretEnvVar("HTTP_HEADERS", HTTPFluentdHeadersSecretName, FluentdHeadersKey))
+			}
+		}
+		otlp := c.cfg.LogCollector.Spec.AdditionalStores.OTLP
+		if otlp != nil {
+			envs = append(envs,
+				corev1.EnvVar{Name: "OTLP_ENDPOINT", Value: otlp.Endpoint},
+				corev1.EnvVar{Name: "OTLP_CA_FILE", Value: c.trustedBundlePath()},
+				corev1.EnvVar{Name: "OTLP_FLUSH_INTERVAL", Value: fluentdDefaultFlush},
+			)
+			envs = append(envs, logTypeEnvVars("OTLP", otlp.LogTypes)...)
+			if len(c.cfg.OTLPHeaders) > 0 {
+				envs = append(envs, secretEnvVar("OTLP_HEADERS", OTLPFluentdHeadersSecretName, FluentdHeadersKey))
+			}
+		}
 	}
 
 	if c.cfg.Filters != nil {
@@ -827,6 +973,41 @@ func (c *fluentdComponent) envvars() []corev1.EnvVar {
 	return envs
 }
 
+// logTypeEnvVars returns the env vars that enable exporting each of the log types to the store with the prefix, in the
+// same way as the syslog log types.
+func logTypeEnvVars(prefix string, logTypes []operatorv1.SyslogLogType) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	for _, t := range logTypes {
+		switch t {
+		case operatorv1.SyslogLogAudit:
+			envs = append(envs,
+				corev1.EnvVar{Name: prefix + "_AUDIT_EE_LOG", Value: "true"},
+				corev1.EnvVar{Name: prefix + "_AUDIT_KUBE_LOG", Value: "true"},
+			)
+		case operatorv1.SyslogLogDNS:
+			envs = append(envs, corev1.EnvVar{Name: prefix + "_DNS_LOG", Value: "true"})
+		case operatorv1.SyslogLogFlows:
+			envs = append(envs, corev1.EnvVar{Name: prefix + "_FLOW_LOG", Value: "true"})
+		case operatorv1.SyslogLogIDSEvents:
+			envs = append(envs, corev1.EnvVar{Name: prefix + "_IDS_EVENT_LOG", Value: "true"})
+		}
+	}
+	return envs
+}
+
+// secretEnvVar returns an env var with the value of a key of a secret in the fluentd namespace.
+func secretEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
 func (c *fluentdComponent) trustedBundlePath() string {
 	if c.cfg.OSType == rmeta.OSTypeWindows {
 		return certificatemanagement.TrustedCertBundleMountPathWindows
@@ -265,7 +265,12 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 		component := render.Fluentd(managedCfg)
 		createResources, deleteResources := component.Objects()
 		rtest.ExpectResources(createResources, expectedResources)
-		Expect(deleteResources).To(BeEmpty())
+		// Without the Kafka, HTTP and OTLP stores, the copies of their credentials are removed.
+		rtest.ExpectResources(deleteResources, []client.Object{
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.KafkaFluentdSecretName, Namespace: render.LogCollectorNamespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.HTTPFluentdHeadersSecretName, Namespace: render.LogCollectorNamespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.OTLPFluentdHeadersSecretName, Namespace: render.LogCollectorNamespace}},
+		})
 
 		// Check the namespace.
 		ns := rtest.GetResource(createResources, "tigera-fluentd", "", "", "v1", "Namespace").(*corev1.Namespace)
@@ -549,6 +554,91 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 			}
 		}
 	})
+	It("should render with Kafka, HTTP and OTLP configuration", func() {
+		cfg.KafkaCredential = &render.KafkaCredential{
+			Username: []byte("user"),
+			Password: []byte("pass"),
+		}
+		cfg.HTTPHeaders = map[string]string{"Authorization": "Bearer token"}
+		cfg.LogCollector.Spec.AdditionalStores = &operatorv1.AdditionalLogStoreSpec{
+			Kafka: &operatorv1.KafkaStoreSpec{
+				Brokers:       []string{"kafka-0:9093", "kafka-1:9093"},
+				Topic:         "calico",
+				LogTypes:      []operatorv1.SyslogLogType{operatorv1.SyslogLogFlows},
+				Encryption:    operatorv1.EncryptionTLS,
+				SASLMechanism: operatorv1.KafkaSASLScramSHA512,
+			},
+			HTTP: &operatorv1.HTTPStoreSpec{
+				Endpoint: "https://logs.example.com/ingest",
+				LogTypes: []operatorv1.SyslogLogType{operatorv1.SyslogLogAudit},
+			},
+			OTLP: &operatorv1.OTLPStoreSpec{
+				Endpoint: "https://otel.example.com:4318/v1/logs",
+				LogTypes: []operatorv1.SyslogLogType{operatorv1.SyslogLogDNS, operatorv1.SyslogLogIDSEvents},
+			},
+		}
+
+		component := render.Fluentd(cfg)
+		resources, toDelete := component.Objects()
+
+		// The OTLP store has no headers, so the copy of its headers is removed.
+		Expect(rtest.GetResource(toDelete, render.OTLPFluentdHeadersSecretName, render.LogCollectorNamespace, "", "v1", "Secret")).NotTo(BeNil())
+		Expect(rtest.GetResource(toDelete, render.KafkaFluentdSecretName, render.LogCollectorNamespace, "", "v1", "Secret")).To(BeNil())
+
+		kafkaSecret := rtest.GetResource(resources, render.KafkaFluentdSecretName, render.LogCollectorNamespace, "", "v1", "Secret").(*corev1.Secret)
+		Expect(kafkaSecret.Data).To(Equal(map[string][]byte{"username": []byte("user"), "password": []byte("pass")}))
+		headersSecret := rtest.GetResource(resources, render.HTTPFluentdHeadersSecretName, render.LogCollectorNamespace, "", "v1", "Secret").(*corev1.Secret)
+		Expect(headersSecret.Data[render.FluentdHeadersKey]).To(MatchJSON(`{"Authorization": "Bearer token"}`))
+		Expect(rtest.GetResource(resources, render.OTLPFluentdHeadersSecretName, render.LogCollectorNamespace, "", "v1", "Secret")).To(BeNil())
+
+		ds := rtest.GetResource(resources, "fluentd-node", "tigera-fluentd", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/kafka-credentials"))
+		Expect(ds.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/http-headers"))
+		Expect(ds.Spec.Template.Annotations).NotTo(HaveKey("hash.operator.tigera.io/otlp-headers"))
+		envs := ds.Spec.Template.Spec.Containers[0].Env
+
+		expectedEnvs := []struct {
+			name       string
+			val        string
+			secretName string
+			secretKey  string
+		}{
+			{"KAFKA_BROKERS", "kafka-0:9093,kafka-1:9093", "", ""},
+			{"KAFKA_TOPIC", "calico", "", ""},
+			{"KAFKA_FLUSH_INTERVAL", "5s", "", ""},
+			{"KAFKA_FLOW_LOG", "true", "", ""},
+			{"KAFKA_TLS", "true", "", ""},
+			{"KAFKA_CA_FILE", "/etc/pki/tls/certs/tigera-ca-bundle.crt", "", ""},
+			{"KAFKA_SASL_MECHANISM", "SCRAM-SHA-512", "", ""},
+			{"KAFKA_SASL_USERNAME", "", "logcollector-kafka-credentials", "username"},
+			{"KAFKA_SASL_PASSWORD", "", "logcollector-kafka-credentials", "password"},
+			{"HTTP_ENDPOINT", "https://logs.example.com/ingest", "", ""},
+			{"HTTP_AUDIT_EE_LOG", "true", "", ""},
+			{"HTTP_AUDIT_KUBE_LOG", "true", "", ""},
+			{"HTTP_HEADERS", "", "logcollector-http-headers", "headers"},
+			{"OTLP_ENDPOINT", "https://otel.example.com:4318/v1/logs", "", ""},
+			{"OTLP_DNS_LOG", "true", "", ""},
+			{"OTLP_IDS_EVENT_LOG", "true", "", ""},
+		}
+		for _, expected := range expectedEnvs {
+			if expected.val != "" {
+				Expect(envs).To(ContainElement(corev1.EnvVar{Name: expected.name, Value: expected.val}))
+			} else {
+				Expect(envs).To(ContainElement(corev1.EnvVar{
+					Name: expected.name,
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: expected.secretName},
+							Key:                  expected.secretKey,
+						},
+					},
+				}))
+			}
+		}
+		for _, env := range envs {
+			Expect(env.Name).NotTo(BeElementOf("KAFKA_DNS_LOG", "HTTP_FLOW_LOG", "OTLP_HEADERS"))
+		}
+	})
 	It("should render with Syslog configuration", func() {
 		expectedResources := []struct {
 			name    string
@@ -102,8 +102,9 @@ func (c *GuardianComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 	var err error
-	c.image, err = components.GetReference(components.ComponentGuardian, reg, path, prefix, is)
+	c.image, err = components.GetReference(components.ComponentGuardian, reg, path, prefix, overrides, is)
 	return err
 }
 
@@ -78,6 +78,7 @@ func (d *dpiComponent) ResolveImages(is *operatorv1.ImageSet) error {
 		d.cfg.Installation.Registry,
 		d.cfg.Installation.ImagePath,
 		d.cfg.Installation.ImagePrefix,
+		d.cfg.Installation.ImageOverrides,
 		is)
 	if err != nil {
 		return err
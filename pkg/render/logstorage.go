@@ -19,7 +19,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 
 	cmnv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -145,6 +147,20 @@ const (
 	csrRootCAConfigMapName    = "elasticsearch-config"
 )
 
+// Snapshot constants.
+const (
+	// ElasticsearchSnapshotCredentialsSecret holds the S3 credentials of the snapshot repository in the keystore format
+	// Elasticsearch expects. It is created from the secret named in the LogStorage's S3 snapshot repository.
+	ElasticsearchSnapshotCredentialsSecret = "tigera-elasticsearch-snapshot-credentials"
+	// SnapshotAccessKeyName and SnapshotSecretKeyName are the keys of the user's S3 credentials secret.
+	SnapshotAccessKeyName = "access-key"
+	SnapshotSecretKeyName = "secret-key"
+	// ElasticsearchSnapshotsPath is where a filesystem snapshot repository is mounted in the Elasticsearch pods.
+	ElasticsearchSnapshotsPath = "/usr/share/elasticsearch/snapshots"
+
+	snapshotsVolumeName = "snapshots"
+)
+
 // Certificate management constants.
 const (
 	// Volume that is added by ECK and is overridden if certificate management is used.
@@ -215,6 +231,17 @@ type ElasticsearchConfiguration struct {
 	KeyStoreSecret          *corev1.Secret
 	KibanaEnabled           bool
 
+	// SnapshotCredentialsSecret is the user's secret with the credentials of the S3 snapshot repository, if any.
+	SnapshotCredentialsSecret *corev1.Secret
+
+	// HoldElasticsearchUpgrade keeps the version and image of the existing Elasticsearch cluster, while the pre-flight
+	// checks of an upgrade to the operator's version have not passed. The existing Kibana and ECK operator keep theirs
+	// as well, as they must stay compatible with the Elasticsearch cluster.
+	HoldElasticsearchUpgrade bool
+
+	// ECKOperator is the existing ECK operator StatefulSet, if any, whose image is kept while the upgrade is held.
+	ECKOperator *appsv1.StatefulSet
+
 	// Whether the cluster supports pod security policies.
 	UsePSP bool
 }
@@ -232,28 +259,42 @@ func (es *elasticsearchComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := es.cfg.Installation.Registry
 	path := es.cfg.Installation.ImagePath
 	prefix := es.cfg.Installation.ImagePrefix
+	overrides := es.cfg.Installation.ImageOverrides
 	var err error
 	if operatorv1.IsFIPSModeEnabled(es.cfg.Installation.FIPSMode) {
-		es.esImage, err = components.GetReference(components.ComponentElasticsearchFIPS, reg, path, prefix, is)
+		es.esImage, err = components.GetReference(components.ComponentElasticsearchFIPS, reg, path, prefix, overrides, is)
 	} else {
-		es.esImage, err = components.GetReference(components.ComponentElasticsearch, reg, path, prefix, is)
+		es.esImage, err = components.GetReference(components.ComponentElasticsearch, reg, path, prefix, overrides, is)
 	}
 	errMsgs := make([]string, 0)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
+	if es.holdUpgrade() {
+		es.esImage = es.cfg.Elasticsearch.Spec.Image
+	}
 
-	es.esOperatorImage, err = components.GetReference(components.ComponentElasticsearchOperator, reg, path, prefix, is)
+	es.esOperatorImage, err = components.GetReference(components.ComponentElasticsearchOperator, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
+	if es.holdUpgrade() && es.cfg.ECKOperator != nil {
+		for _, c := range es.cfg.ECKOperator.Spec.Template.Spec.Containers {
+			if c.Name == "manager" {
+				es.esOperatorImage = c.Image
+			}
+		}
+	}
 
-	es.kibanaImage, err = components.GetReference(components.ComponentKibana, reg, path, prefix, is)
+	es.kibanaImage, err = components.GetReference(components.ComponentKibana, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
+	if es.holdUpgrade() && es.cfg.Kibana != nil {
+		es.kibanaImage = es.cfg.Kibana.Spec.Image
+	}
 
-	if es.cfg.Installation.CertificateManagement != nil {
+	if es.cfg.Installation.CertificateManagement.UseCSRs() {
 		es.csrImage, err = certificatemanagement.ResolveCSRInitImage(es.cfg.Installation, is)
 		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
@@ -349,6 +390,15 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 	toCreate = append(toCreate, es.elasticsearchServiceAccount())
 	toCreate = append(toCreate, es.cfg.ClusterConfig.ConfigMap())
 
+	if es.cfg.SnapshotCredentialsSecret != nil {
+		toCreate = append(toCreate, es.snapshotCredentialsSecret())
+	} else {
+		toDelete = append(toDelete, &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: ElasticsearchSnapshotCredentialsSecret, Namespace: ElasticsearchNamespace},
+		})
+	}
+
 	toCreate = append(toCreate, es.elasticsearchCluster())
 
 	if es.cfg.KibanaEnabled {
@@ -375,7 +425,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 	} else {
 		if es.cfg.KeyStoreSecret != nil {
 			if operatorv1.IsFIPSModeEnabled(es.cfg.Installation.FIPSMode) {
-				es.cfg.KeyStoreSecret.Data["ES_JAVA_OPTS"] = []byte(es.javaOpts())
+				es.cfg.KeyStoreSecret.Data["ES_JAVA_OPTS"] = []byte(es.javaOpts(nil))
 			}
 
 			toCreate = append(toCreate, es.cfg.KeyStoreSecret)
@@ -401,7 +451,7 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 		toDelete = append(toDelete, es.cfg.KbService)
 	}
 
-	if es.cfg.Installation.CertificateManagement != nil {
+	if es.cfg.Installation.CertificateManagement.UseCSRs() {
 		toCreate = append(toCreate, es.cfg.UnusedTLSSecret)
 		if es.cfg.ElasticsearchKeyPair.UseCertificateManagement() {
 			// We need to render a secret. It won't ever be used by Elasticsearch for TLS, but is needed to pass ECK's checks.
@@ -437,8 +487,13 @@ func (es elasticsearchComponent) elasticsearchServiceAccount() *corev1.ServiceAc
 	}
 }
 
-// generate the PVC required for the Elasticsearch nodes
-func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
+// generate the PVC required for the Elasticsearch nodes, or for the nodes in the given NodeSet
+func (es elasticsearchComponent) pvcTemplate(nodeSet *operatorv1.NodeSet) corev1.PersistentVolumeClaim {
+	storageClassName := es.cfg.LogStorage.Spec.StorageClassName
+	if nodeSet != nil && nodeSet.StorageClassName != "" {
+		storageClassName = nodeSet.StorageClassName
+	}
+
 	pvcTemplate := corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "elasticsearch-data", // ECK requires this name
@@ -450,7 +505,7 @@ func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
 					"storage": resource.MustParse(fmt.Sprintf("%dGi", DefaultElasticStorageGi)),
 				},
 			},
-			StorageClassName: &es.cfg.LogStorage.Spec.StorageClassName,
+			StorageClassName: &storageClassName,
 		},
 	}
 
@@ -459,11 +514,15 @@ func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
 		userOverrides := *es.cfg.LogStorage.Spec.Nodes.ResourceRequirements
 		pvcTemplate.Spec.Resources = overridePvcRequirements(pvcTemplate.Spec.Resources, userOverrides)
 	}
+	if nodeSet != nil && nodeSet.ResourceRequirements != nil {
+		pvcTemplate.Spec.Resources = overridePvcRequirements(pvcTemplate.Spec.Resources, *nodeSet.ResourceRequirements)
+	}
 
 	return pvcTemplate
 }
 
-func (es elasticsearchComponent) resourceRequirements() corev1.ResourceRequirements {
+// resourceRequirements returns the resources of the Elasticsearch nodes, or of the nodes in the given NodeSet.
+func (es elasticsearchComponent) resourceRequirements(nodeSet *operatorv1.NodeSet) corev1.ResourceRequirements {
 	resources := corev1.ResourceRequirements{
 		Limits: corev1.ResourceList{
 			"cpu":    resource.MustParse("1"),
@@ -478,13 +537,18 @@ func (es elasticsearchComponent) resourceRequirements() corev1.ResourceRequireme
 		userOverrides := *es.cfg.LogStorage.Spec.Nodes.ResourceRequirements
 		resources = overrideResourceRequirements(resources, userOverrides)
 	}
+	if nodeSet != nil && nodeSet.ResourceRequirements != nil {
+		resources = overrideResourceRequirements(resources, *nodeSet.ResourceRequirements)
+	}
 	return resources
 }
 
-func (es elasticsearchComponent) javaOpts() string {
+// javaOpts returns the JVM options of the Elasticsearch nodes, or of the nodes in the given NodeSet.
+func (es elasticsearchComponent) javaOpts(nodeSet *operatorv1.NodeSet) string {
 	var javaOpts string
-	resources := es.resourceRequirements()
-	if es.cfg.LogStorage.Spec.Nodes != nil && es.cfg.LogStorage.Spec.Nodes.ResourceRequirements != nil {
+	resources := es.resourceRequirements(nodeSet)
+	if (es.cfg.LogStorage.Spec.Nodes != nil && es.cfg.LogStorage.Spec.Nodes.ResourceRequirements != nil) ||
+		(nodeSet != nil && nodeSet.ResourceRequirements != nil) {
 		// Now extract the memory request value to compute the recommended heap size for ES container
 		recommendedHeapSize := memoryQuantityToJVMHeapSize(resources.Requests.Memory())
 		javaOpts = fmt.Sprintf("-Xms%v -Xmx%v", recommendedHeapSize, recommendedHeapSize)
@@ -502,7 +566,7 @@ func (es elasticsearchComponent) javaOpts() string {
 }
 
 // Generate the pod template required for the ElasticSearch nodes (controls the ElasticSearch container)
-func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
+func (es elasticsearchComponent) podTemplate(nodeSet *operatorv1.NodeSet) corev1.PodTemplateSpec {
 	// Setup default configuration for ES container. For more information on managing resources, see:
 	// https://www.elastic.co/guide/en/cloud-on-k8s/current/k8s-managing-compute-resources.html and
 	// https://www.elastic.co/guide/en/cloud-on-k8s/current/k8s-jvm-heap-size.html#k8s-jvm-heap-size
@@ -533,7 +597,7 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 	} else {
 		env = append(env, corev1.EnvVar{
 			Name:  "ES_JAVA_OPTS",
-			Value: es.javaOpts(),
+			Value: es.javaOpts(nodeSet),
 		})
 	}
 
@@ -561,7 +625,7 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 			InitialDelaySeconds: 30,
 			TimeoutSeconds:      20,
 		},
-		Resources:       es.resourceRequirements(),
+		Resources:       es.resourceRequirements(nodeSet),
 		SecurityContext: sc,
 		Env:             env,
 	}
@@ -624,7 +688,7 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 	var volumes []corev1.Volume
 
 	var autoMountToken bool
-	if es.cfg.Installation.CertificateManagement != nil {
+	if es.cfg.Installation.CertificateManagement.UseCSRs() {
 		// If certificate management is used, we need to override a mounting options for this init container.
 		initFSName := "elastic-internal-init-filesystem"
 		initFSContainer := corev1.Container{
@@ -711,6 +775,16 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 		)
 	}
 
+	if repo := es.snapshotRepository(); repo != nil && repo.Filesystem != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: snapshotsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: repo.Filesystem.PersistentVolumeClaimName},
+			},
+		})
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, corev1.VolumeMount{Name: snapshotsVolumeName, MountPath: ElasticsearchSnapshotsPath})
+	}
+
 	// default to controlPlaneNodeSelector unless DataNodeSelector is set
 	nodeSels := es.cfg.Installation.ControlPlaneNodeSelector
 	if es.cfg.LogStorage.Spec.DataNodeSelector != nil {
@@ -736,6 +810,27 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 	return podTemplate
 }
 
+// holdUpgrade returns whether the existing Elasticsearch cluster keeps its version and image.
+func (es elasticsearchComponent) holdUpgrade() bool {
+	return es.cfg.HoldElasticsearchUpgrade && es.cfg.Elasticsearch != nil
+}
+
+// elasticsearchVersion returns the version of Elasticsearch to run.
+func (es elasticsearchComponent) elasticsearchVersion() string {
+	if es.holdUpgrade() {
+		return es.cfg.Elasticsearch.Spec.Version
+	}
+	return components.ComponentEckElasticsearch.Version
+}
+
+// kibanaVersion returns the version of Kibana to run.
+func (es elasticsearchComponent) kibanaVersion() string {
+	if es.holdUpgrade() && es.cfg.Kibana != nil {
+		return es.cfg.Kibana.Spec.Version
+	}
+	return components.ComponentEckKibana.Version
+}
+
 // render the Elasticsearch CR that the ECK operator uses to create elasticsearch cluster
 func (es elasticsearchComponent) elasticsearchCluster() *esv1.Elasticsearch {
 	elasticsearch := &esv1.Elasticsearch{
@@ -745,7 +840,7 @@ func (es elasticsearchComponent) elasticsearchCluster() *esv1.Elasticsearch {
 			Namespace: ElasticsearchNamespace,
 		},
 		Spec: esv1.ElasticsearchSpec{
-			Version: components.ComponentEckElasticsearch.Version,
+			Version: es.elasticsearchVersion(),
 			Image:   es.esImage,
 			HTTP: cmnv1.HTTPConfig{
 				TLS: cmnv1.TLSOptions{
@@ -758,9 +853,57 @@ func (es elasticsearchComponent) elasticsearchCluster() *esv1.Elasticsearch {
 		},
 	}
 
+	if es.cfg.SnapshotCredentialsSecret != nil {
+		elasticsearch.Spec.SecureSettings = []cmnv1.SecretSource{{SecretName: ElasticsearchSnapshotCredentialsSecret}}
+	}
+
 	return elasticsearch
 }
 
+// snapshotRepository returns the LogStorage's snapshot repository, or nil if snapshots are not configured.
+func (es elasticsearchComponent) snapshotRepository() *operatorv1.SnapshotRepository {
+	if es.cfg.LogStorage == nil || es.cfg.LogStorage.Spec.Snapshots == nil {
+		return nil
+	}
+	return &es.cfg.LogStorage.Spec.Snapshots.Repository
+}
+
+// s3EgressDestination returns the destination of the S3 endpoint: the host and port of the endpoint, or the AWS S3
+// domains over HTTPS if no endpoint is set.
+func s3EgressDestination(endpoint string) v3.EntityRule {
+	u, err := url.Parse(endpoint)
+	if endpoint == "" || err != nil || u.Hostname() == "" {
+		return v3.EntityRule{Domains: []string{"*.amazonaws.com"}, Ports: networkpolicy.Ports(443)}
+	}
+	port := uint16(443)
+	if p, err := strconv.ParseUint(u.Port(), 10, 16); err == nil {
+		port = uint16(p)
+	} else if u.Scheme == "http" {
+		port = 80
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		netSuffix := "/32"
+		if ip.To4() == nil {
+			netSuffix = "/128"
+		}
+		return v3.EntityRule{Nets: []string{ip.String() + netSuffix}, Ports: networkpolicy.Ports(port)}
+	}
+	return v3.EntityRule{Domains: []string{u.Hostname()}, Ports: networkpolicy.Ports(port)}
+}
+
+// snapshotCredentialsSecret copies the user's S3 credentials into the Elasticsearch namespace, using the keystore keys of
+// the default S3 client.
+func (es elasticsearchComponent) snapshotCredentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ElasticsearchSnapshotCredentialsSecret, Namespace: ElasticsearchNamespace},
+		Data: map[string][]byte{
+			"s3.client.default.access_key": es.cfg.SnapshotCredentialsSecret.Data[SnapshotAccessKeyName],
+			"s3.client.default.secret_key": es.cfg.SnapshotCredentialsSecret.Data[SnapshotSecretKeyName],
+		},
+	}
+}
+
 // Determine the recommended JVM heap size as a string (with appropriate unit suffix) based on
 // the given resource.Quantity.
 //
@@ -832,7 +975,7 @@ func memoryQuantityToJVMHeapSize(q *resource.Quantity) string {
 // evenly as possible between the NodeSets.
 func (es elasticsearchComponent) nodeSets() []esv1.NodeSet {
 	nodeConfig := es.cfg.LogStorage.Spec.Nodes
-	pvcTemplate := es.pvcTemplate()
+	pvcTemplate := es.pvcTemplate(nil)
 
 	if nodeConfig == nil {
 		// If we return a nil nodesets, this means the generated ElasticSearch CR will not be valid
@@ -847,32 +990,59 @@ func (es elasticsearchComponent) nodeSets() []esv1.NodeSet {
 		nodeSet := es.nodeSetTemplate(pvcTemplate)
 		nodeSet.Name = nodeSetName(pvcTemplate)
 		nodeSet.Count = int32(nodeConfig.Count)
-		nodeSet.PodTemplate = es.podTemplate()
+		nodeSet.PodTemplate = es.podTemplate(nil)
 
 		nodeSets = append(nodeSets, nodeSet)
 	} else {
-		baseNumNodes := nodeConfig.Count / int64(len(nodeConfig.NodeSets))
-
-		for i, nodeSetConfig := range nodeConfig.NodeSets {
-			numNodes := baseNumNodes
-			// Increase the first nodeConfig.Count % nodeConfig.NodeSets by 1, so that the sum of nodes in each
-			// NodeSet is equal to nodeConfig.Count.
-			if int64(i) < nodeConfig.Count%int64(len(nodeConfig.NodeSets)) {
-				numNodes++
+		// The NodeSets without a count share the nodes that are not in a NodeSet with a count.
+		sharedCount, numShared := nodeConfig.Count, int64(0)
+		for _, nodeSetConfig := range nodeConfig.NodeSets {
+			if nodeSetConfig.Count > 0 {
+				sharedCount -= nodeSetConfig.Count
+			} else {
+				numShared++
 			}
+		}
+		var baseNumNodes int64
+		if numShared > 0 && sharedCount > 0 {
+			baseNumNodes = sharedCount / numShared
+		}
 
-			// Don't create a NodeSet with 0 Nodes.
+		shared := int64(0)
+		for i := range nodeConfig.NodeSets {
+			nodeSetConfig := &nodeConfig.NodeSets[i]
+			numNodes := nodeSetConfig.Count
 			if numNodes < 1 {
-				// If count is less than 1 this iteration it will be less than one all subsequent iterations.
-				break
+				numNodes = baseNumNodes
+				// Increase the first sharedCount % numShared by 1, so that the sum of nodes in each NodeSet is equal to
+				// nodeConfig.Count.
+				if sharedCount > 0 && shared < sharedCount%numShared {
+					numNodes++
+				}
+				shared++
 			}
 
-			nodeSet := es.nodeSetTemplate(pvcTemplate)
+			// Don't create a NodeSet with 0 Nodes. The LogStorage validation rejects counts that leave no nodes for the
+			// NodeSets without a count, so this only happens when spec.nodes.count is less than the number of NodeSets.
+			if numNodes < 1 {
+				continue
+			}
+
+			nodeSetPVCTemplate := es.pvcTemplate(nodeSetConfig)
+			nodeSet := es.nodeSetTemplate(nodeSetPVCTemplate)
 			// Each NodeSet needs a unique name, so just add the index as a suffix
-			nodeSet.Name = fmt.Sprintf("%s-%d", nodeSetName(pvcTemplate), i)
+			nodeSet.Name = fmt.Sprintf("%s-%d", nodeSetName(nodeSetPVCTemplate), i)
 			nodeSet.Count = int32(numNodes)
 
-			podTemplate := es.podTemplate()
+			if len(nodeSetConfig.Roles) > 0 {
+				// The legacy role settings can't be combined with node.roles.
+				delete(nodeSet.Config.Data, "node.master")
+				delete(nodeSet.Config.Data, "node.data")
+				delete(nodeSet.Config.Data, "node.ingest")
+				nodeSet.Config.Data["node.roles"] = elasticsearchNodeRoles(nodeSetConfig.Roles)
+			}
+
+			podTemplate := es.podTemplate(nodeSetConfig)
 
 			// If SelectionAttributes is set that means that the user wants the Elasticsearch Nodes and Replicas
 			// spread out across K8s nodes with specific attributes, like availability zone. Therefore, the Node Affinity
@@ -920,6 +1090,29 @@ func (es elasticsearchComponent) nodeSets() []esv1.NodeSet {
 	return nodeSets
 }
 
+// elasticsearchNodeRoles returns the Elasticsearch node.roles for the roles of a NodeSet. Hot nodes also hold the
+// content tier, which is where Elasticsearch allocates new indices that are not part of a data stream.
+func elasticsearchNodeRoles(roles []operatorv1.ElasticsearchNodeRole) []string {
+	var esRoles []string
+	for _, role := range roles {
+		switch role {
+		case operatorv1.ElasticsearchNodeRoleMaster:
+			esRoles = append(esRoles, "master")
+		case operatorv1.ElasticsearchNodeRoleIngest:
+			esRoles = append(esRoles, "ingest")
+		case operatorv1.ElasticsearchNodeRoleHot:
+			esRoles = append(esRoles, "data_hot", "data_content")
+		case operatorv1.ElasticsearchNodeRoleWarm:
+			esRoles = append(esRoles, "data_warm")
+		case operatorv1.ElasticsearchNodeRoleCold:
+			esRoles = append(esRoles, "data_cold")
+		case operatorv1.ElasticsearchNodeRoleFrozen:
+			esRoles = append(esRoles, "data_frozen")
+		}
+	}
+	return esRoles
+}
+
 // nodeSetTemplate returns a NodeSet with default values needed for all Elasticsearch cluster setups.
 //
 // Note that this does not return a complete NodeSet, fields like Name and Count will at least need to be set on the returned
@@ -934,13 +1127,27 @@ func (es elasticsearchComponent) nodeSetTemplate(pvcTemplate corev1.PersistentVo
 		"ingest.geoip.downloader.enabled": false,
 	}
 
-	if es.cfg.Installation.CertificateManagement != nil {
+	if es.cfg.Installation.CertificateManagement.UseCSRs() {
 		config["xpack.security.http.ssl.certificate_authorities"] = []string{"/usr/share/elasticsearch/config/http-certs/ca.crt"}
 	}
 	if operatorv1.IsFIPSModeEnabled(es.cfg.Installation.FIPSMode) {
 		config["xpack.security.fips_mode.enabled"] = "true"
 		config["xpack.security.authc.password_hashing.algorithm"] = "pbkdf2_stretch"
 	}
+	if repo := es.snapshotRepository(); repo != nil {
+		if repo.Filesystem != nil {
+			config["path.repo"] = []string{ElasticsearchSnapshotsPath}
+		}
+		if repo.S3 != nil && repo.S3.Endpoint != "" {
+			if u, err := url.Parse(repo.S3.Endpoint); err == nil && u.Host != "" {
+				config["s3.client.default.endpoint"] = u.Host
+				config["s3.client.default.protocol"] = u.Scheme
+			}
+		}
+		if repo.S3 != nil && repo.S3.PathStyleAccess {
+			config["s3.client.default.path_style_access"] = true
+		}
+	}
 
 	return esv1.NodeSet{
 		// This is configuration that ends up in /usr/share/elasticsearch/config/elasticsearch.yml on the Elastic container.
@@ -1271,7 +1478,7 @@ func (es elasticsearchComponent) kibanaCR() *kbv1.Kibana {
 	var volumes []corev1.Volume
 	var automountToken bool
 	var volumeMounts []corev1.VolumeMount
-	if es.cfg.Installation.CertificateManagement != nil {
+	if es.cfg.Installation.CertificateManagement.UseCSRs() {
 		config["elasticsearch.ssl.certificateAuthorities"] = []string{"/mnt/elastic-internal/http-certs/ca.crt"}
 		automountToken = true
 		csrInitContainer := certificatemanagement.CreateCSRInitContainer(
@@ -1321,7 +1528,7 @@ func (es elasticsearchComponent) kibanaCR() *kbv1.Kibana {
 			},
 		},
 		Spec: kbv1.KibanaSpec{
-			Version: components.ComponentEckKibana.Version,
+			Version: es.kibanaVersion(),
 			Image:   es.kibanaImage,
 			Config: &cmnv1.Config{
 				Data: config,
@@ -1650,6 +1857,13 @@ func (es *elasticsearchComponent) elasticsearchAllowTigeraPolicy() *v3.NetworkPo
 			Destination: networkpolicy.KubeAPIServerServiceSelectorEntityRule,
 		},
 	}...)
+	if repo := es.snapshotRepository(); repo != nil && repo.S3 != nil {
+		egressRules = append(egressRules, v3.Rule{
+			Action:      v3.Allow,
+			Protocol:    &networkpolicy.TCPProtocol,
+			Destination: s3EgressDestination(repo.S3.Endpoint),
+		})
+	}
 
 	elasticSearchIngressDestinationEntityRule := v3.EntityRule{
 		Ports: networkpolicy.Ports(ElasticsearchDefaultPort),
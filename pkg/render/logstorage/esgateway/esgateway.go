@@ -92,14 +92,15 @@ func (e *esGateway) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := e.cfg.Installation.Registry
 	path := e.cfg.Installation.ImagePath
 	prefix := e.cfg.Installation.ImagePrefix
+	overrides := e.cfg.Installation.ImageOverrides
 	var err error
 	errMsgs := []string{}
 
-	e.esGatewayImage, err = components.GetReference(components.ComponentESGateway, reg, path, prefix, is)
+	e.esGatewayImage, err = components.GetReference(components.ComponentESGateway, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
-	if e.cfg.Installation.CertificateManagement != nil {
+	if e.cfg.Installation.CertificateManagement.UseCSRs() {
 		e.csrImage, err = certificatemanagement.ResolveCSRInitImage(e.cfg.Installation, is)
 		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
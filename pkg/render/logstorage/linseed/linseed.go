@@ -130,16 +130,17 @@ func (l *linseed) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := l.cfg.Installation.Registry
 	path := l.cfg.Installation.ImagePath
 	prefix := l.cfg.Installation.ImagePrefix
+	overrides := l.cfg.Installation.ImageOverrides
 	var err error
 	errMsgs := []string{}
 
 	// Calculate the image(s) to use for Linseed, given user registry configuration.
-	l.linseedImage, err = components.GetReference(components.ComponentLinseed, reg, path, prefix, is)
+	l.linseedImage, err = components.GetReference(components.ComponentLinseed, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
 
-	if l.cfg.Installation.CertificateManagement != nil {
+	if l.cfg.Installation.CertificateManagement.UseCSRs() {
 		l.csrImage, err = certificatemanagement.ResolveCSRInitImage(l.cfg.Installation, is)
 		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	cmnv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 
@@ -43,6 +44,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
@@ -225,6 +227,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				createResources, deleteResources := component.Objects()
 				rtest.ExpectResources(createResources, expectedCreateResources)
 				compareResources(deleteResources, []resourceTestObj{
+					{render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, &corev1.Secret{}, nil},
 					{render.ESCuratorName, render.ElasticsearchNamespace, &batchv1.CronJob{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRole{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRoleBinding{}, nil},
@@ -498,6 +501,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				}
 
 				expectedDeleteResources := []resourceTestObj{
+					{render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, &corev1.Secret{}, nil},
 					{render.ESCuratorName, render.ElasticsearchNamespace, &batchv1.CronJob{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRole{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRoleBinding{}, nil},
@@ -582,6 +586,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 
 				compareResources(createResources, expectedCreateResources)
 				compareResources(deleteResources, []resourceTestObj{
+					{render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, &corev1.Secret{}, nil},
 					{render.ESCuratorName, render.ElasticsearchNamespace, &batchv1.CronJob{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRole{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRoleBinding{}, nil},
@@ -663,6 +668,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 
 				compareResources(createResources, expectedCreateResources)
 				compareResources(deleteResources, []resourceTestObj{
+					{render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, &corev1.Secret{}, nil},
 					{render.ESCuratorName, render.ElasticsearchNamespace, &batchv1.CronJob{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRole{}, nil},
 					{render.ESCuratorName, "", &rbacv1.ClusterRoleBinding{}, nil},
@@ -773,6 +779,138 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 			Expect(nodeSelectors["k2"]).To(Equal("v2"))
 		})
 
+		It("should keep the version and image of the existing cluster while its upgrade is held", func() {
+			cfg.Elasticsearch = &esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "7.16.3", Image: "tigera/elasticsearch:old"}}
+			cfg.HoldElasticsearchUpgrade = true
+			component := render.LogStorage(cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+
+			createResources, _ := component.Objects()
+			es := getElasticsearch(createResources)
+			Expect(es.Spec.Version).To(Equal("7.16.3"))
+			Expect(es.Spec.Image).To(Equal("tigera/elasticsearch:old"))
+			Expect(es.Spec.NodeSets[0].PodTemplate.Spec.InitContainers[0].Image).To(Equal("tigera/elasticsearch:old"))
+
+			cfg.HoldElasticsearchUpgrade = false
+			component = render.LogStorage(cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			createResources, _ = component.Objects()
+			Expect(getElasticsearch(createResources).Spec.Version).To(Equal(components.ComponentEckElasticsearch.Version))
+		})
+
+		It("should keep the version and image of the existing Kibana and ECK operator while the upgrade is held", func() {
+			cfg.Elasticsearch = &esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "7.16.3", Image: "tigera/elasticsearch:old"}}
+			cfg.Kibana = &kbv1.Kibana{Spec: kbv1.KibanaSpec{Version: "7.16.3", Image: "tigera/kibana:old"}}
+			cfg.ECKOperator = &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: "tigera/eck-operator:old"}},
+			}}}}
+			cfg.HoldElasticsearchUpgrade = true
+			component := render.LogStorage(cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+
+			createResources, _ := component.Objects()
+			kb := rtest.GetResource(createResources, render.KibanaName, render.KibanaNamespace, "kibana.k8s.elastic.co", "v1", "Kibana").(*kbv1.Kibana)
+			Expect(kb.Spec.Version).To(Equal("7.16.3"))
+			Expect(kb.Spec.Image).To(Equal("tigera/kibana:old"))
+			eck := rtest.GetResource(createResources, render.ECKOperatorName, render.ECKOperatorNamespace, "apps", "v1", "StatefulSet").(*appsv1.StatefulSet)
+			Expect(eck.Spec.Template.Spec.Containers[0].Image).To(Equal("tigera/eck-operator:old"))
+			Expect(eck.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "OPERATOR_IMAGE", Value: "tigera/eck-operator:old"}))
+
+			cfg.HoldElasticsearchUpgrade = false
+			component = render.LogStorage(cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			createResources, _ = component.Objects()
+			kb = rtest.GetResource(createResources, render.KibanaName, render.KibanaNamespace, "kibana.k8s.elastic.co", "v1", "Kibana").(*kbv1.Kibana)
+			Expect(kb.Spec.Version).To(Equal(components.ComponentEckKibana.Version))
+			Expect(kb.Spec.Image).NotTo(Equal("tigera/kibana:old"))
+			eck = rtest.GetResource(createResources, render.ECKOperatorName, render.ECKOperatorNamespace, "apps", "v1", "StatefulSet").(*appsv1.StatefulSet)
+			Expect(eck.Spec.Template.Spec.Containers[0].Image).NotTo(Equal("tigera/eck-operator:old"))
+		})
+
+		It("should mount a filesystem snapshot repository in the Elasticsearch pods", func() {
+			cfg.LogStorage.Spec.Snapshots = &operatorv1.ElasticsearchSnapshots{
+				Repository: operatorv1.SnapshotRepository{
+					Filesystem: &operatorv1.FilesystemSnapshotRepository{PersistentVolumeClaimName: "es-snapshots"},
+				},
+			}
+			component := render.LogStorage(cfg)
+
+			createResources, deleteResources := component.Objects()
+			nodeSet := getElasticsearch(createResources).Spec.NodeSets[0]
+			Expect(nodeSet.Config.Data["path.repo"]).To(Equal([]string{render.ElasticsearchSnapshotsPath}))
+			Expect(nodeSet.PodTemplate.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "snapshots",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "es-snapshots"},
+				},
+			}))
+			Expect(nodeSet.PodTemplate.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name: "snapshots", MountPath: render.ElasticsearchSnapshotsPath,
+			}))
+			Expect(rtest.GetResource(deleteResources, render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, "", "v1", "Secret")).NotTo(BeNil())
+		})
+
+		It("should configure an S3 snapshot repository and its credentials", func() {
+			cfg.LogStorage.Spec.Snapshots = &operatorv1.ElasticsearchSnapshots{
+				Repository: operatorv1.SnapshotRepository{
+					S3: &operatorv1.S3SnapshotRepository{
+						Bucket:                "backups",
+						Endpoint:              "http://minio.minio.svc:9000",
+						PathStyleAccess:       true,
+						CredentialsSecretName: "s3-creds",
+					},
+				},
+			}
+			cfg.SnapshotCredentialsSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"access-key": []byte("access"), "secret-key": []byte("secret")},
+			}
+			component := render.LogStorage(cfg)
+
+			createResources, _ := component.Objects()
+			es := getElasticsearch(createResources)
+			Expect(es.Spec.SecureSettings).To(Equal([]cmnv1.SecretSource{{SecretName: render.ElasticsearchSnapshotCredentialsSecret}}))
+			config := es.Spec.NodeSets[0].Config.Data
+			Expect(config["s3.client.default.endpoint"]).To(Equal("minio.minio.svc:9000"))
+			Expect(config["s3.client.default.protocol"]).To(Equal("http"))
+			Expect(config["s3.client.default.path_style_access"]).To(Equal(true))
+			Expect(config).NotTo(HaveKey("path.repo"))
+
+			secret := rtest.GetResource(createResources, render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, "", "v1", "Secret").(*corev1.Secret)
+			Expect(secret.Data).To(Equal(map[string][]byte{
+				"s3.client.default.access_key": []byte("access"),
+				"s3.client.default.secret_key": []byte("secret"),
+			}))
+
+			policy := rtest.GetResource(createResources, render.ElasticsearchPolicyName, render.ElasticsearchNamespace, "projectcalico.org", "v3", "NetworkPolicy").(*v3.NetworkPolicy)
+			Expect(policy.Spec.Egress).To(ContainElement(v3.Rule{
+				Action:      v3.Allow,
+				Protocol:    &networkpolicy.TCPProtocol,
+				Destination: v3.EntityRule{Domains: []string{"minio.minio.svc"}, Ports: networkpolicy.Ports(9000)},
+			}))
+		})
+
+		DescribeTable("should only allow egress to the S3 endpoint",
+			func(endpoint string, expected v3.EntityRule) {
+				cfg.LogStorage.Spec.Snapshots = &operatorv1.ElasticsearchSnapshots{
+					Repository: operatorv1.SnapshotRepository{
+						S3: &operatorv1.S3SnapshotRepository{Bucket: "backups", Endpoint: endpoint, CredentialsSecretName: "s3-creds"},
+					},
+				}
+				createResources, _ := render.LogStorage(cfg).Objects()
+				policy := rtest.GetResource(createResources, render.ElasticsearchPolicyName, render.ElasticsearchNamespace, "projectcalico.org", "v3", "NetworkPolicy").(*v3.NetworkPolicy)
+				Expect(policy.Spec.Egress).To(ContainElement(v3.Rule{
+					Action:      v3.Allow,
+					Protocol:    &networkpolicy.TCPProtocol,
+					Destination: expected,
+				}))
+			},
+			Entry("AWS S3", "", v3.EntityRule{Domains: []string{"*.amazonaws.com"}, Ports: networkpolicy.Ports(443)}),
+			Entry("an HTTPS host", "https://s3.example.com", v3.EntityRule{Domains: []string{"s3.example.com"}, Ports: networkpolicy.Ports(443)}),
+			Entry("an IPv4 address", "http://10.0.0.5:9000", v3.EntityRule{Nets: []string{"10.0.0.5/32"}, Ports: networkpolicy.Ports(9000)}),
+			Entry("an IPv6 address", "http://[fd00::5]", v3.EntityRule{Nets: []string{"fd00::5/128"}, Ports: networkpolicy.Ports(80)}),
+		)
+
 		It("should configures Kibana publicBaseUrl when BaseURL is specified", func() {
 			cfg.ElasticLicenseType = render.ElasticsearchLicenseTypeBasic
 			cfg.BaseURL = "https://test.domain.com"
@@ -881,6 +1019,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 
 			compareResources(createResources, expectedCreateResources)
 			compareResources(deleteResources, []resourceTestObj{
+				{render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, &corev1.Secret{}, nil},
 				{render.KibanaName, render.KibanaNamespace, &kbv1.Kibana{}, nil},
 				{render.ESCuratorName, render.ElasticsearchNamespace, &batchv1.CronJob{}, nil},
 				{render.ESCuratorName, "", &rbacv1.ClusterRole{}, nil},
@@ -1338,6 +1477,64 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				})
 			})
 		})
+		Context("Data tiers", func() {
+			It("sets the roles, count and resources of each NodeSet", func() {
+				warmStorage := corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{"storage": resource.MustParse("200Gi")},
+					Limits:   corev1.ResourceList{"storage": resource.MustParse("200Gi")},
+				}
+				cfg.LogStorage.Spec.StorageClassName = "fast"
+				cfg.LogStorage.Spec.Nodes = &operatorv1.Nodes{
+					Count: 6,
+					NodeSets: []operatorv1.NodeSet{
+						{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleMaster, operatorv1.ElasticsearchNodeRoleHot}},
+						{
+							Roles:                []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleWarm},
+							Count:                2,
+							ResourceRequirements: &warmStorage,
+							StorageClassName:     "slow",
+						},
+						{Roles: []operatorv1.ElasticsearchNodeRole{operatorv1.ElasticsearchNodeRoleFrozen}, Count: 1},
+					},
+				}
+
+				component := render.LogStorage(cfg)
+
+				createResources, _ := component.Objects()
+				nodeSets := getElasticsearch(createResources).Spec.NodeSets
+				Expect(nodeSets).To(HaveLen(3))
+
+				hot, warm, frozen := nodeSets[0], nodeSets[1], nodeSets[2]
+				Expect(hot.Count).To(Equal(int32(3)))
+				Expect(hot.Config.Data).To(HaveKeyWithValue("node.roles", []string{"master", "data_hot", "data_content"}))
+				Expect(hot.Config.Data).NotTo(HaveKey("node.master"))
+				Expect(*hot.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("fast"))
+
+				Expect(warm.Count).To(Equal(int32(2)))
+				Expect(warm.Config.Data).To(HaveKeyWithValue("node.roles", []string{"data_warm"}))
+				Expect(*warm.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("slow"))
+				Expect(warm.VolumeClaimTemplates[0].Spec.Resources).To(Equal(warmStorage))
+				Expect(warm.PodTemplate.Spec.Containers[0].Resources).To(Equal(hot.PodTemplate.Spec.Containers[0].Resources))
+				Expect(warm.Name).NotTo(Equal(hot.Name))
+
+				Expect(frozen.Count).To(Equal(int32(1)))
+				Expect(frozen.Config.Data).To(HaveKeyWithValue("node.roles", []string{"data_frozen"}))
+			})
+
+			It("keeps the legacy node roles for NodeSets without roles", func() {
+				cfg.LogStorage.Spec.Nodes = &operatorv1.Nodes{Count: 2, NodeSets: []operatorv1.NodeSet{{}}}
+
+				component := render.LogStorage(cfg)
+
+				createResources, _ := component.Objects()
+				nodeSets := getElasticsearch(createResources).Spec.NodeSets
+				Expect(nodeSets).To(HaveLen(1))
+				Expect(nodeSets[0].Count).To(Equal(int32(2)))
+				Expect(nodeSets[0].Config.Data).To(HaveKeyWithValue("node.master", "true"))
+				Expect(nodeSets[0].Config.Data).NotTo(HaveKey("node.roles"))
+			})
+		})
+
 		Context("Node selection", func() {
 			When("NodeSets is set but empty", func() {
 				It("returns the default NodeSet", func() {
@@ -145,21 +145,22 @@ func (mc *monitorComponent) ResolveImages(is *operatorv1.ImageSet) error {
 	reg := mc.cfg.Installation.Registry
 	path := mc.cfg.Installation.ImagePath
 	prefix := mc.cfg.Installation.ImagePrefix
+	overrides := mc.cfg.Installation.ImageOverrides
 
 	errMsgs := []string{}
 	var err error
 
-	mc.alertmanagerImage, err = components.GetReference(components.ComponentPrometheusAlertmanager, reg, path, prefix, is)
+	mc.alertmanagerImage, err = components.GetReference(components.ComponentPrometheusAlertmanager, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
 
-	mc.prometheusImage, err = components.GetReference(components.ComponentPrometheus, reg, path, prefix, is)
+	mc.prometheusImage, err = components.GetReference(components.ComponentPrometheus, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
 
-	mc.prometheusServiceImage, err = components.GetReference(components.ComponentTigeraPrometheusService, reg, path, prefix, is)
+	mc.prometheusServiceImage, err = components.GetReference(components.ComponentTigeraPrometheusService, reg, path, prefix, overrides, is)
 	if err != nil {
 		errMsgs = append(errMsgs, err.Error())
 	}
@@ -776,7 +777,7 @@ func (mc *monitorComponent) serviceMonitorCalicoNode() *monitoringv1.ServiceMoni
 					{
 						Key:      "k8s-app",
 						Operator: metav1.LabelSelectorOpIn,
-						Values:   []string{"calico-node", "calico-node-windows"},
+						Values:   []string{"calico-node", "calico-node-windows", common.NodeCanaryDaemonSetName},
 					},
 				},
 			},
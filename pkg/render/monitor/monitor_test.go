@@ -428,7 +428,7 @@ var _ = Describe("monitor rendering tests", func() {
 		Expect(servicemonitorObj.Spec.Selector.MatchExpressions).To(ConsistOf([]metav1.LabelSelectorRequirement{
 			{Key: "k8s-app",
 				Operator: metav1.LabelSelectorOpIn,
-				Values:   []string{"calico-node", "calico-node-windows"}},
+				Values:   []string{"calico-node", "calico-node-windows", "calico-node-canary"}},
 		}))
 		Expect(servicemonitorObj.Spec.NamespaceSelector.MatchNames).To(HaveLen(1))
 		Expect(servicemonitorObj.Spec.NamespaceSelector.MatchNames[0]).To(Equal("calico-system"))
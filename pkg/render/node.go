@@ -58,10 +58,11 @@ const (
 	nodeTerminationGracePeriodSeconds = 5
 	NodeFinalizer                     = "tigera.io/cni-protector"
 
-	CalicoNodeMetricsService      = "calico-node-metrics"
-	NodePrometheusTLSServerSecret = "calico-node-prometheus-server-tls"
-	CalicoNodeObjectName          = "calico-node"
-	CalicoCNIPluginObjectName     = "calico-cni-plugin"
+	CalicoNodeMetricsService       = "calico-node-metrics"
+	CalicoNodeCanaryMetricsService = "calico-node-metrics-canary"
+	NodePrometheusTLSServerSecret  = "calico-node-prometheus-server-tls"
+	CalicoNodeObjectName           = "calico-node"
+	CalicoCNIPluginObjectName      = "calico-cni-plugin"
 )
 
 var (
@@ -120,6 +121,14 @@ type NodeConfiguration struct {
 
 	// Whether the cluster supports pod security policies.
 	UsePSP bool
+
+	// CanaryImageSet holds the images for the canary nodes selected by the Installation's ImageSetCanary. When set,
+	// a second DaemonSet with its images runs on the canary nodes and calico-node runs on every other node.
+	CanaryImageSet *operatorv1.ImageSet
+
+	// CanaryPromoted is set once the canary has been promoted. calico-node then runs the CanaryImageSet's images on
+	// every node and there is no second DaemonSet.
+	CanaryPromoted bool
 }
 
 // Node creates the node daemonset and other resources for the daemonset to operate normally.
@@ -135,12 +144,35 @@ type nodeComponent struct {
 	cniImage     string
 	flexvolImage string
 	nodeImage    string
+
+	// canaryImages maps each of the images above to the image to use for it on the canary nodes.
+	canaryImages map[string]string
 }
 
 func (c *nodeComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	if c.canaryPromoted() {
+		is = c.cfg.CanaryImageSet
+	}
+	var err error
+	if c.cniImage, c.flexvolImage, c.nodeImage, err = c.resolveImages(is); err != nil {
+		return err
+	}
+	if c.canaryEnabled() {
+		cni, flexvol, node, err := c.resolveImages(c.cfg.CanaryImageSet)
+		if err != nil {
+			return fmt.Errorf("canary ImageSet %s: %v", c.cfg.CanaryImageSet.Name, err)
+		}
+		c.canaryImages = map[string]string{c.cniImage: cni, c.flexvolImage: flexvol, c.nodeImage: node}
+	}
+	return nil
+}
+
+// resolveImages returns the CNI, FlexVolume and node images to use from the ImageSet.
+func (c *nodeComponent) resolveImages(is *operatorv1.ImageSet) (cniImage, flexvolImage, nodeImage string, err error) {
 	reg := c.cfg.Installation.Registry
 	path := c.cfg.Installation.ImagePath
 	prefix := c.cfg.Installation.ImagePrefix
+	overrides := c.cfg.Installation.ImageOverrides
 	var errMsgs []string
 	appendIfErr := func(imageName string, err error) string {
 		if err != nil {
@@ -151,27 +183,37 @@ func (c *nodeComponent) ResolveImages(is *operatorv1.ImageSet) error {
 
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		if operatorv1.IsFIPSModeEnabled(c.cfg.Installation.FIPSMode) {
-			c.cniImage = appendIfErr(components.GetReference(components.ComponentTigeraCNIFIPS, reg, path, prefix, is))
+			cniImage = appendIfErr(components.GetReference(components.ComponentTigeraCNIFIPS, reg, path, prefix, overrides, is))
 		} else {
-			c.cniImage = appendIfErr(components.GetReference(components.ComponentTigeraCNI, reg, path, prefix, is))
+			cniImage = appendIfErr(components.GetReference(components.ComponentTigeraCNI, reg, path, prefix, overrides, is))
 		}
-		c.nodeImage = appendIfErr(components.GetReference(components.ComponentTigeraNode, reg, path, prefix, is))
-		c.flexvolImage = appendIfErr(components.GetReference(components.ComponentFlexVolumePrivate, reg, path, prefix, is))
+		nodeImage = appendIfErr(components.GetReference(components.ComponentTigeraNode, reg, path, prefix, overrides, is))
+		flexvolImage = appendIfErr(components.GetReference(components.ComponentFlexVolumePrivate, reg, path, prefix, overrides, is))
 	} else {
-		c.flexvolImage = appendIfErr(components.GetReference(components.ComponentFlexVolume, reg, path, prefix, is))
+		flexvolImage = appendIfErr(components.GetReference(components.ComponentFlexVolume, reg, path, prefix, overrides, is))
 		if operatorv1.IsFIPSModeEnabled(c.cfg.Installation.FIPSMode) {
-			c.cniImage = appendIfErr(components.GetReference(components.ComponentCalicoCNIFIPS, reg, path, prefix, is))
-			c.nodeImage = appendIfErr(components.GetReference(components.ComponentCalicoNodeFIPS, reg, path, prefix, is))
+			cniImage = appendIfErr(components.GetReference(components.ComponentCalicoCNIFIPS, reg, path, prefix, overrides, is))
+			nodeImage = appendIfErr(components.GetReference(components.ComponentCalicoNodeFIPS, reg, path, prefix, overrides, is))
 		} else {
-			c.cniImage = appendIfErr(components.GetReference(components.ComponentCalicoCNI, reg, path, prefix, is))
-			c.nodeImage = appendIfErr(components.GetReference(components.ComponentCalicoNode, reg, path, prefix, is))
+			cniImage = appendIfErr(components.GetReference(components.ComponentCalicoCNI, reg, path, prefix, overrides, is))
+			nodeImage = appendIfErr(components.GetReference(components.ComponentCalicoNode, reg, path, prefix, overrides, is))
 		}
 	}
 
 	if len(errMsgs) != 0 {
-		return fmt.Errorf(strings.Join(errMsgs, ","))
+		return "", "", "", fmt.Errorf(strings.Join(errMsgs, ","))
 	}
-	return nil
+	return cniImage, flexvolImage, nodeImage, nil
+}
+
+// canaryEnabled returns true if a second DaemonSet should run on the canary nodes.
+func (c *nodeComponent) canaryEnabled() bool {
+	return c.cfg.CanaryImageSet != nil && c.cfg.Installation.ImageSetCanary != nil && !c.cfg.CanaryPromoted
+}
+
+// canaryPromoted returns true if calico-node should run the canary's images on every node.
+func (c *nodeComponent) canaryPromoted() bool {
+	return c.cfg.CanaryImageSet != nil && c.cfg.Installation.ImageSetCanary != nil && c.cfg.CanaryPromoted
 }
 
 func (c *nodeComponent) SupportedOSType() rmeta.OSType {
@@ -205,6 +247,14 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		// Include Service for exposing node metrics.
 		objs = append(objs, c.nodeMetricsService())
+		if c.canaryEnabled() {
+			objs = append(objs, c.canaryMetricsService())
+		} else {
+			objsToDelete = append(objsToDelete, &corev1.Service{
+				TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: CalicoNodeCanaryMetricsService, Namespace: common.CalicoNamespace},
+			})
+		}
 	}
 
 	cniConfig := c.nodeCNIConfigMap()
@@ -224,7 +274,17 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, c.nodePodSecurityPolicy())
 	}
 
-	objs = append(objs, c.nodeDaemonset(cniConfig))
+	ds := c.nodeDaemonset(cniConfig)
+	if c.canaryEnabled() {
+		objs = append(objs, c.canaryDaemonset(ds))
+		excludeCanaryNodes(ds, c.cfg.Installation.ImageSetCanary.NodeSelector)
+	} else {
+		objsToDelete = append(objsToDelete, &appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: common.NodeCanaryDaemonSetName, Namespace: common.CalicoNamespace},
+		})
+	}
+	objs = append(objs, ds)
 
 	if c.cfg.MigrateNamespaces {
 		objs = append(objs, migration.ClusterRoleForKubeSystemNode())
@@ -992,6 +1052,76 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 	return &ds
 }
 
+// canaryDaemonset returns a copy of the calico-node DaemonSet that runs on the canary nodes with the canary images.
+// It selects its pods by its own label, so that neither DaemonSet selects the pods of the other.
+func (c *nodeComponent) canaryDaemonset(ds *appsv1.DaemonSet) *appsv1.DaemonSet {
+	canary := ds.DeepCopy()
+	canary.Name = common.NodeCanaryDaemonSetName
+	canary.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": common.NodeCanaryDaemonSetName}}
+	if canary.Spec.Template.Labels == nil {
+		canary.Spec.Template.Labels = map[string]string{}
+	}
+	canary.Spec.Template.Labels["k8s-app"] = common.NodeCanaryDaemonSetName
+
+	spec := &canary.Spec.Template.Spec
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			if image, ok := c.canaryImages[containers[i].Image]; ok {
+				containers[i].Image = image
+			}
+		}
+	}
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range c.cfg.Installation.ImageSetCanary.NodeSelector {
+		spec.NodeSelector[k] = v
+	}
+	return canary
+}
+
+// excludeCanaryNodes keeps the DaemonSet off the canary nodes. A node is a canary if it has every label in the
+// selector, so the DaemonSet must be allowed on nodes that lack any one of them. Node selector terms are ORed, so
+// each existing term is replaced by one term per label that also requires the node not to have that label's value.
+func excludeCanaryNodes(ds *appsv1.DaemonSet, selector map[string]string) {
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	spec := &ds.Spec.Template.Spec
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		required = &corev1.NodeSelector{}
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	}
+	terms := required.NodeSelectorTerms
+	if len(terms) == 0 {
+		terms = []corev1.NodeSelectorTerm{{}}
+	}
+
+	var excluded []corev1.NodeSelectorTerm
+	for _, term := range terms {
+		for _, k := range keys {
+			t := *term.DeepCopy()
+			t.MatchExpressions = append(t.MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      k,
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{selector[k]},
+			})
+			excluded = append(excluded, t)
+		}
+	}
+	required.NodeSelectorTerms = excluded
+}
+
 // cniDirectories returns the binary and network config directories for the configured platform.
 func (c *nodeComponent) cniDirectories() (string, string, string) {
 	var cniBinDir, cniNetDir, cniLogDir string
@@ -1799,6 +1929,16 @@ func (c *nodeComponent) nodeMetricsService() *corev1.Service {
 	}
 }
 
+// canaryMetricsService returns a copy of the node metrics Service for the pods of the canary DaemonSet, which are
+// labeled with its name rather than calico-node's.
+func (c *nodeComponent) canaryMetricsService() *corev1.Service {
+	svc := c.nodeMetricsService()
+	svc.Name = CalicoNodeCanaryMetricsService
+	svc.Labels = map[string]string{"k8s-app": common.NodeCanaryDaemonSetName}
+	svc.Spec.Selector = map[string]string{"k8s-app": common.NodeCanaryDaemonSetName}
+	return svc
+}
+
 func (c *nodeComponent) nodePodSecurityPolicy() *policyv1beta1.PodSecurityPolicy {
 	psp := podsecuritypolicy.NewBasePolicy(common.NodeDaemonSetName)
 	psp.Spec.Privileged = true
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -3499,6 +3500,128 @@ var _ = Describe("Node rendering tests", func() {
 				Expect(nodeDS.Spec.Template.Spec.InitContainers[1].Image).To(ContainSubstring("-fips"))
 			})
 
+			Context("With an ImageSet canary", func() {
+				BeforeEach(func() {
+					cfg.Installation.Variant = operatorv1.Calico
+					cfg.Installation.ImageSetCanary = &operatorv1.ImageSetCanary{
+						ImageSet:     "calico-canary",
+						NodeSelector: map[string]string{"zone": "a", "canary": "true"},
+					}
+					cfg.CanaryImageSet = &operatorv1.ImageSet{
+						ObjectMeta: metav1.ObjectMeta{Name: "calico-canary"},
+						Spec: operatorv1.ImageSetSpec{Images: []operatorv1.Image{
+							{Image: "calico/node", Digest: "sha256:node"},
+							{Image: "calico/cni", Digest: "sha256:cni"},
+							{Image: "calico/pod2daemon-flexvol", Digest: "sha256:flexvol"},
+						}},
+					}
+				})
+
+				It("should render a canary DaemonSet with the canary images on the selected nodes", func() {
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, toDelete := component.Objects()
+
+					canary := rtest.GetResource(resources, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(canary.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"zone": "a", "canary": "true"}))
+					Expect(canary.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix("calico/node@sha256:node"))
+					cni := rtest.GetContainer(canary.Spec.Template.Spec.InitContainers, "install-cni")
+					Expect(cni.Image).To(HaveSuffix("calico/cni@sha256:cni"))
+					Expect(rtest.GetResource(toDelete, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).To(BeNil())
+
+					ds := rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix("calico/node:" + components.ComponentCalicoNode.Version))
+					Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "canary", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}}}},
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+					}))
+				})
+
+				It("should select the canary pods by their own label", func() {
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, _ := component.Objects()
+
+					canary := rtest.GetResource(resources, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(canary.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": common.NodeCanaryDaemonSetName}))
+					Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("k8s-app", common.NodeCanaryDaemonSetName))
+					ds := rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Template.Labels).NotTo(HaveKeyWithValue("k8s-app", common.NodeCanaryDaemonSetName))
+				})
+
+				It("should keep existing node affinity when excluding the canary nodes", func() {
+					cfg.Installation.KubernetesProvider = operatorv1.ProviderAKS
+					cfg.Installation.ImageSetCanary.NodeSelector = map[string]string{"canary": "true"}
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, _ := component.Objects()
+
+					ds := rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"virtual-kubelet"}},
+							{Key: "canary", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}},
+						}},
+					}))
+					canary := rtest.GetResource(resources, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(canary.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(HaveLen(1))
+					Expect(canary.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).To(HaveLen(1))
+				})
+
+				It("should error if the canary ImageSet is missing an image", func() {
+					cfg.CanaryImageSet.Spec.Images = cfg.CanaryImageSet.Spec.Images[:1]
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(MatchError(ContainSubstring("canary ImageSet calico-canary")))
+				})
+
+				It("should delete the canary DaemonSet when there is no canary", func() {
+					cfg.CanaryImageSet = nil
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, toDelete := component.Objects()
+					Expect(rtest.GetResource(resources, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).To(BeNil())
+					Expect(rtest.GetResource(toDelete, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+				})
+
+				It("should run the canary images on every node once the canary is promoted", func() {
+					cfg.CanaryPromoted = true
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, toDelete := component.Objects()
+
+					Expect(rtest.GetResource(resources, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).To(BeNil())
+					Expect(rtest.GetResource(toDelete, common.NodeCanaryDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+					ds := rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix("calico/node@sha256:node"))
+					Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "install-cni").Image).To(HaveSuffix("calico/cni@sha256:cni"))
+					Expect(ds.Spec.Template.Spec.Affinity).To(BeNil())
+				})
+
+				It("should expose the metrics of the canary pods", func() {
+					cfg.Installation.Variant = operatorv1.TigeraSecureEnterprise
+					cfg.CanaryImageSet.Spec.Images = []operatorv1.Image{
+						{Image: "tigera/cnx-node", Digest: "sha256:node"},
+						{Image: "tigera/cni", Digest: "sha256:cni"},
+						{Image: "tigera/pod2daemon-flexvol", Digest: "sha256:flexvol"},
+					}
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, toDelete := component.Objects()
+
+					svc := rtest.GetResource(resources, render.CalicoNodeCanaryMetricsService, common.CalicoNamespace, "", "v1", "Service").(*corev1.Service)
+					Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": common.NodeCanaryDaemonSetName}))
+					Expect(svc.Spec.ClusterIP).To(Equal("None"))
+					Expect(rtest.GetResource(toDelete, render.CalicoNodeCanaryMetricsService, common.CalicoNamespace, "", "v1", "Service")).To(BeNil())
+
+					cfg.CanaryPromoted = true
+					component = render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, toDelete = component.Objects()
+					Expect(rtest.GetResource(resources, render.CalicoNodeCanaryMetricsService, common.CalicoNamespace, "", "v1", "Service")).To(BeNil())
+					Expect(rtest.GetResource(toDelete, render.CalicoNodeCanaryMetricsService, common.CalicoNamespace, "", "v1", "Service")).NotTo(BeNil())
+				})
+			})
+
 			Context("With calico-node DaemonSet overrides", func() {
 				rr1 := corev1.ResourceRequirements{
 					Limits: corev1.ResourceList{
@@ -92,9 +92,10 @@ func (pc *packetCaptureApiComponent) ResolveImages(is *operatorv1.ImageSet) erro
 	reg := pc.cfg.Installation.Registry
 	path := pc.cfg.Installation.ImagePath
 	prefix := pc.cfg.Installation.ImagePrefix
+	overrides := pc.cfg.Installation.ImageOverrides
 
 	var err error
-	pc.image, err = components.GetReference(components.ComponentPacketCapture, reg, path, prefix, is)
+	pc.image, err = components.GetReference(components.ComponentPacketCapture, reg, path, prefix, overrides, is)
 	if err != nil {
 		return err
 	}
@@ -97,9 +97,10 @@ func (pr *policyRecommendationComponent) ResolveImages(is *operatorv1.ImageSet)
 	reg := pr.cfg.Installation.Registry
 	path := pr.cfg.Installation.ImagePath
 	prefix := pr.cfg.Installation.ImagePrefix
+	overrides := pr.cfg.Installation.ImageOverrides
 
 	var err error
-	pr.image, err = components.GetReference(components.ComponentPolicyRecommendation, reg, path, prefix, is)
+	pr.image, err = components.GetReference(components.ComponentPolicyRecommendation, reg, path, prefix, overrides, is)
 	if err != nil {
 		return err
 	}
@@ -21,8 +21,12 @@ import (
 )
 
 const (
-	TenantCASecretName                = "tigera-ca-private-tenant"
-	CASecretName                      = "tigera-ca-private"
+	TenantCASecretName = "tigera-ca-private-tenant"
+	CASecretName       = "tigera-ca-private"
+
+	// PreviousCACertKey is the key in the CA secret that holds the certificate of the CA that was rotated out.
+	PreviousCACertKey = "previous-ca.crt"
+
 	TrustedCertConfigMapKeyName       = "tigera-ca-bundle.crt"
 	TrustedCertVolumeMountPath        = "/etc/pki/tls/"
 	TrustedCertVolumeMountPathWindows = "c:/etc/pki/tls/"
@@ -43,6 +43,10 @@ type KeyPair struct {
 
 	// OriginalSecret maintains a copy of the secret that the KeyPair was created from.
 	OriginalSecret *corev1.Secret
+
+	// PreviousCA is the PEM encoded certificate of the CA that this CA replaced. It is only set on the operator CA
+	// while the previous CA is still trusted, and is written to its secret under PreviousCACertKey.
+	PreviousCA []byte
 }
 
 func (k *KeyPair) GetCertificatePEM() []byte {
@@ -78,6 +82,9 @@ func (k *KeyPair) Secret(namespace string) *corev1.Secret {
 	}
 	data[corev1.TLSPrivateKeyKey] = k.PrivateKeyPEM
 	data[corev1.TLSCertKey] = k.CertificatePEM
+	if len(k.PreviousCA) > 0 {
+		data[PreviousCACertKey] = k.PreviousCA
+	}
 	return &corev1.Secret{
 		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{Name: k.GetName(), Namespace: namespace},
@@ -0,0 +1,412 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhooks serves validating and defaulting admission webhooks for the operator.tigera.io custom resources,
+// so that invalid resources are rejected when they are applied rather than reported as degraded after reconcile.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/amazoncloudintegration"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/controller/egressgateway"
+	"github.com/tigera/operator/pkg/controller/installation"
+	"github.com/tigera/operator/pkg/controller/logstorage/initializer"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	rcertificatemanagement "github.com/tigera/operator/pkg/render/certificatemanagement"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+const (
+	// Port is the port the manager's webhook server listens on.
+	Port = 9443
+
+	// CertDir is the directory the webhook server loads its serving certificate from.
+	CertDir = "/tmp/k8s-webhook-server/serving-certs"
+
+	// ServiceName is the name of the Service in the operator namespace that fronts the webhook server.
+	ServiceName = "tigera-operator-webhook"
+
+	// SecretName is the name of the Secret in the operator namespace that holds the serving certificate.
+	SecretName = "tigera-operator-webhook-tls"
+
+	// ConfigurationName is the name of both the ValidatingWebhookConfiguration and MutatingWebhookConfiguration.
+	ConfigurationName = "tigera-operator"
+
+	// webhookTimeoutSeconds is kept short since the webhooks fail open.
+	webhookTimeoutSeconds = 5
+
+	// certSyncInterval is how often each operator pod copies the serving certificate from its Secret to CertDir.
+	certSyncInterval = 30 * time.Second
+
+	controllerName = "webhooks-controller"
+)
+
+var log = logf.Log.WithName("webhooks")
+
+// registration is a custom resource that the webhooks are served for.
+type registration struct {
+	obj      client.Object
+	resource string
+	hook     admission.CustomValidator
+}
+
+// registrations returns the custom resources to serve webhooks for. Like the controllers, the enterprise and Amazon
+// resources are only included if their CRDs exist.
+func registrations(cli client.Client, opts options.AddOptions) []registration {
+	regs := []registration{
+		{obj: &operatorv1.Installation{}, resource: "installations", hook: &installation.Webhook{Client: cli, Provider: opts.DetectedProvider}},
+		{obj: &operatorv1.ImageSet{}, resource: "imagesets", hook: &imageset.Webhook{Client: cli}},
+	}
+	if opts.EnterpriseCRDExists {
+		regs = append(regs,
+			registration{obj: &operatorv1.EgressGateway{}, resource: "egressgateways", hook: &egressgateway.Webhook{Client: cli}},
+			registration{obj: &operatorv1.LogStorage{}, resource: "logstorages", hook: &initializer.Webhook{}},
+		)
+	}
+	if opts.AmazonCRDExists {
+		regs = append(regs, registration{obj: &operatorv1.AmazonCloudIntegration{}, resource: "amazoncloudintegrations", hook: &amazoncloudintegration.Webhook{}})
+	}
+	return regs
+}
+
+// AddToManager registers the webhooks with the manager's webhook server. The serving certificate, the Service and the
+// webhook configurations are reconciled by a controller, so that only the leader writes them and the certificate is
+// renewed by the certificate manager. Each operator pod copies the serving certificate to CertDir. The client must be
+// usable before the manager is started.
+func AddToManager(ctx context.Context, mgr manager.Manager, cli client.Client, opts options.AddOptions) error {
+	regs := registrations(mgr.GetClient(), opts)
+	for _, r := range regs {
+		b := builder.WebhookManagedBy(mgr).For(r.obj).WithValidator(r.hook)
+		if d, ok := r.hook.(admission.CustomDefaulter); ok {
+			b = b.WithDefaulter(d)
+		}
+		if err := b.Complete(); err != nil {
+			return fmt.Errorf("failed to register webhook for %T: %w", r.obj, err)
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	pod := &corev1.Pod{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: hostname, Namespace: common.OperatorNamespace()}, pod); err != nil {
+		return fmt.Errorf("webhooks require the operator to run in a pod: %w", err)
+	}
+
+	// The webhook server doesn't start without a certificate, so start with the serving certificate if it has already
+	// been created, or with a temporary one until the leader has created it.
+	if err := writeInitialCert(ctx, cli, CertDir); err != nil {
+		return err
+	}
+	if err := mgr.Add(&certSyncer{reader: mgr.GetAPIReader(), certDir: CertDir}); err != nil {
+		return err
+	}
+
+	r := &Reconciler{
+		client:        mgr.GetClient(),
+		scheme:        mgr.GetScheme(),
+		clusterDomain: opts.ClusterDomain,
+		regs:          regs,
+		selector:      podSelector(pod),
+	}
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{SecretName, certificatemanagement.CASecretName} {
+		if err = utils.AddSecretsWatch(c, name, common.OperatorNamespace()); err != nil {
+			return fmt.Errorf("%s failed to watch secret %s: %w", controllerName, name, err)
+		}
+	}
+	if err = utils.AddPeriodicReconcile(c, utils.PeriodicReconcileTime, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("%s failed to create periodic reconcile watch: %w", controllerName, err)
+	}
+	return nil
+}
+
+// podSelector returns the labels that select the operator pods, i.e. the labels of this pod without those that are
+// specific to its ReplicaSet.
+func podSelector(pod *corev1.Pod) map[string]string {
+	selector := map[string]string{}
+	for k, v := range pod.Labels {
+		if k == "pod-template-hash" {
+			continue
+		}
+		selector[k] = v
+	}
+	return selector
+}
+
+// Reconciler provisions the webhook serving certificate, signed by the operator CA, and creates or updates the
+// objects that route admission requests for the registered resources to the operator pods.
+type Reconciler struct {
+	client        client.Client
+	scheme        *runtime.Scheme
+	clusterDomain string
+	regs          []registration
+	selector      map[string]string
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	ns := common.OperatorNamespace()
+
+	// The serving certificate is always issued by the operator CA, since the webhook server can't use a certificate
+	// that is issued through certificate management.
+	cm, err := certificatemanager.Create(r.client, nil, r.clusterDomain, ns, certificatemanager.WithLogger(reqLogger), certificatemanager.WithContext(ctx))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	keyPair, err := cm.GetOrCreateKeyPair(r.client, SecretName, ns, []string{fmt.Sprintf("%s.%s.svc", ServiceName, ns)})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	hdler := utils.NewComponentHandler(reqLogger, r.client, r.scheme, nil)
+	hdler.SetController(controllerName)
+	component := rcertificatemanagement.CertificateManagement(&rcertificatemanagement.Config{
+		Namespace:      ns,
+		KeyPairOptions: []rcertificatemanagement.KeyPairOption{rcertificatemanagement.NewKeyPairOption(keyPair, true, false)},
+	})
+	if err := hdler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Trust the previous CA as well while the operator CA is being rotated, so that the pods that haven't picked up
+	// the new serving certificate yet are still trusted.
+	caBundle := cm.CreateTrustedBundle().ConfigMap(ns).Data[certificatemanagement.TrustedCertConfigMapKeyName]
+	if err := writeConfigurations(ctx, r.client, r.scheme, r.regs, r.selector, []byte(caBundle)); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: certificatemanager.RequeueAfter(cm)}, nil
+}
+
+// writeConfigurations creates or updates the Service and webhook configurations for the registered resources.
+func writeConfigurations(ctx context.Context, cli client.Client, scheme *runtime.Scheme, regs []registration, selector map[string]string, caBundle []byte) error {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: ServiceName, Namespace: common.OperatorNamespace()},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       "webhook",
+				Port:       443,
+				TargetPort: intstr.FromInt(Port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: ConfigurationName}}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: ConfigurationName}}
+	for _, r := range regs {
+		gvk, err := apiutil.GVKForObject(r.obj, scheme)
+		if err != nil {
+			return err
+		}
+		suffix := strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+		validating.Webhooks = append(validating.Webhooks, admissionregistrationv1.ValidatingWebhook{
+			Name:                    fmt.Sprintf("validate-%s.%s", strings.ToLower(gvk.Kind), gvk.Group),
+			ClientConfig:            clientConfig("/validate-"+suffix, caBundle),
+			Rules:                   rules(gvk.Group, gvk.Version, r.resource),
+			FailurePolicy:           failurePolicy(),
+			SideEffects:             sideEffects(),
+			AdmissionReviewVersions: []string{"v1"},
+			TimeoutSeconds:          timeoutSeconds(),
+		})
+		if _, ok := r.hook.(admission.CustomDefaulter); ok {
+			mutating.Webhooks = append(mutating.Webhooks, admissionregistrationv1.MutatingWebhook{
+				Name:                    fmt.Sprintf("default-%s.%s", strings.ToLower(gvk.Kind), gvk.Group),
+				ClientConfig:            clientConfig("/mutate-"+suffix, caBundle),
+				Rules:                   rules(gvk.Group, gvk.Version, r.resource),
+				FailurePolicy:           failurePolicy(),
+				SideEffects:             sideEffects(),
+				AdmissionReviewVersions: []string{"v1"},
+				TimeoutSeconds:          timeoutSeconds(),
+			})
+		}
+	}
+
+	for _, obj := range []client.Object{svc, validating, mutating} {
+		if err := createOrUpdate(ctx, cli, obj); err != nil {
+			return fmt.Errorf("failed to write %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// writeInitialCert writes the serving certificate to certDir if its Secret exists, or else a temporary self-signed
+// certificate that isn't trusted by the webhook configurations. The certSyncer replaces it once the Secret exists.
+func writeInitialCert(ctx context.Context, cli client.Client, certDir string) error {
+	secret := &corev1.Secret{}
+	err := cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, secret)
+	if errors.IsNotFound(err) {
+		ns := common.OperatorNamespace()
+		dnsName := fmt.Sprintf("%s.%s.svc", ServiceName, ns)
+		secret, err = certificatemanagement.CreateSelfSignedSecret(SecretName, ns, dnsName, []string{dnsName})
+	}
+	if err != nil {
+		return err
+	}
+	return writeCert(secret, certDir)
+}
+
+// writeCert writes the certificate and key in the Secret to certDir, if they have changed. The webhook server reloads
+// them when they change.
+func writeCert(secret *corev1.Secret, certDir string) error {
+	key, cert := certificatemanagement.GetKeyCertPEM(secret)
+	if len(key) == 0 || len(cert) == 0 {
+		return fmt.Errorf("secret %s/%s does not contain a certificate and key", secret.Namespace, secret.Name)
+	}
+	if err := os.MkdirAll(certDir, 0o755); err != nil {
+		return err
+	}
+	for name, data := range map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key} {
+		path := filepath.Join(certDir, name)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// certSyncer copies the serving certificate from its Secret to the certificate directory. Unlike the controllers, it
+// runs in every operator pod, since the Service sends admission requests to all of them.
+type certSyncer struct {
+	reader  client.Reader
+	certDir string
+}
+
+func (s *certSyncer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *certSyncer) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, s.sync, certSyncInterval)
+	return nil
+}
+
+func (s *certSyncer) sync(ctx context.Context) {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "Failed to read the webhook serving certificate")
+		}
+		return
+	}
+	if err := writeCert(secret, s.certDir); err != nil {
+		log.Error(err, "Failed to write the webhook serving certificate")
+	}
+}
+
+// createOrUpdate creates the object, or replaces the fields that the webhooks manage on the existing object.
+func createOrUpdate(ctx context.Context, cli client.Client, desired client.Object) error {
+	current := desired.DeepCopyObject().(client.Object)
+	err := cli.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if errors.IsNotFound(err) {
+		return cli.Create(ctx, desired)
+	} else if err != nil {
+		return err
+	}
+
+	switch c := current.(type) {
+	case *corev1.Secret:
+		c.Data = desired.(*corev1.Secret).Data
+	case *corev1.Service:
+		c.Spec.Selector = desired.(*corev1.Service).Spec.Selector
+		c.Spec.Ports = desired.(*corev1.Service).Spec.Ports
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		c.Webhooks = desired.(*admissionregistrationv1.ValidatingWebhookConfiguration).Webhooks
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		c.Webhooks = desired.(*admissionregistrationv1.MutatingWebhookConfiguration).Webhooks
+	}
+	return cli.Update(ctx, current)
+}
+
+func clientConfig(path string, caBundle []byte) admissionregistrationv1.WebhookClientConfig {
+	port := int32(443)
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: common.OperatorNamespace(),
+			Name:      ServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: caBundle,
+	}
+}
+
+func rules(group, version, resource string) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{group},
+			APIVersions: []string{version},
+			Resources:   []string{resource},
+		},
+	}}
+}
+
+// failurePolicy returns Ignore, so that the operator's custom resources can still be edited when the operator isn't
+// running, e.g. to fix the configuration that stops it from starting, or to remove the finalizers that it adds. This
+// is safe because the webhooks only reject what the controllers reject too: each controller runs the same defaulting
+// and validation before it renders anything, so a resource that is admitted while the webhooks are unavailable is
+// reported as degraded and not acted on. For the Installation, this includes the merge of the 'overlay' Installation.
+// The flip side is that validation at admission time is best effort: while no operator pod is ready, every change is
+// admitted and any problem is only reported on the TigeraStatus once the operator is back.
+func failurePolicy() *admissionregistrationv1.FailurePolicyType {
+	p := admissionregistrationv1.Ignore
+	return &p
+}
+
+func sideEffects() *admissionregistrationv1.SideEffectClass {
+	s := admissionregistrationv1.SideEffectClassNone
+	return &s
+}
+
+func timeoutSeconds() *int32 {
+	t := int32(webhookTimeoutSeconds)
+	return &t
+}
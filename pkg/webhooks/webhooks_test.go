@@ -0,0 +1,185 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/offline"
+	"github.com/tigera/operator/pkg/tls/certificatemanagement"
+)
+
+var _ = Describe("webhooks", func() {
+	var ctx context.Context
+	var cli client.Client
+	var scheme *runtime.Scheme
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		scheme = offline.NewScheme()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		dir, err = os.MkdirTemp("", "webhooks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should select the operator pods without the pod-template-hash", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			"k8s-app":           "tigera-operator",
+			"pod-template-hash": "abcde",
+		}}}
+		Expect(podSelector(pod)).To(Equal(map[string]string{"k8s-app": "tigera-operator"}))
+	})
+
+	It("should only register the enterprise resources when their CRDs exist", func() {
+		Expect(registrations(cli, options.AddOptions{})).To(HaveLen(2))
+		Expect(registrations(cli, options.AddOptions{EnterpriseCRDExists: true})).To(HaveLen(4))
+		Expect(registrations(cli, options.AddOptions{EnterpriseCRDExists: true, AmazonCRDExists: true})).To(HaveLen(5))
+	})
+
+	Context("with the operator CA", func() {
+		var r *Reconciler
+		var caPEM []byte
+
+		BeforeEach(func() {
+			cm, err := certificatemanager.Create(cli, nil, "cluster.local", common.OperatorNamespace(), certificatemanager.AllowCACreation())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, cm.KeyPair().Secret(common.OperatorNamespace()))).To(Succeed())
+			caPEM = cm.KeyPair().GetCertificatePEM()
+
+			r = &Reconciler{
+				client:        cli,
+				scheme:        scheme,
+				clusterDomain: "cluster.local",
+				regs:          registrations(cli, options.AddOptions{EnterpriseCRDExists: true}),
+				selector:      map[string]string{"k8s-app": "tigera-operator"},
+			}
+		})
+
+		It("should write the serving certificate and the webhook configurations", func() {
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, secret)).To(Succeed())
+			cert, err := certificatemanagement.ParseCertificate(secret.Data[corev1.TLSCertKey])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Issuer.CommonName).To(HavePrefix("tigera-operator-signer"))
+			Expect(cert.DNSNames).To(ContainElement(fmt.Sprintf("%s.%s.svc", ServiceName, common.OperatorNamespace())))
+
+			svc := &corev1.Service{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: ServiceName, Namespace: common.OperatorNamespace()}, svc)).To(Succeed())
+			Expect(svc.Spec.Selector).To(Equal(r.selector))
+			Expect(svc.Spec.Ports[0].TargetPort.IntValue()).To(Equal(Port))
+
+			validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: ConfigurationName}, validating)).To(Succeed())
+			Expect(validating.Webhooks).To(HaveLen(4))
+			installation := validating.Webhooks[0]
+			Expect(installation.Name).To(Equal("validate-installation.operator.tigera.io"))
+			Expect(*installation.ClientConfig.Service.Path).To(Equal("/validate-operator-tigera-io-v1-installation"))
+			Expect(string(installation.ClientConfig.CABundle)).To(ContainSubstring(string(caPEM)))
+			Expect(installation.Rules[0].Resources).To(Equal([]string{"installations"}))
+			Expect(*installation.FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+
+			mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: ConfigurationName}, mutating)).To(Succeed())
+			Expect(mutating.Webhooks).To(HaveLen(2))
+			Expect(*mutating.Webhooks[0].ClientConfig.Service.Path).To(Equal("/mutate-operator-tigera-io-v1-egressgateway"))
+			Expect(*mutating.Webhooks[1].ClientConfig.Service.Path).To(Equal("/mutate-operator-tigera-io-v1-logstorage"))
+		})
+
+		It("should reuse a valid serving certificate", func() {
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			first := &corev1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, first)).To(Succeed())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			second := &corev1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, second)).To(Succeed())
+			Expect(second.Data).To(Equal(first.Data))
+		})
+
+		It("should replace a serving certificate for the wrong DNS names", func() {
+			cm, err := certificatemanager.Create(cli, nil, "cluster.local", common.OperatorNamespace())
+			Expect(err).NotTo(HaveOccurred())
+			wrong, err := cm.GetOrCreateKeyPair(cli, SecretName, common.OperatorNamespace(), []string{"wrong.example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, wrong.Secret(common.OperatorNamespace()))).To(Succeed())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			secret := &corev1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, secret)).To(Succeed())
+			cert, err := certificatemanagement.ParseCertificate(secret.Data[corev1.TLSCertKey])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.DNSNames).To(ContainElement(fmt.Sprintf("%s.%s.svc", ServiceName, common.OperatorNamespace())))
+			Expect(cert.DNSNames).NotTo(ContainElement("wrong.example.com"))
+		})
+	})
+
+	It("should fail to reconcile until the operator CA exists", func() {
+		r := &Reconciler{client: cli, scheme: scheme, regs: registrations(cli, options.AddOptions{})}
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).To(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, &corev1.Secret{})).NotTo(Succeed())
+	})
+
+	It("should start with a temporary certificate and copy the serving certificate once it exists", func() {
+		Expect(writeInitialCert(ctx, cli, dir)).To(Succeed())
+		temporary, err := os.ReadFile(filepath.Join(dir, corev1.TLSCertKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(temporary)).To(ContainSubstring("BEGIN CERTIFICATE"))
+		Expect(cli.Get(ctx, client.ObjectKey{Name: SecretName, Namespace: common.OperatorNamespace()}, &corev1.Secret{})).NotTo(Succeed())
+
+		ns := common.OperatorNamespace()
+		dnsName := fmt.Sprintf("%s.%s.svc", ServiceName, ns)
+		secret, err := certificatemanagement.CreateSelfSignedSecret(SecretName, ns, dnsName, []string{dnsName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, secret)).To(Succeed())
+
+		(&certSyncer{reader: cli, certDir: dir}).sync(ctx)
+		cert, err := os.ReadFile(filepath.Join(dir, corev1.TLSCertKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert).To(Equal(secret.Data[corev1.TLSCertKey]))
+		key, err := os.ReadFile(filepath.Join(dir, corev1.TLSPrivateKeyKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(Equal(secret.Data[corev1.TLSPrivateKeyKey]))
+	})
+})